@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"slices"
 	"syscall"
 	"time"
 
@@ -20,6 +21,7 @@ import (
 	"github.com/remiehneppo/be-task-management/internal/repository"
 	"github.com/remiehneppo/be-task-management/internal/service"
 	"github.com/remiehneppo/be-task-management/internal/worker"
+	"github.com/remiehneppo/be-task-management/types"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 	"github.com/weaviate/weaviate-go-client/v4/weaviate"
@@ -182,6 +184,27 @@ func (a *App) RegisterHandler() {
 	reportRepo := repository.NewReportRepository(a.database)
 	fileMetadataRepo := repository.NewFileMetadataRepository(a.database)
 	pendingDocumentRepo := repository.NewPendingDocumentRepository(a.database)
+	taskTransitionRepo := repository.NewTaskTransitionRepository(a.database)
+	taskActivityRepo := repository.NewTaskActivityRepository(a.database)
+	taskTemplateRepo := repository.NewTaskTemplateRepository(a.database)
+	customFieldRepo := repository.NewCustomFieldRepository(a.database)
+	commentRepo := repository.NewCommentRepository(a.database)
+	exportJobRepo := repository.NewExportJobRepository(a.database)
+	weeklySummaryRepo := repository.NewWeeklySummaryRepository(a.database)
+	taskDeadlineAlertRepo := repository.NewTaskDeadlineAlertRepository(a.database)
+	notificationRepo := repository.NewNotificationRepository(a.database)
+	notificationPreferenceRepo := repository.NewNotificationPreferenceRepository(a.database)
+	notificationDeliveryRepo := repository.NewNotificationDeliveryRepository(a.database)
+	rolePermissionRepo := repository.NewRolePermissionRepository(a.database)
+	workspaceRepo := repository.NewWorkspaceRepository(a.database)
+	auditLogRepo := repository.NewAuditLogRepository(a.database)
+	chatMessageRepo := repository.NewChatMessageRepository(a.database)
+	if err := taskRepo.EnsureIndexes(context.Background()); err != nil {
+		a.logger.Error("Failed to create task indexes: ", err)
+	}
+	if err := workspaceRepo.EnsureDefaults(context.Background()); err != nil {
+		a.logger.Error("Failed to create default workspaces: ", err)
+	}
 	documentClass := repository.DefaultDocumentClass
 	documentClass.Vectorizer = a.config.Weaviate.Text2Vec.Module
 	moduleConfig := make(map[string]interface{})
@@ -198,6 +221,22 @@ func (a *App) RegisterHandler() {
 		documentClass,
 		100,
 	)
+	taskClass := repository.DefaultTaskClass
+	taskClass.Vectorizer = a.config.Weaviate.Text2Vec.Module
+	taskClass.ModuleConfig = moduleConfig
+	// the IDs would only blur the meaning of the task
+	for _, property := range taskClass.Properties {
+		if slices.Contains(repository.TaskVectorProperties, property.Name) {
+			property.ModuleConfig = map[string]interface{}{
+				a.config.Weaviate.Text2Vec.Module: map[string]interface{}{"skip": true},
+			}
+		}
+	}
+	taskVectorRepo := repository.NewTaskVectorRepository(
+		context.Background(),
+		a.vectorDb,
+		taskClass,
+	)
 
 	jwtService := service.NewJWTService(
 		a.config.JWT.Secret,
@@ -212,16 +251,111 @@ func (a *App) RegisterHandler() {
 	} else {
 		aiService = service.NewOpenAIService(a.config.OpenAI)
 	}
-	aiAssistantService := service.NewAIAssistantService(aiService, a.config.OpenAI.SystemPrompt)
+	aiAssistantService := service.NewAIAssistantService(aiService, chatMessageRepo, a.config.OpenAI.SystemPrompt)
 	loginService := service.NewLoginService(jwtService, userRepo)
+	authorizationService := service.NewAuthorizationService(rolePermissionRepo, userRepo, auditLogRepo)
+	adminService := service.NewAdminService(
+		userRepo,
+		taskRepo,
+		reportRepo,
+		commentRepo,
+		workspaceRepo,
+		auditLogRepo,
+		authorizationService,
+	)
+	workspaceService := service.NewWorkspaceService(
+		workspaceRepo,
+		userRepo,
+		taskRepo,
+		auditLogRepo,
+		authorizationService,
+	)
 	userService := service.NewUserService(userRepo)
-	taskService := service.NewTaskService(taskRepo, reportRepo, userRepo)
+	notificationChannels := map[string]service.NotificationChannel{
+		types.NOTIFICATION_CHANNEL_WEBHOOK: service.NewWebhookChannel(a.config.Notification.WebhookTimeout),
+	}
+	if a.config.Notification.SMTP.Host != "" {
+		notificationChannels[types.NOTIFICATION_CHANNEL_EMAIL] = service.NewEmailChannel(a.config.Notification.SMTP)
+	}
+	notificationService := service.NewNotificationService(
+		notificationRepo,
+		notificationPreferenceRepo,
+		notificationDeliveryRepo,
+		a.redisClient,
+		notificationChannels,
+		lockService,
+		a.config.Notification,
+	)
+	taskEventHub := service.NewTaskEventHub(a.redisClient, userRepo)
 	fileService := service.NewFileService(
 		a.config.FileUpload.UploadDir,
 		a.config.FileUpload.MaxSize,
 		fileMetadataRepo,
 	)
+	taskService := service.NewTaskService(
+		taskRepo,
+		reportRepo,
+		userRepo,
+		pendingDocumentRepo,
+		taskTransitionRepo,
+		taskActivityRepo,
+		taskTemplateRepo,
+		customFieldRepo,
+		commentRepo,
+		taskVectorRepo,
+		documentVectorRepo,
+		fileService,
+		lockService,
+		notificationService,
+		taskEventHub,
+		authorizationService,
+		a.config.Similarity,
+	)
+	analyticsService := service.NewAnalyticsService(
+		taskRepo,
+		reportRepo,
+		userRepo,
+		authorizationService,
+	)
+	exportService := service.NewExportService(
+		taskService,
+		exportJobRepo,
+		userRepo,
+		lockService,
+		notificationService,
+		a.config.Export,
+	)
+	summaryService := service.NewSummaryService(
+		aiService,
+		taskRepo,
+		reportRepo,
+		userRepo,
+		weeklySummaryRepo,
+		workspaceRepo,
+		notificationService,
+		lockService,
+		authorizationService,
+		a.config.Summary,
+	)
+	importService := service.NewImportService(
+		userRepo,
+		taskRepo,
+		reportRepo,
+		workspaceRepo,
+		auditLogRepo,
+		a.database,
+		authorizationService,
+	)
+	escalationService := service.NewEscalationService(
+		taskRepo,
+		userRepo,
+		taskDeadlineAlertRepo,
+		notificationService,
+		lockService,
+		a.config.Escalation,
+	)
 	pdfService := service.NewPDFService(service.DefaultDocumentServiceConfig)
+	docxService := service.NewDOCXService(service.DefaultDocumentServiceConfig.MaxChunkSize)
 	ragService := service.NewRAGService(
 		aiService,
 		a.config.RAG.SystemPrompt,
@@ -231,10 +365,30 @@ func (a *App) RegisterHandler() {
 		ragService,
 		fileService,
 		pdfService,
+		docxService,
 		documentVectorRepo,
 		pendingDocumentRepo,
+		userRepo,
 		[]string{".pdf"},
 		lockService,
+		notificationService,
+	)
+
+	taskDraftService := service.NewTaskDraftService(
+		aiService,
+		taskService,
+		userRepo,
+		pdfService,
+		docxService,
+		authorizationService,
+	)
+	taskQuestionService := service.NewTaskQuestionService(
+		aiService,
+		taskRepo,
+		reportRepo,
+		userRepo,
+		taskVectorRepo,
+		documentVectorRepo,
 	)
 
 	aiAssistantHandler := handler.NewAIAssistantHandler(aiAssistantService)
@@ -242,13 +396,58 @@ func (a *App) RegisterHandler() {
 	userHandler := handler.NewUserHandler(userService, a.logger)
 	taskHandler := handler.NewTaskHandler(taskService, a.logger)
 	documentHandler := handler.NewDocumentHandler(documentService)
-
-	authMiddleware := middleware.NewAuthMiddleware(jwtService)
+	notificationHandler := handler.NewNotificationHandler(notificationService, a.logger)
+	taskEventHandler := handler.NewTaskEventHandler(taskEventHub, a.logger)
+	analyticsHandler := handler.NewAnalyticsHandler(analyticsService, a.logger)
+	exportHandler := handler.NewExportHandler(exportService, a.logger)
+	importHandler := handler.NewImportHandler(importService, a.logger)
+	summaryHandler := handler.NewSummaryHandler(summaryService, a.logger)
+	taskDraftHandler := handler.NewTaskDraftHandler(taskDraftService, a.logger)
+	taskQuestionHandler := handler.NewTaskQuestionHandler(taskQuestionService, a.logger)
+	roleHandler := handler.NewRoleHandler(authorizationService, a.logger)
+	adminHandler := handler.NewAdminHandler(adminService, a.logger)
+	workspaceHandler := handler.NewWorkspaceHandler(workspaceService, a.logger)
+
+	authMiddleware := middleware.NewAuthMiddleware(jwtService, userRepo)
+	permissionMiddleware := middleware.NewPermissionMiddleware(authorizationService)
 
 	a.worker.RegisterIntervalJob(
 		60,
 		documentService.ProcessDocumentJob(),
 	)
+	a.worker.RegisterScheduleJob(
+		"0 * * * *",
+		taskService.GenerateRecurringTasksJob(),
+	)
+	a.worker.RegisterScheduleJob(
+		a.config.Escalation.Schedule,
+		escalationService.EscalationJob(),
+	)
+	a.worker.RegisterIntervalJob(
+		30,
+		notificationService.DeliveryJob(),
+	)
+	a.worker.RegisterScheduleJob(
+		a.config.Notification.DigestSchedule,
+		notificationService.DigestJob(),
+	)
+	a.worker.RegisterIntervalJob(
+		30,
+		exportService.ProcessExportJob(),
+	)
+	a.worker.RegisterIntervalJob(
+		600,
+		taskService.IndexTasksJob(),
+	)
+	if a.config.UseAI {
+		a.worker.RegisterScheduleJob(
+			a.config.Summary.Schedule,
+			summaryService.SummaryJob(),
+		)
+	}
+
+	go taskEventHub.Run(context.Background())
+	go notificationService.Run(context.Background())
 
 	a.api.Use(middleware.CorsMiddleware)
 	// Register routes
@@ -265,37 +464,128 @@ func (a *App) RegisterHandler() {
 	userGroup := a.api.Group("/api/v1/users")
 	userGroup.Use(authMiddleware.AuthBearerMiddleware())
 	userGroup.GET("/me", userHandler.GetUserInfo)
+	userGroup.GET("/me/permissions", roleHandler.GetMyPermissions)
 	userGroup.POST("/password", userHandler.UpdatePassword)
 	userGroup.GET("/workspace", userHandler.GetUsersSameWorkspace)
 
+	a.api.GET("/api/v1/tasks/events", authMiddleware.AuthStreamMiddleware(), taskEventHandler.StreamTaskEvents)
 	taskGroup := a.api.Group("/api/v1/tasks")
 	taskGroup.Use(authMiddleware.AuthBearerMiddleware())
 
 	taskGroup.GET("/assigned", taskHandler.GetTasksAssignedToUser)
 	taskGroup.GET("/created", taskHandler.GetTasksCreatedByUser)
-	taskGroup.GET("/{id}", taskHandler.GetTaskByID)
+	taskGroup.GET("/watching", taskHandler.GetTasksWatchedByUser)
+	taskGroup.GET("/board", taskHandler.GetTaskBoard)
+	taskGroup.GET("/calendar", taskHandler.GetTaskCalendar)
+	taskGroup.GET("/reports", taskHandler.GetReports)
+	taskGroup.GET("/reports/awaiting", taskHandler.GetReportsAwaitingReview)
+	taskGroup.GET("/reports/:id/comments", taskHandler.GetReportComments)
+	taskGroup.POST("/comments/create", taskHandler.CreateComment)
+	taskGroup.POST("/comments/update", taskHandler.UpdateComment)
+	taskGroup.POST("/comments/delete/:id", taskHandler.DeleteComment)
+	taskGroup.GET("/comments/:id/history", taskHandler.GetCommentHistory)
+	taskGroup.GET("/comments/:id/attachments/:index", taskHandler.DownloadCommentAttachment)
+	taskGroup.GET("/fields", taskHandler.GetCustomFields)
+	taskGroup.POST("/fields/create", taskHandler.CreateCustomField)
+	taskGroup.POST("/fields/update", taskHandler.UpdateCustomField)
+	taskGroup.POST("/fields/delete/:id", taskHandler.DeleteCustomField)
+	taskGroup.GET("/:id", taskHandler.GetTaskByID)
+	taskGroup.GET("/:id/history", taskHandler.GetTaskHistory)
+	taskGroup.GET("/:id/comments", taskHandler.GetTaskComments)
+	taskGroup.GET("/:id/subtree", taskHandler.GetTaskSubtree)
+	taskGroup.GET("/:id/dependencies", taskHandler.GetTaskDependencies)
+	taskGroup.GET("/:id/similar", taskHandler.GetSimilarTasks)
 	taskGroup.POST("/create", taskHandler.CreateTask)
+	taskGroup.POST("/drafts", taskDraftHandler.DraftTasks)
+	taskGroup.POST("/drafts/confirm", taskDraftHandler.ConfirmDrafts)
+	taskGroup.POST("/ask", taskQuestionHandler.AskTasks)
 	taskGroup.POST("/update", taskHandler.UpdateTask)
-	taskGroup.POST("/delete/{id}", taskHandler.DeleteTask)
+	taskGroup.POST("/delete/:id", taskHandler.DeleteTask)
+	taskGroup.POST("/watch/:id", taskHandler.WatchTask)
+	taskGroup.POST("/unwatch/:id", taskHandler.UnwatchTask)
 	taskGroup.GET("/filter", taskHandler.FilterTasks)
 	taskGroup.POST("/report/add", taskHandler.AddReportTask)
 	taskGroup.POST("/report/delete", taskHandler.DeleteReport)
 	taskGroup.POST("/report/update", taskHandler.UpdateReportTask)
 	taskGroup.POST("/report/feedback", taskHandler.FeedbackReport)
+	taskGroup.POST("/report/review", taskHandler.ReviewReport)
+	taskGroup.POST("/dependency/add", taskHandler.AddTaskDependency)
+	taskGroup.POST("/dependency/remove", taskHandler.RemoveTaskDependency)
+	taskGroup.GET("/templates", taskHandler.GetTaskTemplates)
+	taskGroup.GET("/templates/:id", taskHandler.GetTaskTemplateByID)
+	taskGroup.POST("/templates/create", taskHandler.CreateTaskTemplate)
+	taskGroup.POST("/templates/update", taskHandler.UpdateTaskTemplate)
+	taskGroup.POST("/templates/delete/:id", taskHandler.DeleteTaskTemplate)
+
+	a.api.GET("/api/v1/notifications/stream", authMiddleware.AuthStreamMiddleware(), notificationHandler.StreamNotifications)
+	notificationGroup := a.api.Group("/api/v1/notifications")
+	notificationGroup.Use(authMiddleware.AuthBearerMiddleware())
+	notificationGroup.GET("", notificationHandler.GetNotifications)
+	notificationGroup.GET("/unread-count", notificationHandler.CountUnread)
+	notificationGroup.POST("/read", notificationHandler.MarkRead)
+	notificationGroup.POST("/read-all", notificationHandler.MarkAllRead)
+	notificationGroup.GET("/preferences", notificationHandler.GetPreference)
+	notificationGroup.POST("/preferences/update", notificationHandler.UpdatePreference)
+
+	analyticsGroup := a.api.Group("/api/v1/analytics")
+	analyticsGroup.Use(authMiddleware.AuthBearerMiddleware())
+	analyticsGroup.GET("/metrics", analyticsHandler.GetMetrics)
+	analyticsGroup.GET("/weekly", analyticsHandler.GetWeeklySeries)
+
+	exportGroup := a.api.Group("/api/v1/exports")
+	exportGroup.Use(authMiddleware.AuthBearerMiddleware())
+	exportGroup.GET("", exportHandler.Export)
+	exportGroup.GET("/jobs/:id", exportHandler.GetExportJob)
+	exportGroup.GET("/jobs/:id/download", exportHandler.DownloadExport)
+
+	summaryGroup := a.api.Group("/api/v1/summaries")
+	summaryGroup.Use(authMiddleware.AuthBearerMiddleware())
+	summaryGroup.GET("", summaryHandler.GetSummaries)
+	summaryGroup.POST("/generate", summaryHandler.GenerateSummary)
+	summaryGroup.GET("/:id", summaryHandler.GetSummary)
+
+	adminGroup := a.api.Group("/api/v1/admin")
+	adminGroup.Use(authMiddleware.AuthBearerMiddleware())
+	adminGroup.POST("/import", importHandler.Import)
+	adminGroup.GET("/roles", permissionMiddleware.RequirePermission(types.PERMISSION_ROLE_MANAGE), roleHandler.GetRolePermissions)
+	adminGroup.POST("/roles/update", permissionMiddleware.RequirePermission(types.PERMISSION_ROLE_MANAGE), roleHandler.UpdateRolePermissions)
+	userManage := permissionMiddleware.RequirePermission(types.PERMISSION_USER_MANAGE)
+	adminGroup.GET("/users", userManage, adminHandler.GetUsers)
+	adminGroup.GET("/users/:id", userManage, adminHandler.GetUser)
+	adminGroup.POST("/users/create", userManage, adminHandler.CreateUser)
+	adminGroup.POST("/users/update", userManage, adminHandler.UpdateUser)
+	adminGroup.POST("/users/role", userManage, adminHandler.UpdateUserRole)
+	adminGroup.POST("/users/password", userManage, adminHandler.ResetPassword)
+	adminGroup.POST("/users/deactivate/:id", userManage, adminHandler.DeactivateUser)
+	adminGroup.POST("/users/activate/:id", userManage, adminHandler.ActivateUser)
+	adminGroup.POST("/users/delete/:id", userManage, adminHandler.DeleteUser)
+	workspaceManage := permissionMiddleware.RequirePermission(types.PERMISSION_WORKSPACE_MANAGE)
+	adminGroup.POST("/workspaces/create", workspaceManage, workspaceHandler.CreateWorkspace)
+	adminGroup.POST("/workspaces/update", workspaceManage, workspaceHandler.UpdateWorkspace)
+	adminGroup.POST("/workspaces/delete/:id", workspaceManage, workspaceHandler.DeleteWorkspace)
+	adminGroup.GET("/audit-logs", permissionMiddleware.RequirePermission(types.PERMISSION_AUDIT_READ), adminHandler.GetAuditLogs)
+
+	workspaceGroup := a.api.Group("/api/v1/workspaces")
+	workspaceGroup.Use(authMiddleware.AuthBearerMiddleware())
+	workspaceGroup.GET("", workspaceHandler.GetWorkspaces)
 
 	aiAssistantGroup := a.api.Group("/api/v1/assistant")
 	aiAssistantGroup.Use(authMiddleware.AuthBearerMiddleware())
 	aiAssistantGroup.POST("/chat", aiAssistantHandler.ChatWithAssistant)
 	aiAssistantGroup.POST("/chat-stateless", aiAssistantHandler.ChatWithAssistantStateless)
+	aiAssistantGroup.GET("/messages", aiAssistantHandler.PaginateMessages)
 
 	a.api.POST("/api/v1/documents/demo-load-text", documentHandler.DemoloadText)
 	documentGroup := a.api.Group("/api/v1/documents")
 	documentGroup.Use(authMiddleware.AuthBearerMiddleware())
-	documentGroup.POST("/upload", documentHandler.UploadPDF)
-	documentGroup.POST("/search", documentHandler.SearchDocument)
-	documentGroup.POST("/ask-ai", documentHandler.AskAI)
-	documentGroup.POST("/batch-upload", documentHandler.BatchUploadPDFAsync)
-	documentGroup.GET("/view", documentHandler.ViewDocument)
+	documentRead := permissionMiddleware.RequirePermission(types.PERMISSION_DOCUMENT_READ)
+	documentUpload := permissionMiddleware.RequirePermission(types.PERMISSION_DOCUMENT_UPLOAD)
+	documentGroup.GET("", documentRead, documentHandler.ListDocuments)
+	documentGroup.POST("/upload", documentUpload, documentHandler.UploadPDF)
+	documentGroup.POST("/search", documentRead, documentHandler.SearchDocument)
+	documentGroup.POST("/ask-ai", documentRead, documentHandler.AskAI)
+	documentGroup.POST("/batch-upload", documentUpload, documentHandler.BatchUploadPDFAsync)
+	documentGroup.GET("/view", documentRead, documentHandler.ViewDocument)
 
 	// Middleware
 
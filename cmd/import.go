@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/remiehneppo/be-task-management/config"
+	"github.com/remiehneppo/be-task-management/internal/database"
+	"github.com/remiehneppo/be-task-management/internal/repository"
+	"github.com/remiehneppo/be-task-management/internal/service"
+	"github.com/remiehneppo/be-task-management/types"
+	"github.com/spf13/cobra"
+)
+
+// importCmd loads users, tasks or reports from a CSV or XLSX file
+var importCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Import users, tasks or reports from a CSV or XLSX file",
+	Long: `Imports the rows of a CSV or XLSX file into the database, the first row
+naming the columns as in the files of mock/. Import the users first, then
+the tasks and the reports, which name their users by username or ID. Users
+with "admin" in the optional role column are admins, holding every
+permission, which only this command can create.
+
+Without --skip-invalid any invalid row aborts the import and the rows are
+written in a single transaction, which needs MongoDB to run as a replica set.
+
+  be-task-management import --kind users --file mock/user.csv --dry-run`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfgYml, _ := cmd.Flags().GetString("config")
+		kind, _ := cmd.Flags().GetString("kind")
+		filePath, _ := cmd.Flags().GetString("file")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		skipInvalid, _ := cmd.Flags().GetBool("skip-invalid")
+
+		cfg, err := config.LoadConfig(cfgYml)
+		if err != nil {
+			fmt.Println("Error loading config:", err)
+			os.Exit(1)
+		}
+		file, err := os.Open(filePath)
+		if err != nil {
+			fmt.Println("Error opening file:", err)
+			os.Exit(1)
+		}
+		defer file.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+		defer cancel()
+		db := database.NewMongoDatabase(cfg.MongoDB.URI, cfg.MongoDB.Database)
+		if err := db.Connect(ctx); err != nil {
+			fmt.Println("Error connecting to database:", err)
+			os.Exit(1)
+		}
+		defer db.Disconnect(context.Background())
+
+		userRepo := repository.NewUserRepository(db)
+		auditLogRepo := repository.NewAuditLogRepository(db)
+		workspaceRepo := repository.NewWorkspaceRepository(db)
+		if err := workspaceRepo.EnsureDefaults(ctx); err != nil {
+			fmt.Println("Error creating default workspaces:", err)
+			os.Exit(1)
+		}
+		importService := service.NewImportService(
+			userRepo,
+			repository.NewTaskRepository(db),
+			repository.NewReportRepository(db),
+			workspaceRepo,
+			auditLogRepo,
+			db,
+			service.NewAuthorizationService(
+				repository.NewRolePermissionRepository(db),
+				userRepo,
+				auditLogRepo,
+			),
+		)
+		result, err := importService.Import(ctx, &types.ImportRequest{
+			Kind:        kind,
+			DryRun:      dryRun,
+			SkipInvalid: skipInvalid,
+		}, file, filePath)
+		if result != nil {
+			for _, rowErr := range result.Errors {
+				if rowErr.Column != "" {
+					fmt.Printf("line %d: %s %s\n", rowErr.Row, rowErr.Column, rowErr.Message)
+				} else {
+					fmt.Printf("line %d: %s\n", rowErr.Row, rowErr.Message)
+				}
+			}
+			fmt.Printf("%d rows, %d imported, %d skipped\n", result.Rows, result.Imported, result.Skipped)
+			if dryRun {
+				fmt.Println("Dry run, nothing was written")
+			}
+		}
+		if err != nil {
+			if !errors.Is(err, types.ErrImportInvalidRows) {
+				fmt.Println("Error importing:", err)
+			} else {
+				fmt.Println(err)
+			}
+			db.Disconnect(context.Background())
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(importCmd)
+
+	importCmd.Flags().StringP("config", "c", "config.yaml", "Path to the configuration file")
+	importCmd.Flags().String("kind", "", "Kind of rows: users, tasks or reports")
+	importCmd.Flags().String("file", "", "Path to the CSV or XLSX file")
+	importCmd.Flags().Bool("dry-run", false, "Only check the rows")
+	importCmd.Flags().Bool("skip-invalid", false, "Import the valid rows and skip the others")
+	importCmd.MarkFlagRequired("kind")
+	importCmd.MarkFlagRequired("file")
+}
@@ -42,7 +42,12 @@ type AppConfig struct {
 	RAG struct {
 		SystemPrompt string `mapstructure:"system_prompt"`
 	} `mapstructure:"rag"`
-	Environment string `mapstructure:"ENVIRONMENT"`
+	Escalation   EscalationConfig   `mapstructure:"escalation"`
+	Notification NotificationConfig `mapstructure:"notification"`
+	Export       ExportConfig       `mapstructure:"export"`
+	Summary      SummaryConfig      `mapstructure:"summary"`
+	Similarity   SimilarityConfig   `mapstructure:"similarity"`
+	Environment  string             `mapstructure:"ENVIRONMENT"`
 }
 
 type Text2VecConfig struct {
@@ -70,6 +75,80 @@ type OpenaiConfig struct {
 	AllowTool    bool   `mapstructure:"allow_tool"`
 }
 
+// EscalationConfig holds the deadline reminder and overdue escalation rules
+type EscalationConfig struct {
+	// Schedule is the cron expression the escalation job runs on
+	Schedule string `mapstructure:"schedule"`
+	// ReminderLeadTimes are how long before the deadline assignees are reminded
+	ReminderLeadTimes []time.Duration `mapstructure:"reminder_lead_times"`
+	// OverdueLevels are how long after the deadline each level is alerted,
+	// the first level is the creator then every next management level up
+	OverdueLevels []time.Duration `mapstructure:"overdue_levels"`
+}
+
+// NotificationConfig holds the email and webhook delivery settings
+type NotificationConfig struct {
+	// DigestSchedule is the cron expression digest emails are sent on
+	DigestSchedule string     `mapstructure:"digest_schedule"`
+	SMTP           SMTPConfig `mapstructure:"smtp"`
+	// WebhookTimeout bounds a single webhook request
+	WebhookTimeout time.Duration `mapstructure:"webhook_timeout"`
+	// MaxAttempts is how many times a delivery is tried before it is failed
+	MaxAttempts int `mapstructure:"max_attempts"`
+	// RetryBase is the first retry delay, doubled after every failed attempt
+	RetryBase time.Duration `mapstructure:"retry_base"`
+}
+
+// SMTPConfig holds the mail server settings, email is disabled without a
+// host and sent without authentication without a username
+type SMTPConfig struct {
+	Host     string `mapstructure:"host"`
+	Port     int    `mapstructure:"port"`
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+	From     string `mapstructure:"from"`
+	// Timeout bounds the whole exchange with the server
+	Timeout time.Duration `mapstructure:"timeout"`
+}
+
+// ExportConfig holds the task and report export settings
+type ExportConfig struct {
+	// Dir is where background exports are written
+	Dir string `mapstructure:"dir"`
+	// FontPath and BoldFontPath are TrueType fonts with Vietnamese glyphs,
+	// the PDF core fonts only cover Latin-1
+	FontPath     string `mapstructure:"font_path"`
+	BoldFontPath string `mapstructure:"bold_font_path"`
+	// MaxSyncRows is the most rows an export writes in the request, larger
+	// exports run as background jobs
+	MaxSyncRows int64 `mapstructure:"max_sync_rows"`
+	// Retention is how long a finished background export can be downloaded
+	Retention time.Duration `mapstructure:"retention"`
+}
+
+// SummaryConfig holds the AI weekly summary settings
+type SummaryConfig struct {
+	// Schedule is the cron expression the summaries of every workspace are
+	// written on
+	Schedule string `mapstructure:"schedule"`
+	// MaxTasks and MaxReports bound what a summary is written from, the
+	// overdue and blocked tasks and the latest reports are kept first
+	MaxTasks   int `mapstructure:"max_tasks"`
+	MaxReports int `mapstructure:"max_reports"`
+}
+
+// SimilarityConfig holds the similar task search settings, similarities
+// are Weaviate certainties between 0 and 1
+type SimilarityConfig struct {
+	// DuplicateThreshold is the similarity above which an open task is
+	// returned as a likely duplicate of a created task
+	DuplicateThreshold float32 `mapstructure:"duplicate_threshold"`
+	// SimilarThreshold is the lowest similarity of the similar tasks
+	SimilarThreshold float32 `mapstructure:"similar_threshold"`
+	// Limit is how many duplicates or similar tasks are returned at most
+	Limit int `mapstructure:"limit"`
+}
+
 type RedisConfig struct {
 	URL      string `mapstructure:"url"`
 	Username string `mapstructure:"username"`
@@ -105,6 +184,30 @@ func LoadConfig(cfgYml string) (*AppConfig, error) {
 	viper.BindEnv("REDIS.URL", "REDIS_URL")
 	viper.BindEnv("REDIS.USERNAME", "REDIS_USERNAME")
 	viper.BindEnv("REDIS.PASSWORD", "REDIS_PASSWORD")
+	viper.BindEnv("NOTIFICATION.SMTP.HOST", "SMTP_HOST")
+	viper.BindEnv("NOTIFICATION.SMTP.USERNAME", "SMTP_USERNAME")
+	viper.BindEnv("NOTIFICATION.SMTP.PASSWORD", "SMTP_PASSWORD")
+
+	viper.SetDefault("escalation.schedule", "*/15 * * * *")
+	viper.SetDefault("escalation.reminder_lead_times", []string{"24h", "2h"})
+	viper.SetDefault("escalation.overdue_levels", []string{"0s", "24h", "72h"})
+	viper.SetDefault("notification.digest_schedule", "0 7 * * *")
+	viper.SetDefault("notification.smtp.port", 25)
+	viper.SetDefault("notification.smtp.timeout", "30s")
+	viper.SetDefault("notification.webhook_timeout", "10s")
+	viper.SetDefault("notification.max_attempts", 6)
+	viper.SetDefault("notification.retry_base", "30s")
+	viper.SetDefault("export.dir", "./exports")
+	viper.SetDefault("export.font_path", "/usr/share/fonts/truetype/dejavu/DejaVuSans.ttf")
+	viper.SetDefault("export.bold_font_path", "/usr/share/fonts/truetype/dejavu/DejaVuSans-Bold.ttf")
+	viper.SetDefault("export.max_sync_rows", 2000)
+	viper.SetDefault("export.retention", "72h")
+	viper.SetDefault("summary.schedule", "0 15 * * 5")
+	viper.SetDefault("summary.max_tasks", 150)
+	viper.SetDefault("summary.max_reports", 100)
+	viper.SetDefault("similarity.duplicate_threshold", 0.88)
+	viper.SetDefault("similarity.similar_threshold", 0.75)
+	viper.SetDefault("similarity.limit", 5)
 
 	var config AppConfig
 	if err := viper.Unmarshal(&config); err != nil {
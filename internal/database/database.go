@@ -7,12 +7,25 @@ type Database interface {
 	Disconnect(ctx context.Context) error
 
 	Save(ctx context.Context, collection string, data interface{}) error
+	Insert(ctx context.Context, collection string, data interface{}) (string, error)
 	FindByID(ctx context.Context, collection string, id string, data interface{}) error
 	FindAll(ctx context.Context, collection string, sort interface{}, data interface{}) error
 	Update(ctx context.Context, collection string, id string, data interface{}) error
+	UpdateMany(ctx context.Context, collection string, filter interface{}, update interface{}) error
 	Delete(ctx context.Context, collection string, id string) error
 	DeleteMany(ctx context.Context, collection string, filter interface{}) error
 	Query(ctx context.Context, collection string, filter interface{}, skip int64, limit int64, sort interface{}, data interface{}) error
 	Aggregate(ctx context.Context, collection string, pipeline interface{}, data interface{}) error
 	Count(ctx context.Context, collection string, filter interface{}) (int64, error)
+	// EnsureTextIndex creates the full-text index of the collection over
+	// fields when it is missing, a collection has at most one
+	EnsureTextIndex(ctx context.Context, collection string, fields []string) error
+	Transactor
+}
+
+// Transactor runs fn in a transaction, the writes made with the context fn
+// receives are committed when it returns nil and rolled back otherwise. fn
+// may be retried on transient errors and must not keep state between runs.
+type Transactor interface {
+	WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error
 }
@@ -56,6 +56,22 @@ func (m *mongoDatabase) Save(ctx context.Context, collection string, data interf
 	return nil
 }
 
+// Insert stores data and returns the hex id of the inserted document
+func (m *mongoDatabase) Insert(ctx context.Context, collection string, data interface{}) (string, error) {
+	coll := m.mongoClient.Database(m.database).Collection(collection)
+	result, err := coll.InsertOne(ctx, data)
+	if err != nil {
+		return "", err
+	}
+	if objId, ok := result.InsertedID.(bson.ObjectID); ok {
+		return objId.Hex(), nil
+	}
+	if id, ok := result.InsertedID.(string); ok {
+		return id, nil
+	}
+	return "", nil
+}
+
 func (m *mongoDatabase) FindByID(ctx context.Context, collection string, id string, data interface{}) error {
 	coll := m.mongoClient.Database(m.database).Collection(collection)
 	objId, err := bson.ObjectIDFromHex(id)
@@ -103,6 +119,17 @@ func (m *mongoDatabase) Update(ctx context.Context, collection string, id string
 	return nil
 }
 
+// UpdateMany applies a raw update document, such as {"$pull": ...}, to every
+// document matching filter
+func (m *mongoDatabase) UpdateMany(ctx context.Context, collection string, filter interface{}, update interface{}) error {
+	coll := m.mongoClient.Database(m.database).Collection(collection)
+	_, err := coll.UpdateMany(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
 func (m *mongoDatabase) Delete(ctx context.Context, collection string, id string) error {
 	coll := m.mongoClient.Database(m.database).Collection(collection)
 	objId, err := bson.ObjectIDFromHex(id)
@@ -180,3 +207,32 @@ func (m *mongoDatabase) Count(ctx context.Context, collection string, filter int
 func (m *mongoDatabase) GetClient() *mongo.Client {
 	return m.mongoClient
 }
+
+func (m *mongoDatabase) EnsureTextIndex(ctx context.Context, collection string, fields []string) error {
+	coll := m.mongoClient.Database(m.database).Collection(collection)
+	keys := bson.D{}
+	for _, field := range fields {
+		keys = append(keys, bson.E{Key: field, Value: "text"})
+	}
+	// no stemming nor stop words, they are English only and content is
+	// mostly Vietnamese
+	_, err := coll.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    keys,
+		Options: options.Index().SetDefaultLanguage("none"),
+	})
+	return err
+}
+
+// WithTransaction needs a replica set or a sharded cluster, a standalone
+// server rejects transactions
+func (m *mongoDatabase) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	session, err := m.mongoClient.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+	_, err = session.WithTransaction(ctx, func(ctx context.Context) (interface{}, error) {
+		return nil, fn(ctx)
+	})
+	return err
+}
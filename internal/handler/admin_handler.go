@@ -0,0 +1,408 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/remiehneppo/be-task-management/internal/logger"
+	"github.com/remiehneppo/be-task-management/internal/service"
+	"github.com/remiehneppo/be-task-management/types"
+)
+
+type AdminHandler interface {
+	GetUsers(ctx *gin.Context)
+	GetUser(ctx *gin.Context)
+	CreateUser(ctx *gin.Context)
+	UpdateUser(ctx *gin.Context)
+	UpdateUserRole(ctx *gin.Context)
+	ResetPassword(ctx *gin.Context)
+	DeactivateUser(ctx *gin.Context)
+	ActivateUser(ctx *gin.Context)
+	DeleteUser(ctx *gin.Context)
+	GetAuditLogs(ctx *gin.Context)
+}
+
+type adminHandler struct {
+	adminService service.AdminService
+	logger       *logger.Logger
+}
+
+func NewAdminHandler(
+	adminService service.AdminService,
+	logger *logger.Logger,
+) AdminHandler {
+	return &adminHandler{
+		adminService: adminService,
+		logger:       logger,
+	}
+}
+
+// GetUsers godoc
+// @Summary Get users
+// @Description Returns the users matching the filters by name, without their password. Members without admin role only get the users of their workspace. Requires user.manage.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param username query string false "Part of the username"
+// @Param fullName query string false "Part of the full name"
+// @Param workspace query string false "Workspace name"
+// @Param workspaceRole query string false "Workspace role"
+// @Param page query int false "Page number (default: 1)"
+// @Param limit query int false "Items per page (default: 10)"
+// @Success 200 {object} types.PaginatedResponse{data=types.PaginatedData{items=[]types.User}}
+// @Failure 400 {object} types.Response
+// @Failure 401 {object} types.Response
+// @Failure 403 {object} types.Response
+// @Security BearerAuth
+// @Router /admin/users [get]
+func (h *adminHandler) GetUsers(ctx *gin.Context) {
+	page, limit := GetPaginationParams(ctx)
+	filter := types.UserFilter{
+		Username:      ctx.Query("username"),
+		FullName:      ctx.Query("fullName"),
+		Workspace:     ctx.Query("workspace"),
+		WorkspaceRole: ctx.Query("workspaceRole"),
+	}
+	users, total, err := h.adminService.GetUsers(ctx, filter, page, limit)
+	if err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: err.Error(),
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	res := types.PaginatedResponse{
+		Status:  true,
+		Message: "Users retrieved successfully",
+		Data: types.PaginatedData{
+			Items: users,
+			Total: total,
+			Limit: limit,
+			Page:  page,
+		},
+	}
+	ctx.JSON(200, res)
+}
+
+// GetUser godoc
+// @Summary Get a user
+// @Description Returns a user without their password. Requires user.manage.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} types.Response{data=types.User}
+// @Failure 400 {object} types.Response
+// @Failure 401 {object} types.Response
+// @Failure 403 {object} types.Response
+// @Security BearerAuth
+// @Router /admin/users/{id} [get]
+func (h *adminHandler) GetUser(ctx *gin.Context) {
+	user, err := h.adminService.GetUser(ctx, ctx.Param("id"))
+	if err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: err.Error(),
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	res := types.Response{
+		Status:  true,
+		Message: "User retrieved successfully",
+		Data:    user,
+	}
+	ctx.JSON(200, res)
+}
+
+// CreateUser godoc
+// @Summary Create a user
+// @Description Creates a user in an existing workspace. The management level defaults to the level of the workspace role and must match it. Role admin creates an admin, only admins may. Admins manage every user, members whose role grants user.manage only manage the users of their workspace below their management level. Audit-logged. Requires user.manage.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body types.CreateUserRequest true "User"
+// @Success 200 {object} types.Response{data=types.User}
+// @Failure 400 {object} types.Response
+// @Failure 401 {object} types.Response
+// @Failure 403 {object} types.Response
+// @Security BearerAuth
+// @Router /admin/users/create [post]
+func (h *adminHandler) CreateUser(ctx *gin.Context) {
+	var req types.CreateUserRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: "Invalid request",
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	user, err := h.adminService.CreateUser(ctx, &req)
+	if err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: err.Error(),
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	res := types.Response{
+		Status:  true,
+		Message: "User created successfully",
+		Data:    user,
+	}
+	ctx.JSON(201, res)
+}
+
+// UpdateUser godoc
+// @Summary Update a user
+// @Description Updates the full name and the workspace of a user, empty fields are kept. Only admins move users to another workspace. Audit-logged. Requires user.manage.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body types.UpdateUserRequest true "User"
+// @Success 200 {object} types.Response{data=types.User}
+// @Failure 400 {object} types.Response
+// @Failure 401 {object} types.Response
+// @Failure 403 {object} types.Response
+// @Security BearerAuth
+// @Router /admin/users/update [post]
+func (h *adminHandler) UpdateUser(ctx *gin.Context) {
+	var req types.UpdateUserRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: "Invalid request",
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	user, err := h.adminService.UpdateUser(ctx, &req)
+	if err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: err.Error(),
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	res := types.Response{
+		Status:  true,
+		Message: "User updated successfully",
+		Data:    user,
+	}
+	ctx.JSON(200, res)
+}
+
+// UpdateUserRole godoc
+// @Summary Change the roles of a user
+// @Description Changes the workspace role, the management level and the admin role of a user. The management level defaults to the level of the workspace role and must match it, an empty role removes the admin role. Users cannot change their own roles and only admins grant the admin role. Audit-logged. Requires user.manage.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body types.UpdateUserRoleRequest true "Roles"
+// @Success 200 {object} types.Response{data=types.User}
+// @Failure 400 {object} types.Response
+// @Failure 401 {object} types.Response
+// @Failure 403 {object} types.Response
+// @Security BearerAuth
+// @Router /admin/users/role [post]
+func (h *adminHandler) UpdateUserRole(ctx *gin.Context) {
+	var req types.UpdateUserRoleRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: "Invalid request",
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	user, err := h.adminService.UpdateUserRole(ctx, &req)
+	if err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: err.Error(),
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	res := types.Response{
+		Status:  true,
+		Message: "User role updated successfully",
+		Data:    user,
+	}
+	ctx.JSON(200, res)
+}
+
+// ResetPassword godoc
+// @Summary Reset the password of a user
+// @Description Sets a new password for a user without their old password. Audit-logged without the password. Requires user.manage.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body types.ResetPasswordRequest true "New password"
+// @Success 200 {object} types.Response
+// @Failure 400 {object} types.Response
+// @Failure 401 {object} types.Response
+// @Failure 403 {object} types.Response
+// @Security BearerAuth
+// @Router /admin/users/password [post]
+func (h *adminHandler) ResetPassword(ctx *gin.Context) {
+	var req types.ResetPasswordRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: "Invalid request",
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	if err := h.adminService.ResetPassword(ctx, &req); err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: err.Error(),
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	res := types.Response{
+		Status:  true,
+		Message: "Password reset successfully",
+	}
+	ctx.JSON(200, res)
+}
+
+// DeactivateUser godoc
+// @Summary Deactivate a user
+// @Description Prevents a user from signing in or refreshing their tokens and from being put on tasks, their tasks and history are kept. The tokens already issued are refused at once. Audit-logged. Requires user.manage.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} types.Response
+// @Failure 400 {object} types.Response
+// @Failure 401 {object} types.Response
+// @Failure 403 {object} types.Response
+// @Security BearerAuth
+// @Router /admin/users/deactivate/{id} [post]
+func (h *adminHandler) DeactivateUser(ctx *gin.Context) {
+	if err := h.adminService.DeactivateUser(ctx, ctx.Param("id")); err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: err.Error(),
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	res := types.Response{
+		Status:  true,
+		Message: "User deactivated successfully",
+	}
+	ctx.JSON(200, res)
+}
+
+// ActivateUser godoc
+// @Summary Activate a user
+// @Description Lets a deactivated user sign in again. Audit-logged. Requires user.manage.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} types.Response
+// @Failure 400 {object} types.Response
+// @Failure 401 {object} types.Response
+// @Failure 403 {object} types.Response
+// @Security BearerAuth
+// @Router /admin/users/activate/{id} [post]
+func (h *adminHandler) ActivateUser(ctx *gin.Context) {
+	if err := h.adminService.ActivateUser(ctx, ctx.Param("id")); err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: err.Error(),
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	res := types.Response{
+		Status:  true,
+		Message: "User activated successfully",
+	}
+	ctx.JSON(200, res)
+}
+
+// DeleteUser godoc
+// @Summary Delete a user
+// @Description Deletes a user whom no task, report nor comment refers to, the others are deactivated instead. Audit-logged. Requires user.manage.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} types.Response
+// @Failure 400 {object} types.Response
+// @Failure 401 {object} types.Response
+// @Failure 403 {object} types.Response
+// @Security BearerAuth
+// @Router /admin/users/delete/{id} [post]
+func (h *adminHandler) DeleteUser(ctx *gin.Context) {
+	if err := h.adminService.DeleteUser(ctx, ctx.Param("id")); err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: err.Error(),
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	res := types.Response{
+		Status:  true,
+		Message: "User deleted successfully",
+	}
+	ctx.JSON(200, res)
+}
+
+// GetAuditLogs godoc
+// @Summary Get the audit log
+// @Description Returns the admin actions matching the filters, latest first: user, workspace and role permission changes with the changed fields. Passwords are never recorded. Requires audit.read.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param actor query string false "ID of the user who acted"
+// @Param action query string false "Action, such as user.create"
+// @Param targetType query string false "user, workspace or role"
+// @Param targetId query string false "ID of the user or workspace, or the role"
+// @Param page query int false "Page number (default: 1)"
+// @Param limit query int false "Items per page (default: 10)"
+// @Success 200 {object} types.PaginatedResponse{data=types.PaginatedData{items=[]types.AuditLog}}
+// @Failure 400 {object} types.Response
+// @Failure 401 {object} types.Response
+// @Failure 403 {object} types.Response
+// @Security BearerAuth
+// @Router /admin/audit-logs [get]
+func (h *adminHandler) GetAuditLogs(ctx *gin.Context) {
+	page, limit := GetPaginationParams(ctx)
+	filter := types.AuditLogFilter{
+		Actor:      ctx.Query("actor"),
+		Action:     ctx.Query("action"),
+		TargetType: ctx.Query("targetType"),
+		TargetID:   ctx.Query("targetId"),
+	}
+	logs, total, err := h.adminService.GetAuditLogs(ctx, filter, page, limit)
+	if err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: err.Error(),
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	res := types.PaginatedResponse{
+		Status:  true,
+		Message: "Audit logs retrieved successfully",
+		Data: types.PaginatedData{
+			Items: logs,
+			Total: total,
+			Limit: limit,
+			Page:  page,
+		},
+	}
+	ctx.JSON(200, res)
+}
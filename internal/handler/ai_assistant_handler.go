@@ -11,6 +11,7 @@ var _ AIAssistantHandler = (*aiAssistantHandler)(nil)
 type AIAssistantHandler interface {
 	ChatWithAssistant(ctx *gin.Context)
 	ChatWithAssistantStateless(ctx *gin.Context)
+	PaginateMessages(ctx *gin.Context)
 }
 
 type aiAssistantHandler struct {
@@ -95,3 +96,46 @@ func (h *aiAssistantHandler) ChatWithAssistantStateless(ctx *gin.Context) {
 	}
 	ctx.JSON(200, data)
 }
+
+// PaginateMessages godoc
+// @Summary List chat messages
+// @Description Returns a page of the messages of a chat of the current user, newest first
+// @Tags assistant
+// @Accept json
+// @Produce json
+// @Param chat_id query string true "Chat ID"
+// @Param page query int false "Page number (default: 1)"
+// @Param limit query int false "Items per page (default: 10)"
+// @Param cursor query string false "Cursor of the next page, from next_cursor of the previous response. Takes precedence over page."
+// @Success 200 {object} types.PaginatedResponse{data=types.PaginatedData{items=[]types.ChatMessage}}
+// @Failure 400 {object} types.Response
+// @Failure 401 {object} types.Response
+// @Security BearerAuth
+// @Router /assistant/messages [get]
+func (h *aiAssistantHandler) PaginateMessages(ctx *gin.Context) {
+	page := GetPageRequest(ctx)
+	messages, total, nextCursor, err := h.aiAssistantService.PaginateMessages(ctx, types.PaginateMessagesRequest{
+		ChatId: ctx.Query("chat_id"),
+		Page:   page.Page,
+		Limit:  page.Limit,
+		Cursor: page.Cursor,
+	})
+	if err != nil {
+		ctx.JSON(400, types.Response{
+			Status:  false,
+			Message: err.Error(),
+		})
+		return
+	}
+	ctx.JSON(200, types.PaginatedResponse{
+		Status:  true,
+		Message: "Messages retrieved successfully",
+		Data: types.PaginatedData{
+			Items:      messages,
+			Total:      total,
+			Limit:      page.Limit,
+			Page:       page.Page,
+			NextCursor: nextCursor,
+		},
+	})
+}
@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/remiehneppo/be-task-management/internal/logger"
+	"github.com/remiehneppo/be-task-management/internal/service"
+	"github.com/remiehneppo/be-task-management/types"
+)
+
+type AnalyticsHandler interface {
+	GetMetrics(ctx *gin.Context)
+	GetWeeklySeries(ctx *gin.Context)
+}
+
+type analyticsHandler struct {
+	analyticsService service.AnalyticsService
+	logger           *logger.Logger
+}
+
+func NewAnalyticsHandler(
+	analyticsService service.AnalyticsService,
+	logger *logger.Logger,
+) AnalyticsHandler {
+	return &analyticsHandler{
+		analyticsService: analyticsService,
+		logger:           logger,
+	}
+}
+
+// GetMetrics godoc
+// @Summary Get workload and performance metrics
+// @Description Returns per user completed tasks, on-time rate, average cycle time in seconds from the first move to doing to completion, overdue and report counts over the range. Users see themselves and the members below their management level, members with analytics.workspace also get the workspace totals.
+// @Tags analytics
+// @Accept json
+// @Produce json
+// @Param from query int64 true "Range start (unix timestamp)"
+// @Param to query int64 true "Range end (unix timestamp)"
+// @Param userId query string false "Only measure this user"
+// @Success 200 {object} types.Response{data=types.AnalyticsMetrics}
+// @Failure 400 {object} types.Response
+// @Failure 401 {object} types.Response
+// @Security BearerAuth
+// @Router /analytics/metrics [get]
+func (h *analyticsHandler) GetMetrics(ctx *gin.Context) {
+	req, err := analyticsRequestFromQuery(ctx)
+	if err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: err.Error(),
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	metrics, err := h.analyticsService.GetMetrics(ctx, req)
+	if err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: err.Error(),
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	res := types.Response{
+		Status:  true,
+		Message: "Metrics retrieved successfully",
+		Data:    metrics,
+	}
+	ctx.JSON(200, res)
+}
+
+// GetWeeklySeries godoc
+// @Summary Get the weekly burn-down and throughput
+// @Description Returns per Monday based week the tasks created, the tasks completed and the tasks still unfinished at the end of the week. The workspace series requires analytics.workspace, pass userId for the series of a member below your management level.
+// @Tags analytics
+// @Accept json
+// @Produce json
+// @Param from query int64 true "Range start (unix timestamp)"
+// @Param to query int64 true "Range end (unix timestamp)"
+// @Param timezone query string false "IANA timezone the weeks are cut in (default: server timezone)"
+// @Param userId query string false "Only count the tasks of this user"
+// @Success 200 {object} types.Response{data=types.AnalyticsSeries}
+// @Failure 400 {object} types.Response
+// @Failure 401 {object} types.Response
+// @Security BearerAuth
+// @Router /analytics/weekly [get]
+func (h *analyticsHandler) GetWeeklySeries(ctx *gin.Context) {
+	req, err := analyticsRequestFromQuery(ctx)
+	if err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: err.Error(),
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	series, err := h.analyticsService.GetWeeklySeries(ctx, req)
+	if err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: err.Error(),
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	res := types.Response{
+		Status:  true,
+		Message: "Weekly series retrieved successfully",
+		Data:    series,
+	}
+	ctx.JSON(200, res)
+}
+
+func analyticsRequestFromQuery(ctx *gin.Context) (*types.AnalyticsRequest, error) {
+	from, err := strconv.ParseInt(ctx.Query("from"), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid from parameter")
+	}
+	to, err := strconv.ParseInt(ctx.Query("to"), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid to parameter")
+	}
+	return &types.AnalyticsRequest{
+		From:     from,
+		To:       to,
+		Timezone: ctx.Query("timezone"),
+		UserID:   ctx.Query("userId"),
+	}, nil
+}
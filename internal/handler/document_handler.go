@@ -17,6 +17,7 @@ type DocumentHandler interface {
 	SearchDocument(ctx *gin.Context)
 	AskAI(ctx *gin.Context)
 	ViewDocument(ctx *gin.Context)
+	ListDocuments(ctx *gin.Context)
 	DemoloadText(ctx *gin.Context)
 }
 
@@ -202,6 +203,45 @@ func (h *documentHandler) ViewDocument(ctx *gin.Context) {
 
 }
 
+// ListDocuments godoc
+// @Summary List uploaded documents
+// @Description Returns a page of the uploaded documents, newest first
+// @Tags documents
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number (default: 1)"
+// @Param limit query int false "Items per page (default: 10)"
+// @Param cursor query string false "Cursor of the next page, from next_cursor of the previous response. Takes precedence over page."
+// @Success 200 {object} types.PaginatedResponse{data=types.PaginatedData{items=[]types.FileMetadata}}
+// @Failure 400 {object} types.Response
+// @Failure 401 {object} types.Response
+// @Security BearerAuth
+// @Router /documents [get]
+func (h *documentHandler) ListDocuments(ctx *gin.Context) {
+	page := GetPageRequest(ctx)
+	documents, total, nextCursor, err := h.documentService.ListDocuments(ctx, page)
+	if err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: err.Error(),
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	res := types.PaginatedResponse{
+		Status:  true,
+		Message: "Documents retrieved successfully",
+		Data: types.PaginatedData{
+			Items:      documents,
+			Total:      total,
+			Limit:      page.Limit,
+			Page:       page.Page,
+			NextCursor: nextCursor,
+		},
+	}
+	ctx.JSON(200, res)
+}
+
 // DemoloadText godoc
 // @Summary Demo load text from a PDF document
 // @Description Loads text from a PDF document for demonstration purposes
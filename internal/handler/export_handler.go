@@ -0,0 +1,204 @@
+package handler
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/remiehneppo/be-task-management/internal/logger"
+	"github.com/remiehneppo/be-task-management/internal/service"
+	"github.com/remiehneppo/be-task-management/types"
+)
+
+var exportContentTypes = map[string]string{
+	types.EXPORT_FORMAT_CSV:  "text/csv; charset=utf-8",
+	types.EXPORT_FORMAT_XLSX: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	types.EXPORT_FORMAT_PDF:  "application/pdf",
+}
+
+type ExportHandler interface {
+	Export(ctx *gin.Context)
+	GetExportJob(ctx *gin.Context)
+	DownloadExport(ctx *gin.Context)
+}
+
+type exportHandler struct {
+	exportService service.ExportService
+	logger        *logger.Logger
+}
+
+func NewExportHandler(
+	exportService service.ExportService,
+	logger *logger.Logger,
+) ExportHandler {
+	return &exportHandler{
+		exportService: exportService,
+		logger:        logger,
+	}
+}
+
+// Export godoc
+// @Summary Export tasks and reports
+// @Description Exports the tasks and the reports of the workspace as CSV, XLSX with a sheet for each, or a printable PDF summary, with Vietnamese headings. Tasks take every filter of /tasks/filter, reports the filters of /tasks/reports prefixed with "report.". Small exports are sent in the response, larger or async ones are queued and answered with 202 and the job to poll.
+// @Tags exports
+// @Produce octet-stream
+// @Produce json
+// @Param format query string true "csv, xlsx or pdf"
+// @Param source query string false "Table of a CSV export: tasks (default) or reports"
+// @Param timezone query string false "IANA timezone dates are written in (default: server timezone)"
+// @Param async query bool false "Always run the export in the background"
+// @Param title query string false "Filter tasks by title (partial match)"
+// @Param status query string false "Filter tasks by status"
+// @Param statuses query string false "Comma separated task statuses, any of them matches"
+// @Param assignees query string false "Comma separated user IDs, tasks assigned to any of them match"
+// @Param deadlineFrom query int64 false "Filter tasks by deadline from (unix timestamp)"
+// @Param deadlineTo query int64 false "Filter tasks by deadline to (unix timestamp)"
+// @Param overdue query bool false "Only unfinished tasks past their deadline"
+// @Param search query string false "Full-text search over task title and description"
+// @Param sort query string false "Task sort keys, as in /tasks/filter"
+// @Param report.taskId query string false "Filter reports by task ID"
+// @Param report.creator query string false "Filter reports by creator ID"
+// @Param report.createdFrom query int64 false "Filter reports by creation date from (unix timestamp)"
+// @Param report.createdTo query int64 false "Filter reports by creation date to (unix timestamp)"
+// @Param report.status query string false "Filter reports by review status: pending, approved or rejected"
+// @Success 200 {file} file "Export content"
+// @Success 202 {object} types.Response{data=types.ExportJobResponse}
+// @Failure 400 {object} types.Response
+// @Failure 401 {object} types.Response
+// @Security BearerAuth
+// @Router /exports [get]
+func (h *exportHandler) Export(ctx *gin.Context) {
+	userID, ok := ctx.Value("user_id").(string)
+	if !ok {
+		res := types.Response{
+			Status:  false,
+			Message: types.ErrInvalidCredentials.Error(),
+		}
+		ctx.JSON(401, res)
+		return
+	}
+	req, err := exportRequestFromQuery(ctx, userID)
+	if err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: err.Error(),
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	job, queued, err := h.exportService.PrepareExport(ctx, req)
+	if err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: err.Error(),
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	if queued {
+		res := types.Response{
+			Status:  true,
+			Message: "Export queued, download it once ready",
+			Data:    job,
+		}
+		ctx.JSON(202, res)
+		return
+	}
+
+	ctx.Header("Content-Type", exportContentTypes[req.Format])
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", job.FileName))
+	if err := h.exportService.WriteExport(ctx, req, ctx.Writer); err != nil {
+		// the error can only be answered before the first byte is sent
+		if ctx.Writer.Written() {
+			h.logger.Error("Failed to write export: ", err)
+			ctx.Abort()
+			return
+		}
+		ctx.Writer.Header().Del("Content-Disposition")
+		ctx.Writer.Header().Set("Content-Type", "application/json; charset=utf-8")
+		res := types.Response{
+			Status:  false,
+			Message: err.Error(),
+		}
+		ctx.JSON(400, res)
+	}
+}
+
+// GetExportJob godoc
+// @Summary Get a background export
+// @Description Returns the status of an export job of the current user, with the download URL once it is done
+// @Tags exports
+// @Accept json
+// @Produce json
+// @Param id path string true "Export job ID"
+// @Success 200 {object} types.Response{data=types.ExportJobResponse}
+// @Failure 400 {object} types.Response
+// @Failure 401 {object} types.Response
+// @Security BearerAuth
+// @Router /exports/jobs/{id} [get]
+func (h *exportHandler) GetExportJob(ctx *gin.Context) {
+	job, err := h.exportService.GetExportJob(ctx, ctx.Param("id"))
+	if err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: err.Error(),
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	res := types.Response{
+		Status:  true,
+		Message: "Export job retrieved successfully",
+		Data:    job,
+	}
+	ctx.JSON(200, res)
+}
+
+// DownloadExport godoc
+// @Summary Download a background export
+// @Description Sends the file of a finished export job of the current user, until the job expires
+// @Tags exports
+// @Produce octet-stream
+// @Param id path string true "Export job ID"
+// @Success 200 {file} file "Export content"
+// @Failure 400 {object} types.Response
+// @Failure 401 {object} types.Response
+// @Security BearerAuth
+// @Router /exports/jobs/{id}/download [get]
+func (h *exportHandler) DownloadExport(ctx *gin.Context) {
+	filePath, fileName, err := h.exportService.DownloadExport(ctx, ctx.Param("id"))
+	if err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: err.Error(),
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	ctx.FileAttachment(filePath, fileName)
+}
+
+func exportRequestFromQuery(ctx *gin.Context, userID string) (*types.ExportRequest, error) {
+	taskFilter, err := taskFilterFromQuery(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	reportFilter, err := reportFilterFromQuery(ctx, "report.")
+	if err != nil {
+		return nil, err
+	}
+	req := &types.ExportRequest{
+		Format:       ctx.Query("format"),
+		Source:       ctx.Query("source"),
+		Timezone:     ctx.Query("timezone"),
+		TaskFilter:   *taskFilter,
+		ReportFilter: *reportFilter,
+	}
+	if ctx.Query("async") != "" {
+		req.Async, err = strconv.ParseBool(ctx.Query("async"))
+		if err != nil {
+			return nil, fmt.Errorf("Invalid async parameter")
+		}
+	}
+	return req, nil
+}
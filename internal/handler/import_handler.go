@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/remiehneppo/be-task-management/internal/logger"
+	"github.com/remiehneppo/be-task-management/internal/service"
+	"github.com/remiehneppo/be-task-management/types"
+)
+
+type ImportHandler interface {
+	Import(ctx *gin.Context)
+}
+
+type importHandler struct {
+	importService service.ImportService
+	logger        *logger.Logger
+}
+
+func NewImportHandler(
+	importService service.ImportService,
+	logger *logger.Logger,
+) ImportHandler {
+	return &importHandler{
+		importService: importService,
+		logger:        logger,
+	}
+}
+
+// Import godoc
+// @Summary Import users, tasks or reports
+// @Description Imports the rows of a CSV or XLSX file into the workspace of the current user, whose role must grant data.import. Importing users also needs user.manage, the users must be below the importer's management level and their creation is audit-logged. The first row names the columns, as in the files of mock/. Users of tasks and reports are named by username or ID and must belong to the workspace. Without skipInvalid any invalid row aborts the import and nothing is written, the rows are then written in a single transaction. With dryRun the file is only checked. The errors of the rows are returned by file line, the header being line 1.
+// @Tags admin
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "CSV or XLSX file"
+// @Param kind formData string true "users, tasks or reports"
+// @Param dryRun formData bool false "Only check the rows"
+// @Param skipInvalid formData bool false "Import the valid rows and skip the others"
+// @Success 200 {object} types.Response{data=types.ImportResult}
+// @Failure 400 {object} types.Response{data=types.ImportResult}
+// @Failure 401 {object} types.Response
+// @Security BearerAuth
+// @Router /admin/import [post]
+func (h *importHandler) Import(ctx *gin.Context) {
+	var req types.ImportRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: "Invalid request",
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	fileHeader, err := ctx.FormFile("file")
+	if err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: "File upload error",
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: "File upload error",
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	defer file.Close()
+
+	result, err := h.importService.ImportIntoWorkspace(ctx, &req, file, fileHeader.Filename)
+	if err != nil {
+		if !errors.Is(err, types.ErrImportInvalidRows) {
+			h.logger.Error("Failed to import: ", err)
+		}
+		res := types.Response{
+			Status:  false,
+			Message: err.Error(),
+			Data:    result,
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	res := types.Response{
+		Status:  true,
+		Message: "Import completed successfully",
+		Data:    result,
+	}
+	ctx.JSON(200, res)
+}
@@ -0,0 +1,289 @@
+package handler
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/remiehneppo/be-task-management/internal/logger"
+	"github.com/remiehneppo/be-task-management/internal/service"
+	"github.com/remiehneppo/be-task-management/types"
+)
+
+// streamKeepAlive is how often an idle stream sends a ping so that proxies
+// do not close it
+const streamKeepAlive = 30 * time.Second
+
+type NotificationHandler interface {
+	GetNotifications(ctx *gin.Context)
+	CountUnread(ctx *gin.Context)
+	MarkRead(ctx *gin.Context)
+	MarkAllRead(ctx *gin.Context)
+	StreamNotifications(ctx *gin.Context)
+	GetPreference(ctx *gin.Context)
+	UpdatePreference(ctx *gin.Context)
+}
+
+type notificationHandler struct {
+	notificationService service.NotificationService
+	logger              *logger.Logger
+}
+
+func NewNotificationHandler(
+	notificationService service.NotificationService,
+	logger *logger.Logger,
+) NotificationHandler {
+	return &notificationHandler{
+		notificationService: notificationService,
+		logger:              logger,
+	}
+}
+
+// GetNotifications godoc
+// @Summary Get notifications of the current user
+// @Description Returns a paginated, newest first list of the authenticated user notifications
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number (default: 1)"
+// @Param limit query int false "Items per page (default: 10)"
+// @Param unread query bool false "Only return unread notifications"
+// @Success 200 {object} types.PaginatedResponse{data=types.PaginatedData{items=[]types.Notification}}
+// @Failure 400 {object} types.Response
+// @Failure 401 {object} types.Response
+// @Security BearerAuth
+// @Router /notifications [get]
+func (h *notificationHandler) GetNotifications(ctx *gin.Context) {
+	page, limit := GetPaginationParams(ctx)
+	unreadOnly := ctx.Query("unread") == "true"
+	notifications, total, err := h.notificationService.GetNotifications(ctx, unreadOnly, page, limit)
+	if err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: err.Error(),
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	res := types.PaginatedResponse{
+		Status:  true,
+		Message: "Notifications retrieved successfully",
+		Data: types.PaginatedData{
+			Items: notifications,
+			Total: total,
+			Limit: limit,
+			Page:  page,
+		},
+	}
+	ctx.JSON(200, res)
+}
+
+// CountUnread godoc
+// @Summary Count unread notifications
+// @Description Returns how many notifications of the authenticated user are unread
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Success 200 {object} types.Response{data=types.UnreadCountResponse}
+// @Failure 400 {object} types.Response
+// @Failure 401 {object} types.Response
+// @Security BearerAuth
+// @Router /notifications/unread-count [get]
+func (h *notificationHandler) CountUnread(ctx *gin.Context) {
+	unread, err := h.notificationService.CountUnread(ctx)
+	if err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: err.Error(),
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	res := types.Response{
+		Status:  true,
+		Message: "Unread notifications counted successfully",
+		Data:    types.UnreadCountResponse{Unread: unread},
+	}
+	ctx.JSON(200, res)
+}
+
+// MarkRead godoc
+// @Summary Mark notifications as read
+// @Description Marks the given notifications of the authenticated user as read
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Param request body types.MarkNotificationsReadRequest true "Notification IDs"
+// @Success 200 {object} types.Response
+// @Failure 400 {object} types.Response
+// @Failure 401 {object} types.Response
+// @Security BearerAuth
+// @Router /notifications/read [post]
+func (h *notificationHandler) MarkRead(ctx *gin.Context) {
+	req := &types.MarkNotificationsReadRequest{}
+	if err := ctx.ShouldBindJSON(req); err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: err.Error(),
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	err := h.notificationService.MarkRead(ctx, req)
+	if err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: err.Error(),
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	res := types.Response{
+		Status:  true,
+		Message: "Notifications marked as read",
+	}
+	ctx.JSON(200, res)
+}
+
+// MarkAllRead godoc
+// @Summary Mark all notifications as read
+// @Description Marks every notification of the authenticated user as read
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Success 200 {object} types.Response
+// @Failure 400 {object} types.Response
+// @Failure 401 {object} types.Response
+// @Security BearerAuth
+// @Router /notifications/read-all [post]
+func (h *notificationHandler) MarkAllRead(ctx *gin.Context) {
+	err := h.notificationService.MarkAllRead(ctx)
+	if err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: err.Error(),
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	res := types.Response{
+		Status:  true,
+		Message: "Notifications marked as read",
+	}
+	ctx.JSON(200, res)
+}
+
+// GetPreference godoc
+// @Summary Get notification preferences
+// @Description Returns the email and webhook subscriptions of the authenticated user, the webhook secret is never returned
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Success 200 {object} types.Response{data=types.NotificationPreference}
+// @Failure 400 {object} types.Response
+// @Failure 401 {object} types.Response
+// @Security BearerAuth
+// @Router /notifications/preferences [get]
+func (h *notificationHandler) GetPreference(ctx *gin.Context) {
+	preference, err := h.notificationService.GetPreference(ctx)
+	if err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: err.Error(),
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	res := types.Response{
+		Status:  true,
+		Message: "Notification preferences retrieved successfully",
+		Data:    preference,
+	}
+	ctx.JSON(200, res)
+}
+
+// UpdatePreference godoc
+// @Summary Update notification preferences
+// @Description Updates the email and webhook subscriptions of the authenticated user. Omitted fields are kept, an empty type list subscribes to every notification type. Email mode is one of off, instant or digest. Webhooks are signed with the given secret, see the X-Webhook-Signature header.
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Param request body types.UpdateNotificationPreferenceRequest true "Notification preferences"
+// @Success 200 {object} types.Response{data=types.NotificationPreference}
+// @Failure 400 {object} types.Response
+// @Failure 401 {object} types.Response
+// @Security BearerAuth
+// @Router /notifications/preferences/update [post]
+func (h *notificationHandler) UpdatePreference(ctx *gin.Context) {
+	req := &types.UpdateNotificationPreferenceRequest{}
+	if err := ctx.ShouldBindJSON(req); err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: err.Error(),
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	preference, err := h.notificationService.UpdatePreference(ctx, req)
+	if err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: err.Error(),
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	res := types.Response{
+		Status:  true,
+		Message: "Notification preferences updated successfully",
+		Data:    preference,
+	}
+	ctx.JSON(200, res)
+}
+
+// StreamNotifications godoc
+// @Summary Stream notifications
+// @Description Server-Sent Events stream of the authenticated user new notifications, sent as "notification" events with a types.Notification JSON payload. Browsers using EventSource, which cannot set headers, may pass the access token in the token query parameter.
+// @Tags notifications
+// @Produce text/event-stream
+// @Param token query string false "Access token, when the Authorization header cannot be set"
+// @Success 200 {object} types.Notification
+// @Failure 401 {object} types.Response
+// @Security BearerAuth
+// @Router /notifications/stream [get]
+func (h *notificationHandler) StreamNotifications(ctx *gin.Context) {
+	notifications, cancel, err := h.notificationService.Subscribe(ctx)
+	if err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: err.Error(),
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	defer cancel()
+
+	ctx.Header("Content-Type", "text/event-stream")
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("Connection", "keep-alive")
+	ctx.Header("X-Accel-Buffering", "no")
+	ctx.Status(200)
+	ctx.Writer.Flush()
+
+	ticker := time.NewTicker(streamKeepAlive)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Request.Context().Done():
+			return
+		case notification, ok := <-notifications:
+			if !ok {
+				return
+			}
+			ctx.SSEvent("notification", notification)
+			ctx.Writer.Flush()
+		case <-ticker.C:
+			ctx.SSEvent("ping", time.Now().Unix())
+			ctx.Writer.Flush()
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/remiehneppo/be-task-management/internal/logger"
+	"github.com/remiehneppo/be-task-management/internal/service"
+	"github.com/remiehneppo/be-task-management/types"
+)
+
+type RoleHandler interface {
+	GetMyPermissions(ctx *gin.Context)
+	GetRolePermissions(ctx *gin.Context)
+	UpdateRolePermissions(ctx *gin.Context)
+}
+
+type roleHandler struct {
+	authorizationService service.AuthorizationService
+	logger               *logger.Logger
+}
+
+func NewRoleHandler(
+	authorizationService service.AuthorizationService,
+	logger *logger.Logger,
+) RoleHandler {
+	return &roleHandler{
+		authorizationService: authorizationService,
+		logger:               logger,
+	}
+}
+
+// GetMyPermissions godoc
+// @Summary Get the permissions of the current user
+// @Description Returns the permissions the workspace role of the current user grants, admins hold every permission. Clients use them to show only the actions the user may take.
+// @Tags users
+// @Produce json
+// @Success 200 {object} types.Response{data=[]string}
+// @Failure 400 {object} types.Response
+// @Failure 401 {object} types.Response
+// @Security BearerAuth
+// @Router /users/me/permissions [get]
+func (h *roleHandler) GetMyPermissions(ctx *gin.Context) {
+	permissions, err := h.authorizationService.GetPermissions(ctx)
+	if err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: err.Error(),
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	res := types.Response{
+		Status:  true,
+		Message: "Permissions retrieved successfully",
+		Data:    permissions,
+	}
+	ctx.JSON(200, res)
+}
+
+// GetRolePermissions godoc
+// @Summary Get the permissions of every role
+// @Description Returns the permissions of every workspace role, highest management level first. The roles an admin has not edited have their default permissions and are marked default. Requires role.manage.
+// @Tags admin
+// @Produce json
+// @Success 200 {object} types.Response{data=[]types.RolePermissions}
+// @Failure 400 {object} types.Response
+// @Failure 401 {object} types.Response
+// @Failure 403 {object} types.Response
+// @Security BearerAuth
+// @Router /admin/roles [get]
+func (h *roleHandler) GetRolePermissions(ctx *gin.Context) {
+	roles, err := h.authorizationService.GetRolePermissions(ctx)
+	if err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: err.Error(),
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	res := types.Response{
+		Status:  true,
+		Message: "Role permissions retrieved successfully",
+		Data:    roles,
+	}
+	ctx.JSON(200, res)
+}
+
+// UpdateRolePermissions godoc
+// @Summary Update the permissions of a role
+// @Description Replaces the permissions of a workspace role. The permissions are task.create, task.assign, task.manage, task.template, report.create, report.feedback, report.review, field.manage, document.read, document.upload, analytics.workspace, summary.read, data.import, user.manage and role.manage. The change applies to every member of the role within 30 seconds. Requires role.manage.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body types.UpdateRolePermissionsRequest true "Role and its permissions"
+// @Success 200 {object} types.Response{data=types.RolePermissions}
+// @Failure 400 {object} types.Response
+// @Failure 401 {object} types.Response
+// @Failure 403 {object} types.Response
+// @Security BearerAuth
+// @Router /admin/roles/update [post]
+func (h *roleHandler) UpdateRolePermissions(ctx *gin.Context) {
+	var req types.UpdateRolePermissionsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: "Invalid request",
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	role, err := h.authorizationService.UpdateRolePermissions(ctx, &req)
+	if err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: err.Error(),
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	res := types.Response{
+		Status:  true,
+		Message: "Role permissions updated successfully",
+		Data:    role,
+	}
+	ctx.JSON(200, res)
+}
@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/remiehneppo/be-task-management/internal/logger"
+	"github.com/remiehneppo/be-task-management/internal/service"
+	"github.com/remiehneppo/be-task-management/types"
+)
+
+type SummaryHandler interface {
+	GenerateSummary(ctx *gin.Context)
+	GetSummaries(ctx *gin.Context)
+	GetSummary(ctx *gin.Context)
+}
+
+type summaryHandler struct {
+	summaryService service.SummaryService
+	logger         *logger.Logger
+}
+
+func NewSummaryHandler(
+	summaryService service.SummaryService,
+	logger *logger.Logger,
+) SummaryHandler {
+	return &summaryHandler{
+		summaryService: summaryService,
+		logger:         logger,
+	}
+}
+
+// GenerateSummary godoc
+// @Summary Generate a weekly summary
+// @Description Has the AI write the Vietnamese status summary of a week of the workspace: progress, risks, overdue tasks and blockers, from the tasks updated during the week or still unfinished and the reports and feedback of the week. Cited tasks are written as their ID in brackets and listed in cited_task_ids. Requires summary.read.
+// @Tags summaries
+// @Accept json
+// @Produce json
+// @Param request body types.GenerateSummaryRequest true "Week to summarize"
+// @Success 200 {object} types.Response{data=types.WeeklySummary}
+// @Failure 400 {object} types.Response
+// @Failure 401 {object} types.Response
+// @Security BearerAuth
+// @Router /summaries/generate [post]
+func (h *summaryHandler) GenerateSummary(ctx *gin.Context) {
+	var req types.GenerateSummaryRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: "Invalid request",
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	summary, err := h.summaryService.GenerateSummary(ctx, &req)
+	if err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: err.Error(),
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	res := types.Response{
+		Status:  true,
+		Message: "Summary generated successfully",
+		Data:    summary,
+	}
+	ctx.JSON(200, res)
+}
+
+// GetSummaries godoc
+// @Summary Get weekly summaries
+// @Description Returns the weekly summaries of the workspace, latest week first. Requires summary.read.
+// @Tags summaries
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number (default: 1)"
+// @Param limit query int false "Items per page (default: 10)"
+// @Success 200 {object} types.PaginatedResponse{data=types.PaginatedData{items=[]types.WeeklySummary}}
+// @Failure 400 {object} types.Response
+// @Failure 401 {object} types.Response
+// @Security BearerAuth
+// @Router /summaries [get]
+func (h *summaryHandler) GetSummaries(ctx *gin.Context) {
+	page, limit := GetPaginationParams(ctx)
+	summaries, total, err := h.summaryService.GetSummaries(ctx, page, limit)
+	if err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: err.Error(),
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	res := types.PaginatedResponse{
+		Status:  true,
+		Message: "Summaries retrieved successfully",
+		Data: types.PaginatedData{
+			Items: summaries,
+			Total: total,
+			Limit: limit,
+			Page:  page,
+		},
+	}
+	ctx.JSON(200, res)
+}
+
+// GetSummary godoc
+// @Summary Get a weekly summary
+// @Description Returns a weekly summary of the workspace. Requires summary.read.
+// @Tags summaries
+// @Accept json
+// @Produce json
+// @Param id path string true "Summary ID"
+// @Success 200 {object} types.Response{data=types.WeeklySummary}
+// @Failure 400 {object} types.Response
+// @Failure 401 {object} types.Response
+// @Security BearerAuth
+// @Router /summaries/{id} [get]
+func (h *summaryHandler) GetSummary(ctx *gin.Context) {
+	summary, err := h.summaryService.GetSummary(ctx, ctx.Param("id"))
+	if err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: err.Error(),
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	res := types.Response{
+		Status:  true,
+		Message: "Summary retrieved successfully",
+		Data:    summary,
+	}
+	ctx.JSON(200, res)
+}
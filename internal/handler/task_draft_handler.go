@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/remiehneppo/be-task-management/internal/logger"
+	"github.com/remiehneppo/be-task-management/internal/service"
+	"github.com/remiehneppo/be-task-management/types"
+)
+
+type TaskDraftHandler interface {
+	DraftTasks(ctx *gin.Context)
+	ConfirmDrafts(ctx *gin.Context)
+}
+
+type taskDraftHandler struct {
+	taskDraftService service.TaskDraftService
+	logger           *logger.Logger
+}
+
+func NewTaskDraftHandler(
+	taskDraftService service.TaskDraftService,
+	logger *logger.Logger,
+) TaskDraftHandler {
+	return &taskDraftHandler{
+		taskDraftService: taskDraftService,
+		logger:           logger,
+	}
+}
+
+// DraftTasks godoc
+// @Summary Draft tasks from text or a document
+// @Description Has the AI read a meeting note, a work order or any free text, pasted or uploaded as PDF, DOCX or TXT, and returns draft tasks with a title, a description, a suggested assignee among the workspace members the user may assign, a start date and a deadline. Nothing is created, review the drafts then send them to /tasks/drafts/confirm.
+// @Tags tasks
+// @Accept multipart/form-data
+// @Produce json
+// @Param text formData string false "Free text to draft tasks from"
+// @Param file formData file false "PDF, DOCX or TXT document to draft tasks from"
+// @Param timezone formData string false "IANA timezone the dates of the text are read in (default: server timezone)"
+// @Success 200 {object} types.Response{data=types.TaskDraftsResponse}
+// @Failure 400 {object} types.Response
+// @Failure 401 {object} types.Response
+// @Security BearerAuth
+// @Router /tasks/drafts [post]
+func (h *taskDraftHandler) DraftTasks(ctx *gin.Context) {
+	var req types.DraftTasksRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: "Invalid request",
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	fileHeader, err := ctx.FormFile("file")
+	if err != nil && !errors.Is(err, http.ErrMissingFile) {
+		res := types.Response{
+			Status:  false,
+			Message: "File upload error",
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	drafts, err := h.taskDraftService.DraftTasks(ctx, &req, fileHeader)
+	if err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: err.Error(),
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	res := types.Response{
+		Status:  true,
+		Message: "Task drafts generated successfully",
+		Data:    types.TaskDraftsResponse{Drafts: drafts},
+	}
+	ctx.JSON(200, res)
+}
+
+// ConfirmDrafts godoc
+// @Summary Create reviewed task drafts
+// @Description Creates the reviewed drafts as tasks, each one like /tasks/create and with the same checks. The drafts that cannot be created are returned with their index and the reason, the others are created.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param request body types.ConfirmTaskDraftsRequest true "Reviewed drafts"
+// @Success 200 {object} types.Response{data=types.ConfirmTaskDraftsResponse}
+// @Failure 400 {object} types.Response
+// @Failure 401 {object} types.Response
+// @Security BearerAuth
+// @Router /tasks/drafts/confirm [post]
+func (h *taskDraftHandler) ConfirmDrafts(ctx *gin.Context) {
+	var req types.ConfirmTaskDraftsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: "Invalid request",
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	result, err := h.taskDraftService.ConfirmDrafts(ctx, &req)
+	if err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: err.Error(),
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	res := types.Response{
+		Status:  true,
+		Message: "Task drafts confirmed",
+		Data:    result,
+	}
+	ctx.JSON(200, res)
+}
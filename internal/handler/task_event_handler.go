@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/remiehneppo/be-task-management/internal/logger"
+	"github.com/remiehneppo/be-task-management/internal/service"
+	"github.com/remiehneppo/be-task-management/types"
+)
+
+const (
+	// boardWriteTimeout bounds a single write to a board connection
+	boardWriteTimeout = 10 * time.Second
+	// boardPongTimeout is how long a board may stay silent, it is pinged
+	// well within that
+	boardPongTimeout = 60 * time.Second
+	boardPingPeriod  = boardPongTimeout * 9 / 10
+)
+
+var boardUpgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	// the API is open to every origin and authenticates with a token, not a
+	// cookie, so a foreign page cannot ride on the user session
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+type TaskEventHandler interface {
+	StreamTaskEvents(ctx *gin.Context)
+}
+
+type taskEventHandler struct {
+	taskEventHub service.TaskEventHub
+	logger       *logger.Logger
+}
+
+func NewTaskEventHandler(
+	taskEventHub service.TaskEventHub,
+	logger *logger.Logger,
+) TaskEventHandler {
+	return &taskEventHandler{
+		taskEventHub: taskEventHub,
+		logger:       logger,
+	}
+}
+
+// StreamTaskEvents godoc
+// @Summary Stream task board events
+// @Description WebSocket upgrade streaming every task and report change of the authenticated user workspace, each message is a types.TaskActivity JSON. Browsers, which cannot set headers on a WebSocket, may pass the access token in the token query parameter.
+// @Tags tasks
+// @Produce json
+// @Param token query string false "Access token, when the Authorization header cannot be set"
+// @Success 101 {object} types.TaskActivity
+// @Failure 400 {object} types.Response
+// @Failure 401 {object} types.Response
+// @Security BearerAuth
+// @Router /tasks/events [get]
+func (h *taskEventHandler) StreamTaskEvents(ctx *gin.Context) {
+	events, cancel, err := h.taskEventHub.Subscribe(ctx)
+	if err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: err.Error(),
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	defer cancel()
+
+	conn, err := boardUpgrader.Upgrade(ctx.Writer, ctx.Request, nil)
+	if err != nil {
+		// the upgrader already replied with the error
+		h.logger.Warnf("Failed to upgrade task board connection: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	// boards only listen, reading is needed to handle pongs and close frames
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		conn.SetReadLimit(512)
+		conn.SetReadDeadline(time.Now().Add(boardPongTimeout))
+		conn.SetPongHandler(func(string) error {
+			return conn.SetReadDeadline(time.Now().Add(boardPongTimeout))
+		})
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(boardPingPeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-closed:
+			return
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			conn.SetWriteDeadline(time.Now().Add(boardWriteTimeout))
+			if err := conn.WriteJSON(event); err != nil {
+				return
+			}
+		case <-ticker.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(boardWriteTimeout)); err != nil {
+				return
+			}
+		}
+	}
+}
@@ -1,7 +1,12 @@
 package handler
 
 import (
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"sort"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/remiehneppo/be-task-management/internal/logger"
@@ -21,6 +26,36 @@ type TaskHandler interface {
 	UpdateReportTask(ctx *gin.Context)
 	DeleteReport(ctx *gin.Context)
 	FeedbackReport(ctx *gin.Context)
+	GetReports(ctx *gin.Context)
+	GetReportsAwaitingReview(ctx *gin.Context)
+	ReviewReport(ctx *gin.Context)
+	GetTaskComments(ctx *gin.Context)
+	GetReportComments(ctx *gin.Context)
+	CreateComment(ctx *gin.Context)
+	UpdateComment(ctx *gin.Context)
+	DeleteComment(ctx *gin.Context)
+	GetCommentHistory(ctx *gin.Context)
+	DownloadCommentAttachment(ctx *gin.Context)
+	GetTaskHistory(ctx *gin.Context)
+	GetTaskSubtree(ctx *gin.Context)
+	GetTaskDependencies(ctx *gin.Context)
+	GetSimilarTasks(ctx *gin.Context)
+	AddTaskDependency(ctx *gin.Context)
+	RemoveTaskDependency(ctx *gin.Context)
+	GetTasksWatchedByUser(ctx *gin.Context)
+	WatchTask(ctx *gin.Context)
+	UnwatchTask(ctx *gin.Context)
+	GetTaskTemplates(ctx *gin.Context)
+	GetTaskTemplateByID(ctx *gin.Context)
+	CreateTaskTemplate(ctx *gin.Context)
+	UpdateTaskTemplate(ctx *gin.Context)
+	DeleteTaskTemplate(ctx *gin.Context)
+	GetTaskBoard(ctx *gin.Context)
+	GetTaskCalendar(ctx *gin.Context)
+	GetCustomFields(ctx *gin.Context)
+	CreateCustomField(ctx *gin.Context)
+	UpdateCustomField(ctx *gin.Context)
+	DeleteCustomField(ctx *gin.Context)
 }
 
 type taskHandler struct {
@@ -40,20 +75,21 @@ func NewTaskHandler(
 
 // GetTasksAssignedToUser godoc
 // @Summary Get tasks assigned to the current user
-// @Description Returns a paginated list of tasks assigned to the authenticated user
+// @Description Returns a paginated list of tasks the authenticated user is assigned to, as primary assignee or helper
 // @Tags tasks
 // @Accept json
 // @Produce json
 // @Param page query int false "Page number (default: 1)"
 // @Param limit query int false "Items per page (default: 10)"
+// @Param cursor query string false "Cursor of the next page, from next_cursor of the previous response. Takes precedence over page."
 // @Success 200 {object} types.PaginatedResponse
 // @Failure 400 {object} types.Response
 // @Failure 401 {object} types.Response
 // @Security BearerAuth
 // @Router /tasks/assigned [get]
 func (h *taskHandler) GetTasksAssignedToUser(ctx *gin.Context) {
-	page, limit := GetPaginationParams(ctx)
-	tasks, total, err := h.taskService.GetTasksAssignedToUser(ctx, page, limit)
+	page := GetPageRequest(ctx)
+	tasks, total, nextCursor, err := h.taskService.GetTasksAssignedToUser(ctx, page)
 	if err != nil {
 		res := types.Response{
 			Status:  false,
@@ -66,10 +102,11 @@ func (h *taskHandler) GetTasksAssignedToUser(ctx *gin.Context) {
 		Status:  true,
 		Message: "Tasks retrieved successfully",
 		Data: types.PaginatedData{
-			Items: tasks,
-			Total: total,
-			Limit: limit,
-			Page:  page,
+			Items:      tasks,
+			Total:      total,
+			Limit:      page.Limit,
+			Page:       page.Page,
+			NextCursor: nextCursor,
 		},
 	}
 	ctx.JSON(200, res)
@@ -83,14 +120,15 @@ func (h *taskHandler) GetTasksAssignedToUser(ctx *gin.Context) {
 // @Produce json
 // @Param page query int false "Page number (default: 1)"
 // @Param limit query int false "Items per page (default: 10)"
+// @Param cursor query string false "Cursor of the next page, from next_cursor of the previous response. Takes precedence over page."
 // @Success 200 {object} types.PaginatedResponse
 // @Failure 400 {object} types.Response
 // @Failure 401 {object} types.Response
 // @Security BearerAuth
 // @Router /tasks/created [get]
 func (h *taskHandler) GetTasksCreatedByUser(ctx *gin.Context) {
-	page, limit := GetPaginationParams(ctx)
-	tasks, total, err := h.taskService.GetTaskCreatedByUser(ctx, page, limit)
+	page := GetPageRequest(ctx)
+	tasks, total, nextCursor, err := h.taskService.GetTaskCreatedByUser(ctx, page)
 	if err != nil {
 		res := types.Response{
 			Status:  false,
@@ -103,10 +141,11 @@ func (h *taskHandler) GetTasksCreatedByUser(ctx *gin.Context) {
 		Status:  true,
 		Message: "Tasks retrieved successfully",
 		Data: types.PaginatedData{
-			Items: tasks,
-			Total: total,
-			Limit: limit,
-			Page:  page,
+			Items:      tasks,
+			Total:      total,
+			Limit:      page.Limit,
+			Page:       page.Page,
+			NextCursor: nextCursor,
 		},
 	}
 	ctx.JSON(200, res)
@@ -146,12 +185,12 @@ func (h *taskHandler) GetTaskByID(ctx *gin.Context) {
 
 // CreateTask godoc
 // @Summary Create a new task
-// @Description Creates a new task with the provided information
+// @Description Creates a new task with the provided information. The open tasks of the workspace whose title and description look like the new one are returned as likely duplicates, the task is created either way.
 // @Tags tasks
 // @Accept json
 // @Produce json
 // @Param task body types.CreateTaskRequest true "Task information"
-// @Success 201 {object} types.Response
+// @Success 201 {object} types.Response{data=types.CreateTaskResponse}
 // @Failure 400 {object} types.Response
 // @Failure 401 {object} types.Response
 // @Security BearerAuth
@@ -166,7 +205,7 @@ func (h *taskHandler) CreateTask(ctx *gin.Context) {
 		ctx.JSON(400, res)
 		return
 	}
-	err := h.taskService.CreateTask(ctx, req)
+	created, err := h.taskService.CreateTask(ctx, req)
 	if err != nil {
 		res := types.Response{
 			Status:  false,
@@ -178,13 +217,14 @@ func (h *taskHandler) CreateTask(ctx *gin.Context) {
 	res := types.Response{
 		Status:  true,
 		Message: "Task created successfully",
+		Data:    created,
 	}
 	ctx.JSON(201, res)
 }
 
 // UpdateTask godoc
 // @Summary Update an existing task
-// @Description Updates a task with the provided information
+// @Description Updates a task with the provided information. Status changes must follow the task state machine: the assignee moves open→doing→review, the creator or a higher management level moves review→completed/doing, completed→close and cancels or reopens tasks
 // @Tags tasks
 // @Accept json
 // @Produce json
@@ -223,7 +263,7 @@ func (h *taskHandler) UpdateTask(ctx *gin.Context) {
 
 // DeleteTask godoc
 // @Summary Delete a task
-// @Description Deletes a task by its ID
+// @Description Deletes a task by its ID with its reports, comments and comment attachments
 // @Tags tasks
 // @Accept json
 // @Produce json
@@ -254,12 +294,13 @@ func (h *taskHandler) DeleteTask(ctx *gin.Context) {
 
 // FilterTasks godoc
 // @Summary Filter tasks based on criteria
-// @Description Returns a paginated list of tasks matching the filter criteria
+// @Description Returns a paginated list of tasks matching the filter criteria. Custom fields are filtered with field.<key>=value, number and date fields also with field.<key>.from and field.<key>.to.
 // @Tags tasks
 // @Accept json
 // @Produce json
 // @Param page query int false "Page number (default: 1)"
 // @Param limit query int false "Items per page (default: 10)"
+// @Param cursor query string false "Cursor of the next page, from next_cursor of the previous response. Takes precedence over page."
 // @Param deadlineFrom query int64 false "Filter by deadline starting from (unix timestamp)"
 // @Param deadlineTo query int64 false "Filter by deadline up to (unix timestamp)"
 // @Param startFrom query int64 false "Filter by start date from (unix timestamp)"
@@ -269,14 +310,26 @@ func (h *taskHandler) DeleteTask(ctx *gin.Context) {
 // @Param title query string false "Filter by task title (partial match)"
 // @Param status query string false "Filter by task status"
 // @Param assignee query string false "Filter tasks assigned to current user"
+// @Param primaryAssignee query string false "Filter tasks led by current user"
+// @Param watcher query string false "Filter tasks watched by current user"
 // @Param creator query string false "Filter tasks created by current user"
+// @Param search query string false "Full-text search over title and description"
+// @Param statuses query string false "Comma separated statuses, any of them matches"
+// @Param assignees query string false "Comma separated user IDs, tasks assigned to any of them match"
+// @Param priorities query string false "Comma separated priorities, any of them matches"
+// @Param tags query string false "Comma separated tags, any of them matches"
+// @Param progressMin query int false "Minimum progress, inclusive"
+// @Param progressMax query int false "Maximum progress, inclusive"
+// @Param overdue query bool false "Only unfinished tasks past their deadline"
+// @Param noReports query bool false "Only tasks without any report"
+// @Param sort query string false "Comma separated field:asc|desc keys, fields are deadline, start_at, created_at, updated_at, progress, priority, title, status and relevance (with search). Deadline descending by default."
 // @Success 200 {object} types.PaginatedResponse
 // @Failure 400 {object} types.Response
 // @Failure 401 {object} types.Response
 // @Security BearerAuth
 // @Router /tasks/filter [get]
 func (h *taskHandler) FilterTasks(ctx *gin.Context) {
-	page, limit := GetPaginationParams(ctx)
+	page := GetPageRequest(ctx)
 	userID, ok := ctx.Value("user_id").(string)
 	if !ok {
 		res := types.Response{
@@ -286,95 +339,167 @@ func (h *taskHandler) FilterTasks(ctx *gin.Context) {
 		ctx.JSON(401, res)
 		return
 	}
-	filter := &types.TaskFilter{}
-	if ctx.Query("deadlineFrom") != "" {
-		deadlineFrom, err := strconv.ParseInt(ctx.Query("deadlineFrom"), 10, 64)
-		if err != nil {
-			res := types.Response{
-				Status:  false,
-				Message: "Invalid deadlineFrom parameter",
-			}
-			ctx.JSON(400, res)
-			return
+	filter, err := taskFilterFromQuery(ctx, userID)
+	if err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: err.Error(),
 		}
-		filter.DeadlineFrom = deadlineFrom
+		ctx.JSON(400, res)
+		return
 	}
-	if ctx.Query("deadlineTo") != "" {
-		deadlineTo, err := strconv.ParseInt(ctx.Query("deadlineTo"), 10, 64)
-		if err != nil {
-			res := types.Response{
-				Status:  false,
-				Message: "Invalid deadlineTo parameter",
-			}
-			ctx.JSON(400, res)
-			return
+
+	tasks, total, nextCursor, err := h.taskService.FilterTasks(ctx, page, *filter)
+	if err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: err.Error(),
 		}
-		filter.DeadlineTo = deadlineTo
+		ctx.JSON(400, res)
+		return
 	}
-	if ctx.Query("title") != "" {
-		filter.Title = ctx.Query("title")
+	res := types.PaginatedResponse{
+		Status:  true,
+		Message: "Tasks retrieved successfully",
+		Data: types.PaginatedData{
+			Items:      tasks,
+			Total:      total,
+			Limit:      page.Limit,
+			Page:       page.Page,
+			NextCursor: nextCursor,
+		},
 	}
+	ctx.JSON(200, res)
+}
 
-	if ctx.Query("status") != "" {
-		filter.Status = ctx.Query("status")
+// GetTaskBoard godoc
+// @Summary Get the task board
+// @Description Returns the workspace tasks grouped by status, one page per column with the column total. Pass status to page through a single column. Accepts the search, list, progress, overdue, noReports and sort filters of /tasks/filter. Custom fields are filtered with field.<key>=value, number and date fields also with field.<key>.from and field.<key>.to.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number of every column (default: 1)"
+// @Param limit query int false "Tasks per column (default: 10)"
+// @Param cursor query string false "Cursor of the next page of the column given by status, from its next_cursor"
+// @Param status query string false "Only return this column"
+// @Param deadlineFrom query int64 false "Filter by deadline starting from (unix timestamp)"
+// @Param deadlineTo query int64 false "Filter by deadline up to (unix timestamp)"
+// @Param startFrom query int64 false "Filter by start date from (unix timestamp)"
+// @Param startTo query int64 false "Filter by start date to (unix timestamp)"
+// @Param reportFrom query int64 false "Filter by report date from (unix timestamp)"
+// @Param reportTo query int64 false "Filter by report date to (unix timestamp)"
+// @Param title query string false "Filter by task title (partial match)"
+// @Param assignee query string false "Filter tasks assigned to current user"
+// @Param primaryAssignee query string false "Filter tasks led by current user"
+// @Param watcher query string false "Filter tasks watched by current user"
+// @Param creator query string false "Filter tasks created by current user"
+// @Success 200 {object} types.Response{data=types.TaskBoard}
+// @Failure 400 {object} types.Response
+// @Failure 401 {object} types.Response
+// @Security BearerAuth
+// @Router /tasks/board [get]
+func (h *taskHandler) GetTaskBoard(ctx *gin.Context) {
+	page := GetPageRequest(ctx)
+	userID, ok := ctx.Value("user_id").(string)
+	if !ok {
+		res := types.Response{
+			Status:  false,
+			Message: types.ErrInvalidCredentials.Error(),
+		}
+		ctx.JSON(401, res)
+		return
 	}
-
-	if ctx.Query("assignee") != "" {
-		filter.Assignee = userID
+	filter, err := taskFilterFromQuery(ctx, userID)
+	if err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: err.Error(),
+		}
+		ctx.JSON(400, res)
+		return
 	}
-	if ctx.Query("creator") != "" {
-		filter.Creator = userID
+	board, err := h.taskService.GetTaskBoard(ctx, page, *filter)
+	if err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: err.Error(),
+		}
+		ctx.JSON(400, res)
+		return
 	}
-	if ctx.Query("startFrom") != "" {
-		startFrom, err := strconv.ParseInt(ctx.Query("startFrom"), 10, 64)
-		if err != nil {
-			res := types.Response{
-				Status:  false,
-				Message: "Invalid startFrom parameter",
-			}
-			ctx.JSON(400, res)
-			return
+	res := types.Response{
+		Status:  true,
+		Message: "Task board retrieved successfully",
+		Data:    board,
+	}
+	ctx.JSON(200, res)
+}
+
+// GetTaskCalendar godoc
+// @Summary Get the task calendar
+// @Description Returns the workspace tasks whose start to deadline span overlaps the range, with the tasks of every day or week. A task without start date only spans its deadline. The range is limited to 366 days. Accepts the search, list, progress, overdue, noReports and sort filters of /tasks/filter. Custom fields are filtered with field.<key>=value, number and date fields also with field.<key>.from and field.<key>.to.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param from query int64 true "Range start (unix timestamp)"
+// @Param to query int64 true "Range end (unix timestamp)"
+// @Param groupBy query string false "day (default) or week, weeks start on Monday"
+// @Param timezone query string false "IANA time zone of the days, the server zone by default"
+// @Param status query string false "Filter by task status"
+// @Param title query string false "Filter by task title (partial match)"
+// @Param assignee query string false "Filter tasks assigned to current user"
+// @Param primaryAssignee query string false "Filter tasks led by current user"
+// @Param watcher query string false "Filter tasks watched by current user"
+// @Param creator query string false "Filter tasks created by current user"
+// @Success 200 {object} types.Response{data=types.TaskCalendar}
+// @Failure 400 {object} types.Response
+// @Failure 401 {object} types.Response
+// @Security BearerAuth
+// @Router /tasks/calendar [get]
+func (h *taskHandler) GetTaskCalendar(ctx *gin.Context) {
+	userID, ok := ctx.Value("user_id").(string)
+	if !ok {
+		res := types.Response{
+			Status:  false,
+			Message: types.ErrInvalidCredentials.Error(),
 		}
-		filter.StartFrom = startFrom
+		ctx.JSON(401, res)
+		return
 	}
-	if ctx.Query("startTo") != "" {
-		startTo, err := strconv.ParseInt(ctx.Query("startTo"), 10, 64)
-		if err != nil {
-			res := types.Response{
-				Status:  false,
-				Message: "Invalid startTo parameter",
-			}
-			ctx.JSON(400, res)
-			return
+	from, err := strconv.ParseInt(ctx.Query("from"), 10, 64)
+	if err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: "Invalid from parameter",
 		}
-		filter.StartTo = startTo
+		ctx.JSON(400, res)
+		return
 	}
-	if ctx.Query("reportFrom") != "" {
-		reportFrom, err := strconv.ParseInt(ctx.Query("reportFrom"), 10, 64)
-		if err != nil {
-			res := types.Response{
-				Status:  false,
-				Message: "Invalid reportFrom parameter",
-			}
-			ctx.JSON(400, res)
-			return
+	to, err := strconv.ParseInt(ctx.Query("to"), 10, 64)
+	if err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: "Invalid to parameter",
 		}
-		filter.ReportFrom = reportFrom
+		ctx.JSON(400, res)
+		return
 	}
-	if ctx.Query("reportTo") != "" {
-		reportTo, err := strconv.ParseInt(ctx.Query("reportTo"), 10, 64)
-		if err != nil {
-			res := types.Response{
-				Status:  false,
-				Message: "Invalid reportTo parameter",
-			}
-			ctx.JSON(400, res)
-			return
+	filter, err := taskFilterFromQuery(ctx, userID)
+	if err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: err.Error(),
 		}
-		filter.ReportTo = reportTo
+		ctx.JSON(400, res)
+		return
 	}
-
-	tasks, total, err := h.taskService.FilterTasks(ctx, page, limit, *filter)
+	req := &types.TaskCalendarRequest{
+		From:     from,
+		To:       to,
+		GroupBy:  ctx.Query("groupBy"),
+		Timezone: ctx.Query("timezone"),
+	}
+	calendar, err := h.taskService.GetTaskCalendar(ctx, req, *filter)
 	if err != nil {
 		res := types.Response{
 			Status:  false,
@@ -383,15 +508,10 @@ func (h *taskHandler) FilterTasks(ctx *gin.Context) {
 		ctx.JSON(400, res)
 		return
 	}
-	res := types.PaginatedResponse{
+	res := types.Response{
 		Status:  true,
-		Message: "Tasks retrieved successfully",
-		Data: types.PaginatedData{
-			Items: tasks,
-			Total: total,
-			Limit: limit,
-			Page:  page,
-		},
+		Message: "Task calendar retrieved successfully",
+		Data:    calendar,
 	}
 	ctx.JSON(200, res)
 }
@@ -438,7 +558,7 @@ func (h *taskHandler) AddReportTask(ctx *gin.Context) {
 
 // DeleteReport godoc
 // @Summary Delete a report
-// @Description Deletes an existing report by ID
+// @Description Deletes an existing report by ID with its comments and their attachments
 // @Tags reports
 // @Accept json
 // @Produce json
@@ -516,7 +636,7 @@ func (h *taskHandler) UpdateReportTask(ctx *gin.Context) {
 
 // FeedbackReport godoc
 // @Summary Provide feedback on a report
-// @Description Adds feedback to a specific report
+// @Description Adds feedback to a report of a task in the workspace of the user. Only the task creator or a manager of the creator may give feedback.
 // @Tags reports
 // @Accept json
 // @Produce json
@@ -553,22 +673,1248 @@ func (h *taskHandler) FeedbackReport(ctx *gin.Context) {
 	ctx.JSON(200, res)
 }
 
-func GetPaginationParams(c *gin.Context) (page int64, limit int64) {
-	// Default values
-	page = 1
-	limit = 10
+// GetReports godoc
+// @Summary List the reports of the workspace
+// @Description Returns a paginated, newest first list of the workspace reports, filtered by task, creator, creation date and review status
+// @Tags reports
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number (default: 1)"
+// @Param limit query int false "Items per page (default: 10)"
+// @Param cursor query string false "Cursor of the next page, from next_cursor of the previous response. Takes precedence over page."
+// @Param taskId query string false "Filter by task ID"
+// @Param creator query string false "Filter by report creator ID"
+// @Param createdFrom query int64 false "Filter by creation date from (unix timestamp)"
+// @Param createdTo query int64 false "Filter by creation date to (unix timestamp)"
+// @Param status query string false "Filter by review status: pending, approved or rejected"
+// @Success 200 {object} types.PaginatedResponse{data=types.PaginatedData{items=[]types.ReportResponse}}
+// @Failure 400 {object} types.Response
+// @Failure 401 {object} types.Response
+// @Security BearerAuth
+// @Router /tasks/reports [get]
+func (h *taskHandler) GetReports(ctx *gin.Context) {
+	page := GetPageRequest(ctx)
+	filter, err := reportFilterFromQuery(ctx, "")
+	if err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: err.Error(),
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	reports, total, nextCursor, err := h.taskService.GetReports(ctx, page, *filter)
+	if err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: err.Error(),
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	res := types.PaginatedResponse{
+		Status:  true,
+		Message: "Reports retrieved successfully",
+		Data: types.PaginatedData{
+			Items:      reports,
+			Total:      total,
+			Limit:      page.Limit,
+			Page:       page.Page,
+			NextCursor: nextCursor,
+		},
+	}
+	ctx.JSON(200, res)
+}
 
-	// Try to parse page parameter
-	pageStr := c.DefaultQuery("page", "1")
-	if pageVal, err := strconv.ParseInt(pageStr, 10, 64); err == nil && pageVal > 0 {
-		page = pageVal
+// GetReportsAwaitingReview godoc
+// @Summary List the reports awaiting my review
+// @Description Returns a paginated, newest first list of the reports waiting for a decision on the tasks the current user created or manages
+// @Tags reports
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number (default: 1)"
+// @Param limit query int false "Items per page (default: 10)"
+// @Param cursor query string false "Cursor of the next page, from next_cursor of the previous response. Takes precedence over page."
+// @Success 200 {object} types.PaginatedResponse{data=types.PaginatedData{items=[]types.ReportResponse}}
+// @Failure 400 {object} types.Response
+// @Failure 401 {object} types.Response
+// @Security BearerAuth
+// @Router /tasks/reports/awaiting [get]
+func (h *taskHandler) GetReportsAwaitingReview(ctx *gin.Context) {
+	page := GetPageRequest(ctx)
+	reports, total, nextCursor, err := h.taskService.GetReportsAwaitingReview(ctx, page)
+	if err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: err.Error(),
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	res := types.PaginatedResponse{
+		Status:  true,
+		Message: "Reports retrieved successfully",
+		Data: types.PaginatedData{
+			Items:      reports,
+			Total:      total,
+			Limit:      page.Limit,
+			Page:       page.Page,
+			NextCursor: nextCursor,
+		},
 	}
+	ctx.JSON(200, res)
+}
 
-	// Try to parse limit parameter
-	limitStr := c.DefaultQuery("limit", "10")
-	if limitVal, err := strconv.ParseInt(limitStr, 10, 64); err == nil && limitVal > 0 {
-		limit = limitVal
+// ReviewReport godoc
+// @Summary Approve or reject a report
+// @Description Records the review decision and feedback on a report. A task in review is completed when its report is approved and goes back to doing when it is rejected. Rejections need feedback. Only a pending report can be reviewed.
+// @Tags reports
+// @Accept json
+// @Produce json
+// @Param review body types.ReviewReportRequest true "Review decision"
+// @Success 200 {object} types.Response "Report reviewed successfully"
+// @Failure 400 {object} types.Response "Invalid request format or validation error"
+// @Failure 401 {object} types.Response "Unauthorized"
+// @Security BearerAuth
+// @Router /tasks/report/review [post]
+func (h *taskHandler) ReviewReport(ctx *gin.Context) {
+	req := &types.ReviewReportRequest{}
+	if err := ctx.ShouldBindJSON(req); err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: err.Error(),
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	err := h.taskService.ReviewReport(ctx, req)
+	if err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: err.Error(),
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	res := types.Response{
+		Status:  true,
+		Message: "Report reviewed successfully",
 	}
+	ctx.JSON(200, res)
+}
 
-	return page, limit
+// GetTaskComments godoc
+// @Summary Get the discussion of a task
+// @Description Returns a paginated, oldest first list of the comment threads of a task, each with its replies
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Param id path string true "Task ID"
+// @Param page query int false "Page number (default: 1)"
+// @Param limit query int false "Threads per page (default: 10)"
+// @Param cursor query string false "Cursor of the next page, from next_cursor of the previous response. Takes precedence over page."
+// @Success 200 {object} types.PaginatedResponse{data=types.PaginatedData{items=[]types.CommentResponse}}
+// @Failure 400 {object} types.Response
+// @Failure 401 {object} types.Response
+// @Security BearerAuth
+// @Router /tasks/{id}/comments [get]
+func (h *taskHandler) GetTaskComments(ctx *gin.Context) {
+	page := GetPageRequest(ctx)
+	comments, total, nextCursor, err := h.taskService.GetTaskComments(ctx, ctx.Param("id"), page)
+	if err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: err.Error(),
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	res := types.PaginatedResponse{
+		Status:  true,
+		Message: "Comments retrieved successfully",
+		Data: types.PaginatedData{
+			Items:      comments,
+			Total:      total,
+			Limit:      page.Limit,
+			Page:       page.Page,
+			NextCursor: nextCursor,
+		},
+	}
+	ctx.JSON(200, res)
+}
+
+// GetReportComments godoc
+// @Summary Get the discussion of a report
+// @Description Returns a paginated, oldest first list of the comment threads of a report, each with its replies. Report feedback is posted to this discussion too, comments posted here do not change the feedback of the report.
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Param id path string true "Report ID"
+// @Param page query int false "Page number (default: 1)"
+// @Param limit query int false "Threads per page (default: 10)"
+// @Param cursor query string false "Cursor of the next page, from next_cursor of the previous response. Takes precedence over page."
+// @Success 200 {object} types.PaginatedResponse{data=types.PaginatedData{items=[]types.CommentResponse}}
+// @Failure 400 {object} types.Response
+// @Failure 401 {object} types.Response
+// @Security BearerAuth
+// @Router /tasks/reports/{id}/comments [get]
+func (h *taskHandler) GetReportComments(ctx *gin.Context) {
+	page := GetPageRequest(ctx)
+	comments, total, nextCursor, err := h.taskService.GetReportComments(ctx, ctx.Param("id"), page)
+	if err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: err.Error(),
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	res := types.PaginatedResponse{
+		Status:  true,
+		Message: "Comments retrieved successfully",
+		Data: types.PaginatedData{
+			Items:      comments,
+			Total:      total,
+			Limit:      page.Limit,
+			Page:       page.Page,
+			NextCursor: nextCursor,
+		},
+	}
+	ctx.JSON(200, res)
+}
+
+// CreateComment godoc
+// @Summary Comment on a task or a report
+// @Description Posts a comment, or a reply with parent_id. Members mentioned with @username are notified. Send JSON, or a multipart form with the request as metadata and the files as attachments.
+// @Tags comments
+// @Accept json,mpfd
+// @Produce json
+// @Param comment body types.CreateCommentRequest false "Comment, when sent as JSON"
+// @Param metadata formData string false "Comment in JSON format, when sent as a multipart form"
+// @Param attachments formData []file false "Files to attach" collectionFormat(multi)
+// @Success 200 {object} types.Response{data=types.CommentResponse}
+// @Failure 400 {object} types.Response
+// @Failure 401 {object} types.Response
+// @Security BearerAuth
+// @Router /tasks/comments/create [post]
+func (h *taskHandler) CreateComment(ctx *gin.Context) {
+	req := &types.CreateCommentRequest{}
+	attachments, err := bindCommentRequest(ctx, req)
+	if err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: err.Error(),
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	req.Attachments = attachments
+	comment, err := h.taskService.CreateComment(ctx, req)
+	if err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: err.Error(),
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	res := types.Response{
+		Status:  true,
+		Message: "Comment created successfully",
+		Data:    comment,
+	}
+	ctx.JSON(200, res)
+}
+
+// UpdateComment godoc
+// @Summary Edit a comment
+// @Description Replaces the content of a comment of the current user, removes and adds attachments. The previous version is kept in the comment history. Send JSON, or a multipart form with the request as metadata and the new files as attachments.
+// @Tags comments
+// @Accept json,mpfd
+// @Produce json
+// @Param comment body types.UpdateCommentRequest false "Comment changes, when sent as JSON"
+// @Param metadata formData string false "Comment changes in JSON format, when sent as a multipart form"
+// @Param attachments formData []file false "Files to attach" collectionFormat(multi)
+// @Success 200 {object} types.Response{data=types.CommentResponse}
+// @Failure 400 {object} types.Response
+// @Failure 401 {object} types.Response
+// @Security BearerAuth
+// @Router /tasks/comments/update [post]
+func (h *taskHandler) UpdateComment(ctx *gin.Context) {
+	req := &types.UpdateCommentRequest{}
+	attachments, err := bindCommentRequest(ctx, req)
+	if err == nil && req.ID == "" {
+		err = fmt.Errorf("Comment ID is required")
+	}
+	if err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: err.Error(),
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	req.Attachments = attachments
+	comment, err := h.taskService.UpdateComment(ctx, req)
+	if err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: err.Error(),
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	res := types.Response{
+		Status:  true,
+		Message: "Comment updated successfully",
+		Data:    comment,
+	}
+	ctx.JSON(200, res)
+}
+
+// DeleteComment godoc
+// @Summary Delete a comment
+// @Description Empties a comment, its replies stay in the thread. The author, the task creator and its managers may delete it, the last version is kept in the comment history.
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Param id path string true "Comment ID"
+// @Success 200 {object} types.Response
+// @Failure 400 {object} types.Response
+// @Failure 401 {object} types.Response
+// @Security BearerAuth
+// @Router /tasks/comments/delete/{id} [post]
+func (h *taskHandler) DeleteComment(ctx *gin.Context) {
+	err := h.taskService.DeleteComment(ctx, ctx.Param("id"))
+	if err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: err.Error(),
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	res := types.Response{
+		Status:  true,
+		Message: "Comment deleted successfully",
+	}
+	ctx.JSON(200, res)
+}
+
+// GetCommentHistory godoc
+// @Summary Get the history of a comment
+// @Description Returns the previous versions of a comment, oldest first
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Param id path string true "Comment ID"
+// @Success 200 {object} types.Response{data=[]types.CommentEditResponse}
+// @Failure 400 {object} types.Response
+// @Failure 401 {object} types.Response
+// @Security BearerAuth
+// @Router /tasks/comments/{id}/history [get]
+func (h *taskHandler) GetCommentHistory(ctx *gin.Context) {
+	edits, err := h.taskService.GetCommentHistory(ctx, ctx.Param("id"))
+	if err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: err.Error(),
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	res := types.Response{
+		Status:  true,
+		Message: "Comment history retrieved successfully",
+		Data:    edits,
+	}
+	ctx.JSON(200, res)
+}
+
+// DownloadCommentAttachment godoc
+// @Summary Download a comment attachment
+// @Description Sends an attachment of a comment, selected by its position in the attachment list
+// @Tags comments
+// @Produce octet-stream
+// @Param id path string true "Comment ID"
+// @Param index path int true "Attachment position, from 0"
+// @Success 200 {file} file "Attachment content"
+// @Failure 400 {object} types.Response
+// @Failure 401 {object} types.Response
+// @Security BearerAuth
+// @Router /tasks/comments/{id}/attachments/{index} [get]
+func (h *taskHandler) DownloadCommentAttachment(ctx *gin.Context) {
+	index, err := strconv.Atoi(ctx.Param("index"))
+	if err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: "Invalid index parameter",
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	filePath, fileName, err := h.taskService.GetCommentAttachment(ctx, ctx.Param("id"), index)
+	if err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: err.Error(),
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	ctx.FileAttachment(filePath, fileName)
+}
+
+// GetTaskHistory godoc
+// @Summary Get the activity history of a task
+// @Description Returns a paginated, newest first list of changes made to a task and its reports, still readable after the task is deleted
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param id path string true "Task ID"
+// @Param page query int false "Page number (default: 1)"
+// @Param limit query int false "Items per page (default: 10)"
+// @Success 200 {object} types.PaginatedResponse{data=types.PaginatedData{items=[]types.TaskActivityResponse}}
+// @Failure 400 {object} types.Response
+// @Failure 401 {object} types.Response
+// @Security BearerAuth
+// @Router /tasks/{id}/history [get]
+func (h *taskHandler) GetTaskHistory(ctx *gin.Context) {
+	page, limit := GetPaginationParams(ctx)
+	id := ctx.Param("id")
+	activities, total, err := h.taskService.GetTaskHistory(ctx, id, page, limit)
+	if err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: err.Error(),
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	res := types.PaginatedResponse{
+		Status:  true,
+		Message: "Task history retrieved successfully",
+		Data: types.PaginatedData{
+			Items: activities,
+			Total: total,
+			Limit: limit,
+			Page:  page,
+		},
+	}
+	ctx.JSON(200, res)
+}
+
+// GetTaskSubtree godoc
+// @Summary Get a task with its subtasks
+// @Description Returns the task and all of its subtasks nested as a tree
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param id path string true "Task ID"
+// @Success 200 {object} types.Response{data=types.TaskTreeNode}
+// @Failure 400 {object} types.Response
+// @Failure 401 {object} types.Response
+// @Security BearerAuth
+// @Router /tasks/{id}/subtree [get]
+func (h *taskHandler) GetTaskSubtree(ctx *gin.Context) {
+	id := ctx.Param("id")
+	tree, err := h.taskService.GetTaskSubtree(ctx, id)
+	if err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: err.Error(),
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	res := types.Response{
+		Status:  true,
+		Message: "Task subtree retrieved successfully",
+		Data:    tree,
+	}
+	ctx.JSON(200, res)
+}
+
+// GetSimilarTasks godoc
+// @Summary Get tasks similar to a task
+// @Description Returns the tasks of the workspace whose title and description are closest in meaning to the given task, whatever their status, most similar first
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param id path string true "Task ID"
+// @Param limit query int false "Maximum number of tasks (default: server setting)"
+// @Success 200 {object} types.Response{data=[]types.SimilarTask}
+// @Failure 400 {object} types.Response
+// @Failure 401 {object} types.Response
+// @Security BearerAuth
+// @Router /tasks/{id}/similar [get]
+func (h *taskHandler) GetSimilarTasks(ctx *gin.Context) {
+	id := ctx.Param("id")
+	limit := 0
+	if ctx.Query("limit") != "" {
+		var err error
+		limit, err = strconv.Atoi(ctx.Query("limit"))
+		if err != nil || limit < 1 {
+			res := types.Response{
+				Status:  false,
+				Message: "Invalid limit",
+			}
+			ctx.JSON(400, res)
+			return
+		}
+	}
+	tasks, err := h.taskService.GetSimilarTasks(ctx, id, limit)
+	if err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: err.Error(),
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	res := types.Response{
+		Status:  true,
+		Message: "Similar tasks retrieved successfully",
+		Data:    tasks,
+	}
+	ctx.JSON(200, res)
+}
+
+// GetTaskDependencies godoc
+// @Summary Get the dependency graph of a task
+// @Description Returns the tasks the given task depends on and the tasks depending on it, transitively, with the edges between them. Blocked is true while a direct predecessor is not completed, closed or cancelled.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param id path string true "Task ID"
+// @Success 200 {object} types.Response{data=types.TaskDependencyGraph}
+// @Failure 400 {object} types.Response
+// @Failure 401 {object} types.Response
+// @Security BearerAuth
+// @Router /tasks/{id}/dependencies [get]
+func (h *taskHandler) GetTaskDependencies(ctx *gin.Context) {
+	id := ctx.Param("id")
+	graph, err := h.taskService.GetTaskDependencyGraph(ctx, id)
+	if err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: err.Error(),
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	res := types.Response{
+		Status:  true,
+		Message: "Task dependencies retrieved successfully",
+		Data:    graph,
+	}
+	ctx.JSON(200, res)
+}
+
+// AddTaskDependency godoc
+// @Summary Add a dependency to a task
+// @Description Makes task_id depend on depends_on, the task cannot move to doing, review or completed until depends_on is finished. Only the task creator or a manager may change dependencies.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param dependency body types.TaskDependencyRequest true "Dependency information"
+// @Success 200 {object} types.Response
+// @Failure 400 {object} types.Response
+// @Failure 401 {object} types.Response
+// @Security BearerAuth
+// @Router /tasks/dependency/add [post]
+func (h *taskHandler) AddTaskDependency(ctx *gin.Context) {
+	req := &types.TaskDependencyRequest{}
+	if err := ctx.ShouldBindJSON(req); err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: err.Error(),
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	err := h.taskService.AddTaskDependency(ctx, req)
+	if err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: err.Error(),
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	res := types.Response{
+		Status:  true,
+		Message: "Task dependency added successfully",
+	}
+	ctx.JSON(200, res)
+}
+
+// RemoveTaskDependency godoc
+// @Summary Remove a dependency from a task
+// @Description Removes depends_on from the predecessors of task_id
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param dependency body types.TaskDependencyRequest true "Dependency information"
+// @Success 200 {object} types.Response
+// @Failure 400 {object} types.Response
+// @Failure 401 {object} types.Response
+// @Security BearerAuth
+// @Router /tasks/dependency/remove [post]
+func (h *taskHandler) RemoveTaskDependency(ctx *gin.Context) {
+	req := &types.TaskDependencyRequest{}
+	if err := ctx.ShouldBindJSON(req); err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: err.Error(),
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	err := h.taskService.RemoveTaskDependency(ctx, req)
+	if err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: err.Error(),
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	res := types.Response{
+		Status:  true,
+		Message: "Task dependency removed successfully",
+	}
+	ctx.JSON(200, res)
+}
+
+// GetTasksWatchedByUser godoc
+// @Summary Get tasks watched by the current user
+// @Description Returns a paginated list of tasks the authenticated user is watching
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number (default: 1)"
+// @Param limit query int false "Items per page (default: 10)"
+// @Param cursor query string false "Cursor of the next page, from next_cursor of the previous response. Takes precedence over page."
+// @Success 200 {object} types.PaginatedResponse
+// @Failure 400 {object} types.Response
+// @Failure 401 {object} types.Response
+// @Security BearerAuth
+// @Router /tasks/watching [get]
+func (h *taskHandler) GetTasksWatchedByUser(ctx *gin.Context) {
+	page := GetPageRequest(ctx)
+	tasks, total, nextCursor, err := h.taskService.GetTasksWatchedByUser(ctx, page)
+	if err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: err.Error(),
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	res := types.PaginatedResponse{
+		Status:  true,
+		Message: "Tasks retrieved successfully",
+		Data: types.PaginatedData{
+			Items:      tasks,
+			Total:      total,
+			Limit:      page.Limit,
+			Page:       page.Page,
+			NextCursor: nextCursor,
+		},
+	}
+	ctx.JSON(200, res)
+}
+
+// WatchTask godoc
+// @Summary Watch a task
+// @Description Adds the current user to the watchers of a task of their workspace
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param id path string true "Task ID"
+// @Success 200 {object} types.Response
+// @Failure 400 {object} types.Response
+// @Failure 401 {object} types.Response
+// @Security BearerAuth
+// @Router /tasks/watch/{id} [post]
+func (h *taskHandler) WatchTask(ctx *gin.Context) {
+	id := ctx.Param("id")
+	err := h.taskService.WatchTask(ctx, id)
+	if err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: err.Error(),
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	res := types.Response{
+		Status:  true,
+		Message: "Task watched successfully",
+	}
+	ctx.JSON(200, res)
+}
+
+// UnwatchTask godoc
+// @Summary Stop watching a task
+// @Description Removes the current user from the watchers of a task
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param id path string true "Task ID"
+// @Success 200 {object} types.Response
+// @Failure 400 {object} types.Response
+// @Failure 401 {object} types.Response
+// @Security BearerAuth
+// @Router /tasks/unwatch/{id} [post]
+func (h *taskHandler) UnwatchTask(ctx *gin.Context) {
+	id := ctx.Param("id")
+	err := h.taskService.UnwatchTask(ctx, id)
+	if err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: err.Error(),
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	res := types.Response{
+		Status:  true,
+		Message: "Task unwatched successfully",
+	}
+	ctx.JSON(200, res)
+}
+
+// GetTaskTemplates godoc
+// @Summary Get the task templates of the workspace
+// @Description Returns a paginated list of the recurring task templates of the current user workspace
+// @Tags task-templates
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number (default: 1)"
+// @Param limit query int false "Items per page (default: 10)"
+// @Success 200 {object} types.PaginatedResponse{data=types.PaginatedData{items=[]types.TaskTemplateResponse}}
+// @Failure 400 {object} types.Response
+// @Failure 401 {object} types.Response
+// @Security BearerAuth
+// @Router /tasks/templates [get]
+func (h *taskHandler) GetTaskTemplates(ctx *gin.Context) {
+	page, limit := GetPaginationParams(ctx)
+	templates, total, err := h.taskService.GetTaskTemplates(ctx, page, limit)
+	if err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: err.Error(),
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	res := types.PaginatedResponse{
+		Status:  true,
+		Message: "Task templates retrieved successfully",
+		Data: types.PaginatedData{
+			Items: templates,
+			Total: total,
+			Limit: limit,
+			Page:  page,
+		},
+	}
+	ctx.JSON(200, res)
+}
+
+// GetTaskTemplateByID godoc
+// @Summary Get a task template by ID
+// @Description Returns a recurring task template with its next occurrence
+// @Tags task-templates
+// @Accept json
+// @Produce json
+// @Param id path string true "Task template ID"
+// @Success 200 {object} types.Response{data=types.TaskTemplateResponse}
+// @Failure 400 {object} types.Response
+// @Failure 401 {object} types.Response
+// @Security BearerAuth
+// @Router /tasks/templates/{id} [get]
+func (h *taskHandler) GetTaskTemplateByID(ctx *gin.Context) {
+	id := ctx.Param("id")
+	template, err := h.taskService.GetTaskTemplateByID(ctx, id)
+	if err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: err.Error(),
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	res := types.Response{
+		Status:  true,
+		Message: "Task template retrieved successfully",
+		Data:    template,
+	}
+	ctx.JSON(200, res)
+}
+
+// CreateTaskTemplate godoc
+// @Summary Create a recurring task template
+// @Description Creates a template generating a task for every occurrence of its recurrence, a 5 field cron expression (e.g. "0 8 * * 1") or an RRULE (e.g. "FREQ=MONTHLY;BYMONTHDAY=1;BYHOUR=8"). Tasks are created generate_ahead seconds (default 7 days) before the occurrence and last duration seconds. Occurrences missed past their deadline are skipped, overlap_policy "skip" also skips an occurrence while an earlier task of the template is unfinished and overlaps it.
+// @Tags task-templates
+// @Accept json
+// @Produce json
+// @Param template body types.CreateTaskTemplateRequest true "Task template information"
+// @Success 200 {object} types.Response
+// @Failure 400 {object} types.Response
+// @Failure 401 {object} types.Response
+// @Security BearerAuth
+// @Router /tasks/templates/create [post]
+func (h *taskHandler) CreateTaskTemplate(ctx *gin.Context) {
+	req := &types.CreateTaskTemplateRequest{}
+	if err := ctx.ShouldBindJSON(req); err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: err.Error(),
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	err := h.taskService.CreateTaskTemplate(ctx, req)
+	if err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: err.Error(),
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	res := types.Response{
+		Status:  true,
+		Message: "Task template created successfully",
+	}
+	ctx.JSON(200, res)
+}
+
+// UpdateTaskTemplate godoc
+// @Summary Update a recurring task template
+// @Description Updates a template, only its creator or a higher management level may do so. Changes apply to the occurrences not generated yet.
+// @Tags task-templates
+// @Accept json
+// @Produce json
+// @Param template body types.UpdateTaskTemplateRequest true "Task template information"
+// @Success 200 {object} types.Response
+// @Failure 400 {object} types.Response
+// @Failure 401 {object} types.Response
+// @Security BearerAuth
+// @Router /tasks/templates/update [post]
+func (h *taskHandler) UpdateTaskTemplate(ctx *gin.Context) {
+	req := &types.UpdateTaskTemplateRequest{}
+	if err := ctx.ShouldBindJSON(req); err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: err.Error(),
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	err := h.taskService.UpdateTaskTemplate(ctx, req)
+	if err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: err.Error(),
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	res := types.Response{
+		Status:  true,
+		Message: "Task template updated successfully",
+	}
+	ctx.JSON(200, res)
+}
+
+// DeleteTaskTemplate godoc
+// @Summary Delete a recurring task template
+// @Description Deletes a template, the tasks it already generated are kept
+// @Tags task-templates
+// @Accept json
+// @Produce json
+// @Param id path string true "Task template ID"
+// @Success 200 {object} types.Response
+// @Failure 400 {object} types.Response
+// @Failure 401 {object} types.Response
+// @Security BearerAuth
+// @Router /tasks/templates/delete/{id} [post]
+func (h *taskHandler) DeleteTaskTemplate(ctx *gin.Context) {
+	id := ctx.Param("id")
+	err := h.taskService.DeleteTaskTemplate(ctx, id)
+	if err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: err.Error(),
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	res := types.Response{
+		Status:  true,
+		Message: "Task template deleted successfully",
+	}
+	ctx.JSON(200, res)
+}
+
+// GetCustomFields godoc
+// @Summary Get the workspace custom fields
+// @Description Returns the custom task fields defined for the workspace of the authenticated user
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Success 200 {object} types.Response{data=[]types.CustomFieldDefinition}
+// @Failure 400 {object} types.Response
+// @Failure 401 {object} types.Response
+// @Security BearerAuth
+// @Router /tasks/fields [get]
+func (h *taskHandler) GetCustomFields(ctx *gin.Context) {
+	fields, err := h.taskService.GetCustomFields(ctx)
+	if err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: err.Error(),
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	res := types.Response{
+		Status:  true,
+		Message: "Custom fields retrieved successfully",
+		Data:    fields,
+	}
+	ctx.JSON(200, res)
+}
+
+// CreateCustomField godoc
+// @Summary Create a custom field
+// @Description Defines a custom task field for the workspace, managing them requires field.manage. The type is text, number, date (unix timestamp) or enum, enum fields need their options. Task values are validated against the definition on create and update.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param field body types.CreateCustomFieldRequest true "Custom field definition"
+// @Success 201 {object} types.Response{data=types.CustomFieldDefinition}
+// @Failure 400 {object} types.Response
+// @Failure 401 {object} types.Response
+// @Security BearerAuth
+// @Router /tasks/fields/create [post]
+func (h *taskHandler) CreateCustomField(ctx *gin.Context) {
+	req := &types.CreateCustomFieldRequest{}
+	if err := ctx.ShouldBindJSON(req); err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: err.Error(),
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	field, err := h.taskService.CreateCustomField(ctx, req)
+	if err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: err.Error(),
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	res := types.Response{
+		Status:  true,
+		Message: "Custom field created successfully",
+		Data:    field,
+	}
+	ctx.JSON(201, res)
+}
+
+// UpdateCustomField godoc
+// @Summary Update a custom field
+// @Description Changes the name, enum options or requirement of a custom field, its key and type cannot change. Omitted fields are kept.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param field body types.UpdateCustomFieldRequest true "Custom field changes"
+// @Success 200 {object} types.Response{data=types.CustomFieldDefinition}
+// @Failure 400 {object} types.Response
+// @Failure 401 {object} types.Response
+// @Security BearerAuth
+// @Router /tasks/fields/update [post]
+func (h *taskHandler) UpdateCustomField(ctx *gin.Context) {
+	req := &types.UpdateCustomFieldRequest{}
+	if err := ctx.ShouldBindJSON(req); err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: err.Error(),
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	field, err := h.taskService.UpdateCustomField(ctx, req)
+	if err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: err.Error(),
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	res := types.Response{
+		Status:  true,
+		Message: "Custom field updated successfully",
+		Data:    field,
+	}
+	ctx.JSON(200, res)
+}
+
+// DeleteCustomField godoc
+// @Summary Delete a custom field
+// @Description Deletes a custom field and removes its values from the workspace tasks
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param id path string true "Custom field ID"
+// @Success 200 {object} types.Response
+// @Failure 400 {object} types.Response
+// @Failure 401 {object} types.Response
+// @Security BearerAuth
+// @Router /tasks/fields/delete/{id} [post]
+func (h *taskHandler) DeleteCustomField(ctx *gin.Context) {
+	err := h.taskService.DeleteCustomField(ctx, ctx.Param("id"))
+	if err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: err.Error(),
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	res := types.Response{
+		Status:  true,
+		Message: "Custom field deleted successfully",
+	}
+	ctx.JSON(200, res)
+}
+
+// GetPageRequest reads the page, limit and cursor query parameters, a
+// cursor selects the page after the position it was issued for
+func GetPageRequest(c *gin.Context) types.PageRequest {
+	page, limit := GetPaginationParams(c)
+	return types.PageRequest{
+		Page:   page,
+		Limit:  limit,
+		Cursor: c.Query("cursor"),
+	}
+}
+
+func GetPaginationParams(c *gin.Context) (page int64, limit int64) {
+	// Default values
+	page = 1
+	limit = 10
+
+	// Try to parse page parameter
+	pageStr := c.DefaultQuery("page", "1")
+	if pageVal, err := strconv.ParseInt(pageStr, 10, 64); err == nil && pageVal > 0 {
+		page = pageVal
+	}
+
+	// Try to parse limit parameter
+	limitStr := c.DefaultQuery("limit", "10")
+	if limitVal, err := strconv.ParseInt(limitStr, 10, 64); err == nil && limitVal > 0 {
+		limit = limitVal
+	}
+
+	return page, limit
+}
+
+// bindCommentRequest reads a comment request sent as JSON, or as a multipart
+// form with the request in metadata and the files in attachments
+func bindCommentRequest(ctx *gin.Context, req interface{}) ([]*multipart.FileHeader, error) {
+	if ctx.ContentType() != "multipart/form-data" {
+		if err := ctx.ShouldBindJSON(req); err != nil {
+			return nil, err
+		}
+		return nil, nil
+	}
+	form, err := ctx.MultipartForm()
+	if err != nil {
+		return nil, fmt.Errorf("Invalid request: unable to parse multipart form")
+	}
+	if metadata := ctx.PostForm("metadata"); metadata != "" {
+		if err := json.Unmarshal([]byte(metadata), req); err != nil {
+			return nil, fmt.Errorf("Invalid metadata format")
+		}
+	}
+	return form.File["attachments"], nil
+}
+
+// taskFilterFromQuery reads the filter query parameters shared by the task
+// listing endpoints, the user filters only ever match the current user
+func taskFilterFromQuery(ctx *gin.Context, userID string) (*types.TaskFilter, error) {
+	filter := &types.TaskFilter{
+		Title:  ctx.Query("title"),
+		Status: ctx.Query("status"),
+	}
+	timestamps := []struct {
+		name  string
+		value *int64
+	}{
+		{"deadlineFrom", &filter.DeadlineFrom},
+		{"deadlineTo", &filter.DeadlineTo},
+		{"startFrom", &filter.StartFrom},
+		{"startTo", &filter.StartTo},
+		{"reportFrom", &filter.ReportFrom},
+		{"reportTo", &filter.ReportTo},
+	}
+	for _, timestamp := range timestamps {
+		if ctx.Query(timestamp.name) == "" {
+			continue
+		}
+		value, err := strconv.ParseInt(ctx.Query(timestamp.name), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid %s parameter", timestamp.name)
+		}
+		*timestamp.value = value
+	}
+	if ctx.Query("assignee") != "" {
+		filter.Assignee = userID
+	}
+	if ctx.Query("primaryAssignee") != "" {
+		filter.PrimaryAssignee = userID
+	}
+	if ctx.Query("watcher") != "" {
+		filter.Watcher = userID
+	}
+	if ctx.Query("creator") != "" {
+		filter.Creator = userID
+	}
+	filter.Search = strings.TrimSpace(ctx.Query("search"))
+	filter.Statuses = queryList(ctx, "statuses")
+	filter.Assignees = queryList(ctx, "assignees")
+	filter.Tags = queryList(ctx, "tags")
+	for _, priority := range queryList(ctx, "priorities") {
+		value, err := strconv.Atoi(priority)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid priorities parameter")
+		}
+		filter.Priorities = append(filter.Priorities, value)
+	}
+	bounds := []struct {
+		name  string
+		value **int
+	}{
+		{"progressMin", &filter.ProgressMin},
+		{"progressMax", &filter.ProgressMax},
+	}
+	for _, bound := range bounds {
+		if ctx.Query(bound.name) == "" {
+			continue
+		}
+		value, err := strconv.Atoi(ctx.Query(bound.name))
+		if err != nil {
+			return nil, fmt.Errorf("Invalid %s parameter", bound.name)
+		}
+		*bound.value = &value
+	}
+	filter.Overdue = ctx.Query("overdue") == "true"
+	filter.NoReports = ctx.Query("noReports") == "true"
+	filter.CustomFields = customFieldFiltersFromQuery(ctx)
+	// sort is a list of field:direction, the direction defaults to asc
+	for _, key := range queryList(ctx, "sort") {
+		field, direction, _ := strings.Cut(key, ":")
+		if direction != "" && direction != "asc" && direction != "desc" {
+			return nil, fmt.Errorf("Invalid sort parameter")
+		}
+		filter.Sort = append(filter.Sort, types.TaskSort{
+			Field: field,
+			Desc:  direction == "desc",
+		})
+	}
+	return filter, nil
+}
+
+// reportFilterFromQuery reads the report filter query parameters, each
+// name prefixed with prefix
+func reportFilterFromQuery(ctx *gin.Context, prefix string) (*types.ReportFilter, error) {
+	filter := &types.ReportFilter{
+		TaskID:  ctx.Query(prefix + "taskId"),
+		Creator: ctx.Query(prefix + "creator"),
+		Status:  ctx.Query(prefix + "status"),
+	}
+	timestamps := []struct {
+		name  string
+		value *int64
+	}{
+		{prefix + "createdFrom", &filter.CreatedFrom},
+		{prefix + "createdTo", &filter.CreatedTo},
+	}
+	for _, timestamp := range timestamps {
+		if ctx.Query(timestamp.name) == "" {
+			continue
+		}
+		value, err := strconv.ParseInt(ctx.Query(timestamp.name), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid %s parameter", timestamp.name)
+		}
+		*timestamp.value = value
+	}
+	return filter, nil
+}
+
+// customFieldFiltersFromQuery reads the field.<key> equality and the
+// field.<key>.from and field.<key>.to range parameters, the service types
+// the values after the field definitions
+func customFieldFiltersFromQuery(ctx *gin.Context) []types.CustomFieldFilter {
+	names := make([]string, 0)
+	for name := range ctx.Request.URL.Query() {
+		if strings.HasPrefix(name, "field.") {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	filters := make([]types.CustomFieldFilter, 0)
+	indexes := make(map[string]int)
+	for _, name := range names {
+		value := ctx.Query(name)
+		if value == "" {
+			continue
+		}
+		key := strings.TrimPrefix(name, "field.")
+		bound := ""
+		if trimmed, ok := strings.CutSuffix(key, ".from"); ok {
+			key, bound = trimmed, "from"
+		} else if trimmed, ok := strings.CutSuffix(key, ".to"); ok {
+			key, bound = trimmed, "to"
+		}
+		index, ok := indexes[key]
+		if !ok {
+			index = len(filters)
+			indexes[key] = index
+			filters = append(filters, types.CustomFieldFilter{Key: key})
+		}
+		switch bound {
+		case "from":
+			filters[index].From = value
+		case "to":
+			filters[index].To = value
+		default:
+			filters[index].Equals = value
+		}
+	}
+	return filters
+}
+
+// queryList splits a comma separated query parameter, empty values are
+// dropped
+func queryList(ctx *gin.Context, name string) []string {
+	values := make([]string, 0)
+	for _, value := range strings.Split(ctx.Query(name), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	if len(values) == 0 {
+		return nil
+	}
+	return values
 }
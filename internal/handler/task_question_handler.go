@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/remiehneppo/be-task-management/internal/logger"
+	"github.com/remiehneppo/be-task-management/internal/service"
+	"github.com/remiehneppo/be-task-management/types"
+)
+
+type TaskQuestionHandler interface {
+	AskTasks(ctx *gin.Context)
+}
+
+type taskQuestionHandler struct {
+	taskQuestionService service.TaskQuestionService
+	logger              *logger.Logger
+}
+
+func NewTaskQuestionHandler(
+	taskQuestionService service.TaskQuestionService,
+	logger *logger.Logger,
+) TaskQuestionHandler {
+	return &taskQuestionHandler{
+		taskQuestionService: taskQuestionService,
+		logger:              logger,
+	}
+}
+
+// AskTasks godoc
+// @Summary Ask a question about tasks and reports
+// @Description Answers a question in natural language about the tasks and reports of the workspace, such as "which tasks were delayed by supplier issues last quarter?". The AI reads the question as a task filter (statuses, assignees, priorities, overdue, dates) and a text searched by meaning, the matching tasks and reports are searched and the answer cites them as their ID in brackets. The filter read and the cited tasks and reports are returned with the answer.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param request body types.AskTasksRequest true "Question"
+// @Success 200 {object} types.Response{data=types.AskTasksResponse}
+// @Failure 400 {object} types.Response
+// @Failure 401 {object} types.Response
+// @Security BearerAuth
+// @Router /tasks/ask [post]
+func (h *taskQuestionHandler) AskTasks(ctx *gin.Context) {
+	var req types.AskTasksRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: "Invalid request",
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	answer, err := h.taskQuestionService.AskTasks(ctx, &req)
+	if err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: err.Error(),
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	res := types.Response{
+		Status:  true,
+		Message: "Question answered successfully",
+		Data:    answer,
+	}
+	ctx.JSON(200, res)
+}
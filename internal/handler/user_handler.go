@@ -114,11 +114,14 @@ func (h *userHandler) UpdatePassword(ctx *gin.Context) {
 
 // GetUsersSameWorkspace godoc
 // @Summary Get users in the same workspace
-// @Description Returns a list of users in the same workspace as the authenticated user
+// @Description Returns the users in the same workspace as the authenticated user. Without page, limit or cursor every user is returned as a plain list, otherwise a page of users ordered by name.
 // @Tags users
 // @Accept json
 // @Produce json
-// @Success 200 {object} types.Response "Users retrieved successfully"
+// @Param page query int false "Page number (default: 1)"
+// @Param limit query int false "Items per page (default: 10)"
+// @Param cursor query string false "Cursor of the next page, from next_cursor of the previous response. Takes precedence over page."
+// @Success 200 {object} types.PaginatedResponse{data=types.PaginatedData{items=[]types.User}} "Users retrieved successfully, data is a plain list of users when not paginating"
 // @Failure 400 {object} types.Response "Invalid request format"
 // @Failure 401 {object} types.Response "Unauthorized or invalid token"
 // @Security BearerAuth
@@ -143,6 +146,33 @@ func (h *userHandler) GetUsersSameWorkspace(ctx *gin.Context) {
 		return
 	}
 
+	// the plain list is kept for the clients written before pagination
+	if ctx.Query("page") != "" || ctx.Query("limit") != "" || ctx.Query("cursor") != "" {
+		page := GetPageRequest(ctx)
+		users, total, nextCursor, err := h.userService.PaginateUsersInWorkspace(ctx, user.Workspace, page)
+		if err != nil {
+			res := types.Response{
+				Status:  false,
+				Message: err.Error(),
+			}
+			ctx.JSON(400, res)
+			return
+		}
+		res := types.PaginatedResponse{
+			Status:  true,
+			Message: "Users retrieved successfully",
+			Data: types.PaginatedData{
+				Items:      users,
+				Total:      total,
+				Limit:      page.Limit,
+				Page:       page.Page,
+				NextCursor: nextCursor,
+			},
+		}
+		ctx.JSON(200, res)
+		return
+	}
+
 	users, err := h.userService.GetUsersInWorkspace(ctx, user.Workspace)
 	if err != nil {
 		res := types.Response{
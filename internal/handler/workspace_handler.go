@@ -0,0 +1,168 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/remiehneppo/be-task-management/internal/logger"
+	"github.com/remiehneppo/be-task-management/internal/service"
+	"github.com/remiehneppo/be-task-management/types"
+)
+
+type WorkspaceHandler interface {
+	GetWorkspaces(ctx *gin.Context)
+	CreateWorkspace(ctx *gin.Context)
+	UpdateWorkspace(ctx *gin.Context)
+	DeleteWorkspace(ctx *gin.Context)
+}
+
+type workspaceHandler struct {
+	workspaceService service.WorkspaceService
+	logger           *logger.Logger
+}
+
+func NewWorkspaceHandler(
+	workspaceService service.WorkspaceService,
+	logger *logger.Logger,
+) WorkspaceHandler {
+	return &workspaceHandler{
+		workspaceService: workspaceService,
+		logger:           logger,
+	}
+}
+
+// GetWorkspaces godoc
+// @Summary Get workspaces
+// @Description Returns the workspaces by name. Users and tasks refer to a workspace by its name.
+// @Tags workspaces
+// @Accept json
+// @Produce json
+// @Success 200 {object} types.Response{data=[]types.Workspace}
+// @Failure 400 {object} types.Response
+// @Failure 401 {object} types.Response
+// @Security BearerAuth
+// @Router /workspaces [get]
+func (h *workspaceHandler) GetWorkspaces(ctx *gin.Context) {
+	workspaces, err := h.workspaceService.GetWorkspaces(ctx)
+	if err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: err.Error(),
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	res := types.Response{
+		Status:  true,
+		Message: "Workspaces retrieved successfully",
+		Data:    workspaces,
+	}
+	ctx.JSON(200, res)
+}
+
+// CreateWorkspace godoc
+// @Summary Create a workspace
+// @Description Creates a workspace. The name starts with a letter, has 2 to 64 letters, digits, dashes or underscores and cannot change, the display name defaults to it. Audit-logged. Requires workspace.manage.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body types.CreateWorkspaceRequest true "Workspace"
+// @Success 200 {object} types.Response{data=types.Workspace}
+// @Failure 400 {object} types.Response
+// @Failure 401 {object} types.Response
+// @Failure 403 {object} types.Response
+// @Security BearerAuth
+// @Router /admin/workspaces/create [post]
+func (h *workspaceHandler) CreateWorkspace(ctx *gin.Context) {
+	var req types.CreateWorkspaceRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: "Invalid request",
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	workspace, err := h.workspaceService.CreateWorkspace(ctx, &req)
+	if err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: err.Error(),
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	res := types.Response{
+		Status:  true,
+		Message: "Workspace created successfully",
+		Data:    workspace,
+	}
+	ctx.JSON(201, res)
+}
+
+// UpdateWorkspace godoc
+// @Summary Update a workspace
+// @Description Updates the display name and the description of a workspace, an empty display name is reset to the name. Audit-logged. Requires workspace.manage.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body types.UpdateWorkspaceRequest true "Workspace"
+// @Success 200 {object} types.Response{data=types.Workspace}
+// @Failure 400 {object} types.Response
+// @Failure 401 {object} types.Response
+// @Failure 403 {object} types.Response
+// @Security BearerAuth
+// @Router /admin/workspaces/update [post]
+func (h *workspaceHandler) UpdateWorkspace(ctx *gin.Context) {
+	var req types.UpdateWorkspaceRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: "Invalid request",
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	workspace, err := h.workspaceService.UpdateWorkspace(ctx, &req)
+	if err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: err.Error(),
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	res := types.Response{
+		Status:  true,
+		Message: "Workspace updated successfully",
+		Data:    workspace,
+	}
+	ctx.JSON(200, res)
+}
+
+// DeleteWorkspace godoc
+// @Summary Delete a workspace
+// @Description Deletes a workspace without users nor tasks. Audit-logged. Requires workspace.manage.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Workspace ID"
+// @Success 200 {object} types.Response
+// @Failure 400 {object} types.Response
+// @Failure 401 {object} types.Response
+// @Failure 403 {object} types.Response
+// @Security BearerAuth
+// @Router /admin/workspaces/delete/{id} [post]
+func (h *workspaceHandler) DeleteWorkspace(ctx *gin.Context) {
+	if err := h.workspaceService.DeleteWorkspace(ctx, ctx.Param("id")); err != nil {
+		res := types.Response{
+			Status:  false,
+			Message: err.Error(),
+		}
+		ctx.JSON(400, res)
+		return
+	}
+	res := types.Response{
+		Status:  true,
+		Message: "Workspace deleted successfully",
+	}
+	ctx.JSON(200, res)
+}
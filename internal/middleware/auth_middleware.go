@@ -4,6 +4,7 @@ import (
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/remiehneppo/be-task-management/internal/repository"
 	"github.com/remiehneppo/be-task-management/internal/service"
 	"github.com/remiehneppo/be-task-management/types"
 )
@@ -12,11 +13,13 @@ const BearerPrefix = "bearer "
 
 type AuthMiddleware struct {
 	jwtService service.JWTService
+	userRepo   repository.UserRepository
 }
 
-func NewAuthMiddleware(jwtService service.JWTService) *AuthMiddleware {
+func NewAuthMiddleware(jwtService service.JWTService, userRepo repository.UserRepository) *AuthMiddleware {
 	return &AuthMiddleware{
 		jwtService: jwtService,
+		userRepo:   userRepo,
 	}
 }
 
@@ -59,7 +62,42 @@ func (a *AuthMiddleware) AuthBearerMiddleware() gin.HandlerFunc {
 			return
 		}
 
+		// tokens stay valid until they expire, a deactivated or deleted user
+		// is refused at once
+		account, err := a.userRepo.FindByID(ctx, user.ID)
+		if err != nil {
+			res := types.Response{
+				Status:  false,
+				Message: "Invalid credentials",
+			}
+			ctx.JSON(401, res)
+			ctx.Abort()
+			return
+		}
+		if account.Deactivated {
+			res := types.Response{
+				Status:  false,
+				Message: types.ErrUserDeactivated.Error(),
+			}
+			ctx.JSON(401, res)
+			ctx.Abort()
+			return
+		}
+
 		ctx.Set("user_id", user.ID)
 		ctx.Next()
 	}
 }
+
+// AuthStreamMiddleware accepts the access token from the token query parameter
+// when there is no Authorization header, EventSource and browser WebSockets
+// cannot set headers
+func (a *AuthMiddleware) AuthStreamMiddleware() gin.HandlerFunc {
+	authBearer := a.AuthBearerMiddleware()
+	return func(ctx *gin.Context) {
+		if ctx.GetHeader("Authorization") == "" && ctx.Query("token") != "" {
+			ctx.Request.Header.Set("Authorization", "Bearer "+ctx.Query("token"))
+		}
+		authBearer(ctx)
+	}
+}
@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/remiehneppo/be-task-management/internal/service"
+	"github.com/remiehneppo/be-task-management/types"
+)
+
+type PermissionMiddleware struct {
+	authorizationService service.AuthorizationService
+}
+
+func NewPermissionMiddleware(authorizationService service.AuthorizationService) *PermissionMiddleware {
+	return &PermissionMiddleware{
+		authorizationService: authorizationService,
+	}
+}
+
+// RequirePermission lets the request through when the role of the user
+// grants the permission, it runs after AuthBearerMiddleware
+func (m *PermissionMiddleware) RequirePermission(permission string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		_, err := m.authorizationService.Authorize(ctx, ctx.GetString("user_id"), permission)
+		if errors.Is(err, types.ErrPermissionDenied) {
+			res := types.Response{
+				Status:  false,
+				Message: err.Error(),
+			}
+			ctx.JSON(403, res)
+			ctx.Abort()
+			return
+		}
+		if err != nil {
+			res := types.Response{
+				Status:  false,
+				Message: "Invalid credentials",
+			}
+			ctx.JSON(401, res)
+			ctx.Abort()
+			return
+		}
+		ctx.Next()
+	}
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/remiehneppo/be-task-management/internal/database"
+	"github.com/remiehneppo/be-task-management/types"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+const AuditLogCollection = "audit_logs"
+
+var _ AuditLogRepository = (*auditLogRepository)(nil)
+
+// AuditLogRepository is append-only, audit logs are never updated or deleted
+type AuditLogRepository interface {
+	Save(ctx context.Context, log *types.AuditLog) error
+	// Paginate returns the audit logs matching filter, latest first
+	Paginate(ctx context.Context, filter types.AuditLogFilter, page, limit int64) ([]*types.AuditLog, int64, error)
+}
+
+type auditLogRepository struct {
+	database   database.Database
+	collection string
+}
+
+func NewAuditLogRepository(db database.Database) AuditLogRepository {
+	return &auditLogRepository{
+		database:   db,
+		collection: AuditLogCollection,
+	}
+}
+
+func (r *auditLogRepository) Save(ctx context.Context, log *types.AuditLog) error {
+	id, err := r.database.Insert(ctx, r.collection, log)
+	if err != nil {
+		return err
+	}
+	log.ID = id
+	return nil
+}
+
+func (r *auditLogRepository) Paginate(ctx context.Context, filter types.AuditLogFilter, page, limit int64) ([]*types.AuditLog, int64, error) {
+	query := bson.M{}
+	if filter.Actor != "" {
+		query["actor"] = filter.Actor
+	}
+	if filter.Action != "" {
+		query["action"] = filter.Action
+	}
+	if filter.TargetType != "" {
+		query["target_type"] = filter.TargetType
+	}
+	if filter.TargetID != "" {
+		query["target_id"] = filter.TargetID
+	}
+	total, err := r.database.Count(ctx, r.collection, query)
+	if err != nil {
+		return nil, 0, err
+	}
+	var skip int64 = 0
+	if page > 0 {
+		skip = (page - 1) * limit
+	}
+	logs := make([]*types.AuditLog, 0)
+	err = r.database.Query(ctx, r.collection, query, skip, limit, bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}, &logs)
+	if err != nil {
+		return nil, 0, err
+	}
+	return logs, total, nil
+}
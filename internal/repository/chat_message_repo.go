@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"slices"
+
+	"github.com/remiehneppo/be-task-management/internal/database"
+	"github.com/remiehneppo/be-task-management/types"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+const ChatMessageCollection = "chat_messages"
+
+// chatMessagePageSort lists the newest messages first, the _id tiebreaker
+// makes positions unique for cursors
+var chatMessagePageSort = bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}
+
+var _ ChatMessageRepository = (*chatMessageRepository)(nil)
+
+type ChatMessageRepository interface {
+	Save(ctx context.Context, message *types.ChatMessage) error
+	// FindRecent returns the latest messages of the chat, oldest first
+	FindRecent(ctx context.Context, userID, chatID string, limit int64) ([]*types.ChatMessage, error)
+	// Paginate returns a page of the chat messages, newest first, the message
+	// count and the cursor of the next page when there is one
+	Paginate(ctx context.Context, userID, chatID string, page types.PageRequest) ([]*types.ChatMessage, int64, string, error)
+}
+
+type chatMessageRepository struct {
+	database   database.Database
+	collection string
+}
+
+func NewChatMessageRepository(db database.Database) *chatMessageRepository {
+	return &chatMessageRepository{
+		database:   db,
+		collection: ChatMessageCollection,
+	}
+}
+
+func (r *chatMessageRepository) Save(ctx context.Context, message *types.ChatMessage) error {
+	id, err := r.database.Insert(ctx, r.collection, message)
+	if err != nil {
+		return err
+	}
+	message.ID = id
+	return nil
+}
+
+func (r *chatMessageRepository) FindRecent(ctx context.Context, userID, chatID string, limit int64) ([]*types.ChatMessage, error) {
+	messages := make([]*types.ChatMessage, 0)
+	filter := bson.M{"user_id": userID, "chat_id": chatID}
+	err := r.database.Query(ctx, r.collection, filter, 0, limit, chatMessagePageSort, &messages)
+	if err != nil {
+		return nil, err
+	}
+	slices.Reverse(messages)
+	return messages, nil
+}
+
+func (r *chatMessageRepository) Paginate(ctx context.Context, userID, chatID string, page types.PageRequest) ([]*types.ChatMessage, int64, string, error) {
+	messages := make([]*types.ChatMessage, 0)
+	filter := bson.M{"user_id": userID, "chat_id": chatID}
+	totalCount, err := r.database.Count(ctx, r.collection, filter)
+	if err != nil {
+		return nil, 0, "", err
+	}
+
+	skip := pageSkip(page)
+	if page.Cursor != "" {
+		values, err := decodeCursor(chatMessagePageSort, page.Cursor)
+		if err != nil {
+			return nil, 0, "", err
+		}
+		filter = bson.M{"$and": []bson.M{filter, cursorMatch(chatMessagePageSort, values)}}
+		skip = 0
+	}
+	// one more message than the page tells whether a next page exists
+	err = r.database.Query(ctx, r.collection, filter, skip, page.Limit+1, chatMessagePageSort, &messages)
+	if err != nil {
+		return nil, 0, "", err
+	}
+	nextCursor := ""
+	if int64(len(messages)) > page.Limit {
+		messages = messages[:page.Limit]
+		nextCursor, err = encodeItemCursor(chatMessagePageSort, messages[len(messages)-1])
+		if err != nil {
+			return nil, 0, "", err
+		}
+	}
+	return messages, totalCount, nextCursor, nil
+}
@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/remiehneppo/be-task-management/internal/database"
+	"github.com/remiehneppo/be-task-management/types"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+const CommentCollection = "comments"
+
+// commentPageSort orders threads oldest first, the way a discussion reads
+var commentPageSort = bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}}
+
+var _ CommentRepository = (*commentRepository)(nil)
+
+type CommentRepository interface {
+	Save(ctx context.Context, comment *types.Comment) error
+	FindByID(ctx context.Context, id string) (*types.Comment, error)
+	Update(ctx context.Context, id string, comment *types.Comment) error
+	// PaginateThreads pages through the first comments of the threads of a
+	// task, or of a report when reportID is set
+	PaginateThreads(ctx context.Context, taskID, reportID string, page types.PageRequest) ([]*types.Comment, int64, string, error)
+	// FindReplies returns the replies of the threads, oldest first
+	FindReplies(ctx context.Context, parentIDs []string) ([]*types.Comment, error)
+	// CountByAuthor counts the comments of the author, deleted ones included
+	CountByAuthor(ctx context.Context, author string) (int64, error)
+	// FindByTaskID returns every comment of the task, those of its reports
+	// included, or only those of the report when reportID is set
+	FindByTaskID(ctx context.Context, taskID, reportID string) ([]*types.Comment, error)
+	DeleteByTaskID(ctx context.Context, taskID string) error
+	DeleteByReportID(ctx context.Context, reportID string) error
+}
+
+type commentRepository struct {
+	database   database.Database
+	collection string
+}
+
+func NewCommentRepository(db database.Database) CommentRepository {
+	return &commentRepository{
+		database:   db,
+		collection: CommentCollection,
+	}
+}
+
+func (r *commentRepository) Save(ctx context.Context, comment *types.Comment) error {
+	id, err := r.database.Insert(ctx, r.collection, comment)
+	if err != nil {
+		return err
+	}
+	comment.ID = id
+	return nil
+}
+
+func (r *commentRepository) FindByID(ctx context.Context, id string) (*types.Comment, error) {
+	var comment types.Comment
+	err := r.database.FindByID(ctx, r.collection, id, &comment)
+	if err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+func (r *commentRepository) Update(ctx context.Context, id string, comment *types.Comment) error {
+	return r.database.Update(ctx, r.collection, id, comment)
+}
+
+func (r *commentRepository) PaginateThreads(ctx context.Context, taskID, reportID string, page types.PageRequest) ([]*types.Comment, int64, string, error) {
+	// task comments have no report ID, a null match also finds missing fields
+	filter := bson.M{"task_id": taskID, "parent_id": nil, "report_id": nil}
+	if reportID != "" {
+		filter["report_id"] = reportID
+	}
+	total, err := r.database.Count(ctx, r.collection, filter)
+	if err != nil {
+		return nil, 0, "", err
+	}
+	skip := pageSkip(page)
+	if page.Cursor != "" {
+		values, err := decodeCursor(commentPageSort, page.Cursor)
+		if err != nil {
+			return nil, 0, "", err
+		}
+		filter = bson.M{"$and": []bson.M{filter, cursorMatch(commentPageSort, values)}}
+		skip = 0
+	}
+	// one more comment than the page tells whether a next page exists
+	comments := make([]*types.Comment, 0)
+	err = r.database.Query(ctx, r.collection, filter, skip, page.Limit+1, commentPageSort, &comments)
+	if err != nil {
+		return nil, 0, "", err
+	}
+	nextCursor := ""
+	if int64(len(comments)) > page.Limit {
+		comments = comments[:page.Limit]
+		nextCursor, err = encodeItemCursor(commentPageSort, comments[len(comments)-1])
+		if err != nil {
+			return nil, 0, "", err
+		}
+	}
+	return comments, total, nextCursor, nil
+}
+
+func (r *commentRepository) FindReplies(ctx context.Context, parentIDs []string) ([]*types.Comment, error) {
+	replies := make([]*types.Comment, 0)
+	if len(parentIDs) == 0 {
+		return replies, nil
+	}
+	err := r.database.Query(ctx, r.collection, bson.M{"parent_id": bson.M{"$in": parentIDs}}, 0, 0, commentPageSort, &replies)
+	if err != nil {
+		return nil, err
+	}
+	return replies, nil
+}
+
+func (r *commentRepository) CountByAuthor(ctx context.Context, author string) (int64, error) {
+	return r.database.Count(ctx, r.collection, bson.M{"author": author})
+}
+
+func (r *commentRepository) FindByTaskID(ctx context.Context, taskID, reportID string) ([]*types.Comment, error) {
+	filter := bson.M{"task_id": taskID}
+	if reportID != "" {
+		filter["report_id"] = reportID
+	}
+	comments := make([]*types.Comment, 0)
+	err := r.database.Query(ctx, r.collection, filter, 0, 0, commentPageSort, &comments)
+	if err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
+
+func (r *commentRepository) DeleteByTaskID(ctx context.Context, taskID string) error {
+	return r.database.DeleteMany(ctx, r.collection, bson.M{"task_id": taskID})
+}
+
+func (r *commentRepository) DeleteByReportID(ctx context.Context, reportID string) error {
+	return r.database.DeleteMany(ctx, r.collection, bson.M{"report_id": reportID})
+}
@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/remiehneppo/be-task-management/types"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// pageCursor is the position after the last item of a page, the values of
+// its sort keys in order. The keys are kept to reject cursors issued for a
+// different sort.
+type pageCursor struct {
+	Keys   []string      `json:"k"`
+	Values []interface{} `json:"v"`
+}
+
+// cursorKeys names the keys and directions of a sort, the sort must end
+// with _id so that every position is unique
+func cursorKeys(sort bson.D) []string {
+	keys := make([]string, 0, len(sort))
+	for _, key := range sort {
+		keys = append(keys, fmt.Sprintf("%s:%v", key.Key, key.Value))
+	}
+	return keys
+}
+
+// encodeCursor returns the opaque cursor pointing after doc
+func encodeCursor(sort bson.D, doc bson.Raw) (string, error) {
+	cursor := pageCursor{
+		Keys:   cursorKeys(sort),
+		Values: make([]interface{}, 0, len(sort)),
+	}
+	for _, key := range sort {
+		var value interface{}
+		if rawValue, err := doc.LookupErr(key.Key); err == nil {
+			if err := rawValue.Unmarshal(&value); err != nil {
+				return "", err
+			}
+		}
+		if id, ok := value.(bson.ObjectID); ok {
+			value = id.Hex()
+		}
+		cursor.Values = append(cursor.Values, value)
+	}
+	data, err := json.Marshal(cursor)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// encodeItemCursor returns the cursor pointing after a decoded item
+func encodeItemCursor(sort bson.D, item interface{}) (string, error) {
+	doc, err := bson.Marshal(item)
+	if err != nil {
+		return "", err
+	}
+	return encodeCursor(sort, doc)
+}
+
+// decodeCursor returns the values of the sort keys stored in the cursor
+func decodeCursor(sort bson.D, encoded string) ([]interface{}, error) {
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, types.ErrInvalidCursor
+	}
+	var cursor pageCursor
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&cursor); err != nil {
+		return nil, types.ErrInvalidCursor
+	}
+	keys := cursorKeys(sort)
+	if len(cursor.Keys) != len(keys) || len(cursor.Values) != len(keys) {
+		return nil, types.ErrInvalidCursor
+	}
+	for i, key := range keys {
+		if cursor.Keys[i] != key {
+			return nil, types.ErrInvalidCursor
+		}
+		switch value := cursor.Values[i].(type) {
+		case json.Number:
+			// timestamps must stay integers to compare like the stored values
+			if number, err := value.Int64(); err == nil {
+				cursor.Values[i] = number
+			} else if number, err := value.Float64(); err == nil {
+				cursor.Values[i] = number
+			} else {
+				return nil, types.ErrInvalidCursor
+			}
+		case string:
+			if sort[i].Key == "_id" {
+				id, err := bson.ObjectIDFromHex(value)
+				if err != nil {
+					return nil, types.ErrInvalidCursor
+				}
+				cursor.Values[i] = id
+			}
+		}
+	}
+	return cursor.Values, nil
+}
+
+// cursorMatch matches the documents sorted after the cursor values. It is
+// the usual keyset condition, equal on the leading keys and past the value
+// on the next one. Missing values sort first, as null.
+func cursorMatch(sort bson.D, values []interface{}) bson.M {
+	branches := make([]bson.M, 0, len(sort))
+	for i, key := range sort {
+		branch := make([]bson.M, 0, i+1)
+		for j := 0; j < i; j++ {
+			branch = append(branch, bson.M{sort[j].Key: values[j]})
+		}
+		descending := fmt.Sprint(key.Value) == "-1"
+		switch {
+		case values[i] == nil && descending:
+			// nothing sorts after null in descending order
+			continue
+		case values[i] == nil:
+			branch = append(branch, bson.M{key.Key: bson.M{"$ne": nil}})
+		case descending:
+			branch = append(branch, bson.M{"$or": []bson.M{
+				{key.Key: bson.M{"$lt": values[i]}},
+				{key.Key: nil},
+			}})
+		default:
+			branch = append(branch, bson.M{key.Key: bson.M{"$gt": values[i]}})
+		}
+		branches = append(branches, bson.M{"$and": branch})
+	}
+	if len(branches) == 0 {
+		// only reachable with a malformed sort, match nothing
+		return bson.M{"_id": bson.M{"$exists": false}}
+	}
+	return bson.M{"$or": branches}
+}
+
+// pageSkip is the number of documents before a numbered page
+func pageSkip(page types.PageRequest) int64 {
+	if page.Page > 1 {
+		return (page.Page - 1) * page.Limit
+	}
+	return 0
+}
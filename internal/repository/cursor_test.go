@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"encoding/base64"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/remiehneppo/be-task-management/types"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type cursorItem struct {
+	ID       string  `bson:"_id,omitempty"`
+	Title    string  `bson:"title"`
+	Priority int64   `bson:"priority"`
+	Deadline int64   `bson:"deadline"`
+	Score    float64 `bson:"score"`
+	Parent   string  `bson:"parent,omitempty"`
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	id := bson.NewObjectID()
+	item := struct {
+		ID       bson.ObjectID `bson:"_id"`
+		Title    string        `bson:"title"`
+		Priority int64         `bson:"priority"`
+		Deadline int64         `bson:"deadline"`
+		Score    float64       `bson:"score"`
+	}{ID: id, Title: "Báo cáo quý", Priority: 3, Deadline: 1714532800, Score: 0.75}
+
+	tests := []struct {
+		name string
+		sort bson.D
+		want []interface{}
+	}{
+		{
+			name: "integers keep their type",
+			sort: bson.D{{Key: "deadline", Value: 1}, {Key: "_id", Value: 1}},
+			want: []interface{}{int64(1714532800), id},
+		},
+		{
+			name: "mixed directions",
+			sort: bson.D{{Key: "priority", Value: -1}, {Key: "title", Value: 1}, {Key: "_id", Value: -1}},
+			want: []interface{}{int64(3), "Báo cáo quý", id},
+		},
+		{
+			name: "fractions stay floats",
+			sort: bson.D{{Key: "score", Value: -1}, {Key: "_id", Value: 1}},
+			want: []interface{}{0.75, id},
+		},
+		{
+			name: "missing values are null",
+			sort: bson.D{{Key: "parent", Value: 1}, {Key: "_id", Value: 1}},
+			want: []interface{}{nil, id},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cursor, err := encodeItemCursor(tt.sort, item)
+			if err != nil {
+				t.Fatalf("encodeItemCursor: %v", err)
+			}
+			values, err := decodeCursor(tt.sort, cursor)
+			if err != nil {
+				t.Fatalf("decodeCursor: %v", err)
+			}
+			if !reflect.DeepEqual(values, tt.want) {
+				t.Errorf("decodeCursor = %#v, want %#v", values, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeCursorRejects(t *testing.T) {
+	sort := bson.D{{Key: "deadline", Value: 1}, {Key: "_id", Value: 1}}
+	valid, err := encodeItemCursor(sort, cursorItem{ID: bson.NewObjectID().Hex(), Deadline: 10})
+	if err != nil {
+		t.Fatalf("encodeItemCursor: %v", err)
+	}
+	encode := func(json string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(json))
+	}
+
+	tests := []struct {
+		name   string
+		sort   bson.D
+		cursor string
+	}{
+		{name: "not base64", sort: sort, cursor: "%%%"},
+		{name: "not json", sort: sort, cursor: encode("{")},
+		{name: "other keys", sort: bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}}, cursor: valid},
+		{name: "other direction", sort: bson.D{{Key: "deadline", Value: -1}, {Key: "_id", Value: 1}}, cursor: valid},
+		{name: "missing values", sort: sort, cursor: encode(`{"k":["deadline:1","_id:1"],"v":[10]}`)},
+		{name: "invalid id", sort: sort, cursor: encode(`{"k":["deadline:1","_id:1"],"v":[10,"nope"]}`)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := decodeCursor(tt.sort, tt.cursor); !errors.Is(err, types.ErrInvalidCursor) {
+				t.Errorf("decodeCursor error = %v, want %v", err, types.ErrInvalidCursor)
+			}
+		})
+	}
+}
+
+func TestCursorMatch(t *testing.T) {
+	id := bson.NewObjectID()
+
+	tests := []struct {
+		name   string
+		sort   bson.D
+		values []interface{}
+		want   bson.M
+	}{
+		{
+			name:   "ascending",
+			sort:   bson.D{{Key: "deadline", Value: 1}, {Key: "_id", Value: 1}},
+			values: []interface{}{int64(10), id},
+			want: bson.M{"$or": []bson.M{
+				{"$and": []bson.M{{"deadline": bson.M{"$gt": int64(10)}}}},
+				{"$and": []bson.M{{"deadline": int64(10)}, {"_id": bson.M{"$gt": id}}}},
+			}},
+		},
+		{
+			name:   "descending then ascending",
+			sort:   bson.D{{Key: "priority", Value: -1}, {Key: "_id", Value: 1}},
+			values: []interface{}{int64(3), id},
+			want: bson.M{"$or": []bson.M{
+				{"$and": []bson.M{{"$or": []bson.M{
+					{"priority": bson.M{"$lt": int64(3)}},
+					{"priority": nil},
+				}}}},
+				{"$and": []bson.M{{"priority": int64(3)}, {"_id": bson.M{"$gt": id}}}},
+			}},
+		},
+		{
+			name:   "ascending then descending",
+			sort:   bson.D{{Key: "title", Value: 1}, {Key: "_id", Value: -1}},
+			values: []interface{}{"a", id},
+			want: bson.M{"$or": []bson.M{
+				{"$and": []bson.M{{"title": bson.M{"$gt": "a"}}}},
+				{"$and": []bson.M{{"title": "a"}, {"$or": []bson.M{
+					{"_id": bson.M{"$lt": id}},
+					{"_id": nil},
+				}}}},
+			}},
+		},
+		{
+			name:   "null ascending",
+			sort:   bson.D{{Key: "parent", Value: 1}, {Key: "_id", Value: 1}},
+			values: []interface{}{nil, id},
+			want: bson.M{"$or": []bson.M{
+				{"$and": []bson.M{{"parent": bson.M{"$ne": nil}}}},
+				{"$and": []bson.M{{"parent": nil}, {"_id": bson.M{"$gt": id}}}},
+			}},
+		},
+		{
+			name:   "null descending",
+			sort:   bson.D{{Key: "parent", Value: -1}, {Key: "_id", Value: 1}},
+			values: []interface{}{nil, id},
+			want: bson.M{"$or": []bson.M{
+				{"$and": []bson.M{{"parent": nil}, {"_id": bson.M{"$gt": id}}}},
+			}},
+		},
+		{
+			name:   "nothing after",
+			sort:   bson.D{{Key: "parent", Value: -1}},
+			values: []interface{}{nil},
+			want:   bson.M{"_id": bson.M{"$exists": false}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cursorMatch(tt.sort, tt.values); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("cursorMatch = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
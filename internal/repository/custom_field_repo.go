@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/remiehneppo/be-task-management/internal/database"
+	"github.com/remiehneppo/be-task-management/types"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+const CustomFieldCollection = "custom_fields"
+
+var _ CustomFieldRepository = (*customFieldRepository)(nil)
+
+type CustomFieldRepository interface {
+	Save(ctx context.Context, field *types.CustomFieldDefinition) error
+	FindByID(ctx context.Context, id string) (*types.CustomFieldDefinition, error)
+	FindByWorkspace(ctx context.Context, workspace string) ([]*types.CustomFieldDefinition, error)
+	FindByKey(ctx context.Context, workspace, key string) (*types.CustomFieldDefinition, error)
+	Update(ctx context.Context, id string, field *types.CustomFieldDefinition) error
+	Delete(ctx context.Context, id string) error
+}
+
+type customFieldRepository struct {
+	database   database.Database
+	collection string
+}
+
+func NewCustomFieldRepository(db database.Database) CustomFieldRepository {
+	return &customFieldRepository{
+		database:   db,
+		collection: CustomFieldCollection,
+	}
+}
+
+func (r *customFieldRepository) Save(ctx context.Context, field *types.CustomFieldDefinition) error {
+	id, err := r.database.Insert(ctx, r.collection, field)
+	if err != nil {
+		return err
+	}
+	field.ID = id
+	return nil
+}
+
+func (r *customFieldRepository) FindByID(ctx context.Context, id string) (*types.CustomFieldDefinition, error) {
+	var field types.CustomFieldDefinition
+	err := r.database.FindByID(ctx, r.collection, id, &field)
+	if err != nil {
+		return nil, err
+	}
+	return &field, nil
+}
+
+func (r *customFieldRepository) FindByWorkspace(ctx context.Context, workspace string) ([]*types.CustomFieldDefinition, error) {
+	fields := make([]*types.CustomFieldDefinition, 0)
+	err := r.database.Query(ctx, r.collection, bson.M{"workspace": workspace}, 0, 0, bson.M{"created_at": 1}, &fields)
+	if err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+func (r *customFieldRepository) FindByKey(ctx context.Context, workspace, key string) (*types.CustomFieldDefinition, error) {
+	var fields []*types.CustomFieldDefinition
+	err := r.database.Query(ctx, r.collection, bson.M{"workspace": workspace, "key": key}, 0, 1, nil, &fields)
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) == 0 {
+		return nil, types.ErrCustomFieldDefinitionMissing
+	}
+	return fields[0], nil
+}
+
+func (r *customFieldRepository) Update(ctx context.Context, id string, field *types.CustomFieldDefinition) error {
+	return r.database.Update(ctx, r.collection, id, field)
+}
+
+func (r *customFieldRepository) Delete(ctx context.Context, id string) error {
+	return r.database.Delete(ctx, r.collection, id)
+}
@@ -22,6 +22,11 @@ var DefaultDocumentClass = &models.Class{
 		{Name: "page_number", DataType: []string{"int"}},
 		{Name: "chunk_number", DataType: []string{"int"}},
 		{Name: "tags", DataType: []string{"text[]"}},
+		{Name: "source", DataType: []string{"text"}},
+		{Name: "task_id", DataType: []string{"text"}},
+		{Name: "report_id", DataType: []string{"text"}},
+		{Name: "workspace", DataType: []string{"text"}},
+		{Name: "assignee", DataType: []string{"text"}},
 	},
 	VectorIndexType: "hnsw",
 }
@@ -30,6 +35,12 @@ type DocumentVectorRepository interface {
 	SaveBatchDocumentVector(ctx context.Context, metadata *types.DocumentMetadata, document []*types.DocumentChunk) error
 	SaveDocumentVector(ctx context.Context, metadata *types.DocumentMetadata, document *types.DocumentChunk) error
 	SearchDocumentVector(ctx context.Context, metadata *types.DocumentMetadata, queries []string, limit int) ([]*types.ChunkDocumentResponse, error)
+	// SearchWorkspaceDocumentVector searches the uploaded documents, shared
+	// by everyone, and the report chunks of the workspace
+	SearchWorkspaceDocumentVector(ctx context.Context, metadata *types.DocumentMetadata, workspace string, queries []string, limit int) ([]*types.ChunkDocumentResponse, error)
+	// SearchReportVector searches the report chunks of the workspace, only
+	// those of taskIDs when set
+	SearchReportVector(ctx context.Context, workspace string, taskIDs []string, queries []string, limit int) ([]*types.ChunkDocumentResponse, error)
 	RemoveDocuments(ctx context.Context, metadata *types.DocumentMetadata) error
 }
 
@@ -74,13 +85,7 @@ func (r *documentVectorRepository) SaveBatchDocumentVector(ctx context.Context,
 		batcher := r.client.Batch().ObjectsBatcher()
 
 		for j := i; j < end; j++ {
-			properties := map[string]interface{}{
-				"title":        metadata.Title,
-				"content":      documents[j].Content,
-				"page_number":  documents[j].Page,
-				"chunk_number": documents[j].Chunk,
-				"tags":         metadata.Tags,
-			}
+			properties := documentProperties(metadata, documents[j])
 			batcher.WithObjects(
 				&models.Object{
 					Class:      r.class.Class,
@@ -98,13 +103,7 @@ func (r *documentVectorRepository) SaveBatchDocumentVector(ctx context.Context,
 }
 
 func (r *documentVectorRepository) SaveDocumentVector(ctx context.Context, metadata *types.DocumentMetadata, document *types.DocumentChunk) error {
-	properties := map[string]interface{}{
-		"title":        metadata.Title,
-		"content":      document.Content,
-		"page_number":  document.Page,
-		"chunk_number": document.Chunk,
-		"tags":         metadata.Tags,
-	}
+	properties := documentProperties(metadata, document)
 	creator := r.client.Data().Creator().
 		WithClassName(r.class.Class).
 		WithProperties(properties)
@@ -129,12 +128,59 @@ func (r *documentVectorRepository) RemoveDocuments(ctx context.Context, metadata
 }
 
 func (r *documentVectorRepository) SearchDocumentVector(ctx context.Context, metadata *types.DocumentMetadata, queries []string, limit int) ([]*types.ChunkDocumentResponse, error) {
+	return r.search(ctx, buildMetadataFilter(metadata), queries, limit)
+}
+
+func (r *documentVectorRepository) SearchWorkspaceDocumentVector(ctx context.Context, metadata *types.DocumentMetadata, workspace string, queries []string, limit int) ([]*types.ChunkDocumentResponse, error) {
+	// uploaded documents carry no source, NotEqual matches them
+	whereFilter := filters.Where().WithOperator(filters.Or).WithOperands([]*filters.WhereBuilder{
+		filters.Where().
+			WithPath([]string{"source"}).
+			WithOperator(filters.NotEqual).
+			WithValueString(types.DOCUMENT_SOURCE_REPORT),
+		filters.Where().
+			WithPath([]string{"workspace"}).
+			WithOperator(filters.Equal).
+			WithValueString(workspace),
+	})
+	if metadataFilter := buildMetadataFilter(metadata); metadataFilter != nil {
+		whereFilter = filters.Where().WithOperator(filters.And).WithOperands([]*filters.WhereBuilder{
+			metadataFilter,
+			whereFilter,
+		})
+	}
+	return r.search(ctx, whereFilter, queries, limit)
+}
+
+func (r *documentVectorRepository) SearchReportVector(ctx context.Context, workspace string, taskIDs []string, queries []string, limit int) ([]*types.ChunkDocumentResponse, error) {
+	whereFilter := buildMetadataFilter(&types.DocumentMetadata{
+		Source:    types.DOCUMENT_SOURCE_REPORT,
+		Workspace: workspace,
+	})
+	if len(taskIDs) > 0 {
+		whereFilter = filters.Where().WithOperator(filters.And).WithOperands([]*filters.WhereBuilder{
+			whereFilter,
+			filters.Where().
+				WithPath([]string{"task_id"}).
+				WithOperator(filters.ContainsAny).
+				WithValueString(taskIDs...),
+		})
+	}
+	return r.search(ctx, whereFilter, queries, limit)
+}
+
+func (r *documentVectorRepository) search(ctx context.Context, whereFilter *filters.WhereBuilder, queries []string, limit int) ([]*types.ChunkDocumentResponse, error) {
 	fields := []graphql.Field{
 		{Name: "title"},
 		{Name: "content"},
 		{Name: "page_number"},
 		{Name: "chunk_number"},
 		{Name: "tags"},
+		{Name: "source"},
+		{Name: "task_id"},
+		{Name: "report_id"},
+		{Name: "workspace"},
+		{Name: "assignee"},
 		{Name: "_additional", Fields: []graphql.Field{
 			{Name: "id"},
 		},
@@ -146,7 +192,6 @@ func (r *documentVectorRepository) SearchDocumentVector(ctx context.Context, met
 	nearVector := r.client.GraphQL().NearTextArgBuilder().
 		WithConcepts(queries).
 		WithCertainty(0.7)
-	whereFilter := buildMetadataFilter(metadata)
 
 	if limit > 0 {
 		getBuilder.WithLimit(limit)
@@ -178,6 +223,11 @@ func (r *documentVectorRepository) SearchDocumentVector(ctx context.Context, met
 					PageNumber:  int(doc["page_number"].(float64)),
 					ChunkNumber: int(doc["chunk_number"].(float64)),
 					Tags:        utils.ParseStringArray(doc["tags"]),
+					Source:      utils.ParseString(doc["source"]),
+					TaskID:      utils.ParseString(doc["task_id"]),
+					ReportID:    utils.ParseString(doc["report_id"]),
+					Workspace:   utils.ParseString(doc["workspace"]),
+					Assignee:    utils.ParseString(doc["assignee"]),
 				})
 			}
 		}
@@ -185,29 +235,72 @@ func (r *documentVectorRepository) SearchDocumentVector(ctx context.Context, met
 	return docs, nil
 }
 
+func documentProperties(metadata *types.DocumentMetadata, document *types.DocumentChunk) map[string]interface{} {
+	properties := map[string]interface{}{
+		"title":        metadata.Title,
+		"content":      document.Content,
+		"page_number":  document.Page,
+		"chunk_number": document.Chunk,
+		"tags":         metadata.Tags,
+	}
+	if metadata.Source != "" {
+		properties["source"] = metadata.Source
+	}
+	if metadata.TaskID != "" {
+		properties["task_id"] = metadata.TaskID
+	}
+	if metadata.ReportID != "" {
+		properties["report_id"] = metadata.ReportID
+	}
+	if metadata.Workspace != "" {
+		properties["workspace"] = metadata.Workspace
+	}
+	if metadata.Assignee != "" {
+		properties["assignee"] = metadata.Assignee
+	}
+	return properties
+}
+
 func buildMetadataFilter(metadata *types.DocumentMetadata) *filters.WhereBuilder {
 
-	var whereFilter *filters.WhereBuilder
+	operands := make([]*filters.WhereBuilder, 0)
 
 	if metadata.Title != "" {
-		whereFilter = filters.Where().WithPath([]string{"title"}).
+		operands = append(operands, filters.Where().WithPath([]string{"title"}).
 			WithOperator(filters.Equal).
-			WithValueString(metadata.Title)
+			WithValueString(metadata.Title))
 	}
 
-	if len(metadata.Tags) > 0 {
-		for _, tag := range metadata.Tags {
-			tagFilter := filters.Where().
-				WithPath([]string{"tags"}).
-				WithOperator(filters.ContainsAny).
-				WithValueString(tag)
-			if whereFilter == nil {
-				whereFilter = tagFilter
-			} else {
-				whereFilter = whereFilter.WithOperator(filters.And).WithOperands([]*filters.WhereBuilder{tagFilter})
-			}
+	for _, tag := range metadata.Tags {
+		operands = append(operands, filters.Where().
+			WithPath([]string{"tags"}).
+			WithOperator(filters.ContainsAny).
+			WithValueString(tag))
+	}
+
+	equalFields := map[string]string{
+		"source":    metadata.Source,
+		"task_id":   metadata.TaskID,
+		"report_id": metadata.ReportID,
+		"workspace": metadata.Workspace,
+		"assignee":  metadata.Assignee,
+	}
+	for path, value := range equalFields {
+		if value == "" {
+			continue
 		}
+		operands = append(operands, filters.Where().
+			WithPath([]string{path}).
+			WithOperator(filters.Equal).
+			WithValueString(value))
 	}
 
-	return whereFilter
+	switch len(operands) {
+	case 0:
+		return nil
+	case 1:
+		return operands[0]
+	default:
+		return filters.Where().WithOperator(filters.And).WithOperands(operands)
+	}
 }
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/remiehneppo/be-task-management/internal/database"
+	"github.com/remiehneppo/be-task-management/types"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+const ExportJobCollection = "export_jobs"
+
+var _ ExportJobRepository = (*exportJobRepository)(nil)
+
+type ExportJobRepository interface {
+	Save(ctx context.Context, job *types.ExportJob) error
+	FindByID(ctx context.Context, id string) (*types.ExportJob, error)
+	Update(ctx context.Context, id string, job *types.ExportJob) error
+	Delete(ctx context.Context, id string) error
+	// FindUnfinished returns the pending and running jobs, oldest first. A
+	// running job is left over by a worker that stopped while writing it.
+	FindUnfinished(ctx context.Context, limit int64) ([]*types.ExportJob, error)
+	// FindExpired returns the finished jobs expired before the time
+	FindExpired(ctx context.Context, before int64, limit int64) ([]*types.ExportJob, error)
+}
+
+type exportJobRepository struct {
+	database   database.Database
+	collection string
+}
+
+func NewExportJobRepository(db database.Database) ExportJobRepository {
+	return &exportJobRepository{
+		database:   db,
+		collection: ExportJobCollection,
+	}
+}
+
+func (r *exportJobRepository) Save(ctx context.Context, job *types.ExportJob) error {
+	id, err := r.database.Insert(ctx, r.collection, job)
+	if err != nil {
+		return err
+	}
+	job.ID = id
+	return nil
+}
+
+func (r *exportJobRepository) FindByID(ctx context.Context, id string) (*types.ExportJob, error) {
+	var job types.ExportJob
+	err := r.database.FindByID(ctx, r.collection, id, &job)
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (r *exportJobRepository) Update(ctx context.Context, id string, job *types.ExportJob) error {
+	return r.database.Update(ctx, r.collection, id, job)
+}
+
+func (r *exportJobRepository) Delete(ctx context.Context, id string) error {
+	return r.database.Delete(ctx, r.collection, id)
+}
+
+func (r *exportJobRepository) FindUnfinished(ctx context.Context, limit int64) ([]*types.ExportJob, error) {
+	filter := bson.M{"status": bson.M{"$in": []string{
+		types.EXPORT_STATUS_PENDING,
+		types.EXPORT_STATUS_RUNNING,
+	}}}
+	jobs := make([]*types.ExportJob, 0)
+	err := r.database.Query(ctx, r.collection, filter, 0, limit, bson.D{{Key: "created_at", Value: 1}}, &jobs)
+	if err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+func (r *exportJobRepository) FindExpired(ctx context.Context, before int64, limit int64) ([]*types.ExportJob, error) {
+	filter := bson.M{
+		"status": bson.M{"$in": []string{
+			types.EXPORT_STATUS_DONE,
+			types.EXPORT_STATUS_FAILED,
+		}},
+		"expires_at": bson.M{"$lt": before},
+	}
+	jobs := make([]*types.ExportJob, 0)
+	err := r.database.Query(ctx, r.collection, filter, 0, limit, bson.D{{Key: "expires_at", Value: 1}}, &jobs)
+	if err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
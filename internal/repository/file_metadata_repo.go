@@ -5,18 +5,26 @@ import (
 
 	"github.com/remiehneppo/be-task-management/internal/database"
 	"github.com/remiehneppo/be-task-management/types"
+	"go.mongodb.org/mongo-driver/v2/bson"
 )
 
 var fileMetadataCollection = "file_metadata"
 
+// fileMetadataPageSort lists the newest files first, the _id tiebreaker
+// makes positions unique for cursors
+var fileMetadataPageSort = bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}
+
 var _ FileMetadataRepository = (*fileMetadataRepository)(nil)
 
 type FileMetadataRepository interface {
 	GetFileMetadata(ctx context.Context, fileID string) (*types.FileMetadata, error)
-	GetFileList(ctx context.Context, page, limit int64) ([]*types.FileMetadata, int64, error)
+	GetFileList(ctx context.Context, page types.PageRequest) ([]*types.FileMetadata, int64, string, error)
 	CreateFileMetadata(ctx context.Context, fileMetadata *types.FileMetadata) error
 	UpdateFileMetadata(ctx context.Context, fileMetadata *types.FileMetadata) error
 	DeleteFileMetadata(ctx context.Context, fileID string) error
+	// DeleteFileMetadataByPath deletes the metadata of the file stored at
+	// filePath, the path UploadFile returned
+	DeleteFileMetadataByPath(ctx context.Context, filePath string) error
 	GetFileByName(ctx context.Context, fileName string) (*types.FileMetadata, error)
 }
 
@@ -41,18 +49,39 @@ func (r *fileMetadataRepository) GetFileMetadata(ctx context.Context, fileID str
 	return fileMetadata, nil
 }
 
-func (r *fileMetadataRepository) GetFileList(ctx context.Context, page, limit int64) ([]*types.FileMetadata, int64, error) {
+// GetFileList returns a page of the files, newest first, the file count and
+// the cursor of the next page when there is one
+func (r *fileMetadataRepository) GetFileList(ctx context.Context, page types.PageRequest) ([]*types.FileMetadata, int64, string, error) {
 	fileMetadataList := make([]*types.FileMetadata, 0)
 	totalCount, err := r.database.Count(ctx, r.collection, nil)
 	if err != nil {
-		return nil, 0, err
+		return nil, 0, "", err
 	}
 
-	err = r.database.Query(ctx, r.collection, nil, page*limit, limit, nil, &fileMetadataList)
+	var filter interface{}
+	skip := pageSkip(page)
+	if page.Cursor != "" {
+		values, err := decodeCursor(fileMetadataPageSort, page.Cursor)
+		if err != nil {
+			return nil, 0, "", err
+		}
+		filter = cursorMatch(fileMetadataPageSort, values)
+		skip = 0
+	}
+	// one more file than the page tells whether a next page exists
+	err = r.database.Query(ctx, r.collection, filter, skip, page.Limit+1, fileMetadataPageSort, &fileMetadataList)
 	if err != nil {
-		return nil, 0, err
+		return nil, 0, "", err
+	}
+	nextCursor := ""
+	if int64(len(fileMetadataList)) > page.Limit {
+		fileMetadataList = fileMetadataList[:page.Limit]
+		nextCursor, err = encodeItemCursor(fileMetadataPageSort, fileMetadataList[len(fileMetadataList)-1])
+		if err != nil {
+			return nil, 0, "", err
+		}
 	}
-	return fileMetadataList, totalCount, nil
+	return fileMetadataList, totalCount, nextCursor, nil
 }
 
 func (r *fileMetadataRepository) CreateFileMetadata(ctx context.Context, fileMetadata *types.FileMetadata) error {
@@ -79,6 +108,10 @@ func (r *fileMetadataRepository) DeleteFileMetadata(ctx context.Context, fileID
 	return nil
 }
 
+func (r *fileMetadataRepository) DeleteFileMetadataByPath(ctx context.Context, filePath string) error {
+	return r.database.DeleteMany(ctx, r.collection, bson.M{"file_path": filePath})
+}
+
 func (r *fileMetadataRepository) GetFileByName(ctx context.Context, fileName string) (*types.FileMetadata, error) {
 	filesMetadata := []*types.FileMetadata{}
 	err := r.database.Query(
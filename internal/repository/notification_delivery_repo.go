@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/remiehneppo/be-task-management/internal/database"
+	"github.com/remiehneppo/be-task-management/types"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+const NotificationDeliveryCollection = "notification_deliveries"
+
+var _ NotificationDeliveryRepository = (*notificationDeliveryRepository)(nil)
+
+type NotificationDeliveryRepository interface {
+	Save(ctx context.Context, delivery *types.NotificationDelivery) error
+	// FindDue returns the pending deliveries whose next attempt is due at now,
+	// oldest first
+	FindDue(ctx context.Context, now int64, limit int64) ([]*types.NotificationDelivery, error)
+	Update(ctx context.Context, id string, delivery *types.NotificationDelivery) error
+}
+
+type notificationDeliveryRepository struct {
+	database   database.Database
+	collection string
+}
+
+func NewNotificationDeliveryRepository(db database.Database) NotificationDeliveryRepository {
+	return &notificationDeliveryRepository{
+		database:   db,
+		collection: NotificationDeliveryCollection,
+	}
+}
+
+func (r *notificationDeliveryRepository) Save(ctx context.Context, delivery *types.NotificationDelivery) error {
+	id, err := r.database.Insert(ctx, r.collection, delivery)
+	if err != nil {
+		return err
+	}
+	delivery.ID = id
+	return nil
+}
+
+func (r *notificationDeliveryRepository) FindDue(ctx context.Context, now int64, limit int64) ([]*types.NotificationDelivery, error) {
+	deliveries := make([]*types.NotificationDelivery, 0)
+	filter := bson.M{
+		"status":          types.DELIVERY_STATUS_PENDING,
+		"next_attempt_at": bson.M{"$lte": now},
+	}
+	err := r.database.Query(ctx, r.collection, filter, 0, limit, bson.D{{Key: "next_attempt_at", Value: 1}}, &deliveries)
+	if err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+func (r *notificationDeliveryRepository) Update(ctx context.Context, id string, delivery *types.NotificationDelivery) error {
+	return r.database.Update(ctx, r.collection, id, delivery)
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/remiehneppo/be-task-management/internal/database"
+	"github.com/remiehneppo/be-task-management/types"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+const NotificationPreferenceCollection = "notification_preferences"
+
+var _ NotificationPreferenceRepository = (*notificationPreferenceRepository)(nil)
+
+type NotificationPreferenceRepository interface {
+	FindByUserID(ctx context.Context, userID string) (*types.NotificationPreference, error)
+	FindByEmailMode(ctx context.Context, emailMode string) ([]*types.NotificationPreference, error)
+	Save(ctx context.Context, preference *types.NotificationPreference) error
+	Update(ctx context.Context, id string, preference *types.NotificationPreference) error
+}
+
+type notificationPreferenceRepository struct {
+	database   database.Database
+	collection string
+}
+
+func NewNotificationPreferenceRepository(db database.Database) NotificationPreferenceRepository {
+	return &notificationPreferenceRepository{
+		database:   db,
+		collection: NotificationPreferenceCollection,
+	}
+}
+
+func (r *notificationPreferenceRepository) FindByUserID(ctx context.Context, userID string) (*types.NotificationPreference, error) {
+	var preferences []*types.NotificationPreference
+	err := r.database.Query(ctx, r.collection, bson.M{"user_id": userID}, 0, 1, nil, &preferences)
+	if err != nil {
+		return nil, err
+	}
+	if len(preferences) == 0 {
+		return nil, types.ErrNotificationPreferenceNotFound
+	}
+	return preferences[0], nil
+}
+
+func (r *notificationPreferenceRepository) FindByEmailMode(ctx context.Context, emailMode string) ([]*types.NotificationPreference, error) {
+	preferences := make([]*types.NotificationPreference, 0)
+	err := r.database.Query(ctx, r.collection, bson.M{"email_mode": emailMode}, 0, 0, nil, &preferences)
+	if err != nil {
+		return nil, err
+	}
+	return preferences, nil
+}
+
+func (r *notificationPreferenceRepository) Save(ctx context.Context, preference *types.NotificationPreference) error {
+	id, err := r.database.Insert(ctx, r.collection, preference)
+	if err != nil {
+		return err
+	}
+	preference.ID = id
+	return nil
+}
+
+func (r *notificationPreferenceRepository) Update(ctx context.Context, id string, preference *types.NotificationPreference) error {
+	return r.database.Update(ctx, r.collection, id, preference)
+}
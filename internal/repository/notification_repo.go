@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/remiehneppo/be-task-management/internal/database"
+	"github.com/remiehneppo/be-task-management/types"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+const NotificationCollection = "notifications"
+
+var _ NotificationRepository = (*notificationRepository)(nil)
+
+type NotificationRepository interface {
+	Save(ctx context.Context, notification *types.Notification) error
+	PaginateByRecipient(ctx context.Context, recipient string, unreadOnly bool, page, limit int64) ([]*types.Notification, int64, error)
+	CountUnread(ctx context.Context, recipient string) (int64, error)
+	MarkRead(ctx context.Context, recipient string, ids []string) error
+	MarkAllRead(ctx context.Context, recipient string) error
+	// FindByRecipientSince returns the notifications created after since,
+	// oldest first
+	FindByRecipientSince(ctx context.Context, recipient string, since int64) ([]*types.Notification, error)
+}
+
+type notificationRepository struct {
+	database   database.Database
+	collection string
+}
+
+func NewNotificationRepository(db database.Database) NotificationRepository {
+	return &notificationRepository{
+		database:   db,
+		collection: NotificationCollection,
+	}
+}
+
+func (r *notificationRepository) Save(ctx context.Context, notification *types.Notification) error {
+	id, err := r.database.Insert(ctx, r.collection, notification)
+	if err != nil {
+		return err
+	}
+	notification.ID = id
+	return nil
+}
+
+func (r *notificationRepository) PaginateByRecipient(ctx context.Context, recipient string, unreadOnly bool, page, limit int64) ([]*types.Notification, int64, error) {
+	filter := bson.M{"recipient": recipient}
+	if unreadOnly {
+		filter["read_at"] = 0
+	}
+	total, err := r.database.Count(ctx, r.collection, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	var skip int64 = 0
+	if page > 0 {
+		skip = (page - 1) * limit
+	}
+	notifications := make([]*types.Notification, 0)
+	err = r.database.Query(ctx, r.collection, filter, skip, limit, bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}, &notifications)
+	if err != nil {
+		return nil, 0, err
+	}
+	return notifications, total, nil
+}
+
+func (r *notificationRepository) CountUnread(ctx context.Context, recipient string) (int64, error) {
+	return r.database.Count(ctx, r.collection, bson.M{"recipient": recipient, "read_at": 0})
+}
+
+// MarkRead marks the given notifications of the recipient as read, ids of
+// other users are ignored
+func (r *notificationRepository) MarkRead(ctx context.Context, recipient string, ids []string) error {
+	objIds := make([]bson.ObjectID, len(ids))
+	for i, id := range ids {
+		objId, err := bson.ObjectIDFromHex(id)
+		if err != nil {
+			return err
+		}
+		objIds[i] = objId
+	}
+	return r.database.UpdateMany(
+		ctx,
+		r.collection,
+		bson.M{"_id": bson.M{"$in": objIds}, "recipient": recipient, "read_at": 0},
+		bson.M{"$set": bson.M{"read_at": time.Now().Unix()}},
+	)
+}
+
+func (r *notificationRepository) MarkAllRead(ctx context.Context, recipient string) error {
+	return r.database.UpdateMany(
+		ctx,
+		r.collection,
+		bson.M{"recipient": recipient, "read_at": 0},
+		bson.M{"$set": bson.M{"read_at": time.Now().Unix()}},
+	)
+}
+
+func (r *notificationRepository) FindByRecipientSince(ctx context.Context, recipient string, since int64) ([]*types.Notification, error) {
+	notifications := make([]*types.Notification, 0)
+	filter := bson.M{"recipient": recipient, "created_at": bson.M{"$gt": since}}
+	err := r.database.Query(ctx, r.collection, filter, 0, 0, bson.D{{Key: "created_at", Value: 1}}, &notifications)
+	if err != nil {
+		return nil, err
+	}
+	return notifications, nil
+}
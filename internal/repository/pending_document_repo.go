@@ -5,6 +5,7 @@ import (
 
 	"github.com/remiehneppo/be-task-management/internal/database"
 	"github.com/remiehneppo/be-task-management/types"
+	"go.mongodb.org/mongo-driver/v2/bson"
 )
 
 var PendingDocumentCollection = "pending_documents"
@@ -16,6 +17,9 @@ type PendingDocumentRepository interface {
 	FindByID(ctx context.Context, id string) (*types.PendingDocument, error)
 	FindAll(ctx context.Context, page, limit int64) ([]*types.PendingDocument, int64, error)
 	Remove(ctx context.Context, id string) error
+	// RemoveReportDocuments removes the queued documents of a report, of
+	// every report of the task when reportID is empty
+	RemoveReportDocuments(ctx context.Context, taskID, reportID string) error
 }
 
 type pendingDocumentRepository struct {
@@ -60,3 +64,11 @@ func (r *pendingDocumentRepository) FindAll(ctx context.Context, page, limit int
 func (r *pendingDocumentRepository) Remove(ctx context.Context, id string) error {
 	return r.database.Delete(ctx, r.collection, id)
 }
+
+func (r *pendingDocumentRepository) RemoveReportDocuments(ctx context.Context, taskID, reportID string) error {
+	filter := bson.M{"source": types.DOCUMENT_SOURCE_REPORT, "task_id": taskID}
+	if reportID != "" {
+		filter["report_id"] = reportID
+	}
+	return r.database.DeleteMany(ctx, r.collection, filter)
+}
@@ -12,16 +12,24 @@ const ReportCollection = "reports"
 
 var defaultReportSort = bson.M{"created_at": -1} // sort by created_at descending
 
+// reportPageSort orders paged reports, the _id tiebreaker makes positions
+// unique for cursors
+var reportPageSort = bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}
+
 type ReportRepository interface {
 	Save(ctx context.Context, report *types.Report) error
 	FindByID(ctx context.Context, id string) (*types.Report, error)
 	FindByTaskID(ctx context.Context, taskID string) ([]*types.Report, error)
 	FindByTaskIDs(ctx context.Context, taskIDs []string) (map[string][]*types.Report, error)
-	FilterReports(ctx context.Context, page int64, limit int64, filter types.ReportFilter) ([]*types.Report, int64, error)
+	FilterReports(ctx context.Context, page types.PageRequest, filter types.ReportFilter) ([]*types.Report, int64, string, error)
 	Count(ctx context.Context) (int64, error)
 	CountWithFilter(ctx context.Context, filter types.ReportFilter) (int64, error)
+	// CountByCreator counts the reports matching filter per creator
+	CountByCreator(ctx context.Context, filter types.ReportFilter) (map[string]int64, error)
 	Delete(ctx context.Context, id string) error
 	DeleteByTaskID(ctx context.Context, taskID string) error
+	// CountByUser counts the reports the user wrote or reviewed
+	CountByUser(ctx context.Context, userID string) (int64, error)
 	Update(ctx context.Context, id string, report *types.Report) error
 	FindAll(ctx context.Context) ([]*types.Report, error)
 }
@@ -39,7 +47,12 @@ func NewReportRepository(db database.Database) ReportRepository {
 }
 
 func (r *reportRepository) Save(ctx context.Context, report *types.Report) error {
-	return r.database.Save(ctx, r.collection, report)
+	id, err := r.database.Insert(ctx, r.collection, report)
+	if err != nil {
+		return err
+	}
+	report.ID = id
+	return nil
 }
 func (r *reportRepository) FindByID(ctx context.Context, id string) (*types.Report, error) {
 	report := &types.Report{}
@@ -71,34 +84,52 @@ func (r *reportRepository) FindByTaskIDs(ctx context.Context, taskIDs []string)
 	}
 	return reportsMap, nil
 }
-func (r *reportRepository) FilterReports(ctx context.Context, page int64, limit int64, filter types.ReportFilter) ([]*types.Report, int64, error) {
+
+// FilterReports returns a page of the reports matching filter, newest
+// first, the total of matching reports and the cursor of the next page when
+// there is one. A zero limit returns every report.
+func (r *reportRepository) FilterReports(ctx context.Context, page types.PageRequest, filter types.ReportFilter) ([]*types.Report, int64, string, error) {
 
 	pipelineMongo := r.pipelineFromReportFilter(filter)
-	// Add skip and limit stages for pagination
-	var skip int64 = 0
-	if page > 0 {
-		skip = (page - 1) * limit
+	if page.Cursor != "" {
+		values, err := decodeCursor(reportPageSort, page.Cursor)
+		if err != nil {
+			return nil, 0, "", err
+		}
+		pipelineMongo = append(pipelineMongo, bson.M{"$match": cursorMatch(reportPageSort, values)})
 	}
+	pipelineMongo = append(pipelineMongo, bson.M{"$sort": reportPageSort})
 
-	// Add pagination stages if limit > 0
-	if limit > 0 {
-		pipelineMongo = append(pipelineMongo, bson.M{"$skip": skip})
-		pipelineMongo = append(pipelineMongo, bson.M{"$limit": limit})
+	// Add pagination stages if limit > 0, one more report than the page
+	// tells whether a next page exists
+	if page.Limit > 0 {
+		if page.Cursor == "" {
+			pipelineMongo = append(pipelineMongo, bson.M{"$skip": pageSkip(page)})
+		}
+		pipelineMongo = append(pipelineMongo, bson.M{"$limit": page.Limit + 1})
 	}
 	// Execute aggregation pipeline
 	reports := make([]*types.Report, 0)
 	err := r.database.Aggregate(ctx, r.collection, pipelineMongo, &reports)
 	if err != nil {
-		return nil, 0, err
+		return nil, 0, "", err
+	}
+	nextCursor := ""
+	if page.Limit > 0 && int64(len(reports)) > page.Limit {
+		reports = reports[:page.Limit]
+		nextCursor, err = encodeItemCursor(reportPageSort, reports[len(reports)-1])
+		if err != nil {
+			return nil, 0, "", err
+		}
 	}
 
 	// Get total count for pagination
 	total, err := r.CountWithFilter(ctx, filter)
 	if err != nil {
-		return nil, 0, err
+		return nil, 0, "", err
 	}
 
-	return reports, total, nil
+	return reports, total, nextCursor, nil
 
 }
 func (r *reportRepository) Count(ctx context.Context) (int64, error) {
@@ -113,7 +144,9 @@ func (r *reportRepository) CountWithFilter(ctx context.Context, filter types.Rep
 	// Add count stage
 	pipelineMongo = append(pipelineMongo, bson.M{"$count": "total"})
 	// Execute aggregation pipeline
-	countData := make([]int64, 0)
+	var countData []struct {
+		Total int64 `bson:"total"`
+	}
 	err := r.database.Aggregate(ctx, r.collection, pipelineMongo, &countData)
 	if err != nil {
 		return 0, err
@@ -121,7 +154,27 @@ func (r *reportRepository) CountWithFilter(ctx context.Context, filter types.Rep
 	if len(countData) == 0 {
 		return 0, nil
 	}
-	return countData[0], nil
+	return countData[0].Total, nil
+}
+func (r *reportRepository) CountByCreator(ctx context.Context, filter types.ReportFilter) (map[string]int64, error) {
+	pipelineMongo := r.pipelineFromReportFilter(filter)
+	pipelineMongo = append(pipelineMongo, bson.M{"$group": bson.M{
+		"_id":   "$creator",
+		"total": bson.M{"$sum": 1},
+	}})
+	var countData []struct {
+		Creator string `bson:"_id"`
+		Total   int64  `bson:"total"`
+	}
+	err := r.database.Aggregate(ctx, r.collection, pipelineMongo, &countData)
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int64, len(countData))
+	for _, count := range countData {
+		counts[count.Creator] = count.Total
+	}
+	return counts, nil
 }
 func (r *reportRepository) Delete(ctx context.Context, id string) error {
 	err := r.database.Delete(ctx, r.collection, id)
@@ -131,6 +184,13 @@ func (r *reportRepository) Delete(ctx context.Context, id string) error {
 	return nil
 }
 
+func (r *reportRepository) CountByUser(ctx context.Context, userID string) (int64, error) {
+	return r.database.Count(ctx, r.collection, bson.M{"$or": []bson.M{
+		{"creator": userID},
+		{"reviewed_by": userID},
+	}})
+}
+
 func (r *reportRepository) DeleteByTaskID(ctx context.Context, taskID string) error {
 	err := r.database.DeleteMany(ctx, r.collection, bson.M{"task_id": taskID})
 	if err != nil {
@@ -155,59 +215,101 @@ func (r *reportRepository) FindAll(ctx context.Context) ([]*types.Report, error)
 	return reports, nil
 }
 
+// pipelineFromReportFilter returns the stages selecting the reports matching
+// filter. The task is only looked up for the workspace and task creator
+// conditions, reports refer to it by the hex string of its id.
 func (r *reportRepository) pipelineFromReportFilter(filter types.ReportFilter) []bson.M {
 	mongoFilter := bson.M{}
+	and := make([]bson.M, 0)
 	if filter.TaskID != "" {
 		mongoFilter["task_id"] = filter.TaskID
 	}
 	if filter.Creator != "" {
-		mongoFilter["creator"] = filter.Creator
-	}
-	if filter.CreatedFrom != 0 {
-		mongoFilter["created_at"] = bson.M{"$gte": filter.CreatedFrom}
+		and = append(and, bson.M{"creator": filter.Creator})
 	}
-	if filter.CreatedTo != 0 {
-		mongoFilter["created_at"] = bson.M{"$lte": filter.CreatedTo}
+	if filter.ExcludeCreator != "" {
+		and = append(and, bson.M{"creator": bson.M{"$ne": filter.ExcludeCreator}})
 	}
-	if filter.Workspace != "" {
-		mongoFilter["task.workspace"] = filter.Workspace // Stage 1: Lookup Task collection
+	if filter.CreatedFrom != 0 || filter.CreatedTo != 0 {
+		createdFilter := bson.M{}
+		if filter.CreatedFrom != 0 {
+			createdFilter["$gte"] = filter.CreatedFrom
+		}
+		if filter.CreatedTo != 0 {
+			createdFilter["$lte"] = filter.CreatedTo
+		}
+		mongoFilter["created_at"] = createdFilter
 	}
-
-	lookupStage := bson.M{
-		"$lookup": bson.M{
-			"from":         "tasks",
-			"localField":   "task_id",
-			"foreignField": "_id",
-			"as":           "task",
-		},
+	if filter.Status != "" {
+		and = append(and, reportStatusMatch(filter.Status))
 	}
-	// Stage 2: Unwind task array
-	unwindStage := bson.M{
-		"$unwind": "$task",
+	if filter.Awaiting {
+		// a resubmitted report is pending again
+		and = append(and, reportStatusMatch(types.REPORT_STATUS_PENDING))
 	}
-
-	matchStage := bson.M{
-		"$match": mongoFilter,
+	if len(and) > 0 {
+		mongoFilter["$and"] = and
 	}
 
 	projectStage := bson.M{
 		"$project": bson.M{
-			"_id":        1,
-			"task_id":    1,
-			"creator":    1,
-			"report":     1,
-			"created_at": 1,
-			"updated_at": 1,
+			"_id":         1,
+			"task_id":     1,
+			"creator":     1,
+			"report":      1,
+			"report_file": 1,
+			"feedback":    1,
+			"status":      1,
+			"reviewed_by": 1,
+			"reviewed_at": 1,
+			"created_at":  1,
+			"updated_at":  1,
 		},
 	}
 
 	pipelineMongo := []bson.M{
-		lookupStage,
-		unwindStage,
-		matchStage,
-		projectStage,
-		defaultReportSort,
+		{"$match": mongoFilter},
+	}
+	if filter.Workspace == "" && len(filter.TaskCreators) == 0 {
+		return append(pipelineMongo, projectStage)
 	}
 
+	taskFilter := bson.M{}
+	if filter.Workspace != "" {
+		taskFilter["task.workspace"] = filter.Workspace
+	}
+	if len(filter.TaskCreators) > 0 {
+		taskFilter["task.creator"] = bson.M{"$in": filter.TaskCreators}
+	}
+	pipelineMongo = append(pipelineMongo,
+		bson.M{
+			"$lookup": bson.M{
+				"from": TaskCollection,
+				"let": bson.M{"taskId": bson.M{"$convert": bson.M{
+					"input":   "$task_id",
+					"to":      "objectId",
+					"onError": nil,
+					"onNull":  nil,
+				}}},
+				"pipeline": []bson.M{
+					{"$match": bson.M{"$expr": bson.M{"$eq": []string{"$_id", "$$taskId"}}}},
+					{"$project": bson.M{"workspace": 1, "creator": 1}},
+				},
+				"as": "task",
+			},
+		},
+		bson.M{"$unwind": "$task"},
+		bson.M{"$match": taskFilter},
+		projectStage,
+	)
 	return pipelineMongo
 }
+
+// reportStatusMatch matches the reports in status, reports without status
+// are pending
+func reportStatusMatch(status string) bson.M {
+	if status == types.REPORT_STATUS_PENDING {
+		return bson.M{"status": bson.M{"$in": []interface{}{nil, types.REPORT_STATUS_PENDING}}}
+	}
+	return bson.M{"status": status}
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/remiehneppo/be-task-management/internal/database"
+	"github.com/remiehneppo/be-task-management/types"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+const RolePermissionCollection = "role_permissions"
+
+var _ RolePermissionRepository = (*rolePermissionRepository)(nil)
+
+type RolePermissionRepository interface {
+	FindAll(ctx context.Context) ([]*types.RolePermissions, error)
+	FindByRole(ctx context.Context, role string) (*types.RolePermissions, error)
+	Save(ctx context.Context, rolePermissions *types.RolePermissions) error
+	Update(ctx context.Context, id string, rolePermissions *types.RolePermissions) error
+}
+
+type rolePermissionRepository struct {
+	database   database.Database
+	collection string
+}
+
+func NewRolePermissionRepository(db database.Database) RolePermissionRepository {
+	return &rolePermissionRepository{
+		database:   db,
+		collection: RolePermissionCollection,
+	}
+}
+
+func (r *rolePermissionRepository) FindAll(ctx context.Context) ([]*types.RolePermissions, error) {
+	rolePermissions := make([]*types.RolePermissions, 0)
+	err := r.database.FindAll(ctx, r.collection, nil, &rolePermissions)
+	if err != nil {
+		return nil, err
+	}
+	return rolePermissions, nil
+}
+
+func (r *rolePermissionRepository) FindByRole(ctx context.Context, role string) (*types.RolePermissions, error) {
+	var rolePermissions []*types.RolePermissions
+	err := r.database.Query(ctx, r.collection, bson.M{"role": role}, 0, 1, nil, &rolePermissions)
+	if err != nil {
+		return nil, err
+	}
+	if len(rolePermissions) == 0 {
+		return nil, types.ErrRolePermissionsNotFound
+	}
+	return rolePermissions[0], nil
+}
+
+func (r *rolePermissionRepository) Save(ctx context.Context, rolePermissions *types.RolePermissions) error {
+	id, err := r.database.Insert(ctx, r.collection, rolePermissions)
+	if err != nil {
+		return err
+	}
+	rolePermissions.ID = id
+	return nil
+}
+
+func (r *rolePermissionRepository) Update(ctx context.Context, id string, rolePermissions *types.RolePermissions) error {
+	return r.database.Update(ctx, r.collection, id, rolePermissions)
+}
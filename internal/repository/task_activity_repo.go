@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/remiehneppo/be-task-management/internal/database"
+	"github.com/remiehneppo/be-task-management/types"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+const TaskActivityCollection = "task_activities"
+
+var _ TaskActivityRepository = (*taskActivityRepository)(nil)
+
+// TaskActivityRepository is append-only, activities are never updated or deleted
+type TaskActivityRepository interface {
+	Save(ctx context.Context, activity *types.TaskActivity) error
+	// PaginateByTaskID returns the history of the task recorded in the
+	// workspace, newest first
+	PaginateByTaskID(ctx context.Context, taskID, workspace string, page, limit int64) ([]*types.TaskActivity, int64, error)
+}
+
+type taskActivityRepository struct {
+	database   database.Database
+	collection string
+}
+
+func NewTaskActivityRepository(db database.Database) TaskActivityRepository {
+	return &taskActivityRepository{
+		database:   db,
+		collection: TaskActivityCollection,
+	}
+}
+
+func (r *taskActivityRepository) Save(ctx context.Context, activity *types.TaskActivity) error {
+	return r.database.Save(ctx, r.collection, activity)
+}
+
+func (r *taskActivityRepository) PaginateByTaskID(ctx context.Context, taskID, workspace string, page, limit int64) ([]*types.TaskActivity, int64, error) {
+	filter := bson.M{"task_id": taskID, "workspace": workspace}
+	total, err := r.database.Count(ctx, r.collection, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	var skip int64 = 0
+	if page > 0 {
+		skip = (page - 1) * limit
+	}
+	activities := make([]*types.TaskActivity, 0)
+	err = r.database.Query(ctx, r.collection, filter, skip, limit, bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}, &activities)
+	if err != nil {
+		return nil, 0, err
+	}
+	return activities, total, nil
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/remiehneppo/be-task-management/internal/database"
+	"github.com/remiehneppo/be-task-management/types"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+const TaskDeadlineAlertCollection = "task_deadline_alerts"
+
+var _ TaskDeadlineAlertRepository = (*taskDeadlineAlertRepository)(nil)
+
+type TaskDeadlineAlertRepository interface {
+	Save(ctx context.Context, alert *types.TaskDeadlineAlert) error
+	Exists(ctx context.Context, taskID, alertType string, step int, deadline int64) (bool, error)
+}
+
+type taskDeadlineAlertRepository struct {
+	database   database.Database
+	collection string
+}
+
+func NewTaskDeadlineAlertRepository(db database.Database) TaskDeadlineAlertRepository {
+	return &taskDeadlineAlertRepository{
+		database:   db,
+		collection: TaskDeadlineAlertCollection,
+	}
+}
+
+func (r *taskDeadlineAlertRepository) Save(ctx context.Context, alert *types.TaskDeadlineAlert) error {
+	return r.database.Save(ctx, r.collection, alert)
+}
+
+func (r *taskDeadlineAlertRepository) Exists(ctx context.Context, taskID, alertType string, step int, deadline int64) (bool, error) {
+	count, err := r.database.Count(ctx, r.collection, bson.M{
+		"task_id":  taskID,
+		"type":     alertType,
+		"step":     step,
+		"deadline": deadline,
+	})
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
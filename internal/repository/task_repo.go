@@ -2,6 +2,9 @@ package repository
 
 import (
 	"context"
+	"regexp"
+	"strings"
+	"time"
 
 	"github.com/remiehneppo/be-task-management/internal/database"
 	"github.com/remiehneppo/be-task-management/types"
@@ -12,18 +15,47 @@ const TaskCollection = "tasks"
 
 var defaultSort = bson.M{"deadline": -1} // sort by deadline descending
 
+// unfinishedStatuses are the statuses of tasks still being worked on
+var unfinishedStatuses = []string{
+	types.TASK_STATUS_OPEN,
+	types.TASK_STATUS_DOING,
+	types.TASK_STATUS_REVIEW,
+}
+
 type TaskRepository interface {
+	// EnsureIndexes creates the full-text index task searches rely on
+	EnsureIndexes(ctx context.Context) error
 	Save(ctx context.Context, task *types.Task) error
 	FindByID(ctx context.Context, id string) (*types.Task, error)
+	FindByIDs(ctx context.Context, ids []string) (map[string]*types.Task, error)
+	FindByParentIDs(ctx context.Context, parentIDs []string) ([]*types.Task, error)
+	FindDependents(ctx context.Context, taskIDs []string) ([]*types.Task, error)
+	RemoveDependencyOnTask(ctx context.Context, taskID string) error
+	UnsetCustomField(ctx context.Context, workspace, key string) error
+	CountByTemplateOccurrence(ctx context.Context, templateID string, occurrenceAt int64) (int64, error)
+	FindUnfinishedByTemplateID(ctx context.Context, templateID string) ([]*types.Task, error)
+	FindUnfinishedDueBefore(ctx context.Context, before int64) ([]*types.Task, error)
+	// FindActiveInWorkspace returns the tasks of the workspace unfinished or
+	// updated between from and to
+	FindActiveInWorkspace(ctx context.Context, workspace string, from, to int64) ([]*types.Task, error)
 	FindAll(ctx context.Context) ([]*types.Task, error)
+	// FindAfter returns up to limit tasks whose ID follows afterID in ID
+	// order, from the first task when afterID is empty
+	FindAfter(ctx context.Context, afterID string, limit int64) ([]*types.Task, error)
 	Update(ctx context.Context, id string, task *types.Task) error
 	Delete(ctx context.Context, id string) error
 	FindByWorkspace(ctx context.Context, workspace string) ([]*types.Task, error)
 	FindByWorkspaceAndStatus(ctx context.Context, workspace string, status string) ([]*types.Task, error)
 	Paginate(ctx context.Context, page int64, limit int64) ([]*types.Task, int64, error)
-	PaginateWithFilter(ctx context.Context, page int64, limit int64, filter types.TaskFilter) ([]*types.Task, int64, error)
+	PaginateWithFilter(ctx context.Context, page types.PageRequest, filter types.TaskFilter) ([]*types.Task, int64, string, error)
 	Count(ctx context.Context) (int64, error)
 	CountWithFilter(ctx context.Context, filter types.TaskFilter) (int64, error)
+	// AggregateMetrics returns the task metrics of the workspace and of
+	// every assignee over the query range
+	AggregateMetrics(ctx context.Context, query types.AnalyticsQuery) (*types.TaskMetrics, []*types.UserMetrics, error)
+	// AggregateSeries counts the tasks created and finished between
+	// consecutive boundaries, the last boundary ends the last bucket
+	AggregateSeries(ctx context.Context, query types.AnalyticsQuery, boundaries []int64) (*types.TaskSeriesCounts, error)
 }
 
 type taskRepository struct {
@@ -38,8 +70,17 @@ func NewTaskRepository(db database.Database) TaskRepository {
 	}
 }
 
+func (r *taskRepository) EnsureIndexes(ctx context.Context) error {
+	return r.database.EnsureTextIndex(ctx, r.collection, []string{"title", "description"})
+}
+
 func (r *taskRepository) Save(ctx context.Context, task *types.Task) error {
-	return r.database.Save(ctx, r.collection, task)
+	id, err := r.database.Insert(ctx, r.collection, task)
+	if err != nil {
+		return err
+	}
+	task.ID = id
+	return nil
 }
 func (r *taskRepository) FindByID(ctx context.Context, id string) (*types.Task, error) {
 	var task types.Task
@@ -49,9 +90,141 @@ func (r *taskRepository) FindByID(ctx context.Context, id string) (*types.Task,
 	}
 	return &task, nil
 }
+func (r *taskRepository) FindByIDs(ctx context.Context, ids []string) (map[string]*types.Task, error) {
+	objIds := make([]bson.ObjectID, len(ids))
+	for i, id := range ids {
+		objId, err := bson.ObjectIDFromHex(id)
+		if err != nil {
+			return nil, err
+		}
+		objIds[i] = objId
+	}
+	tasks := make([]*types.Task, 0)
+	err := r.database.Query(ctx, r.collection, bson.M{"_id": bson.M{"$in": objIds}}, 0, 0, nil, &tasks)
+	if err != nil {
+		return nil, err
+	}
+	tasksMap := make(map[string]*types.Task)
+	for _, task := range tasks {
+		tasksMap[task.ID] = task
+	}
+	return tasksMap, nil
+}
+
+// FindByParentIDs returns the direct subtasks of the given tasks
+func (r *taskRepository) FindByParentIDs(ctx context.Context, parentIDs []string) ([]*types.Task, error) {
+	tasks := make([]*types.Task, 0)
+	err := r.database.Query(ctx, r.collection, bson.M{"parent_id": bson.M{"$in": parentIDs}}, 0, 0, defaultSort, &tasks)
+	if err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// FindDependents returns the tasks that depend on any of the given tasks
+func (r *taskRepository) FindDependents(ctx context.Context, taskIDs []string) ([]*types.Task, error) {
+	tasks := make([]*types.Task, 0)
+	err := r.database.Query(ctx, r.collection, bson.M{"depends_on": bson.M{"$in": taskIDs}}, 0, 0, defaultSort, &tasks)
+	if err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// RemoveDependencyOnTask drops taskID from the predecessors of every task
+func (r *taskRepository) RemoveDependencyOnTask(ctx context.Context, taskID string) error {
+	return r.database.UpdateMany(
+		ctx,
+		r.collection,
+		bson.M{"depends_on": taskID},
+		bson.M{"$pull": bson.M{"depends_on": taskID}},
+	)
+}
+
+// UnsetCustomField removes the value of a deleted custom field from the
+// workspace tasks
+func (r *taskRepository) UnsetCustomField(ctx context.Context, workspace, key string) error {
+	return r.database.UpdateMany(
+		ctx,
+		r.collection,
+		bson.M{"workspace": workspace, "custom_fields." + key: bson.M{"$exists": true}},
+		bson.M{"$unset": bson.M{"custom_fields." + key: ""}},
+	)
+}
+
+// CountByTemplateOccurrence tells whether a template occurrence was generated
+func (r *taskRepository) CountByTemplateOccurrence(ctx context.Context, templateID string, occurrenceAt int64) (int64, error) {
+	return r.database.Count(ctx, r.collection, bson.M{
+		"template_id":   templateID,
+		"occurrence_at": occurrenceAt,
+	})
+}
+
+// FindUnfinishedByTemplateID returns the generated tasks still being worked on
+func (r *taskRepository) FindUnfinishedByTemplateID(ctx context.Context, templateID string) ([]*types.Task, error) {
+	tasks := make([]*types.Task, 0)
+	filter := bson.M{
+		"template_id": templateID,
+		"status":      bson.M{"$in": unfinishedStatuses},
+	}
+	err := r.database.Query(ctx, r.collection, filter, 0, 0, defaultSort, &tasks)
+	if err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// FindUnfinishedDueBefore returns the tasks still being worked on whose
+// deadline is set and earlier than before
+func (r *taskRepository) FindUnfinishedDueBefore(ctx context.Context, before int64) ([]*types.Task, error) {
+	tasks := make([]*types.Task, 0)
+	filter := bson.M{
+		"deadline": bson.M{"$gt": 0, "$lte": before},
+		"status":   bson.M{"$in": unfinishedStatuses},
+	}
+	err := r.database.Query(ctx, r.collection, filter, 0, 0, bson.M{"deadline": 1}, &tasks)
+	if err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+func (r *taskRepository) FindActiveInWorkspace(ctx context.Context, workspace string, from, to int64) ([]*types.Task, error) {
+	tasks := make([]*types.Task, 0)
+	filter := bson.M{
+		"workspace": workspace,
+		"$or": []bson.M{
+			{"status": bson.M{"$in": unfinishedStatuses}},
+			{"updated_at": bson.M{"$gte": from, "$lte": to}},
+		},
+	}
+	err := r.database.Query(ctx, r.collection, filter, 0, 0, bson.M{"updated_at": -1}, &tasks)
+	if err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
 func (r *taskRepository) FindAll(ctx context.Context) ([]*types.Task, error) {
 	var tasks []*types.Task
-	err := r.database.FindAll(ctx, r.collection, defaultSort, tasks) // sort by deadline descending
+	err := r.database.FindAll(ctx, r.collection, defaultSort, &tasks) // sort by deadline descending
+	if err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+func (r *taskRepository) FindAfter(ctx context.Context, afterID string, limit int64) ([]*types.Task, error) {
+	filter := bson.M{}
+	if afterID != "" {
+		objID, err := bson.ObjectIDFromHex(afterID)
+		if err != nil {
+			return nil, err
+		}
+		filter["_id"] = bson.M{"$gt": objID}
+	}
+	tasks := make([]*types.Task, 0)
+	err := r.database.Query(ctx, r.collection, filter, 0, limit, bson.M{"_id": 1}, &tasks)
 	if err != nil {
 		return nil, err
 	}
@@ -75,8 +248,8 @@ func (r *taskRepository) FindByWorkspace(ctx context.Context, workspace string)
 	mongoFilter := bson.M{
 		"workspace": workspace,
 	}
-	var tasks []*types.Task
-	err := r.database.Query(ctx, r.collection, mongoFilter, 0, 0, defaultSort, tasks)
+	tasks := make([]*types.Task, 0)
+	err := r.database.Query(ctx, r.collection, mongoFilter, 0, 0, defaultSort, &tasks)
 	if err != nil {
 		return nil, err
 	}
@@ -87,8 +260,8 @@ func (r *taskRepository) FindByWorkspaceAndStatus(ctx context.Context, workspace
 		"workspace": workspace,
 		"status":    status,
 	}
-	var tasks []*types.Task
-	err := r.database.Query(ctx, r.collection, mongoFilter, 0, 0, defaultSort, tasks)
+	tasks := make([]*types.Task, 0)
+	err := r.database.Query(ctx, r.collection, mongoFilter, 0, 0, defaultSort, &tasks)
 	if err != nil {
 		return nil, err
 	}
@@ -106,79 +279,104 @@ func (r *taskRepository) Paginate(ctx context.Context, page int64, limit int64)
 	}
 	return tasks, count, nil
 }
-func (r *taskRepository) PaginateWithFilter(ctx context.Context, page int64, limit int64, filter types.TaskFilter) ([]*types.Task, int64, error) {
 
-	match, lookup := r.pipelineFromTaskFilter(filter)
+// PaginateWithFilter returns a page of the tasks matching filter, the total
+// of matching tasks and the cursor of the next page when there is one
+func (r *taskRepository) PaginateWithFilter(ctx context.Context, page types.PageRequest, filter types.TaskFilter) ([]*types.Task, int64, string, error) {
+	filter.Search = textSearch(filter.Search)
+	pipeline := r.pipelineFromTaskFilter(filter)
+	sort := taskSort(filter)
 
 	projectStage := bson.M{
 		"$project": bson.M{
-			"_id":         1,
-			"title":       1,
-			"description": 1,
-			"workspace":   1,
-			"creator":     1,
-			"deadline":    1,
-			"assignee":    1,
-			"status":      1,
-			"created_at":  1,
-			"start_at":    1,
-			"updated_at":  1,
-			"progress":    1,
+			"_id":           1,
+			"title":         1,
+			"description":   1,
+			"workspace":     1,
+			"creator":       1,
+			"deadline":      1,
+			"assignee":      1,
+			"assignees":     1,
+			"watchers":      1,
+			"status":        1,
+			"created_at":    1,
+			"start_at":      1,
+			"updated_at":    1,
+			"completed_at":  1,
+			"progress":      1,
+			"parent_id":     1,
+			"depends_on":    1,
+			"template_id":   1,
+			"occurrence_at": 1,
+			"priority":      1,
+			"tags":          1,
+			"custom_fields": 1,
 		},
 	}
+	if filter.Search != "" {
+		projectStage["$project"].(bson.M)["score"] = 1
+	}
+
+	// one more task than the page tells whether a next page exists
+	dataStages := []bson.M{projectStage}
+	if page.Cursor != "" {
+		values, err := decodeCursor(sort, page.Cursor)
+		if err != nil {
+			return nil, 0, "", err
+		}
+		dataStages = append(dataStages,
+			bson.M{"$match": cursorMatch(sort, values)},
+			bson.M{"$sort": sort},
+		)
+	} else {
+		dataStages = append(dataStages,
+			bson.M{"$sort": sort},
+			bson.M{"$skip": pageSkip(page)},
+		)
+	}
+	dataStages = append(dataStages, bson.M{"$limit": page.Limit + 1})
 
 	facetStage := bson.M{
 		"$facet": bson.M{
 			"metadata": []bson.M{
-				match,
 				{"$count": "total"},
 			},
-			"data": []bson.M{
-				match,
-				projectStage,
-				{"$sort": defaultSort},
-				{"$skip": (page - 1) * limit},
-				{"$limit": limit},
-			},
+			"data": dataStages,
 		},
 	}
-	if lookup != nil {
-		facetStage["$facet"].(bson.M)["data"] = append(facetStage["$facet"].(bson.M)["data"].([]bson.M), lookup)
-		facetStage["$facet"].(bson.M)["metadata"] = append(facetStage["$facet"].(bson.M)["metadata"].([]bson.M), lookup)
-	}
-	// Định nghĩa kiểu dữ liệu để match với cấu trúc JSON
+	pipeline = append(pipeline, facetStage)
+	// the tasks are kept raw, the cursor may need the search score
 	type AggregationResult struct {
 		Metadata []struct {
-			Total int64 `json:"total"`
-		} `json:"metadata"`
-		Data []types.Task `json:"data"`
+			Total int64 `bson:"total"`
+		} `bson:"metadata"`
+		Data []bson.Raw `bson:"data"`
 	}
 	aggregationResult := []AggregationResult{}
 	err := r.database.Aggregate(
 		ctx,
 		r.collection,
-		[]bson.M{facetStage},
+		pipeline,
 		&aggregationResult,
 	)
 	if err != nil {
-		return nil, 0, err
+		return nil, 0, "", err
 	}
 
-	tasks := make([]*types.Task, 0)
-	for _, item := range aggregationResult[0].Data {
-		task := &types.Task{
-			ID:          item.ID,
-			Title:       item.Title,
-			Description: item.Description,
-			Workspace:   item.Workspace,
-			Creator:     item.Creator,
-			Deadline:    item.Deadline,
-			Assignee:    item.Assignee,
-			Status:      item.Status,
-			Progress:    item.Progress,
-			CreateAt:    item.CreateAt,
-			StartAt:     item.StartAt,
-			UpdateAt:    item.UpdateAt,
+	data := aggregationResult[0].Data
+	nextCursor := ""
+	if int64(len(data)) > page.Limit {
+		data = data[:page.Limit]
+		nextCursor, err = encodeCursor(sort, data[len(data)-1])
+		if err != nil {
+			return nil, 0, "", err
+		}
+	}
+	tasks := make([]*types.Task, 0, len(data))
+	for _, doc := range data {
+		task := &types.Task{}
+		if err := bson.Unmarshal(doc, task); err != nil {
+			return nil, 0, "", err
 		}
 		tasks = append(tasks, task)
 	}
@@ -187,44 +385,7 @@ func (r *taskRepository) PaginateWithFilter(ctx context.Context, page int64, lim
 		total = aggregationResult[0].Metadata[0].Total
 	}
 
-	// var total int64 = 0
-	// if metadataArr, ok := facetResult["metadata"].(bson.A); ok && len(metadataArr) > 0 {
-	// 	if metadata, ok := metadataArr[0].(bson.M); ok {
-	// 		if totalVal, ok := metadata["total"]; ok {
-	// 			total = totalVal.(int64)
-	// 		}
-	// 	}
-	// } else {
-	// 	return nil, 0, nil
-	// }
-
-	// tasks := make([]*types.Task, 0)
-	// if dataArr, ok := facetResult["data"].([]bson.M); ok {
-	// 	for _, item := range dataArr {
-
-	// 		if !ok {
-	// 			continue
-	// 		}
-
-	// 		task := &types.Task{
-	// 			ID:          item["_id"].(string),
-	// 			Title:       item["title"].(string),
-	// 			Description: item["description"].(string),
-	// 			Workspace:   item["workspace"].(string),
-	// 			Creator:     item["creator"].(string),
-	// 			Deadline:    item["deadline"].(int64),
-	// 			Assignee:    item["assignee"].(string),
-	// 			Status:      item["status"].(string),
-	// 			Progress:    int(item["progress"].(int32)),
-	// 			CreateAt:    item["created_at"].(int64),
-	// 			StartAt:     item["start_at"].(int64),
-	// 			UpdateAt:    item["updated_at"].(int64),
-	// 		}
-	// 		tasks = append(tasks, task)
-	// 	}
-	// }
-
-	return tasks, total, nil
+	return tasks, total, nextCursor, nil
 }
 func (r *taskRepository) Count(ctx context.Context) (int64, error) {
 	count, err := r.database.Count(ctx, r.collection, nil)
@@ -234,20 +395,22 @@ func (r *taskRepository) Count(ctx context.Context) (int64, error) {
 	return count, nil
 }
 func (r *taskRepository) CountWithFilter(ctx context.Context, filter types.TaskFilter) (int64, error) {
-	match, lookup := r.pipelineFromTaskFilter(filter)
+	filter.Search = textSearch(filter.Search)
+	pipeline := r.pipelineFromTaskFilter(filter)
 
 	countStage := bson.M{"$count": "total"}
-	pipeline := []bson.M{match, lookup, countStage}
-	var countResult bson.M
-	err := r.database.Aggregate(ctx, r.collection, pipeline, countResult)
+	pipeline = append(pipeline, countStage)
+	var countResult []struct {
+		Total int64 `bson:"total"`
+	}
+	err := r.database.Aggregate(ctx, r.collection, pipeline, &countResult)
 	if err != nil {
 		return 0, err
 	}
 
 	var total int64 = 0
-	if totalVal, ok := countResult["total"]; ok {
-		total = totalVal.(int64)
-
+	if len(countResult) > 0 {
+		total = countResult[0].Total
 	}
 
 	return total, nil
@@ -256,7 +419,7 @@ func (r *taskRepository) CountWithFilter(ctx context.Context, filter types.TaskF
 func (r *taskRepository) newMongoFilter(filter types.TaskFilter) bson.M {
 	mongoFilter := bson.M{}
 	if filter.Title != "" {
-		mongoFilter["title"] = bson.M{"$regex": filter.Title}
+		mongoFilter["title"] = titleMatch(filter.Title)
 	}
 	if filter.Workspace != "" {
 		mongoFilter["workspace"] = filter.Workspace
@@ -271,7 +434,13 @@ func (r *taskRepository) newMongoFilter(filter types.TaskFilter) bson.M {
 		mongoFilter["deadline"] = bson.M{"$lte": filter.DeadlineTo}
 	}
 	if filter.Assignee != "" {
-		mongoFilter["assignee"] = filter.Assignee
+		mongoFilter["$or"] = assigneeMatch(filter.Assignee)
+	}
+	if filter.PrimaryAssignee != "" {
+		mongoFilter["assignee"] = filter.PrimaryAssignee
+	}
+	if filter.Watcher != "" {
+		mongoFilter["watchers"] = filter.Watcher
 	}
 	if filter.Status != "" {
 		mongoFilter["status"] = filter.Status
@@ -280,10 +449,17 @@ func (r *taskRepository) newMongoFilter(filter types.TaskFilter) bson.M {
 	return mongoFilter
 }
 
-func (r *taskRepository) pipelineFromTaskFilter(filter types.TaskFilter) (match bson.M, lookup bson.M) {
-	match = bson.M{}
+// pipelineFromTaskFilter returns the aggregation stages selecting the tasks
+// matching filter. A full-text search must be the first stage, and the
+// report conditions need the reports looked up first, so they come last.
+func (r *taskRepository) pipelineFromTaskFilter(filter types.TaskFilter) []bson.M {
+	match := bson.M{}
+	and := make([]bson.M, 0)
+	if filter.Search != "" {
+		match["$text"] = bson.M{"$search": filter.Search}
+	}
 	if filter.Title != "" {
-		match["title"] = bson.M{"$regex": filter.Title}
+		match["title"] = titleMatch(filter.Title)
 	}
 	if filter.Workspace != "" {
 		match["workspace"] = filter.Workspace
@@ -302,10 +478,50 @@ func (r *taskRepository) pipelineFromTaskFilter(filter types.TaskFilter) (match
 		match["deadline"] = deadlineFilter
 	}
 	if filter.Assignee != "" {
-		match["assignee"] = filter.Assignee
+		and = append(and, bson.M{"$or": assigneeMatch(filter.Assignee)})
+	}
+	if len(filter.Assignees) > 0 {
+		and = append(and, bson.M{"$or": []bson.M{
+			{"assignee": bson.M{"$in": filter.Assignees}},
+			{"assignees": bson.M{"$in": filter.Assignees}},
+		}})
+	}
+	if filter.PrimaryAssignee != "" {
+		match["assignee"] = filter.PrimaryAssignee
+	}
+	if filter.Watcher != "" {
+		match["watchers"] = filter.Watcher
 	}
 	if filter.Status != "" {
-		match["status"] = filter.Status
+		and = append(and, bson.M{"status": filter.Status})
+	}
+	if len(filter.Statuses) > 0 {
+		and = append(and, bson.M{"status": bson.M{"$in": filter.Statuses}})
+	}
+	if len(filter.Priorities) > 0 {
+		match["priority"] = bson.M{"$in": filter.Priorities}
+	}
+	if len(filter.Tags) > 0 {
+		match["tags"] = bson.M{"$in": filter.Tags}
+	}
+	if filter.ProgressMin != nil || filter.ProgressMax != nil {
+		progressFilter := bson.M{}
+		if filter.ProgressMin != nil {
+			progressFilter["$gte"] = *filter.ProgressMin
+		}
+		if filter.ProgressMax != nil {
+			progressFilter["$lte"] = *filter.ProgressMax
+		}
+		match["progress"] = progressFilter
+	}
+	if filter.Overdue {
+		and = append(and,
+			bson.M{"deadline": bson.M{"$gt": 0, "$lt": time.Now().Unix()}},
+			bson.M{"status": bson.M{"$in": unfinishedStatuses}},
+		)
+	}
+	if filter.SpanFrom != 0 || filter.SpanTo != 0 {
+		and = append(and, spanOverlapMatch(filter.SpanFrom, filter.SpanTo)...)
 	}
 	if filter.StartFrom != 0 || filter.StartTo != 0 {
 		startFilter := bson.M{}
@@ -317,16 +533,36 @@ func (r *taskRepository) pipelineFromTaskFilter(filter types.TaskFilter) (match
 		}
 		match["start_at"] = startFilter
 	}
-	if filter.ReportFrom != 0 || filter.ReportTo != 0 {
-		lookup = bson.M{
-			"$lookup": bson.M{
-				"from":         "reports",
-				"localField":   "_id",
-				"foreignField": "task_id",
-				"as":           "reports",
+	for _, field := range filter.CustomFields {
+		and = append(and, customFieldMatch(field))
+	}
+	if len(and) > 0 {
+		match["$and"] = and
+	}
+	pipeline := []bson.M{{"$match": match}}
+	if filter.Search != "" {
+		pipeline = append(pipeline, bson.M{"$addFields": bson.M{"score": bson.M{"$meta": "textScore"}}})
+	}
+
+	if filter.ReportFrom == 0 && filter.ReportTo == 0 && !filter.NoReports {
+		return pipeline
+	}
+	// reports refer to their task by the hex string of its id
+	pipeline = append(pipeline, bson.M{
+		"$lookup": bson.M{
+			"from": ReportCollection,
+			"let":  bson.M{"taskId": bson.M{"$toString": "$_id"}},
+			"pipeline": []bson.M{
+				{"$match": bson.M{"$expr": bson.M{"$eq": []string{"$task_id", "$$taskId"}}}},
+				{"$project": bson.M{"created_at": 1}},
 			},
-		}
-		//
+			"as": "reports",
+		},
+	})
+	reportMatch := bson.M{}
+	if filter.NoReports {
+		reportMatch["reports"] = bson.M{"$size": 0}
+	} else {
 		reportFilter := bson.M{}
 		if filter.ReportFrom != 0 {
 			reportFilter["$gte"] = filter.ReportFrom
@@ -334,11 +570,337 @@ func (r *taskRepository) pipelineFromTaskFilter(filter types.TaskFilter) (match
 		if filter.ReportTo != 0 {
 			reportFilter["$lte"] = filter.ReportTo
 		}
-		match["reports"] = bson.M{
+		reportMatch["reports"] = bson.M{
 			"$elemMatch": bson.M{
 				"created_at": reportFilter,
 			},
 		}
 	}
-	return bson.M{"$match": match}, lookup
+	return append(pipeline, bson.M{"$match": reportMatch})
+}
+
+// taskSort turns the requested sort keys into a sort document, the _id
+// tiebreaker keeps pages stable
+func taskSort(filter types.TaskFilter) bson.D {
+	sort := bson.D{}
+	seen := make(map[string]bool, len(filter.Sort))
+	for _, key := range filter.Sort {
+		// a repeated key would not change the order, the first one wins
+		if seen[key.Field] {
+			continue
+		}
+		seen[key.Field] = true
+		if key.Field == types.TASK_SORT_RELEVANCE {
+			if filter.Search != "" {
+				sort = append(sort, bson.E{Key: "score", Value: -1})
+			}
+			continue
+		}
+		direction := 1
+		if key.Desc {
+			direction = -1
+		}
+		sort = append(sort, bson.E{Key: key.Field, Value: direction})
+	}
+	if len(sort) == 0 {
+		if filter.Search != "" {
+			sort = append(sort, bson.E{Key: "score", Value: -1})
+		}
+		sort = append(sort, bson.E{Key: "deadline", Value: -1})
+	}
+	return append(sort, bson.E{Key: "_id", Value: 1})
+}
+
+// textSearch returns the words of a full-text search. Quotes and leading
+// minus signs are $text operators, they are dropped so that user input never
+// becomes a phrase or a negation.
+func textSearch(text string) string {
+	words := make([]string, 0)
+	for _, word := range strings.Fields(strings.ReplaceAll(text, `"`, " ")) {
+		if word = strings.TrimLeft(word, "-"); word != "" {
+			words = append(words, word)
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// titleMatch matches titles containing text, case insensitively. The text
+// is escaped, user input is never a pattern.
+func titleMatch(text string) bson.M {
+	return bson.M{"$regex": regexp.QuoteMeta(text), "$options": "i"}
+}
+
+// customFieldMatch matches the custom field value, equal to Equals when it
+// is set, otherwise within the From and To bounds
+func customFieldMatch(field types.CustomFieldFilter) bson.M {
+	path := "custom_fields." + field.Key
+	if field.Equals != nil {
+		return bson.M{path: field.Equals}
+	}
+	rangeFilter := bson.M{}
+	if field.From != nil {
+		rangeFilter["$gte"] = field.From
+	}
+	if field.To != nil {
+		rangeFilter["$lte"] = field.To
+	}
+	return bson.M{path: rangeFilter}
+}
+
+// spanOverlapMatch matches tasks whose start to deadline span overlaps the
+// range, tasks without start date only span their deadline
+func spanOverlapMatch(from, to int64) []bson.M {
+	and := make([]bson.M, 0)
+	if from != 0 {
+		and = append(and, bson.M{"deadline": bson.M{"$gte": from}})
+	}
+	if to != 0 {
+		and = append(and, bson.M{"$or": []bson.M{
+			{"start_at": bson.M{"$gt": 0, "$lte": to}},
+			{"deadline": bson.M{"$lte": to}},
+		}})
+	}
+	return and
+}
+
+// assigneeMatch matches tasks where the user is any of the assignees, tasks
+// created before assignee lists existed only carry the primary assignee
+func assigneeMatch(userID string) []bson.M {
+	return []bson.M{
+		{"assignee": userID},
+		{"assignees": userID},
+	}
+}
+
+func (r *taskRepository) AggregateMetrics(ctx context.Context, query types.AnalyticsQuery) (*types.TaskMetrics, []*types.UserMetrics, error) {
+	inRange := func(field string) bson.M {
+		return bson.M{"$and": []bson.M{
+			{"$gte": []interface{}{field, query.From}},
+			{"$lte": []interface{}{field, query.To}},
+		}}
+	}
+	pipeline := append(analyticsStages(query),
+		bson.M{"$addFields": bson.M{
+			"created_in":   inRange("$created_at"),
+			"completed_in": bson.M{"$and": []interface{}{bson.M{"$ne": []interface{}{"$done_at", nil}}, inRange("$done_at")}},
+			"overdue": bson.M{"$and": []bson.M{
+				{"$in": []interface{}{"$status", unfinishedStatuses}},
+				{"$gt": []interface{}{"$deadline", 0}},
+				{"$gte": []interface{}{"$deadline", query.From}},
+				{"$lte": []interface{}{"$deadline", query.To}},
+				{"$lt": []interface{}{"$deadline", query.Now}},
+			}},
+		}},
+		bson.M{"$match": bson.M{"$or": []bson.M{
+			{"created_in": true},
+			{"completed_in": true},
+			{"overdue": true},
+		}}},
+		// the cycle time runs from the first move to doing, the tasks moved
+		// before transitions were recorded fall back to their start
+		bson.M{"$lookup": bson.M{
+			"from": TaskTransitionCollection,
+			"let":  bson.M{"task_id": bson.M{"$toString": "$_id"}},
+			"pipeline": []bson.M{
+				{"$match": bson.M{"$expr": bson.M{"$and": []bson.M{
+					{"$eq": []interface{}{"$task_id", "$$task_id"}},
+					{"$eq": []interface{}{"$to", types.TASK_STATUS_DOING}},
+				}}}},
+				{"$group": bson.M{"_id": nil, "at": bson.M{"$min": "$created_at"}}},
+			},
+			"as": "first_doing",
+		}},
+		bson.M{"$addFields": bson.M{
+			"on_time": bson.M{"$and": []interface{}{"$completed_in", bson.M{"$or": []bson.M{
+				{"$lte": []interface{}{"$deadline", 0}},
+				{"$lte": []interface{}{"$done_at", "$deadline"}},
+			}}}},
+			"cycle_time": bson.M{"$cond": []interface{}{
+				"$completed_in",
+				bson.M{"$subtract": []interface{}{
+					"$done_at",
+					bson.M{"$ifNull": []interface{}{bson.M{"$arrayElemAt": []interface{}{"$first_doing.at", 0}}, "$started_at"}},
+				}},
+				nil,
+			}},
+		}},
+	)
+	count := func(field string) bson.M {
+		return bson.M{"$sum": bson.M{"$cond": []interface{}{field, 1, 0}}}
+	}
+	group := func(id interface{}) []bson.M {
+		return []bson.M{
+			{"$group": bson.M{
+				"_id":               id,
+				"created":           count("$created_in"),
+				"completed":         count("$completed_in"),
+				"completed_on_time": count("$on_time"),
+				"avg_cycle_time":    bson.M{"$avg": "$cycle_time"},
+				"overdue":           count("$overdue"),
+			}},
+			// the average of no completion is null
+			{"$addFields": bson.M{"avg_cycle_time": bson.M{"$ifNull": []interface{}{"$avg_cycle_time", 0}}}},
+		}
+	}
+	usersStages := []bson.M{{"$unwind": "$members"}}
+	if len(query.Users) > 0 {
+		usersStages = append(usersStages, bson.M{"$match": bson.M{"members": bson.M{"$in": query.Users}}})
+	}
+	usersStages = append(usersStages, group("$members")...)
+	usersStages = append(usersStages, bson.M{"$sort": bson.M{"_id": 1}})
+	pipeline = append(pipeline, bson.M{"$facet": bson.M{
+		"workspace": group(nil),
+		"users":     usersStages,
+	}})
+	var result []struct {
+		Workspace []*types.TaskMetrics `bson:"workspace"`
+		Users     []*types.UserMetrics `bson:"users"`
+	}
+	if err := r.database.Aggregate(ctx, r.collection, pipeline, &result); err != nil {
+		return nil, nil, err
+	}
+	workspace := &types.TaskMetrics{}
+	users := make([]*types.UserMetrics, 0)
+	if len(result) > 0 {
+		if len(result[0].Workspace) > 0 {
+			workspace = result[0].Workspace[0]
+		}
+		users = append(users, result[0].Users...)
+	}
+	return workspace, users, nil
+}
+
+func (r *taskRepository) AggregateSeries(ctx context.Context, query types.AnalyticsQuery, boundaries []int64) (*types.TaskSeriesCounts, error) {
+	series := &types.TaskSeriesCounts{
+		Created:   make([]int64, max(len(boundaries)-1, 0)),
+		Completed: make([]int64, max(len(boundaries)-1, 0)),
+		Finished:  make([]int64, max(len(boundaries)-1, 0)),
+	}
+	if len(boundaries) < 2 {
+		return series, nil
+	}
+	bucket := func(field string) bson.M {
+		return bson.M{"$bucket": bson.M{
+			"groupBy":    field,
+			"boundaries": boundaries,
+			"default":    "outside",
+			"output":     bson.M{"count": bson.M{"$sum": 1}},
+		}}
+	}
+	start := boundaries[0]
+	pipeline := append(analyticsStages(query), bson.M{"$facet": bson.M{
+		"unfinished": []bson.M{
+			{"$match": bson.M{
+				"created_at": bson.M{"$lt": start},
+				"$or": []bson.M{
+					{"finished_at": nil},
+					{"finished_at": bson.M{"$gte": start}},
+				},
+			}},
+			{"$count": "count"},
+		},
+		"created":   []bson.M{bucket("$created_at")},
+		"completed": []bson.M{{"$match": bson.M{"done_at": bson.M{"$ne": nil}}}, bucket("$done_at")},
+		"finished":  []bson.M{{"$match": bson.M{"finished_at": bson.M{"$ne": nil}}}, bucket("$finished_at")},
+	}})
+	type bucketCount struct {
+		ID    interface{} `bson:"_id"`
+		Count int64       `bson:"count"`
+	}
+	var result []struct {
+		Unfinished []bucketCount `bson:"unfinished"`
+		Created    []bucketCount `bson:"created"`
+		Completed  []bucketCount `bson:"completed"`
+		Finished   []bucketCount `bson:"finished"`
+	}
+	if err := r.database.Aggregate(ctx, r.collection, pipeline, &result); err != nil {
+		return nil, err
+	}
+	if len(result) == 0 {
+		return series, nil
+	}
+	if len(result[0].Unfinished) > 0 {
+		series.Unfinished = result[0].Unfinished[0].Count
+	}
+	// buckets are identified by their lower boundary
+	index := make(map[int64]int, len(boundaries))
+	for i, boundary := range boundaries[:len(boundaries)-1] {
+		index[boundary] = i
+	}
+	for _, counts := range []struct {
+		buckets []bucketCount
+		target  []int64
+	}{
+		{result[0].Created, series.Created},
+		{result[0].Completed, series.Completed},
+		{result[0].Finished, series.Finished},
+	} {
+		for _, bucket := range counts.buckets {
+			boundary, ok := bucket.ID.(int64)
+			if !ok {
+				continue
+			}
+			if i, ok := index[boundary]; ok {
+				counts.target[i] = bucket.Count
+			}
+		}
+	}
+	return series, nil
+}
+
+// analyticsStages selects the tasks of the query and derives the fields the
+// analytics measure: done_at when completed or closed after a completion,
+// finished_at when completed, closed or cancelled, started_at and the members
+// the task is assigned to
+func analyticsStages(query types.AnalyticsQuery) []bson.M {
+	match := bson.M{"workspace": query.Workspace}
+	if len(query.Users) > 0 {
+		match["$or"] = []bson.M{
+			{"assignee": bson.M{"$in": query.Users}},
+			{"assignees": bson.M{"$in": query.Users}},
+		}
+	}
+	// tasks completed before completion times were recorded fall back to
+	// their last update
+	lastChange := bson.M{"$cond": []interface{}{
+		bson.M{"$gt": []interface{}{"$completed_at", 0}},
+		"$completed_at",
+		"$updated_at",
+	}}
+	return []bson.M{
+		{"$match": match},
+		{"$addFields": bson.M{
+			"done_at": bson.M{"$switch": bson.M{
+				"branches": []bson.M{
+					{
+						"case": bson.M{"$and": []bson.M{
+							{"$in": []interface{}{"$status", bson.A{types.TASK_STATUS_COMPLETED, types.TASK_STATUS_CLOSE}}},
+							{"$gt": []interface{}{"$completed_at", 0}},
+						}},
+						"then": "$completed_at",
+					},
+					{
+						"case": bson.M{"$eq": []interface{}{"$status", types.TASK_STATUS_COMPLETED}},
+						"then": "$updated_at",
+					},
+				},
+				"default": nil,
+			}},
+			"finished_at": bson.M{"$cond": []interface{}{
+				bson.M{"$in": []interface{}{"$status", unfinishedStatuses}},
+				nil,
+				lastChange,
+			}},
+			"started_at": bson.M{"$cond": []interface{}{
+				bson.M{"$gt": []interface{}{"$start_at", 0}},
+				"$start_at",
+				"$created_at",
+			}},
+			"members": bson.M{"$cond": []interface{}{
+				bson.M{"$gt": []interface{}{bson.M{"$size": bson.M{"$ifNull": []interface{}{"$assignees", bson.A{}}}}, 0}},
+				"$assignees",
+				bson.A{"$assignee"},
+			}},
+		}},
+	}
 }
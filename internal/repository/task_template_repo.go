@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/remiehneppo/be-task-management/internal/database"
+	"github.com/remiehneppo/be-task-management/types"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+const TaskTemplateCollection = "task_templates"
+
+var _ TaskTemplateRepository = (*taskTemplateRepository)(nil)
+
+type TaskTemplateRepository interface {
+	Save(ctx context.Context, template *types.TaskTemplate) error
+	FindByID(ctx context.Context, id string) (*types.TaskTemplate, error)
+	FindActive(ctx context.Context) ([]*types.TaskTemplate, error)
+	PaginateByWorkspace(ctx context.Context, workspace string, page, limit int64) ([]*types.TaskTemplate, int64, error)
+	// Update stores the fields users edit, leaving the occurrences to
+	// UpdateOccurrences
+	Update(ctx context.Context, id string, template *types.TaskTemplate) error
+	// UpdateOccurrences stores the last occurrence handled and the skipped
+	// count, leaving the fields users edit untouched
+	UpdateOccurrences(ctx context.Context, id string, lastOccurrence, skipped int64) error
+	Delete(ctx context.Context, id string) error
+}
+
+type taskTemplateRepository struct {
+	database   database.Database
+	collection string
+}
+
+func NewTaskTemplateRepository(db database.Database) TaskTemplateRepository {
+	return &taskTemplateRepository{
+		database:   db,
+		collection: TaskTemplateCollection,
+	}
+}
+
+func (r *taskTemplateRepository) Save(ctx context.Context, template *types.TaskTemplate) error {
+	id, err := r.database.Insert(ctx, r.collection, template)
+	if err != nil {
+		return err
+	}
+	template.ID = id
+	return nil
+}
+
+func (r *taskTemplateRepository) FindByID(ctx context.Context, id string) (*types.TaskTemplate, error) {
+	var template types.TaskTemplate
+	err := r.database.FindByID(ctx, r.collection, id, &template)
+	if err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+func (r *taskTemplateRepository) FindActive(ctx context.Context) ([]*types.TaskTemplate, error) {
+	templates := make([]*types.TaskTemplate, 0)
+	err := r.database.Query(ctx, r.collection, bson.M{"active": true}, 0, 0, bson.M{"created_at": 1}, &templates)
+	if err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+func (r *taskTemplateRepository) PaginateByWorkspace(ctx context.Context, workspace string, page, limit int64) ([]*types.TaskTemplate, int64, error) {
+	filter := bson.M{"workspace": workspace}
+	total, err := r.database.Count(ctx, r.collection, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	var skip int64 = 0
+	if page > 0 {
+		skip = (page - 1) * limit
+	}
+	templates := make([]*types.TaskTemplate, 0)
+	err = r.database.Query(ctx, r.collection, filter, skip, limit, bson.M{"created_at": -1}, &templates)
+	if err != nil {
+		return nil, 0, err
+	}
+	return templates, total, nil
+}
+
+func (r *taskTemplateRepository) Update(ctx context.Context, id string, template *types.TaskTemplate) error {
+	objId, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	return r.database.UpdateMany(
+		ctx,
+		r.collection,
+		bson.M{"_id": objId},
+		bson.M{"$set": bson.M{
+			"title":          template.Title,
+			"description":    template.Description,
+			"assignee":       template.Assignee,
+			"assignees":      template.Assignees,
+			"watchers":       template.Watchers,
+			"recurrence":     template.Recurrence,
+			"timezone":       template.Timezone,
+			"end_at":         template.EndAt,
+			"duration":       template.Duration,
+			"generate_ahead": template.GenerateAhead,
+			"overlap_policy": template.OverlapPolicy,
+			"active":         template.Active,
+			"updated_at":     template.UpdatedAt,
+		}},
+	)
+}
+
+func (r *taskTemplateRepository) UpdateOccurrences(ctx context.Context, id string, lastOccurrence, skipped int64) error {
+	objId, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	return r.database.UpdateMany(
+		ctx,
+		r.collection,
+		bson.M{"_id": objId},
+		bson.M{"$set": bson.M{"last_occurrence": lastOccurrence, "skipped": skipped}},
+	)
+}
+
+func (r *taskTemplateRepository) Delete(ctx context.Context, id string) error {
+	return r.database.Delete(ctx, r.collection, id)
+}
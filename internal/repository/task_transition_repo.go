@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/remiehneppo/be-task-management/internal/database"
+	"github.com/remiehneppo/be-task-management/types"
+)
+
+const TaskTransitionCollection = "task_status_transitions"
+
+var _ TaskTransitionRepository = (*taskTransitionRepository)(nil)
+
+// TaskTransitionRepository stores the status transitions of the tasks, the
+// analytics read them from TaskTransitionCollection to measure cycle times.
+// The task history shows them through the status change activities.
+type TaskTransitionRepository interface {
+	Save(ctx context.Context, transition *types.TaskStatusTransition) error
+}
+
+type taskTransitionRepository struct {
+	database   database.Database
+	collection string
+}
+
+func NewTaskTransitionRepository(db database.Database) TaskTransitionRepository {
+	return &taskTransitionRepository{
+		database:   db,
+		collection: TaskTransitionCollection,
+	}
+}
+
+func (r *taskTransitionRepository) Save(ctx context.Context, transition *types.TaskStatusTransition) error {
+	return r.database.Save(ctx, r.collection, transition)
+}
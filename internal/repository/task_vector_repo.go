@@ -0,0 +1,257 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/remiehneppo/be-task-management/types"
+	"github.com/remiehneppo/be-task-management/utils"
+	"github.com/weaviate/weaviate-go-client/v4/weaviate"
+	"github.com/weaviate/weaviate-go-client/v4/weaviate/filters"
+	"github.com/weaviate/weaviate-go-client/v4/weaviate/graphql"
+	"github.com/weaviate/weaviate/entities/models"
+)
+
+var _ TaskVectorRepository = (*taskVectorRepository)(nil)
+
+// DefaultTaskClass holds the title and description of every task, only they
+// are vectorized
+var DefaultTaskClass = &models.Class{
+	Class: "Task",
+	Properties: []*models.Property{
+		{Name: "title", DataType: []string{"text"}},
+		{Name: "description", DataType: []string{"text"}},
+		{Name: "task_id", DataType: []string{"text"}},
+		{Name: "workspace", DataType: []string{"text"}},
+	},
+	VectorIndexType: "hnsw",
+}
+
+// TaskVectorProperties are the properties of DefaultTaskClass kept out of
+// the vectors
+var TaskVectorProperties = []string{"task_id", "workspace"}
+
+type TaskVectorRepository interface {
+	// SaveTask creates or replaces the vector of the task
+	SaveTask(ctx context.Context, task *types.Task) error
+	HasTask(ctx context.Context, taskID string) (bool, error)
+	// IndexedTasks returns which of the tasks have a vector
+	IndexedTasks(ctx context.Context, taskIDs []string) (map[string]bool, error)
+	RemoveTask(ctx context.Context, taskID string) error
+	// FindSimilar returns the tasks of the workspace closest to the task,
+	// at least as similar as certainty, the task itself excluded
+	FindSimilar(ctx context.Context, taskID, workspace string, certainty float32, limit int) ([]*types.TaskSimilarity, error)
+	// Search returns the tasks of the workspace closest to the query, only
+	// among taskIDs when set
+	Search(ctx context.Context, workspace string, taskIDs []string, query string, limit int) ([]*types.TaskSimilarity, error)
+}
+
+type taskVectorRepository struct {
+	client *weaviate.Client
+	class  *models.Class
+}
+
+func NewTaskVectorRepository(ctx context.Context, client *weaviate.Client, taskClass *models.Class) *taskVectorRepository {
+	schema, err := client.Schema().Getter().Do(ctx)
+	if err != nil {
+		panic(fmt.Sprintf("failed to get schema: %v", err))
+	}
+	hasTaskClass := false
+	for _, class := range schema.Classes {
+		if class.Class == taskClass.Class {
+			hasTaskClass = true
+			break
+		}
+	}
+	if !hasTaskClass {
+		err = client.Schema().ClassCreator().WithClass(taskClass).Do(ctx)
+		if err != nil {
+			panic(fmt.Sprintf("failed to create class %s: %v", taskClass.Class, err))
+		}
+	}
+	return &taskVectorRepository{
+		client: client,
+		class:  taskClass,
+	}
+}
+
+func (r *taskVectorRepository) SaveTask(ctx context.Context, task *types.Task) error {
+	properties := map[string]interface{}{
+		"title":       task.Title,
+		"description": task.Description,
+		"task_id":     task.ID,
+		"workspace":   task.Workspace,
+	}
+	id := taskVectorID(task.ID)
+	exists, err := r.HasTask(ctx, task.ID)
+	if err != nil {
+		return err
+	}
+	if exists {
+		err = r.client.Data().Updater().
+			WithClassName(r.class.Class).
+			WithID(id).
+			WithProperties(properties).
+			Do(ctx)
+	} else {
+		_, err = r.client.Data().Creator().
+			WithClassName(r.class.Class).
+			WithID(id).
+			WithProperties(properties).
+			Do(ctx)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save task vector: %w", err)
+	}
+	return nil
+}
+
+func (r *taskVectorRepository) HasTask(ctx context.Context, taskID string) (bool, error) {
+	exists, err := r.client.Data().Checker().
+		WithClassName(r.class.Class).
+		WithID(taskVectorID(taskID)).
+		Do(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to check task vector: %w", err)
+	}
+	return exists, nil
+}
+
+func (r *taskVectorRepository) IndexedTasks(ctx context.Context, taskIDs []string) (map[string]bool, error) {
+	indexed := make(map[string]bool, len(taskIDs))
+	if len(taskIDs) == 0 {
+		return indexed, nil
+	}
+	result, err := r.client.GraphQL().Get().
+		WithClassName(r.class.Class).
+		WithFields(graphql.Field{Name: "task_id"}).
+		WithWhere(filters.Where().
+			WithPath([]string{"task_id"}).
+			WithOperator(filters.ContainsAny).
+			WithValueString(taskIDs...)).
+		WithLimit(len(taskIDs)).
+		Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check task vectors: %w", err)
+	}
+	if result.Errors != nil {
+		return nil, fmt.Errorf("failed to check task vectors: %v", result.Errors)
+	}
+	data, _ := result.Data["Get"].(map[string]interface{})[r.class.Class].([]interface{})
+	for _, item := range data {
+		object, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if id := utils.ParseString(object["task_id"]); id != "" {
+			indexed[id] = true
+		}
+	}
+	return indexed, nil
+}
+
+func (r *taskVectorRepository) RemoveTask(ctx context.Context, taskID string) error {
+	_, err := r.client.Batch().ObjectsBatchDeleter().
+		WithClassName(r.class.Class).
+		WithWhere(filters.Where().
+			WithPath([]string{"task_id"}).
+			WithOperator(filters.Equal).
+			WithValueString(taskID)).
+		Do(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to remove task vector: %w", err)
+	}
+	return nil
+}
+
+func (r *taskVectorRepository) FindSimilar(ctx context.Context, taskID, workspace string, certainty float32, limit int) ([]*types.TaskSimilarity, error) {
+	nearObject := r.client.GraphQL().NearObjectArgBuilder().
+		WithID(taskVectorID(taskID)).
+		WithCertainty(certainty)
+	getBuilder := r.getBuilder(workspace, nil).WithNearObject(nearObject)
+	if limit > 0 {
+		// the task itself is the closest match
+		getBuilder.WithLimit(limit + 1)
+	}
+	similar, err := r.search(ctx, getBuilder)
+	if err != nil {
+		return nil, err
+	}
+	similar = slices.DeleteFunc(similar, func(task *types.TaskSimilarity) bool {
+		return task.TaskID == taskID
+	})
+	if limit > 0 && len(similar) > limit {
+		similar = similar[:limit]
+	}
+	return similar, nil
+}
+
+func (r *taskVectorRepository) Search(ctx context.Context, workspace string, taskIDs []string, query string, limit int) ([]*types.TaskSimilarity, error) {
+	nearText := r.client.GraphQL().NearTextArgBuilder().
+		WithConcepts([]string{query}).
+		WithCertainty(0.7)
+	getBuilder := r.getBuilder(workspace, taskIDs).WithNearText(nearText)
+	if limit > 0 {
+		getBuilder.WithLimit(limit)
+	}
+	return r.search(ctx, getBuilder)
+}
+
+func (r *taskVectorRepository) getBuilder(workspace string, taskIDs []string) *graphql.GetBuilder {
+	where := filters.Where().
+		WithPath([]string{"workspace"}).
+		WithOperator(filters.Equal).
+		WithValueString(workspace)
+	if len(taskIDs) > 0 {
+		where = filters.Where().WithOperator(filters.And).WithOperands([]*filters.WhereBuilder{
+			where,
+			filters.Where().
+				WithPath([]string{"task_id"}).
+				WithOperator(filters.ContainsAny).
+				WithValueString(taskIDs...),
+		})
+	}
+	return r.client.GraphQL().Get().
+		WithClassName(r.class.Class).
+		WithFields(
+			graphql.Field{Name: "task_id"},
+			graphql.Field{Name: "_additional", Fields: []graphql.Field{{Name: "certainty"}}},
+		).
+		WithWhere(where)
+}
+
+// search runs the query and returns the tasks found, most similar first
+func (r *taskVectorRepository) search(ctx context.Context, getBuilder *graphql.GetBuilder) ([]*types.TaskSimilarity, error) {
+	result, err := getBuilder.Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search task vector: %w", err)
+	}
+	if result.Errors != nil {
+		return nil, fmt.Errorf("failed to search task vector: %v", result.Errors)
+	}
+	similar := make([]*types.TaskSimilarity, 0)
+	if data, ok := result.Data["Get"].(map[string]interface{})[r.class.Class].([]interface{}); ok {
+		for _, item := range data {
+			object, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			id := utils.ParseString(object["task_id"])
+			if id == "" {
+				continue
+			}
+			additional, _ := object["_additional"].(map[string]interface{})
+			score, _ := additional["certainty"].(float64)
+			similar = append(similar, &types.TaskSimilarity{TaskID: id, Similarity: score})
+		}
+	}
+	return similar, nil
+}
+
+// taskVectorID derives the object ID of a task from its task ID, so that a
+// task has a single vector
+func taskVectorID(taskID string) string {
+	return uuid.NewSHA1(uuid.NameSpaceOID, []byte(taskID)).String()
+}
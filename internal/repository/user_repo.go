@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"regexp"
 
 	"github.com/remiehneppo/be-task-management/internal/database"
 	"github.com/remiehneppo/be-task-management/types"
@@ -10,6 +11,10 @@ import (
 
 var _ UserRepository = &userRepository{}
 
+// userPageSort orders paged users by name, the _id tiebreaker makes
+// positions unique for cursors
+var userPageSort = bson.D{{Key: "full_name", Value: 1}, {Key: "_id", Value: 1}}
+
 type UserRepository interface {
 	Save(ctx context.Context, user *types.User) error
 	FindByID(ctx context.Context, id string) (*types.User, error)
@@ -20,8 +25,13 @@ type UserRepository interface {
 	Delete(ctx context.Context, id string) error
 	FindByWorkspace(ctx context.Context, workspace string) ([]*types.User, error)
 	FindByWorkspaceAndRole(ctx context.Context, workspace string, role string) ([]*types.User, error)
+	PaginateByWorkspace(ctx context.Context, workspace string, page types.PageRequest) ([]*types.User, int64, string, error)
 	Paginate(ctx context.Context, page int64, limit int64) ([]*types.User, int64, error)
+	// PaginateWithFilter returns the users matching filter by name, without
+	// their password
+	PaginateWithFilter(ctx context.Context, filter types.UserFilter, page, limit int64) ([]*types.User, int64, error)
 	Count(ctx context.Context) (int64, error)
+	CountWithFilter(ctx context.Context, filter types.UserFilter) (int64, error)
 }
 
 type userRepository struct {
@@ -37,7 +47,12 @@ func NewUserRepository(db database.Database) UserRepository {
 }
 
 func (r *userRepository) Save(ctx context.Context, user *types.User) error {
-	return r.database.Save(ctx, r.collection, user)
+	id, err := r.database.Insert(ctx, r.collection, user)
+	if err != nil {
+		return err
+	}
+	user.ID = id
+	return nil
 }
 
 func (r *userRepository) FindByID(ctx context.Context, id string) (*types.User, error) {
@@ -138,6 +153,44 @@ func (r *userRepository) FindByWorkspaceAndRole(ctx context.Context, workspace s
 	return users, nil
 }
 
+// PaginateByWorkspace returns a page of the workspace members by name, the
+// member count and the cursor of the next page when there is one
+func (r *userRepository) PaginateByWorkspace(ctx context.Context, workspace string, page types.PageRequest) ([]*types.User, int64, string, error) {
+	filter := bson.M{"workspace": workspace}
+	total, err := r.database.Count(ctx, r.collection, filter)
+	if err != nil {
+		return nil, 0, "", err
+	}
+	skip := pageSkip(page)
+	if page.Cursor != "" {
+		values, err := decodeCursor(userPageSort, page.Cursor)
+		if err != nil {
+			return nil, 0, "", err
+		}
+		filter = bson.M{"$and": []bson.M{filter, cursorMatch(userPageSort, values)}}
+		skip = 0
+	}
+	// one more user than the page tells whether a next page exists
+	users := make([]*types.User, 0)
+	err = r.database.Query(ctx, r.collection, filter, skip, page.Limit+1, userPageSort, &users)
+	if err != nil {
+		return nil, 0, "", err
+	}
+	nextCursor := ""
+	if int64(len(users)) > page.Limit {
+		users = users[:page.Limit]
+		nextCursor, err = encodeItemCursor(userPageSort, users[len(users)-1])
+		if err != nil {
+			return nil, 0, "", err
+		}
+	}
+	// Remove password from users
+	for _, user := range users {
+		user.Password = ""
+	}
+	return users, total, nextCursor, nil
+}
+
 func (r *userRepository) Paginate(ctx context.Context, page int64, limit int64) ([]*types.User, int64, error) {
 	users := make([]*types.User, 0)
 	err := r.database.Query(ctx, r.collection, nil, page*limit, limit, nil, users)
@@ -163,3 +216,48 @@ func (r *userRepository) Count(ctx context.Context) (int64, error) {
 	}
 	return count, nil
 }
+
+func (r *userRepository) PaginateWithFilter(ctx context.Context, filter types.UserFilter, page, limit int64) ([]*types.User, int64, error) {
+	query := userFilterQuery(filter)
+	total, err := r.database.Count(ctx, r.collection, query)
+	if err != nil {
+		return nil, 0, err
+	}
+	var skip int64 = 0
+	if page > 0 {
+		skip = (page - 1) * limit
+	}
+	users := make([]*types.User, 0)
+	err = r.database.Query(ctx, r.collection, query, skip, limit, userPageSort, &users)
+	if err != nil {
+		return nil, 0, err
+	}
+	// Remove password from users
+	for _, user := range users {
+		user.Password = ""
+	}
+	return users, total, nil
+}
+
+func (r *userRepository) CountWithFilter(ctx context.Context, filter types.UserFilter) (int64, error) {
+	return r.database.Count(ctx, r.collection, userFilterQuery(filter))
+}
+
+// userFilterQuery matches the filter, the texts are escaped and matched
+// case insensitively
+func userFilterQuery(filter types.UserFilter) bson.M {
+	query := bson.M{}
+	if filter.Username != "" {
+		query["username"] = bson.M{"$regex": regexp.QuoteMeta(filter.Username), "$options": "i"}
+	}
+	if filter.FullName != "" {
+		query["full_name"] = bson.M{"$regex": regexp.QuoteMeta(filter.FullName), "$options": "i"}
+	}
+	if filter.Workspace != "" {
+		query["workspace"] = filter.Workspace
+	}
+	if filter.WorkspaceRole != "" {
+		query["workspace_role"] = filter.WorkspaceRole
+	}
+	return query
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/remiehneppo/be-task-management/internal/database"
+	"github.com/remiehneppo/be-task-management/types"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+const WeeklySummaryCollection = "weekly_summaries"
+
+var _ WeeklySummaryRepository = (*weeklySummaryRepository)(nil)
+
+type WeeklySummaryRepository interface {
+	Save(ctx context.Context, summary *types.WeeklySummary) error
+	FindByID(ctx context.Context, id string) (*types.WeeklySummary, error)
+	// PaginateByWorkspace returns the summaries of the workspace, latest
+	// week first
+	PaginateByWorkspace(ctx context.Context, workspace string, page, limit int64) ([]*types.WeeklySummary, int64, error)
+	// CountScheduled counts the scheduled summaries of the workspace week
+	CountScheduled(ctx context.Context, workspace string, weekStart int64) (int64, error)
+}
+
+type weeklySummaryRepository struct {
+	database   database.Database
+	collection string
+}
+
+func NewWeeklySummaryRepository(db database.Database) WeeklySummaryRepository {
+	return &weeklySummaryRepository{
+		database:   db,
+		collection: WeeklySummaryCollection,
+	}
+}
+
+func (r *weeklySummaryRepository) Save(ctx context.Context, summary *types.WeeklySummary) error {
+	id, err := r.database.Insert(ctx, r.collection, summary)
+	if err != nil {
+		return err
+	}
+	summary.ID = id
+	return nil
+}
+
+func (r *weeklySummaryRepository) FindByID(ctx context.Context, id string) (*types.WeeklySummary, error) {
+	var summary types.WeeklySummary
+	err := r.database.FindByID(ctx, r.collection, id, &summary)
+	if err != nil {
+		return nil, err
+	}
+	return &summary, nil
+}
+
+func (r *weeklySummaryRepository) PaginateByWorkspace(ctx context.Context, workspace string, page, limit int64) ([]*types.WeeklySummary, int64, error) {
+	filter := bson.M{"workspace": workspace}
+	total, err := r.database.Count(ctx, r.collection, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	var skip int64 = 0
+	if page > 0 {
+		skip = (page - 1) * limit
+	}
+	summaries := make([]*types.WeeklySummary, 0)
+	sort := bson.D{{Key: "week_start", Value: -1}, {Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}
+	err = r.database.Query(ctx, r.collection, filter, skip, limit, sort, &summaries)
+	if err != nil {
+		return nil, 0, err
+	}
+	return summaries, total, nil
+}
+
+func (r *weeklySummaryRepository) CountScheduled(ctx context.Context, workspace string, weekStart int64) (int64, error) {
+	return r.database.Count(ctx, r.collection, bson.M{
+		"workspace":  workspace,
+		"week_start": weekStart,
+		"requester":  bson.M{"$exists": false},
+	})
+}
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/remiehneppo/be-task-management/internal/database"
+	"github.com/remiehneppo/be-task-management/types"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+const WorkspaceCollection = "workspaces"
+
+var _ WorkspaceRepository = (*workspaceRepository)(nil)
+
+type WorkspaceRepository interface {
+	// EnsureDefaults creates the DEFAULT_WORKSPACES when there is no
+	// workspace yet
+	EnsureDefaults(ctx context.Context) error
+	FindAll(ctx context.Context) ([]*types.Workspace, error)
+	FindByID(ctx context.Context, id string) (*types.Workspace, error)
+	FindByName(ctx context.Context, name string) (*types.Workspace, error)
+	Save(ctx context.Context, workspace *types.Workspace) error
+	Update(ctx context.Context, id string, workspace *types.Workspace) error
+	Delete(ctx context.Context, id string) error
+}
+
+type workspaceRepository struct {
+	database   database.Database
+	collection string
+}
+
+func NewWorkspaceRepository(db database.Database) WorkspaceRepository {
+	return &workspaceRepository{
+		database:   db,
+		collection: WorkspaceCollection,
+	}
+}
+
+func (r *workspaceRepository) EnsureDefaults(ctx context.Context) error {
+	count, err := r.database.Count(ctx, r.collection, bson.M{})
+	if err != nil || count > 0 {
+		return err
+	}
+	now := time.Now().Unix()
+	for _, name := range types.DEFAULT_WORKSPACES {
+		err := r.Save(ctx, &types.Workspace{
+			Name:        name,
+			DisplayName: name,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FindAll returns the workspaces by name
+func (r *workspaceRepository) FindAll(ctx context.Context) ([]*types.Workspace, error) {
+	workspaces := make([]*types.Workspace, 0)
+	err := r.database.FindAll(ctx, r.collection, bson.D{{Key: "name", Value: 1}}, &workspaces)
+	if err != nil {
+		return nil, err
+	}
+	return workspaces, nil
+}
+
+func (r *workspaceRepository) FindByID(ctx context.Context, id string) (*types.Workspace, error) {
+	var workspace types.Workspace
+	err := r.database.FindByID(ctx, r.collection, id, &workspace)
+	if err != nil {
+		return nil, err
+	}
+	return &workspace, nil
+}
+
+func (r *workspaceRepository) FindByName(ctx context.Context, name string) (*types.Workspace, error) {
+	var workspaces []*types.Workspace
+	err := r.database.Query(ctx, r.collection, bson.M{"name": name}, 0, 1, nil, &workspaces)
+	if err != nil {
+		return nil, err
+	}
+	if len(workspaces) == 0 {
+		return nil, types.ErrWorkspaceNotFound
+	}
+	return workspaces[0], nil
+}
+
+func (r *workspaceRepository) Save(ctx context.Context, workspace *types.Workspace) error {
+	id, err := r.database.Insert(ctx, r.collection, workspace)
+	if err != nil {
+		return err
+	}
+	workspace.ID = id
+	return nil
+}
+
+func (r *workspaceRepository) Update(ctx context.Context, id string, workspace *types.Workspace) error {
+	return r.database.Update(ctx, r.collection, id, workspace)
+}
+
+func (r *workspaceRepository) Delete(ctx context.Context, id string) error {
+	return r.database.Delete(ctx, r.collection, id)
+}
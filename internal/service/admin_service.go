@@ -0,0 +1,349 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/remiehneppo/be-task-management/internal/repository"
+	"github.com/remiehneppo/be-task-management/types"
+)
+
+var _ AdminService = (*adminService)(nil)
+
+// AdminService manages the users and reads the audit log. Admins manage
+// every user, other members whose role grants user.manage only manage the
+// users of their workspace below their management level. Every change is
+// audit-logged.
+type AdminService interface {
+	GetUsers(ctx context.Context, filter types.UserFilter, page, limit int64) ([]*types.User, int64, error)
+	GetUser(ctx context.Context, id string) (*types.User, error)
+	CreateUser(ctx context.Context, req *types.CreateUserRequest) (*types.User, error)
+	UpdateUser(ctx context.Context, req *types.UpdateUserRequest) (*types.User, error)
+	// UpdateUserRole changes the workspace role, the management level and
+	// the admin role of the user
+	UpdateUserRole(ctx context.Context, req *types.UpdateUserRoleRequest) (*types.User, error)
+	ResetPassword(ctx context.Context, req *types.ResetPasswordRequest) error
+	// DeactivateUser keeps the user and their history but prevents them from
+	// signing in, the access tokens already issued are refused
+	DeactivateUser(ctx context.Context, id string) error
+	ActivateUser(ctx context.Context, id string) error
+	// DeleteUser deletes a user whom no task, report nor comment refers to
+	DeleteUser(ctx context.Context, id string) error
+	GetAuditLogs(ctx context.Context, filter types.AuditLogFilter, page, limit int64) ([]*types.AuditLog, int64, error)
+}
+
+type adminService struct {
+	userRepo      repository.UserRepository
+	taskRepo      repository.TaskRepository
+	reportRepo    repository.ReportRepository
+	commentRepo   repository.CommentRepository
+	workspaceRepo repository.WorkspaceRepository
+	auditLogRepo  repository.AuditLogRepository
+	authorizer    AuthorizationService
+}
+
+func NewAdminService(
+	userRepo repository.UserRepository,
+	taskRepo repository.TaskRepository,
+	reportRepo repository.ReportRepository,
+	commentRepo repository.CommentRepository,
+	workspaceRepo repository.WorkspaceRepository,
+	auditLogRepo repository.AuditLogRepository,
+	authorizer AuthorizationService,
+) AdminService {
+	return &adminService{
+		userRepo:      userRepo,
+		taskRepo:      taskRepo,
+		reportRepo:    reportRepo,
+		commentRepo:   commentRepo,
+		workspaceRepo: workspaceRepo,
+		auditLogRepo:  auditLogRepo,
+		authorizer:    authorizer,
+	}
+}
+
+func (s *adminService) GetUsers(ctx context.Context, filter types.UserFilter, page, limit int64) ([]*types.User, int64, error) {
+	actor, err := s.userManager(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	if actor.Role != types.USER_ROLE_ADMIN {
+		filter.Workspace = actor.Workspace
+	}
+	return s.userRepo.PaginateWithFilter(ctx, filter, page, limit)
+}
+
+func (s *adminService) GetUser(ctx context.Context, id string) (*types.User, error) {
+	actor, err := s.userManager(ctx)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.userRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if actor.Role != types.USER_ROLE_ADMIN && user.Workspace != actor.Workspace {
+		return nil, types.ErrUserNotManaged
+	}
+	user.Password = ""
+	return user, nil
+}
+
+func (s *adminService) CreateUser(ctx context.Context, req *types.CreateUserRequest) (*types.User, error) {
+	actor, err := s.userManager(ctx)
+	if err != nil {
+		return nil, err
+	}
+	username := strings.TrimSpace(req.Username)
+	if username == "" || strings.ContainsFunc(username, func(c rune) bool { return c == ' ' || c == '\t' }) {
+		return nil, types.ErrInvalidUsername
+	}
+	_, err = s.userRepo.FindByUsername(ctx, username)
+	if err == nil {
+		return nil, types.ErrUsernameTaken
+	}
+	if !errors.Is(err, types.ErrUserNotFound) {
+		return nil, err
+	}
+	if _, err := s.workspaceRepo.FindByName(ctx, req.Workspace); err != nil {
+		return nil, err
+	}
+	level, err := userManagementLevel(req.WorkspaceRole, req.ManagementLevel, req.Role)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().Unix()
+	user := &types.User{
+		Username:        username,
+		Password:        req.Password,
+		FullName:        req.FullName,
+		Role:            req.Role,
+		ManagementLevel: level,
+		WorkspaceRole:   req.WorkspaceRole,
+		Workspace:       req.Workspace,
+		CreateAt:        now,
+		UpdateAt:        now,
+	}
+	if err := checkManagedUser(actor, user); err != nil {
+		return nil, err
+	}
+	if err := s.userRepo.Save(ctx, user); err != nil {
+		return nil, err
+	}
+	recordAudit(ctx, s.auditLogRepo, &types.AuditLog{
+		Actor:      actor.ID,
+		Action:     types.AUDIT_ACTION_USER_CREATE,
+		TargetType: types.AUDIT_TARGET_USER,
+		TargetID:   user.ID,
+		Changes:    diffUser(&types.User{}, user),
+	})
+	user.Password = ""
+	return user, nil
+}
+
+func (s *adminService) UpdateUser(ctx context.Context, req *types.UpdateUserRequest) (*types.User, error) {
+	actor, user, err := s.findManagedUser(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+	updated := *user
+	if req.FullName != "" {
+		updated.FullName = req.FullName
+	}
+	if req.Workspace != "" && req.Workspace != user.Workspace {
+		if _, err := s.workspaceRepo.FindByName(ctx, req.Workspace); err != nil {
+			return nil, err
+		}
+		updated.Workspace = req.Workspace
+	}
+	// members without admin role cannot move users out of their workspace
+	if err := checkManagedUser(actor, &updated); err != nil {
+		return nil, err
+	}
+	return s.saveUser(ctx, actor, user, &updated, types.AUDIT_ACTION_USER_UPDATE)
+}
+
+func (s *adminService) UpdateUserRole(ctx context.Context, req *types.UpdateUserRoleRequest) (*types.User, error) {
+	actor, user, err := s.findManagedUser(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+	if user.ID == actor.ID {
+		return nil, types.ErrAdminSelf
+	}
+	level, err := userManagementLevel(req.WorkspaceRole, req.ManagementLevel, req.Role)
+	if err != nil {
+		return nil, err
+	}
+	updated := *user
+	updated.WorkspaceRole = req.WorkspaceRole
+	updated.ManagementLevel = level
+	updated.Role = req.Role
+	if err := checkManagedUser(actor, &updated); err != nil {
+		return nil, err
+	}
+	return s.saveUser(ctx, actor, user, &updated, types.AUDIT_ACTION_USER_ROLE)
+}
+
+func (s *adminService) ResetPassword(ctx context.Context, req *types.ResetPasswordRequest) error {
+	actor, user, err := s.findManagedUser(ctx, req.ID)
+	if err != nil {
+		return err
+	}
+	updated := *user
+	updated.Password = req.Password
+	_, err = s.saveUser(ctx, actor, user, &updated, types.AUDIT_ACTION_USER_PASSWORD)
+	return err
+}
+
+func (s *adminService) DeactivateUser(ctx context.Context, id string) error {
+	return s.setDeactivated(ctx, id, true)
+}
+
+func (s *adminService) ActivateUser(ctx context.Context, id string) error {
+	return s.setDeactivated(ctx, id, false)
+}
+
+func (s *adminService) DeleteUser(ctx context.Context, id string) error {
+	actor, user, err := s.findManagedUser(ctx, id)
+	if err != nil {
+		return err
+	}
+	if user.ID == actor.ID {
+		return types.ErrAdminSelf
+	}
+	counts := []func() (int64, error){
+		func() (int64, error) { return s.taskRepo.CountWithFilter(ctx, types.TaskFilter{Creator: id}) },
+		func() (int64, error) { return s.taskRepo.CountWithFilter(ctx, types.TaskFilter{Assignee: id}) },
+		func() (int64, error) { return s.taskRepo.CountWithFilter(ctx, types.TaskFilter{Watcher: id}) },
+		func() (int64, error) { return s.reportRepo.CountByUser(ctx, id) },
+		func() (int64, error) { return s.commentRepo.CountByAuthor(ctx, id) },
+	}
+	for _, count := range counts {
+		n, err := count()
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			return types.ErrUserHasHistory
+		}
+	}
+	if err := s.userRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+	recordAudit(ctx, s.auditLogRepo, &types.AuditLog{
+		Actor:      actor.ID,
+		Action:     types.AUDIT_ACTION_USER_DELETE,
+		TargetType: types.AUDIT_TARGET_USER,
+		TargetID:   id,
+		Changes:    diffUser(user, &types.User{}),
+	})
+	return nil
+}
+
+func (s *adminService) GetAuditLogs(ctx context.Context, filter types.AuditLogFilter, page, limit int64) ([]*types.AuditLog, int64, error) {
+	userID, ok := ctx.Value("user_id").(string)
+	if !ok {
+		return nil, 0, types.ErrInvalidCredentials
+	}
+	if _, err := s.authorizer.Authorize(ctx, userID, types.PERMISSION_AUDIT_READ); err != nil {
+		return nil, 0, err
+	}
+	return s.auditLogRepo.Paginate(ctx, filter, page, limit)
+}
+
+func (s *adminService) setDeactivated(ctx context.Context, id string, deactivated bool) error {
+	actor, user, err := s.findManagedUser(ctx, id)
+	if err != nil {
+		return err
+	}
+	if user.ID == actor.ID {
+		return types.ErrAdminSelf
+	}
+	if user.Deactivated == deactivated {
+		return nil
+	}
+	updated := *user
+	updated.Deactivated = deactivated
+	action := types.AUDIT_ACTION_USER_ACTIVATE
+	if deactivated {
+		action = types.AUDIT_ACTION_USER_DEACTIVATE
+	}
+	_, err = s.saveUser(ctx, actor, user, &updated, action)
+	return err
+}
+
+// saveUser stores the updated user and audit-logs the change
+func (s *adminService) saveUser(ctx context.Context, actor, user, updated *types.User, action string) (*types.User, error) {
+	updated.ID = ""
+	updated.UpdateAt = time.Now().Unix()
+	if err := s.userRepo.Update(ctx, user.ID, updated); err != nil {
+		return nil, err
+	}
+	updated.ID = user.ID
+	recordAudit(ctx, s.auditLogRepo, &types.AuditLog{
+		Actor:      actor.ID,
+		Action:     action,
+		TargetType: types.AUDIT_TARGET_USER,
+		TargetID:   user.ID,
+		Changes:    diffUser(user, updated),
+	})
+	updated.Password = ""
+	return updated, nil
+}
+
+// userManager returns the current user, whose role must grant user.manage
+func (s *adminService) userManager(ctx context.Context) (*types.User, error) {
+	userID, ok := ctx.Value("user_id").(string)
+	if !ok {
+		return nil, types.ErrInvalidCredentials
+	}
+	return s.authorizer.Authorize(ctx, userID, types.PERMISSION_USER_MANAGE)
+}
+
+// findManagedUser returns the current user and the user they manage
+func (s *adminService) findManagedUser(ctx context.Context, id string) (*types.User, *types.User, error) {
+	actor, err := s.userManager(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	user, err := s.userRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if user.ID != actor.ID {
+		if err := checkManagedUser(actor, user); err != nil {
+			return nil, nil, err
+		}
+	}
+	return actor, user, nil
+}
+
+// checkManagedUser checks the actor manages the user: admins manage every
+// user, the others the users of their workspace below their management
+// level, admins excepted
+func checkManagedUser(actor, user *types.User) error {
+	if actor.Role == types.USER_ROLE_ADMIN {
+		return nil
+	}
+	if user.Role == types.USER_ROLE_ADMIN || !manages(actor, user) {
+		return types.ErrUserNotManaged
+	}
+	return nil
+}
+
+// userManagementLevel validates the roles of a user and returns their
+// management level, the level of the workspace role when it is not given
+func userManagementLevel(workspaceRole string, managementLevel int, role string) (int, error) {
+	if !slices.Contains(types.WORKSPACE_ROLES, workspaceRole) || (role != "" && role != types.USER_ROLE_ADMIN) {
+		return 0, types.ErrInvalidRole
+	}
+	level := types.MAPPING_ROLE_TO_MANAGEMENT_LEVEL[workspaceRole]
+	if managementLevel != 0 && managementLevel != level {
+		return 0, types.ErrInvalidManagementLevel
+	}
+	return level, nil
+}
@@ -2,7 +2,9 @@ package service
 
 import (
 	"context"
+	"time"
 
+	"github.com/remiehneppo/be-task-management/internal/repository"
 	"github.com/remiehneppo/be-task-management/types"
 	"github.com/sashabaranov/go-openai"
 )
@@ -12,30 +14,72 @@ var _ AIAssistantService = (*aiAssistantService)(nil)
 type AIAssistantService interface {
 	ChatWithAssistant(ctx context.Context, req types.ChatRequest) (*types.ChatResponse, error)
 	ChatWithAssistantStateless(ctx context.Context, req types.ChatStatelessRequest) (*types.ChatResponse, error)
-	PaginateMessages(ctx context.Context, req types.PaginateMessagesRequest) ([]*types.ChatMessage, int64, error)
+	PaginateMessages(ctx context.Context, req types.PaginateMessagesRequest) (items []*types.ChatMessage, total int64, nextCursor string, err error)
 }
 
+// chatHistoryLimit is the number of previous messages sent along with a
+// prompt
+const chatHistoryLimit = 20
+
 type aiAssistantService struct {
-	aiService    AIService
-	systemPrompt string
+	aiService       AIService
+	chatMessageRepo repository.ChatMessageRepository
+	systemPrompt    string
 }
 
-func NewAIAssistantService(aiService AIService, systemPrompt string) *aiAssistantService {
+func NewAIAssistantService(aiService AIService, chatMessageRepo repository.ChatMessageRepository, systemPrompt string) *aiAssistantService {
 	return &aiAssistantService{
-		aiService:    aiService,
-		systemPrompt: systemPrompt,
+		aiService:       aiService,
+		chatMessageRepo: chatMessageRepo,
+		systemPrompt:    systemPrompt,
 	}
 }
 
+// ChatWithAssistant answers the prompt with the latest messages of the chat
+// as context, the prompt and the answer are stored in the chat
 func (s *aiAssistantService) ChatWithAssistant(ctx context.Context, req types.ChatRequest) (*types.ChatResponse, error) {
-	// TODO: Implement the chat with assistant logic
-	// Load the chat history from the database
-	// Call the AI service to get a response
-	// Save the response to the database
-	// Return the response
-	// For now, just return a dummy response
+	userID, ok := ctx.Value("user_id").(string)
+	if !ok {
+		return nil, types.ErrInvalidCredentials
+	}
+	history, err := s.chatMessageRepo.FindRecent(ctx, userID, req.ChatId, chatHistoryLimit)
+	if err != nil {
+		return nil, err
+	}
+	messages := make([]types.Message, 0, len(history)+2)
+	if s.systemPrompt != "" {
+		messages = append(messages, types.Message{
+			Role:    openai.ChatMessageRoleSystem,
+			Content: s.systemPrompt,
+		})
+	}
+	for _, message := range history {
+		messages = append(messages, types.Message{
+			Role:    message.Role,
+			Content: message.Content,
+		})
+	}
+	messages = append(messages, types.Message{
+		Role:    openai.ChatMessageRoleUser,
+		Content: req.Prompt,
+	})
+	res, err := s.aiService.Chat(ctx, messages)
+	if err != nil {
+		return nil, err
+	}
+	for _, message := range []*types.ChatMessage{
+		{Role: openai.ChatMessageRoleUser, Content: req.Prompt},
+		{Role: openai.ChatMessageRoleAssistant, Content: res.Content},
+	} {
+		message.ChatID = req.ChatId
+		message.UserID = userID
+		message.CreatedAt = time.Now().Unix()
+		if err := s.chatMessageRepo.Save(ctx, message); err != nil {
+			return nil, err
+		}
+	}
 	return &types.ChatResponse{
-		Content: "Dummy response from AI assistant",
+		Content: res.Content,
 	}, nil
 }
 
@@ -61,10 +105,19 @@ func (s *aiAssistantService) ChatWithAssistantStateless(ctx context.Context, req
 	}, nil
 }
 
-func (s *aiAssistantService) PaginateMessages(ctx context.Context, req types.PaginateMessagesRequest) ([]*types.ChatMessage, int64, error) {
-	// TODO: Implement the pagination logic
-	// Load the messages from the database
-	// Return the messages and the total count
-	// For now, just return a dummy response
-	return nil, 0, nil
+// PaginateMessages returns a page of the messages of a chat of the current
+// user, newest first
+func (s *aiAssistantService) PaginateMessages(ctx context.Context, req types.PaginateMessagesRequest) (items []*types.ChatMessage, total int64, nextCursor string, err error) {
+	userID, ok := ctx.Value("user_id").(string)
+	if !ok {
+		return nil, 0, "", types.ErrInvalidCredentials
+	}
+	if req.ChatId == "" {
+		return nil, 0, "", types.ErrInvalidChatID
+	}
+	return s.chatMessageRepo.Paginate(ctx, userID, req.ChatId, types.PageRequest{
+		Page:   req.Page,
+		Limit:  req.Limit,
+		Cursor: req.Cursor,
+	})
 }
@@ -29,6 +29,9 @@ var _ AIService = (*OpenAIService)(nil)
 
 type AIService interface {
 	Chat(ctx context.Context, messages []types.Message) (*types.Message, error)
+	// ChatStructured has the answer follow the JSON schema and decodes it
+	// into v
+	ChatStructured(ctx context.Context, messages []types.Message, name string, schema *jsonschema.Definition, v any) error
 }
 
 type OpenAIService struct {
@@ -99,6 +102,43 @@ func (s *OpenAIService) Chat(ctx context.Context, messages []types.Message) (*ty
 	}, nil
 }
 
+func (s *OpenAIService) ChatStructured(ctx context.Context, messages []types.Message, name string, schema *jsonschema.Definition, v any) error {
+	openaiMessages := make([]openai.ChatCompletionMessage, 0, len(messages))
+	for _, msg := range messages {
+		role := msg.Role
+		if role == "" {
+			role = openai.ChatMessageRoleUser
+		}
+		openaiMessages = append(openaiMessages, openai.ChatCompletionMessage{
+			Role:    role,
+			Content: msg.Content,
+		})
+	}
+	resp, err := s.client.CreateChatCompletion(ctx, openai.ChatCompletionRequest{
+		Messages: openaiMessages,
+		Model:    s.model,
+		ResponseFormat: &openai.ChatCompletionResponseFormat{
+			Type: openai.ChatCompletionResponseFormatTypeJSONSchema,
+			JSONSchema: &openai.ChatCompletionResponseFormatJSONSchema{
+				Name:   name,
+				Schema: schema,
+				Strict: true,
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if len(resp.Choices) == 0 {
+		return errors.New("no response generated")
+	}
+	if resp.Choices[0].FinishReason == openai.FinishReasonLength {
+		return errors.New("response truncated")
+	}
+	// Unmarshal also checks the answer against the schema
+	return schema.Unmarshal(resp.Choices[0].Message.Content, v)
+}
+
 // func (s *OpenAIService) ChatStream(ctx context.Context, messages []types.Message, streamHandler types.StreamHandler) error {
 // 	// Convert our Message type to OpenAI chat messages
 // 	openaiMessages := make([]openai.ChatCompletionMessage, 0)
@@ -0,0 +1,234 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/remiehneppo/be-task-management/internal/repository"
+	"github.com/remiehneppo/be-task-management/types"
+)
+
+// analyticsMaxWeeks keeps a weekly series to about two years
+const analyticsMaxWeeks = 106
+
+var _ AnalyticsService = (*analyticsService)(nil)
+
+// AnalyticsService measures the workload and performance of a workspace.
+// Members see their own metrics and those of the members below their
+// management level, the members whose role grants analytics.workspace also
+// see the workspace totals.
+type AnalyticsService interface {
+	GetMetrics(ctx context.Context, req *types.AnalyticsRequest) (*types.AnalyticsMetrics, error)
+	GetWeeklySeries(ctx context.Context, req *types.AnalyticsRequest) (*types.AnalyticsSeries, error)
+}
+
+type analyticsService struct {
+	taskRepo   repository.TaskRepository
+	reportRepo repository.ReportRepository
+	userRepo   repository.UserRepository
+	authorizer AuthorizationService
+}
+
+func NewAnalyticsService(
+	taskRepo repository.TaskRepository,
+	reportRepo repository.ReportRepository,
+	userRepo repository.UserRepository,
+	authorizer AuthorizationService,
+) AnalyticsService {
+	return &analyticsService{
+		taskRepo:   taskRepo,
+		reportRepo: reportRepo,
+		userRepo:   userRepo,
+		authorizer: authorizer,
+	}
+}
+
+// GetMetrics returns the completed, on time, cycle time and overdue metrics
+// of the visible users over the range, or of the requested user only
+func (s *analyticsService) GetMetrics(ctx context.Context, req *types.AnalyticsRequest) (*types.AnalyticsMetrics, error) {
+	if req.To < req.From {
+		return nil, types.ErrInvalidAnalyticsRange
+	}
+	viewer, visible, err := s.visibleUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	query := types.AnalyticsQuery{
+		Workspace: viewer.Workspace,
+		From:      req.From,
+		To:        req.To,
+		Now:       time.Now().Unix(),
+	}
+	workspaceAllowed, err := s.authorizer.Can(ctx, viewer, types.PERMISSION_ANALYTICS_WORKSPACE)
+	if err != nil {
+		return nil, err
+	}
+	// the workspace totals are only measured over every member
+	workspaceWide := req.UserID == "" && workspaceAllowed
+	switch {
+	case req.UserID != "":
+		if _, ok := visible[req.UserID]; !ok {
+			return nil, types.ErrAnalyticsForbidden
+		}
+		query.Users = []string{req.UserID}
+	case !workspaceWide:
+		for userID := range visible {
+			query.Users = append(query.Users, userID)
+		}
+		slices.Sort(query.Users)
+	}
+	workspace, users, err := s.taskRepo.AggregateMetrics(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	reports, err := s.reportRepo.CountByCreator(ctx, types.ReportFilter{
+		Workspace:   viewer.Workspace,
+		CreatedFrom: req.From,
+		CreatedTo:   req.To,
+	})
+	if err != nil {
+		return nil, err
+	}
+	// members without tasks in the range are listed with zero metrics
+	usersMap := make(map[string]*types.UserMetrics, len(users))
+	for _, user := range users {
+		usersMap[user.UserID] = user
+	}
+	metrics := &types.AnalyticsMetrics{
+		From:  req.From,
+		To:    req.To,
+		Users: make([]*types.UserMetrics, 0, len(visible)),
+	}
+	for userID, member := range visible {
+		if len(query.Users) > 0 && !slices.Contains(query.Users, userID) {
+			continue
+		}
+		user, ok := usersMap[userID]
+		if !ok {
+			user = &types.UserMetrics{UserID: userID}
+		}
+		user.FullName = member.FullName
+		user.Reports = reports[userID]
+		user.OnTimeRate = onTimeRate(&user.TaskMetrics)
+		metrics.Users = append(metrics.Users, user)
+	}
+	slices.SortFunc(metrics.Users, func(a, b *types.UserMetrics) int {
+		return cmp.Or(strings.Compare(a.FullName, b.FullName), strings.Compare(a.UserID, b.UserID))
+	})
+	if workspaceWide {
+		for _, count := range reports {
+			workspace.Reports += count
+		}
+		workspace.OnTimeRate = onTimeRate(workspace)
+		metrics.Workspace = workspace
+	}
+	return metrics, nil
+}
+
+// GetWeeklySeries returns per Monday based week the tasks created, the
+// tasks completed, the throughput, and the unfinished tasks at the end of
+// the week, the burn-down
+func (s *analyticsService) GetWeeklySeries(ctx context.Context, req *types.AnalyticsRequest) (*types.AnalyticsSeries, error) {
+	if req.To < req.From {
+		return nil, types.ErrInvalidAnalyticsRange
+	}
+	location := time.Local
+	if req.Timezone != "" {
+		var err error
+		location, err = time.LoadLocation(req.Timezone)
+		if err != nil {
+			return nil, types.ErrInvalidTimezone
+		}
+	}
+	weeks := calendarBuckets(time.Unix(req.From, 0).In(location), time.Unix(req.To, 0).In(location), types.CALENDAR_GROUP_WEEK)
+	if len(weeks) > analyticsMaxWeeks {
+		return nil, types.ErrInvalidAnalyticsRange
+	}
+	viewer, visible, err := s.visibleUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	query := types.AnalyticsQuery{
+		Workspace: viewer.Workspace,
+		From:      req.From,
+		To:        req.To,
+		Now:       time.Now().Unix(),
+	}
+	if req.UserID != "" {
+		if _, ok := visible[req.UserID]; !ok {
+			return nil, types.ErrAnalyticsForbidden
+		}
+		query.Users = []string{req.UserID}
+	} else {
+		workspaceAllowed, err := s.authorizer.Can(ctx, viewer, types.PERMISSION_ANALYTICS_WORKSPACE)
+		if err != nil {
+			return nil, err
+		}
+		if !workspaceAllowed {
+			return nil, types.ErrAnalyticsForbidden
+		}
+	}
+	boundaries := make([]int64, 0, len(weeks)+1)
+	for _, week := range weeks {
+		boundaries = append(boundaries, week.Start)
+	}
+	boundaries = append(boundaries, weeks[len(weeks)-1].End+1)
+	counts, err := s.taskRepo.AggregateSeries(ctx, query, boundaries)
+	if err != nil {
+		return nil, err
+	}
+	series := &types.AnalyticsSeries{
+		From:     req.From,
+		To:       req.To,
+		Timezone: location.String(),
+		UserID:   req.UserID,
+		Weeks:    make([]*types.AnalyticsWeek, 0, len(weeks)),
+	}
+	remaining := counts.Unfinished
+	for i, week := range weeks {
+		remaining += counts.Created[i] - counts.Finished[i]
+		series.Weeks = append(series.Weeks, &types.AnalyticsWeek{
+			Date:      week.Date,
+			Start:     week.Start,
+			End:       week.End,
+			Created:   counts.Created[i],
+			Completed: counts.Completed[i],
+			Remaining: remaining,
+		})
+	}
+	return series, nil
+}
+
+// visibleUsers returns the current user and the workspace members whose
+// metrics they may see, themselves and the members below their level
+func (s *analyticsService) visibleUsers(ctx context.Context) (*types.User, map[string]*types.User, error) {
+	userID, ok := ctx.Value("user_id").(string)
+	if !ok {
+		return nil, nil, types.ErrInvalidCredentials
+	}
+	viewer, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	members, err := s.userRepo.FindByWorkspace(ctx, viewer.Workspace)
+	if err != nil {
+		return nil, nil, err
+	}
+	visible := map[string]*types.User{viewer.ID: viewer}
+	for _, member := range members {
+		if s.authorizer.Manages(viewer, member) {
+			visible[member.ID] = member
+		}
+	}
+	return viewer, visible, nil
+}
+
+func onTimeRate(metrics *types.TaskMetrics) float64 {
+	if metrics.Completed == 0 {
+		return 0
+	}
+	return float64(metrics.CompletedOnTime) / float64(metrics.Completed)
+}
@@ -0,0 +1,38 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/remiehneppo/be-task-management/internal/repository"
+	"github.com/remiehneppo/be-task-management/types"
+	"github.com/sirupsen/logrus"
+)
+
+// recordAudit saves the audit log of an admin action, a failure is logged
+// and does not undo the action
+func recordAudit(ctx context.Context, auditLogRepo repository.AuditLogRepository, log *types.AuditLog) {
+	log.CreatedAt = time.Now().Unix()
+	if err := auditLogRepo.Save(ctx, log); err != nil {
+		logrus.Errorf("Failed to record audit log %s of %s %s: %v", log.Action, log.TargetType, log.TargetID, err)
+	}
+}
+
+// diffUser returns the audited fields of the user that differ, the
+// password is never part of them
+func diffUser(before, after *types.User) []types.FieldChange {
+	changes := make([]types.FieldChange, 0)
+	addChange := func(field string, oldValue, newValue interface{}) {
+		if oldValue != newValue {
+			changes = append(changes, types.FieldChange{Field: field, OldValue: oldValue, NewValue: newValue})
+		}
+	}
+	addChange("username", before.Username, after.Username)
+	addChange("full_name", before.FullName, after.FullName)
+	addChange("workspace", before.Workspace, after.Workspace)
+	addChange("workspace_role", before.WorkspaceRole, after.WorkspaceRole)
+	addChange("management_level", before.ManagementLevel, after.ManagementLevel)
+	addChange("role", before.Role, after.Role)
+	addChange("deactivated", before.Deactivated, after.Deactivated)
+	return changes
+}
@@ -0,0 +1,249 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/remiehneppo/be-task-management/internal/repository"
+	"github.com/remiehneppo/be-task-management/types"
+)
+
+// rolePermissionsTTL is how long the role permissions are cached, an edit
+// reaches the other instances within it
+const rolePermissionsTTL = 30 * time.Second
+
+var _ AuthorizationService = (*authorizationService)(nil)
+
+// AuthorizationService is the policy every permission check goes through.
+// A user holds the permissions of their workspace role, admins hold them
+// all and deactivated users none. Roles have the DEFAULT_ROLE_PERMISSIONS
+// until an admin edits them, the edits are audit-logged.
+type AuthorizationService interface {
+	// Authorize returns the user when their role grants the permission and
+	// ErrPermissionDenied otherwise
+	Authorize(ctx context.Context, userID, permission string) (*types.User, error)
+	// Can tells whether the role of the user grants the permission
+	Can(ctx context.Context, user *types.User, permission string) (bool, error)
+	// Manages tells whether the actor ranks above the target, a member of
+	// their workspace with a lower management level
+	Manages(actor, target *types.User) bool
+	// GetPermissions returns the permissions of the current user
+	GetPermissions(ctx context.Context) ([]string, error)
+	GetRolePermissions(ctx context.Context) ([]*types.RolePermissions, error)
+	UpdateRolePermissions(ctx context.Context, req *types.UpdateRolePermissionsRequest) (*types.RolePermissions, error)
+}
+
+type authorizationService struct {
+	rolePermissionRepo repository.RolePermissionRepository
+	userRepo           repository.UserRepository
+	auditLogRepo       repository.AuditLogRepository
+
+	mu       sync.Mutex
+	roles    map[string][]string
+	loadedAt time.Time
+}
+
+func NewAuthorizationService(
+	rolePermissionRepo repository.RolePermissionRepository,
+	userRepo repository.UserRepository,
+	auditLogRepo repository.AuditLogRepository,
+) AuthorizationService {
+	return &authorizationService{
+		rolePermissionRepo: rolePermissionRepo,
+		userRepo:           userRepo,
+		auditLogRepo:       auditLogRepo,
+	}
+}
+
+func (s *authorizationService) Authorize(ctx context.Context, userID, permission string) (*types.User, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	allowed, err := s.Can(ctx, user, permission)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, permissionError(permission)
+	}
+	return user, nil
+}
+
+func (s *authorizationService) Can(ctx context.Context, user *types.User, permission string) (bool, error) {
+	if user.Deactivated {
+		return false, nil
+	}
+	if user.Role == types.USER_ROLE_ADMIN {
+		return true, nil
+	}
+	roles, err := s.rolePermissions(ctx)
+	if err != nil {
+		return false, err
+	}
+	return slices.Contains(roles[user.WorkspaceRole], permission), nil
+}
+
+func (s *authorizationService) Manages(actor, target *types.User) bool {
+	return manages(actor, target)
+}
+
+func (s *authorizationService) GetPermissions(ctx context.Context) ([]string, error) {
+	userID, ok := ctx.Value("user_id").(string)
+	if !ok {
+		return nil, types.ErrInvalidCredentials
+	}
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.Deactivated {
+		return make([]string, 0), nil
+	}
+	if user.Role == types.USER_ROLE_ADMIN {
+		return types.PERMISSIONS, nil
+	}
+	roles, err := s.rolePermissions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	permissions := roles[user.WorkspaceRole]
+	if permissions == nil {
+		permissions = make([]string, 0)
+	}
+	return permissions, nil
+}
+
+func (s *authorizationService) GetRolePermissions(ctx context.Context) ([]*types.RolePermissions, error) {
+	userID, ok := ctx.Value("user_id").(string)
+	if !ok {
+		return nil, types.ErrInvalidCredentials
+	}
+	if _, err := s.Authorize(ctx, userID, types.PERMISSION_ROLE_MANAGE); err != nil {
+		return nil, err
+	}
+	stored, err := s.rolePermissionRepo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*types.RolePermissions, 0, len(types.WORKSPACE_ROLES))
+	for _, role := range types.WORKSPACE_ROLES {
+		i := slices.IndexFunc(stored, func(rolePermissions *types.RolePermissions) bool {
+			return rolePermissions.Role == role
+		})
+		if i < 0 {
+			res = append(res, &types.RolePermissions{
+				Role:        role,
+				Permissions: types.DEFAULT_ROLE_PERMISSIONS[role],
+				Default:     true,
+			})
+			continue
+		}
+		res = append(res, stored[i])
+	}
+	return res, nil
+}
+
+func (s *authorizationService) UpdateRolePermissions(ctx context.Context, req *types.UpdateRolePermissionsRequest) (*types.RolePermissions, error) {
+	userID, ok := ctx.Value("user_id").(string)
+	if !ok {
+		return nil, types.ErrInvalidCredentials
+	}
+	if _, err := s.Authorize(ctx, userID, types.PERMISSION_ROLE_MANAGE); err != nil {
+		return nil, err
+	}
+	if !slices.Contains(types.WORKSPACE_ROLES, req.Role) {
+		return nil, types.ErrInvalidRole
+	}
+	permissions := make([]string, 0, len(req.Permissions))
+	for _, permission := range req.Permissions {
+		if !slices.Contains(types.PERMISSIONS, permission) {
+			return nil, fmt.Errorf("%w: %s", types.ErrInvalidPermission, permission)
+		}
+		if !slices.Contains(permissions, permission) {
+			permissions = append(permissions, permission)
+		}
+	}
+	rolePermissions, err := s.rolePermissionRepo.FindByRole(ctx, req.Role)
+	if errors.Is(err, types.ErrRolePermissionsNotFound) {
+		rolePermissions = &types.RolePermissions{
+			Role:        req.Role,
+			Permissions: types.DEFAULT_ROLE_PERMISSIONS[req.Role],
+		}
+	} else if err != nil {
+		return nil, err
+	}
+	oldPermissions := rolePermissions.Permissions
+	rolePermissions.Permissions = permissions
+	rolePermissions.UpdatedBy = userID
+	rolePermissions.UpdatedAt = time.Now().Unix()
+	if rolePermissions.ID == "" {
+		err = s.rolePermissionRepo.Save(ctx, rolePermissions)
+	} else {
+		rolePermissionsID := rolePermissions.ID
+		rolePermissions.ID = ""
+		err = s.rolePermissionRepo.Update(ctx, rolePermissionsID, rolePermissions)
+		rolePermissions.ID = rolePermissionsID
+	}
+	if err != nil {
+		return nil, err
+	}
+	recordAudit(ctx, s.auditLogRepo, &types.AuditLog{
+		Actor:      userID,
+		Action:     types.AUDIT_ACTION_ROLE_PERMISSIONS,
+		TargetType: types.AUDIT_TARGET_ROLE,
+		TargetID:   req.Role,
+		Changes: []types.FieldChange{
+			{Field: "permissions", OldValue: oldPermissions, NewValue: permissions},
+		},
+	})
+	// the edit applies at once on this instance
+	s.mu.Lock()
+	s.roles = nil
+	s.mu.Unlock()
+	return rolePermissions, nil
+}
+
+// rolePermissions returns the permissions of every workspace role, the
+// stored ones replacing the defaults
+func (s *authorizationService) rolePermissions(ctx context.Context) (map[string][]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.roles != nil && time.Since(s.loadedAt) < rolePermissionsTTL {
+		return s.roles, nil
+	}
+	stored, err := s.rolePermissionRepo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	roles := make(map[string][]string, len(types.DEFAULT_ROLE_PERMISSIONS))
+	for role, permissions := range types.DEFAULT_ROLE_PERMISSIONS {
+		roles[role] = permissions
+	}
+	for _, rolePermissions := range stored {
+		roles[rolePermissions.Role] = rolePermissions.Permissions
+	}
+	s.roles = roles
+	s.loadedAt = time.Now()
+	return roles, nil
+}
+
+// manages tells whether the actor ranks above the target in their workspace
+func manages(actor, target *types.User) bool {
+	return actor.Workspace == target.Workspace && managementLevel(actor) > managementLevel(target)
+}
+
+// managementLevel returns the management level of the workspace role of the
+// user, the stored level is only informative
+func managementLevel(user *types.User) int {
+	return types.MAPPING_ROLE_TO_MANAGEMENT_LEVEL[user.WorkspaceRole]
+}
+
+// permissionError tells which permission an action was denied for
+func permissionError(permission string) error {
+	return fmt.Errorf("%w: %s", types.ErrPermissionDenied, permission)
+}
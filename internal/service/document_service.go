@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"mime/multipart"
 	"os"
@@ -24,6 +25,7 @@ type DocumentService interface {
 	SearchDocument(ctx context.Context, req *types.SearchDocumentRequest) (*types.SearchDocumentResponse, error)
 	AskAI(ctx context.Context, req *types.AskAIRequest) (*types.AskAIResponse, error)
 	ViewDocument(ctx context.Context, req *types.ViewDocumentRequest) (*types.ViewDocumentResponse, error)
+	ListDocuments(ctx context.Context, page types.PageRequest) (items []*types.FileMetadata, total int64, nextCursor string, err error)
 	DemoGetText(ctx context.Context, req *types.DemoGetTextRequest, fileHeader *multipart.FileHeader) (*types.DemoGetTextResponse, error)
 	ProcessDocumentJob() worker.Do
 }
@@ -34,9 +36,12 @@ type documentService struct {
 	ragService          RAGService
 	fileService         FileService
 	pdfService          PDFService
+	docxService         DOCXService
 	documentVectorRepo  repository.DocumentVectorRepository
 	pendingDocumentRepo repository.PendingDocumentRepository
+	userRepo            repository.UserRepository
 	lockService         LockService
+	notifier            Notifier
 }
 
 func NewDocumentService(
@@ -44,20 +49,26 @@ func NewDocumentService(
 	ragService RAGService,
 	fileService FileService,
 	pdfService PDFService,
+	docxService DOCXService,
 	documentVectorRepo repository.DocumentVectorRepository,
 	pendingDocumentRepo repository.PendingDocumentRepository,
+	userRepo repository.UserRepository,
 	allowedTypes []string,
 	lockService LockService,
+	notifier Notifier,
 ) DocumentService {
 	return &documentService{
 		aiService:           aiService,
 		ragService:          ragService,
 		fileService:         fileService,
 		pdfService:          pdfService,
+		docxService:         docxService,
 		documentVectorRepo:  documentVectorRepo,
 		pendingDocumentRepo: pendingDocumentRepo,
+		userRepo:            userRepo,
 		allowedTypes:        allowedTypes,
 		lockService:         lockService,
+		notifier:            notifier,
 	}
 }
 
@@ -102,7 +113,8 @@ func (s *documentService) UploadDocument(ctx context.Context, req *types.UploadD
 }
 
 func (s *documentService) BatchUploadDocumentAsync(ctx context.Context, req *types.BatchUploadDocumentRequest) (*types.BatchUploadDocumentResponse, error) {
-
+	// the uploader is told when the ingestion completes
+	userID, _ := ctx.Value("user_id").(string)
 	uploadStates := make([]*types.UploadStatus, 0)
 
 	for _, fileHeader := range req.Files {
@@ -130,6 +142,7 @@ func (s *documentService) BatchUploadDocumentAsync(ctx context.Context, req *typ
 			DocumentName: uploadReq.FileName,
 			Tags:         req.Tags,
 			ToolUse:      req.ToolUse,
+			Uploader:     userID,
 			CreatedAt:    time.Now().Unix(),
 		}
 		if err := s.pendingDocumentRepo.Save(ctx, pendingDocument); err != nil {
@@ -157,26 +170,36 @@ func (s *documentService) ProcessDocumentJob() worker.Do {
 		}
 
 		for _, pendingDocument := range pendingDocuments {
-			ok, _ := s.lockService.Lock(ctx, pendingDocument.DocumentName, 20*time.Minute)
-			if !ok {
+			lock, err := s.lockService.Lock(ctx, pendingDocument.DocumentName, 20*time.Minute)
+			if err != nil {
 				continue
 			}
-			chunks, err := s.pdfService.ProcessPDF(&types.ProcessPDFRequest{
-				ToolUse:  pendingDocument.ToolUse,
-				FilePath: pendingDocument.DocumentPath,
-			})
+			chunks, err := s.extractChunks(ctx, pendingDocument)
 			if err != nil {
+				logrus.Errorf("Failed to extract pending document %s: %v", pendingDocument.DocumentName, err)
 				continue
 			}
+			metadata := &types.DocumentMetadata{
+				Title:     pendingDocument.Title,
+				Tags:      pendingDocument.Tags,
+				FilePath:  pendingDocument.DocumentPath,
+				Source:    pendingDocument.Source,
+				TaskID:    pendingDocument.TaskID,
+				ReportID:  pendingDocument.ReportID,
+				Workspace: pendingDocument.Workspace,
+				Assignee:  pendingDocument.Assignee,
+			}
+			if metadata.Title == "" {
+				metadata.Title = pendingDocument.DocumentName
+			}
 			// remove documents with same name in the vector db
-			s.documentVectorRepo.RemoveDocuments(ctx, &types.DocumentMetadata{})
+			s.documentVectorRepo.RemoveDocuments(ctx, &types.DocumentMetadata{
+				Title:    metadata.Title,
+				ReportID: metadata.ReportID,
+			})
 			if err := s.documentVectorRepo.SaveBatchDocumentVector(
 				context.Background(),
-				&types.DocumentMetadata{
-					Title:    pendingDocument.DocumentName,
-					Tags:     pendingDocument.Tags,
-					FilePath: pendingDocument.DocumentPath,
-				},
+				metadata,
 				chunks,
 			); err != nil {
 				continue
@@ -185,8 +208,9 @@ func (s *documentService) ProcessDocumentJob() worker.Do {
 			if err := s.pendingDocumentRepo.Remove(ctx, pendingDocument.ID); err != nil {
 				continue
 			}
+			s.notifyDocumentIngested(ctx, pendingDocument, metadata.Title)
 			// unlock the document
-			if err := s.lockService.ReleaseLock(ctx, pendingDocument.DocumentName); err != nil {
+			if err := lock.Release(ctx); err != nil {
 				continue
 			}
 
@@ -196,14 +220,37 @@ func (s *documentService) ProcessDocumentJob() worker.Do {
 	}
 }
 
+// notifyDocumentIngested tells the uploader the document is searchable,
+// documents queued by the application itself have no uploader
+func (s *documentService) notifyDocumentIngested(ctx context.Context, pendingDocument *types.PendingDocument, title string) {
+	if pendingDocument.Uploader == "" {
+		return
+	}
+	err := s.notifier.Notify(ctx, &types.Notification{
+		Recipient: pendingDocument.Uploader,
+		Type:      types.NOTIFICATION_TYPE_DOCUMENT_INGESTED,
+		Title:     fmt.Sprintf("Đã xử lý tài liệu: %s", title),
+		Message:   fmt.Sprintf("Tài liệu \"%s\" đã sẵn sàng để tìm kiếm", title),
+		CreatedAt: time.Now().Unix(),
+	})
+	if err != nil {
+		logrus.Errorf("Failed to notify ingestion of %s: %v", pendingDocument.DocumentName, err)
+	}
+}
+
 func (s *documentService) SearchDocument(ctx context.Context, req *types.SearchDocumentRequest) (*types.SearchDocumentResponse, error) {
+	workspace, err := s.userWorkspace(ctx)
+	if err != nil {
+		return nil, err
+	}
 	queries := s.getQueries(req.Query)
-	chunks, err := s.documentVectorRepo.SearchDocumentVector(
+	chunks, err := s.documentVectorRepo.SearchWorkspaceDocumentVector(
 		ctx,
 		&types.DocumentMetadata{
 			Title: req.Title,
 			Tags:  req.Tags,
 		},
+		workspace,
 		queries,
 		req.Limit,
 	)
@@ -216,13 +263,18 @@ func (s *documentService) SearchDocument(ctx context.Context, req *types.SearchD
 }
 
 func (s *documentService) AskAI(ctx context.Context, req *types.AskAIRequest) (*types.AskAIResponse, error) {
+	workspace, err := s.userWorkspace(ctx)
+	if err != nil {
+		return nil, err
+	}
 	queries := s.getQueries(req.Query)
-	chunks, err := s.documentVectorRepo.SearchDocumentVector(
+	chunks, err := s.documentVectorRepo.SearchWorkspaceDocumentVector(
 		ctx,
 		&types.DocumentMetadata{
 			Title: req.Title,
 			Tags:  req.Tags,
 		},
+		workspace,
 		queries,
 		req.Limit,
 	)
@@ -243,6 +295,60 @@ func (s *documentService) getQueries(query string) []string {
 	return []string{query}
 }
 
+// extractChunks turns a pending document into chunks, reading report text
+// inline and attachments from disk by extension
+func (s *documentService) extractChunks(ctx context.Context, pendingDocument *types.PendingDocument) ([]*types.DocumentChunk, error) {
+	if pendingDocument.DocumentPath == "" {
+		return s.textChunks(pendingDocument.Content), nil
+	}
+	filePath, err := s.fileService.GetFilePath(ctx, pendingDocument.DocumentPath)
+	if err != nil {
+		return nil, err
+	}
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".docx":
+		return s.docxService.ProcessDocx(filePath)
+	case ".pdf":
+		return s.pdfService.ProcessPDF(&types.ProcessPDFRequest{
+			ToolUse:  pendingDocument.ToolUse,
+			FilePath: filePath,
+		})
+	default:
+		return nil, types.ErrUnsupportedFileType
+	}
+}
+
+// textChunks splits plain text such as a report body into chunks of at most
+// DefaultDocumentServiceConfig.MaxChunkSize characters
+func (s *documentService) textChunks(text string) []*types.DocumentChunk {
+	runes := []rune(strings.TrimSpace(text))
+	maxChunkSize := DefaultDocumentServiceConfig.MaxChunkSize
+	chunks := make([]*types.DocumentChunk, 0)
+	for start := 0; start < len(runes); start += maxChunkSize {
+		end := min(start+maxChunkSize, len(runes))
+		chunks = append(chunks, &types.DocumentChunk{
+			Content: string(runes[start:end]),
+			Page:    1,
+			Chunk:   len(chunks),
+		})
+	}
+	return chunks
+}
+
+// userWorkspace returns the workspace of the current user, whose report
+// chunks they may search
+func (s *documentService) userWorkspace(ctx context.Context) (string, error) {
+	userID, ok := ctx.Value("user_id").(string)
+	if !ok {
+		return "", types.ErrInvalidCredentials
+	}
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	return user.Workspace, nil
+}
+
 func (s *documentService) isAllowedType(ext string) bool {
 	for _, allowedType := range s.allowedTypes {
 		if strings.EqualFold(ext, allowedType) {
@@ -263,6 +369,11 @@ func (s *documentService) ViewDocument(ctx context.Context, req *types.ViewDocum
 	}, nil
 }
 
+// ListDocuments returns a page of the uploaded documents, newest first
+func (s *documentService) ListDocuments(ctx context.Context, page types.PageRequest) (items []*types.FileMetadata, total int64, nextCursor string, err error) {
+	return s.fileService.GetFileList(ctx, page)
+}
+
 func (s *documentService) DemoGetText(ctx context.Context, req *types.DemoGetTextRequest, fileHeader *multipart.FileHeader) (*types.DemoGetTextResponse, error) {
 	ext := strings.ToLower(filepath.Ext(fileHeader.Filename))
 	if !s.isAllowedType(ext) {
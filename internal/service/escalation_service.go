@@ -0,0 +1,190 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/remiehneppo/be-task-management/config"
+	"github.com/remiehneppo/be-task-management/internal/repository"
+	"github.com/remiehneppo/be-task-management/internal/worker"
+	"github.com/remiehneppo/be-task-management/types"
+	"github.com/sirupsen/logrus"
+)
+
+const escalationJobLock = "task_escalation_job"
+
+var _ EscalationService = (*escalationService)(nil)
+
+type EscalationService interface {
+	EscalationJob() worker.Do
+}
+
+type escalationService struct {
+	taskRepo    repository.TaskRepository
+	userRepo    repository.UserRepository
+	alertRepo   repository.TaskDeadlineAlertRepository
+	notifier    Notifier
+	lockService LockService
+	config      config.EscalationConfig
+}
+
+func NewEscalationService(
+	taskRepo repository.TaskRepository,
+	userRepo repository.UserRepository,
+	alertRepo repository.TaskDeadlineAlertRepository,
+	notifier Notifier,
+	lockService LockService,
+	config config.EscalationConfig,
+) EscalationService {
+	return &escalationService{
+		taskRepo:    taskRepo,
+		userRepo:    userRepo,
+		alertRepo:   alertRepo,
+		notifier:    notifier,
+		lockService: lockService,
+		config:      config,
+	}
+}
+
+// EscalationJob reminds assignees of approaching deadlines and escalates
+// overdue tasks to their creator, then one management level up per step.
+// Every step is recorded against the task deadline before it is sent, so a
+// restart or another replica never sends it twice.
+func (s *escalationService) EscalationJob() worker.Do {
+	return func() error {
+		logrus.Info("Checking task deadlines...")
+		ctx := context.Background()
+		lock, err := s.lockService.Lock(ctx, escalationJobLock, 10*time.Minute)
+		if err != nil {
+			return nil
+		}
+		defer func() {
+			if err := lock.Release(ctx); err != nil {
+				logrus.Errorf("Failed to release %s lock: %v", escalationJobLock, err)
+			}
+		}()
+		now := time.Now()
+		var maxLeadTime time.Duration
+		for _, leadTime := range s.config.ReminderLeadTimes {
+			maxLeadTime = max(maxLeadTime, leadTime)
+		}
+		tasks, err := s.taskRepo.FindUnfinishedDueBefore(ctx, now.Add(maxLeadTime).Unix())
+		if err != nil {
+			return err
+		}
+		for _, task := range tasks {
+			if err := s.processTask(ctx, task, now); err != nil {
+				logrus.Errorf("Failed to process deadline of task %s: %v", task.ID, err)
+			}
+		}
+		return nil
+	}
+}
+
+func (s *escalationService) processTask(ctx context.Context, task *types.Task, now time.Time) error {
+	deadline := time.Unix(task.Deadline, 0)
+	if now.Before(deadline) {
+		// only the closest reminder is sent, earlier ones missed are dropped
+		step := -1
+		for i, leadTime := range s.config.ReminderLeadTimes {
+			if !now.Before(deadline.Add(-leadTime)) && (step < 0 || leadTime < s.config.ReminderLeadTimes[step]) {
+				step = i
+			}
+		}
+		if step < 0 {
+			return nil
+		}
+		return s.sendAlert(ctx, task, types.NOTIFICATION_TYPE_DEADLINE_REMINDER, step, taskAssignees(task), &types.Notification{
+			Type:    types.NOTIFICATION_TYPE_DEADLINE_REMINDER,
+			TaskID:  task.ID,
+			Title:   fmt.Sprintf("Sắp đến hạn: %s", task.Title),
+			Message: fmt.Sprintf("Công việc \"%s\" đến hạn lúc %s", task.Title, deadline.Format("15:04 02/01/2006")),
+		})
+	}
+	for level, delay := range s.config.OverdueLevels {
+		if now.Before(deadline.Add(delay)) {
+			break
+		}
+		recipients, err := s.escalationRecipients(ctx, task, level)
+		if err != nil {
+			return err
+		}
+		err = s.sendAlert(ctx, task, types.NOTIFICATION_TYPE_OVERDUE_ESCALATION, level, recipients, &types.Notification{
+			Type:    types.NOTIFICATION_TYPE_OVERDUE_ESCALATION,
+			TaskID:  task.ID,
+			Title:   fmt.Sprintf("Quá hạn: %s", task.Title),
+			Message: fmt.Sprintf("Công việc \"%s\" đã quá hạn từ %s", task.Title, deadline.Format("15:04 02/01/2006")),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// escalationRecipients returns the creator for level 0, and for level n the
+// workspace members at the n-th management level above the creator
+func (s *escalationService) escalationRecipients(ctx context.Context, task *types.Task, level int) ([]string, error) {
+	if level == 0 {
+		return []string{task.Creator}, nil
+	}
+	creator, err := s.userRepo.FindByID(ctx, task.Creator)
+	if err != nil {
+		return nil, err
+	}
+	members, err := s.userRepo.FindByWorkspace(ctx, task.Workspace)
+	if err != nil {
+		return nil, err
+	}
+	creatorLevel := managementLevel(creator)
+	levels := make([]int, 0)
+	for _, member := range members {
+		memberLevel := managementLevel(member)
+		if memberLevel > creatorLevel && !slices.Contains(levels, memberLevel) {
+			levels = append(levels, memberLevel)
+		}
+	}
+	if level > len(levels) {
+		return nil, nil
+	}
+	slices.Sort(levels)
+	recipients := make([]string, 0)
+	for _, member := range members {
+		if managementLevel(member) == levels[level-1] {
+			recipients = append(recipients, member.ID)
+		}
+	}
+	return recipients, nil
+}
+
+func (s *escalationService) sendAlert(ctx context.Context, task *types.Task, alertType string, step int, recipients []string, notification *types.Notification) error {
+	exists, err := s.alertRepo.Exists(ctx, task.ID, alertType, step, task.Deadline)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return nil
+	}
+	err = s.alertRepo.Save(ctx, &types.TaskDeadlineAlert{
+		TaskID:     task.ID,
+		Type:       alertType,
+		Step:       step,
+		Deadline:   task.Deadline,
+		Recipients: recipients,
+		CreatedAt:  time.Now().Unix(),
+	})
+	if err != nil {
+		return err
+	}
+	for _, recipient := range recipients {
+		notification := *notification
+		notification.Recipient = recipient
+		notification.CreatedAt = time.Now().Unix()
+		if err := s.notifier.Notify(ctx, &notification); err != nil {
+			logrus.Errorf("Failed to notify %s about task %s: %v", recipient, task.ID, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,457 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/remiehneppo/be-task-management/config"
+	"github.com/remiehneppo/be-task-management/internal/repository"
+	"github.com/remiehneppo/be-task-management/internal/worker"
+	"github.com/remiehneppo/be-task-management/types"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// exportPageSize is how many tasks or reports an export reads at a time
+	exportPageSize = 500
+	// exportJobBatch is how many jobs a worker run picks
+	exportJobBatch = 10
+	// exportLockTTL bounds how long a worker holds a job before another
+	// worker may pick it up again
+	exportLockTTL = 20 * time.Minute
+	// exportDownloadPath is where the requester downloads a finished job
+	exportDownloadPath = "/api/v1/exports/jobs/%s/download"
+)
+
+var exportFormats = []string{
+	types.EXPORT_FORMAT_CSV,
+	types.EXPORT_FORMAT_XLSX,
+	types.EXPORT_FORMAT_PDF,
+}
+
+var _ ExportService = (*exportService)(nil)
+
+// ExportService exports the tasks and reports of the user workspace. Small
+// exports are written within the request, larger ones are written by a
+// background job and downloaded once ready.
+type ExportService interface {
+	// PrepareExport validates the export and counts its rows. Large and async
+	// exports are queued as a job and queued is true, other exports are
+	// written with WriteExport.
+	PrepareExport(ctx context.Context, req *types.ExportRequest) (job *types.ExportJobResponse, queued bool, err error)
+	WriteExport(ctx context.Context, req *types.ExportRequest, w io.Writer) error
+	GetExportJob(ctx context.Context, id string) (*types.ExportJobResponse, error)
+	// DownloadExport returns the path and the file name of a finished job
+	DownloadExport(ctx context.Context, id string) (path string, name string, err error)
+	ProcessExportJob() worker.Do
+}
+
+type exportService struct {
+	taskService   TaskService
+	exportJobRepo repository.ExportJobRepository
+	userRepo      repository.UserRepository
+	lockService   LockService
+	notifier      Notifier
+	config        config.ExportConfig
+}
+
+func NewExportService(
+	taskService TaskService,
+	exportJobRepo repository.ExportJobRepository,
+	userRepo repository.UserRepository,
+	lockService LockService,
+	notifier Notifier,
+	config config.ExportConfig,
+) ExportService {
+	return &exportService{
+		taskService:   taskService,
+		exportJobRepo: exportJobRepo,
+		userRepo:      userRepo,
+		lockService:   lockService,
+		notifier:      notifier,
+		config:        config,
+	}
+}
+
+func (s *exportService) PrepareExport(ctx context.Context, req *types.ExportRequest) (*types.ExportJobResponse, bool, error) {
+	userID, ok := ctx.Value("user_id").(string)
+	if !ok {
+		return nil, false, types.ErrInvalidCredentials
+	}
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, false, err
+	}
+	if err := validateExportRequest(req); err != nil {
+		return nil, false, err
+	}
+	location, err := exportLocation(req.Timezone)
+	if err != nil {
+		return nil, false, err
+	}
+	rows, err := s.countRows(ctx, req)
+	if err != nil {
+		return nil, false, err
+	}
+	now := time.Now()
+	job := &types.ExportJob{
+		Workspace: user.Workspace,
+		Requester: userID,
+		Request:   *req,
+		Status:    types.EXPORT_STATUS_PENDING,
+		FileName:  exportFileName(req, now.In(location)),
+		Rows:      rows,
+		CreatedAt: now.Unix(),
+	}
+	if !req.Async && rows <= s.config.MaxSyncRows {
+		return convertExportJobToRes(job), false, nil
+	}
+	if err := s.exportJobRepo.Save(ctx, job); err != nil {
+		return nil, false, err
+	}
+	return convertExportJobToRes(job), true, nil
+}
+
+func (s *exportService) WriteExport(ctx context.Context, req *types.ExportRequest, w io.Writer) error {
+	if err := validateExportRequest(req); err != nil {
+		return err
+	}
+	userID, ok := ctx.Value("user_id").(string)
+	if !ok {
+		return types.ErrInvalidCredentials
+	}
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	location, err := exportLocation(req.Timezone)
+	if err != nil {
+		return err
+	}
+	members, err := s.userRepo.FindByWorkspace(ctx, user.Workspace)
+	if err != nil {
+		return err
+	}
+	export := &exportWriter{
+		service:  s,
+		request:  req,
+		user:     user,
+		location: location,
+		names:    make(map[string]string, len(members)),
+		now:      time.Now(),
+	}
+	for _, member := range members {
+		export.names[member.ID] = member.FullName
+	}
+	switch req.Format {
+	case types.EXPORT_FORMAT_CSV:
+		return export.writeCSV(ctx, w)
+	case types.EXPORT_FORMAT_XLSX:
+		return export.writeXLSX(ctx, w)
+	default:
+		return export.writePDF(ctx, w)
+	}
+}
+
+func (s *exportService) GetExportJob(ctx context.Context, id string) (*types.ExportJobResponse, error) {
+	job, err := s.requesterJob(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return convertExportJobToRes(job), nil
+}
+
+func (s *exportService) DownloadExport(ctx context.Context, id string) (string, string, error) {
+	job, err := s.requesterJob(ctx, id)
+	if err != nil {
+		return "", "", err
+	}
+	if job.Status != types.EXPORT_STATUS_DONE {
+		return "", "", types.ErrExportNotReady
+	}
+	if job.ExpiresAt < time.Now().Unix() {
+		return "", "", types.ErrExportExpired
+	}
+	return job.FilePath, job.FileName, nil
+}
+
+// requesterJob returns the job when the current user requested it
+func (s *exportService) requesterJob(ctx context.Context, id string) (*types.ExportJob, error) {
+	userID, ok := ctx.Value("user_id").(string)
+	if !ok {
+		return nil, types.ErrInvalidCredentials
+	}
+	job, err := s.exportJobRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if job.Requester != userID {
+		return nil, types.ErrExportNotRequester
+	}
+	return job, nil
+}
+
+// ProcessExportJob writes the queued exports and removes the expired ones
+func (s *exportService) ProcessExportJob() worker.Do {
+	return func() error {
+		ctx := context.Background()
+		s.removeExpiredExports(ctx)
+		jobs, err := s.exportJobRepo.FindUnfinished(ctx, exportJobBatch)
+		if err != nil {
+			return err
+		}
+		for _, job := range jobs {
+			lockKey := "export_job:" + job.ID
+			lock, err := s.lockService.Lock(ctx, lockKey, exportLockTTL)
+			if err != nil {
+				continue
+			}
+			s.runExportJob(ctx, job.ID)
+			if err := lock.Release(ctx); err != nil {
+				logrus.Errorf("Failed to unlock export job %s: %v", job.ID, err)
+			}
+		}
+		return nil
+	}
+}
+
+// runExportJob writes the export file of the job on behalf of its
+// requester, so the export sees what the requester would see
+func (s *exportService) runExportJob(ctx context.Context, jobID string) {
+	// another worker may have finished the job since it was listed
+	job, err := s.exportJobRepo.FindByID(ctx, jobID)
+	if err != nil {
+		logrus.Errorf("Failed to load export job %s: %v", jobID, err)
+		return
+	}
+	if job.Status != types.EXPORT_STATUS_PENDING && job.Status != types.EXPORT_STATUS_RUNNING {
+		return
+	}
+	job.ID = ""
+	job.Status = types.EXPORT_STATUS_RUNNING
+	job.StartedAt = time.Now().Unix()
+	if err := s.exportJobRepo.Update(ctx, jobID, job); err != nil {
+		logrus.Errorf("Failed to start export job %s: %v", jobID, err)
+		return
+	}
+
+	path := filepath.Join(s.config.Dir, fmt.Sprintf("export_%s.%s", jobID, job.Request.Format))
+	requesterCtx := context.WithValue(ctx, "user_id", job.Requester)
+	err = s.writeExportFile(requesterCtx, &job.Request, path)
+	now := time.Now()
+	job.FinishedAt = now.Unix()
+	job.ExpiresAt = now.Add(s.config.Retention).Unix()
+	if err != nil {
+		logrus.Errorf("Failed to write export job %s: %v", jobID, err)
+		os.Remove(path)
+		job.Status = types.EXPORT_STATUS_FAILED
+		job.Error = err.Error()
+	} else {
+		job.Status = types.EXPORT_STATUS_DONE
+		job.FilePath = path
+	}
+	if err := s.exportJobRepo.Update(ctx, jobID, job); err != nil {
+		logrus.Errorf("Failed to finish export job %s: %v", jobID, err)
+		return
+	}
+	job.ID = jobID
+	s.notifyExportFinished(ctx, job)
+}
+
+func (s *exportService) writeExportFile(ctx context.Context, req *types.ExportRequest, path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	err = s.WriteExport(ctx, req, file)
+	return errors.Join(err, file.Close())
+}
+
+// removeExpiredExports deletes the finished jobs past their retention with
+// their files
+func (s *exportService) removeExpiredExports(ctx context.Context) {
+	jobs, err := s.exportJobRepo.FindExpired(ctx, time.Now().Unix(), 100)
+	if err != nil {
+		logrus.Errorf("Failed to find expired exports: %v", err)
+		return
+	}
+	for _, job := range jobs {
+		if job.FilePath != "" {
+			if err := os.Remove(job.FilePath); err != nil && !os.IsNotExist(err) {
+				logrus.Errorf("Failed to remove export file %s: %v", job.FilePath, err)
+				continue
+			}
+		}
+		if err := s.exportJobRepo.Delete(ctx, job.ID); err != nil {
+			logrus.Errorf("Failed to remove export job %s: %v", job.ID, err)
+		}
+	}
+}
+
+// notifyExportFinished tells the requester the export can be downloaded, or
+// why it failed
+func (s *exportService) notifyExportFinished(ctx context.Context, job *types.ExportJob) {
+	notification := &types.Notification{
+		Recipient: job.Requester,
+		Type:      types.NOTIFICATION_TYPE_EXPORT_READY,
+		Title:     fmt.Sprintf("Tệp xuất dữ liệu đã sẵn sàng: %s", job.FileName),
+		Message: fmt.Sprintf(
+			"Tệp \"%s\" (%d dòng) có thể tải về tại %s đến %s",
+			job.FileName,
+			job.Rows,
+			fmt.Sprintf(exportDownloadPath, job.ID),
+			time.Unix(job.ExpiresAt, 0).Format(exportTimeLayout),
+		),
+		CreatedAt: time.Now().Unix(),
+	}
+	if job.Status == types.EXPORT_STATUS_FAILED {
+		notification.Type = types.NOTIFICATION_TYPE_EXPORT_FAILED
+		notification.Title = fmt.Sprintf("Xuất dữ liệu thất bại: %s", job.FileName)
+		notification.Message = fmt.Sprintf("Không thể tạo tệp \"%s\": %s", job.FileName, job.Error)
+	}
+	if err := s.notifier.Notify(ctx, notification); err != nil {
+		logrus.Errorf("Failed to notify export job %s: %v", job.ID, err)
+	}
+}
+
+// countRows counts the tasks and reports the export holds, which also
+// validates the filters
+func (s *exportService) countRows(ctx context.Context, req *types.ExportRequest) (int64, error) {
+	rows := int64(0)
+	page := types.PageRequest{Page: 1, Limit: 1}
+	if exportsTasks(req) {
+		_, total, _, err := s.taskService.FilterTasks(ctx, page, req.TaskFilter)
+		if err != nil {
+			return 0, err
+		}
+		rows += total
+	}
+	if exportsReports(req) {
+		_, total, _, err := s.taskService.GetReports(ctx, page, req.ReportFilter)
+		if err != nil {
+			return 0, err
+		}
+		rows += total
+	}
+	return rows, nil
+}
+
+// eachTask calls fn with every task matching the filter, a page at a time
+func (s *exportService) eachTask(ctx context.Context, filter types.TaskFilter, fn func(task *types.TaskResponse) error) error {
+	page := types.PageRequest{Page: 1, Limit: exportPageSize}
+	for {
+		tasks, _, nextCursor, err := s.taskService.FilterTasks(ctx, page, filter)
+		if err != nil {
+			return err
+		}
+		for _, task := range tasks {
+			if err := fn(task); err != nil {
+				return err
+			}
+		}
+		if nextCursor == "" {
+			return nil
+		}
+		page.Cursor = nextCursor
+	}
+}
+
+// eachReport calls fn with every report matching the filter, newest first
+func (s *exportService) eachReport(ctx context.Context, filter types.ReportFilter, fn func(report *types.ReportResponse) error) error {
+	page := types.PageRequest{Page: 1, Limit: exportPageSize}
+	for {
+		reports, _, nextCursor, err := s.taskService.GetReports(ctx, page, filter)
+		if err != nil {
+			return err
+		}
+		for _, report := range reports {
+			if err := fn(report); err != nil {
+				return err
+			}
+		}
+		if nextCursor == "" {
+			return nil
+		}
+		page.Cursor = nextCursor
+	}
+}
+
+func validateExportRequest(req *types.ExportRequest) error {
+	if !slices.Contains(exportFormats, req.Format) {
+		return types.ErrInvalidExportFormat
+	}
+	switch req.Source {
+	case "":
+		if req.Format == types.EXPORT_FORMAT_CSV {
+			req.Source = types.EXPORT_SOURCE_TASKS
+		}
+	case types.EXPORT_SOURCE_TASKS, types.EXPORT_SOURCE_REPORTS:
+		// XLSX and PDF always hold both
+		if req.Format != types.EXPORT_FORMAT_CSV {
+			req.Source = ""
+		}
+	default:
+		return types.ErrInvalidExportSource
+	}
+	return nil
+}
+
+func exportsTasks(req *types.ExportRequest) bool {
+	return req.Format != types.EXPORT_FORMAT_CSV || req.Source == types.EXPORT_SOURCE_TASKS
+}
+
+func exportsReports(req *types.ExportRequest) bool {
+	return req.Format != types.EXPORT_FORMAT_CSV || req.Source == types.EXPORT_SOURCE_REPORTS
+}
+
+func exportLocation(timezone string) (*time.Location, error) {
+	if timezone == "" {
+		return time.Local, nil
+	}
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, types.ErrInvalidTimezone
+	}
+	return location, nil
+}
+
+// exportFileName names the export after its content and creation time, in
+// ASCII so it survives the Content-Disposition header
+func exportFileName(req *types.ExportRequest, now time.Time) string {
+	name := "bao_cao_cong_viec"
+	switch req.Source {
+	case types.EXPORT_SOURCE_TASKS:
+		name = "cong_viec"
+	case types.EXPORT_SOURCE_REPORTS:
+		name = "bao_cao"
+	}
+	return fmt.Sprintf("%s_%s.%s", name, now.Format("20060102_1504"), req.Format)
+}
+
+func convertExportJobToRes(job *types.ExportJob) *types.ExportJobResponse {
+	res := &types.ExportJobResponse{
+		ID:         job.ID,
+		Format:     job.Request.Format,
+		Source:     job.Request.Source,
+		Status:     job.Status,
+		FileName:   job.FileName,
+		Rows:       job.Rows,
+		Error:      job.Error,
+		CreatedAt:  job.CreatedAt,
+		FinishedAt: job.FinishedAt,
+		ExpiresAt:  job.ExpiresAt,
+	}
+	if job.Status == types.EXPORT_STATUS_DONE {
+		res.DownloadURL = fmt.Sprintf(exportDownloadPath, job.ID)
+	}
+	return res
+}
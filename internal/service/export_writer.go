@@ -0,0 +1,526 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/remiehneppo/be-task-management/types"
+	"github.com/xuri/excelize/v2"
+)
+
+const (
+	exportDateLayout  = "02/01/2006"
+	exportTimeLayout  = "02/01/2006 15:04"
+	exportTaskSheet   = "Công việc"
+	exportReportSheet = "Báo cáo"
+	exportFont        = "DejaVu"
+	// exportPDFReportLength truncates the report content in the PDF summary,
+	// the full content is in the other formats
+	exportPDFReportLength = 400
+)
+
+var exportTaskColumns = []string{
+	"Mã công việc",
+	"Tiêu đề",
+	"Trạng thái",
+	"Mức ưu tiên",
+	"Tiến độ (%)",
+	"Người giao",
+	"Người chủ trì",
+	"Người phối hợp",
+	"Ngày bắt đầu",
+	"Hạn hoàn thành",
+	"Ngày hoàn thành",
+	"Quá hạn",
+	"Nhãn",
+	"Ngày tạo",
+}
+
+var exportReportColumns = []string{
+	"Mã báo cáo",
+	"Mã công việc",
+	"Công việc",
+	"Người báo cáo",
+	"Nội dung",
+	"Trạng thái duyệt",
+	"Người duyệt",
+	"Nhận xét",
+	"Ngày báo cáo",
+	"Ngày duyệt",
+}
+
+var exportTaskStatusLabels = map[string]string{
+	types.TASK_STATUS_OPEN:      "Mới",
+	types.TASK_STATUS_DOING:     "Đang thực hiện",
+	types.TASK_STATUS_REVIEW:    "Chờ nghiệm thu",
+	types.TASK_STATUS_COMPLETED: "Hoàn thành",
+	types.TASK_STATUS_CLOSE:     "Đã đóng",
+	types.TASK_STATUS_CANCEL:    "Đã hủy",
+}
+
+var exportReportStatusLabels = map[string]string{
+	types.REPORT_STATUS_PENDING:  "Chờ duyệt",
+	types.REPORT_STATUS_APPROVED: "Đã duyệt",
+	types.REPORT_STATUS_REJECTED: "Yêu cầu bổ sung",
+}
+
+var exportPriorityLabels = map[int]string{
+	types.TASK_PRIORITY_LOW:    "Thấp",
+	types.TASK_PRIORITY_MEDIUM: "Trung bình",
+	types.TASK_PRIORITY_HIGH:   "Cao",
+	types.TASK_PRIORITY_URGENT: "Khẩn",
+}
+
+// exportWriter writes one export in the timezone of the request
+type exportWriter struct {
+	service  *exportService
+	request  *types.ExportRequest
+	user     *types.User
+	location *time.Location
+	// names maps the workspace members to their full name
+	names map[string]string
+	now   time.Time
+}
+
+// writeCSV streams the source table, the byte order mark makes Excel read
+// the file as UTF-8
+func (e *exportWriter) writeCSV(ctx context.Context, w io.Writer) error {
+	if _, err := io.WriteString(w, "\uFEFF"); err != nil {
+		return err
+	}
+	writer := csv.NewWriter(w)
+	write := func(row []interface{}) error {
+		return writer.Write(exportStrings(row))
+	}
+	var err error
+	if e.request.Source == types.EXPORT_SOURCE_REPORTS {
+		err = writer.Write(exportReportColumns)
+		if err == nil {
+			err = e.service.eachReport(ctx, e.request.ReportFilter, func(report *types.ReportResponse) error {
+				return write(e.reportRow(report))
+			})
+		}
+	} else {
+		err = writer.Write(exportTaskColumns)
+		if err == nil {
+			err = e.service.eachTask(ctx, e.request.TaskFilter, func(task *types.TaskResponse) error {
+				return write(e.taskRow(task))
+			})
+		}
+	}
+	if err != nil {
+		return err
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// writeXLSX writes a sheet of tasks and a sheet of reports, the rows are
+// streamed to the workbook rather than kept as cells
+func (e *exportWriter) writeXLSX(ctx context.Context, w io.Writer) error {
+	file := excelize.NewFile()
+	defer file.Close()
+	if err := file.SetSheetName("Sheet1", exportTaskSheet); err != nil {
+		return err
+	}
+	if _, err := file.NewSheet(exportReportSheet); err != nil {
+		return err
+	}
+	headerStyle, err := file.NewStyle(&excelize.Style{
+		Font: &excelize.Font{Bold: true},
+		Fill: excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{"#DDEBF7"}},
+	})
+	if err != nil {
+		return err
+	}
+
+	tasks, err := newExportSheet(file, exportTaskSheet, exportTaskColumns, headerStyle)
+	if err != nil {
+		return err
+	}
+	err = e.service.eachTask(ctx, e.request.TaskFilter, func(task *types.TaskResponse) error {
+		return tasks.add(e.taskRow(task))
+	})
+	if err != nil {
+		return err
+	}
+	if err := tasks.writer.Flush(); err != nil {
+		return err
+	}
+
+	reports, err := newExportSheet(file, exportReportSheet, exportReportColumns, headerStyle)
+	if err != nil {
+		return err
+	}
+	err = e.service.eachReport(ctx, e.request.ReportFilter, func(report *types.ReportResponse) error {
+		return reports.add(e.reportRow(report))
+	})
+	if err != nil {
+		return err
+	}
+	if err := reports.writer.Flush(); err != nil {
+		return err
+	}
+	return file.Write(w)
+}
+
+// exportSheet appends rows below the frozen header of a sheet
+type exportSheet struct {
+	writer *excelize.StreamWriter
+	row    int
+}
+
+func newExportSheet(file *excelize.File, name string, columns []string, headerStyle int) (*exportSheet, error) {
+	writer, err := file.NewStreamWriter(name)
+	if err != nil {
+		return nil, err
+	}
+	if err := writer.SetColWidth(1, len(columns), 22); err != nil {
+		return nil, err
+	}
+	if err := writer.SetPanes(&excelize.Panes{
+		Freeze:      true,
+		YSplit:      1,
+		TopLeftCell: "A2",
+		ActivePane:  "bottomLeft",
+	}); err != nil {
+		return nil, err
+	}
+	header := make([]interface{}, 0, len(columns))
+	for _, column := range columns {
+		header = append(header, excelize.Cell{StyleID: headerStyle, Value: column})
+	}
+	if err := writer.SetRow("A1", header); err != nil {
+		return nil, err
+	}
+	return &exportSheet{writer: writer, row: 1}, nil
+}
+
+func (s *exportSheet) add(values []interface{}) error {
+	s.row++
+	cell, err := excelize.CoordinatesToCellName(1, s.row)
+	if err != nil {
+		return err
+	}
+	return s.writer.SetRow(cell, values)
+}
+
+// writePDF writes the printable summary: the task and report counts by
+// status followed by the task and report lists
+func (e *exportWriter) writePDF(ctx context.Context, w io.Writer) error {
+	tasks := make([]*types.TaskResponse, 0)
+	err := e.service.eachTask(ctx, e.request.TaskFilter, func(task *types.TaskResponse) error {
+		tasks = append(tasks, task)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	reports := make([]*types.ReportResponse, 0)
+	err = e.service.eachReport(ctx, e.request.ReportFilter, func(report *types.ReportResponse) error {
+		reports = append(reports, report)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// the core PDF fonts have no Vietnamese glyphs
+	pdf := fpdf.New("L", "mm", "A4", "")
+	fonts := []struct {
+		style string
+		path  string
+	}{
+		{"", e.service.config.FontPath},
+		{"B", e.service.config.BoldFontPath},
+	}
+	for _, font := range fonts {
+		data, err := os.ReadFile(font.path)
+		if err != nil {
+			return fmt.Errorf("failed to load export font: %w", err)
+		}
+		pdf.AddUTF8FontFromBytes(exportFont, font.style, data)
+	}
+	if err := pdf.Error(); err != nil {
+		return err
+	}
+	pdf.SetMargins(10, 10, 10)
+	pdf.SetAutoPageBreak(true, 15)
+	pdf.AliasNbPages("")
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-12)
+		pdf.SetFont(exportFont, "", 8)
+		pdf.CellFormat(0, 8, fmt.Sprintf("Trang %d/{nb}", pdf.PageNo()), "", 0, "C", false, 0, "")
+	})
+	pdf.AddPage()
+
+	pdf.SetFont(exportFont, "B", 16)
+	pdf.CellFormat(0, 10, "BÁO CÁO TÌNH HÌNH THỰC HIỆN CÔNG VIỆC", "", 1, "C", false, 0, "")
+	pdf.SetFont(exportFont, "", 10)
+	pdf.CellFormat(0, 6, "Đơn vị: "+e.user.Workspace, "", 1, "C", false, 0, "")
+	if period := e.period(); period != "" {
+		pdf.CellFormat(0, 6, period, "", 1, "C", false, 0, "")
+	}
+	pdf.CellFormat(0, 6, fmt.Sprintf("Người lập: %s - Ngày lập: %s", e.user.FullName, e.now.In(e.location).Format(exportTimeLayout)), "", 1, "C", false, 0, "")
+	pdf.Ln(4)
+
+	exportPDFHeading(pdf, "I. TỔNG HỢP")
+	taskCounts := make(map[string]int)
+	overdue := 0
+	for _, task := range tasks {
+		taskCounts[task.Status]++
+		if e.overdue(task) {
+			overdue++
+		}
+	}
+	summary := newExportPDFTable(pdf, []float64{70, 30}, []string{"Công việc", "Số lượng"})
+	for _, status := range types.TASK_BOARD_STATUSES {
+		summary.row(exportTaskStatusLabels[status], strconv.Itoa(taskCounts[status]))
+	}
+	summary.row("Quá hạn", strconv.Itoa(overdue))
+	summary.row("Tổng số", strconv.Itoa(len(tasks)))
+	pdf.Ln(4)
+	reportCounts := make(map[string]int)
+	for _, report := range reports {
+		reportCounts[report.Status]++
+	}
+	summary = newExportPDFTable(pdf, []float64{70, 30}, []string{"Báo cáo", "Số lượng"})
+	for _, status := range reportStatuses {
+		summary.row(exportReportStatusLabels[status], strconv.Itoa(reportCounts[status]))
+	}
+	summary.row("Tổng số", strconv.Itoa(len(reports)))
+	pdf.Ln(6)
+
+	exportPDFHeading(pdf, "II. DANH SÁCH CÔNG VIỆC")
+	table := newExportPDFTable(pdf,
+		[]float64{10, 85, 35, 40, 27, 30, 20, 30},
+		[]string{"STT", "Tiêu đề", "Người giao", "Người chủ trì", "Hạn hoàn thành", "Trạng thái", "Tiến độ", "Ngày hoàn thành"},
+	)
+	for i, task := range tasks {
+		deadline := e.date(task.Deadline)
+		if e.overdue(task) {
+			deadline += " (quá hạn)"
+		}
+		table.row(
+			strconv.Itoa(i+1),
+			task.Title,
+			task.Creator,
+			task.Assignee,
+			deadline,
+			exportLabel(exportTaskStatusLabels, task.Status),
+			fmt.Sprintf("%d%%", task.Progress),
+			e.date(task.CompletedAt),
+		)
+	}
+	pdf.Ln(6)
+
+	exportPDFHeading(pdf, "III. DANH SÁCH BÁO CÁO")
+	table = newExportPDFTable(pdf,
+		[]float64{10, 60, 35, 27, 28, 117},
+		[]string{"STT", "Công việc", "Người báo cáo", "Ngày báo cáo", "Trạng thái", "Nội dung"},
+	)
+	for i, report := range reports {
+		table.row(
+			strconv.Itoa(i+1),
+			report.TaskTitle,
+			report.CreatorName,
+			e.date(report.CreatedAt),
+			exportLabel(exportReportStatusLabels, report.Status),
+			exportTruncate(report.Report, exportPDFReportLength),
+		)
+	}
+	return pdf.Output(w)
+}
+
+// period describes the report creation range, or else the deadline range,
+// the export was filtered on
+func (e *exportWriter) period() string {
+	from, to := e.request.ReportFilter.CreatedFrom, e.request.ReportFilter.CreatedTo
+	if from == 0 && to == 0 {
+		from, to = e.request.TaskFilter.DeadlineFrom, e.request.TaskFilter.DeadlineTo
+	}
+	switch {
+	case from != 0 && to != 0:
+		return fmt.Sprintf("Kỳ báo cáo: từ ngày %s đến ngày %s", e.date(from), e.date(to))
+	case from != 0:
+		return fmt.Sprintf("Kỳ báo cáo: từ ngày %s", e.date(from))
+	case to != 0:
+		return fmt.Sprintf("Kỳ báo cáo: đến ngày %s", e.date(to))
+	}
+	return ""
+}
+
+func (e *exportWriter) taskRow(task *types.TaskResponse) []interface{} {
+	helpers := make([]string, 0, len(task.Assignees))
+	for _, member := range task.Assignees {
+		if !member.Primary {
+			helpers = append(helpers, member.FullName)
+		}
+	}
+	overdue := ""
+	if e.overdue(task) {
+		overdue = "Có"
+	}
+	return []interface{}{
+		task.ID,
+		task.Title,
+		exportLabel(exportTaskStatusLabels, task.Status),
+		exportPriorityLabels[task.Priority],
+		task.Progress,
+		task.Creator,
+		task.Assignee,
+		strings.Join(helpers, ", "),
+		e.date(task.StartAt),
+		e.date(task.Deadline),
+		e.date(task.CompletedAt),
+		overdue,
+		strings.Join(task.Tags, ", "),
+		e.dateTime(task.CreateAt),
+	}
+}
+
+func (e *exportWriter) reportRow(report *types.ReportResponse) []interface{} {
+	return []interface{}{
+		report.ID,
+		report.TaskID,
+		report.TaskTitle,
+		report.CreatorName,
+		report.Report,
+		exportLabel(exportReportStatusLabels, report.Status),
+		e.names[report.ReviewedBy],
+		report.Feedback,
+		e.dateTime(report.CreatedAt),
+		e.dateTime(report.ReviewedAt),
+	}
+}
+
+func (e *exportWriter) overdue(task *types.TaskResponse) bool {
+	return task.Deadline != 0 && task.Deadline < e.now.Unix() && !isTaskFinished(task.Status)
+}
+
+func (e *exportWriter) date(timestamp int64) string {
+	if timestamp == 0 {
+		return ""
+	}
+	return time.Unix(timestamp, 0).In(e.location).Format(exportDateLayout)
+}
+
+func (e *exportWriter) dateTime(timestamp int64) string {
+	if timestamp == 0 {
+		return ""
+	}
+	return time.Unix(timestamp, 0).In(e.location).Format(exportTimeLayout)
+}
+
+// exportPDFTable draws rows of wrapped cells, repeating the header on every
+// page the table continues on
+type exportPDFTable struct {
+	pdf    *fpdf.Fpdf
+	widths []float64
+	header []string
+}
+
+const exportPDFLineHeight = 5.0
+
+func newExportPDFTable(pdf *fpdf.Fpdf, widths []float64, header []string) *exportPDFTable {
+	table := &exportPDFTable{pdf: pdf, widths: widths, header: header}
+	table.drawHeader()
+	return table
+}
+
+func (t *exportPDFTable) drawHeader() {
+	t.pdf.SetFont(exportFont, "B", 9)
+	t.pdf.SetFillColor(221, 235, 247)
+	t.draw(t.header, true)
+	t.pdf.SetFont(exportFont, "", 9)
+}
+
+func (t *exportPDFTable) row(values ...string) {
+	t.draw(values, false)
+}
+
+func (t *exportPDFTable) draw(values []string, header bool) {
+	lines := make([][]string, len(values))
+	count := 1
+	for i, value := range values {
+		lines[i] = t.pdf.SplitText(exportPDFText(value), t.widths[i])
+		count = max(count, len(lines[i]))
+	}
+	height := float64(count)*exportPDFLineHeight + 2
+	_, pageHeight := t.pdf.GetPageSize()
+	_, _, _, bottom := t.pdf.GetMargins()
+	if t.pdf.GetY()+height > pageHeight-bottom {
+		t.pdf.AddPage()
+		if !header {
+			t.drawHeader()
+		}
+	}
+	left, _, _, _ := t.pdf.GetMargins()
+	x, y := left, t.pdf.GetY()
+	style := "D"
+	if header {
+		style = "FD"
+	}
+	for i, cell := range lines {
+		t.pdf.Rect(x, y, t.widths[i], height, style)
+		for j, line := range cell {
+			t.pdf.SetXY(x, y+1+float64(j)*exportPDFLineHeight)
+			t.pdf.CellFormat(t.widths[i], exportPDFLineHeight, line, "", 0, "L", false, 0, "")
+		}
+		x += t.widths[i]
+	}
+	t.pdf.SetXY(left, y+height)
+}
+
+func exportPDFHeading(pdf *fpdf.Fpdf, title string) {
+	pdf.SetFont(exportFont, "B", 12)
+	pdf.CellFormat(0, 8, title, "", 1, "L", false, 0, "")
+}
+
+// exportPDFText drops the characters outside the basic multilingual plane,
+// which the UTF-8 fonts of fpdf cannot measure
+func exportPDFText(value string) string {
+	return strings.Map(func(r rune) rune {
+		if r > 0xFFFF {
+			return -1
+		}
+		return r
+	}, value)
+}
+
+func exportTruncate(value string, length int) string {
+	if utf8.RuneCountInString(value) <= length {
+		return value
+	}
+	return string([]rune(value)[:length]) + "…"
+}
+
+func exportLabel(labels map[string]string, value string) string {
+	if label, ok := labels[value]; ok {
+		return label
+	}
+	return value
+}
+
+// exportStrings formats a CSV row. Spreadsheets run text starting with =, +,
+// -, @, tab or carriage return as a formula, such text is prefixed with a
+// quote. XLSX cells need no such care, their strings are typed as text.
+func exportStrings(values []interface{}) []string {
+	strs := make([]string, 0, len(values))
+	for _, value := range values {
+		str := fmt.Sprint(value)
+		if _, ok := value.(string); ok && str != "" && strings.ContainsRune("=+-@\t\r", rune(str[0])) {
+			str = "'" + str
+		}
+		strs = append(strs, str)
+	}
+	return strs
+}
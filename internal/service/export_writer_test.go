@@ -0,0 +1,32 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExportStrings(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []interface{}
+		want   []string
+	}{
+		{name: "plain text", values: []interface{}{"Báo cáo tuần", "a=b"}, want: []string{"Báo cáo tuần", "a=b"}},
+		{name: "formula", values: []interface{}{"=HYPERLINK(\"http://x\")"}, want: []string{"'=HYPERLINK(\"http://x\")"}},
+		{name: "plus", values: []interface{}{"+1+1"}, want: []string{"'+1+1"}},
+		{name: "minus", values: []interface{}{"-2+3"}, want: []string{"'-2+3"}},
+		{name: "at", values: []interface{}{"@SUM(A1)"}, want: []string{"'@SUM(A1)"}},
+		{name: "tab", values: []interface{}{"\t=1"}, want: []string{"'\t=1"}},
+		{name: "carriage return", values: []interface{}{"\r=1"}, want: []string{"'\r=1"}},
+		{name: "empty", values: []interface{}{""}, want: []string{""}},
+		{name: "numbers stay numbers", values: []interface{}{-5, int64(-7), 1.5}, want: []string{"-5", "-7", "1.5"}},
+		{name: "mixed row", values: []interface{}{"id", 3, "=cmd", true}, want: []string{"id", "3", "'=cmd", "true"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := exportStrings(tt.values); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("exportStrings(%q) = %q, want %q", tt.values, got, tt.want)
+			}
+		})
+	}
+}
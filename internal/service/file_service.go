@@ -18,10 +18,13 @@ var _ FileService = (*fileService)(nil)
 type FileService interface {
 	UploadFile(ctx context.Context, req types.UploadFileRequest) (*types.UploadFileResponse, error)
 	GetFile(ctx context.Context, filePath string) (*os.File, error)
+	GetFilePath(ctx context.Context, filePath string) (string, error)
+	GetFileList(ctx context.Context, page types.PageRequest) (items []*types.FileMetadata, total int64, nextCursor string, err error)
+	// DeleteFile removes a file returned by UploadFile and its metadata, a
+	// file already gone is not an error
+	DeleteFile(ctx context.Context, filePath string) error
 	// DownloadFile(fileID string) (string, error)
-	// DeleteFile(fileID string) error
 	// GetFileMetadata(fileID string) (*types.FileMetadata, error)
-	// GetFileList(userID string, page, limit int) ([]*types.FileMetadata, error)
 }
 
 type fileService struct {
@@ -60,7 +63,10 @@ func (f *fileService) UploadFile(ctx context.Context, req types.UploadFileReques
 	if filepath.Ext(req.FileName) != ext {
 		req.FileName += ext
 	}
-	// Construct the file path
+	// Construct the file path, the name must not leave the upload directory
+	if !filepath.IsLocal(req.FileName) {
+		return nil, types.ErrInvalidFilePath
+	}
 	filePath := filepath.Join(f.uploadDir, req.FileName)
 
 	src, err := req.FileHeader.Open()
@@ -93,10 +99,68 @@ func (f *fileService) UploadFile(ctx context.Context, req types.UploadFileReques
 }
 
 func (f *fileService) GetFile(ctx context.Context, filePath string) (*os.File, error) {
-	filePath = filepath.Join(f.uploadDir, filePath)
+	filePath, err := f.resolvePath(filePath)
+	if err != nil {
+		return nil, err
+	}
 	file, err := os.Open(filePath)
 	if err != nil {
 		return nil, err
 	}
 	return file, nil
 }
+
+// GetFilePath resolves a stored file reference, either a path returned by
+// UploadFile or a path relative to the upload directory, to a file on disk
+func (f *fileService) GetFilePath(ctx context.Context, filePath string) (string, error) {
+	fullPath, err := f.resolvePath(filePath)
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(fullPath); err != nil {
+		return "", err
+	}
+	return fullPath, nil
+}
+
+func (f *fileService) DeleteFile(ctx context.Context, filePath string) error {
+	fullPath, err := f.resolvePath(filePath)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(fullPath); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return f.fileMetadataRepo.DeleteFileMetadataByPath(ctx, filePath)
+}
+
+// resolvePath maps a file reference to a path under the upload directory.
+// References come from users, absolute paths and paths escaping the upload
+// directory are rejected.
+func (f *fileService) resolvePath(filePath string) (string, error) {
+	root, err := filepath.Abs(f.uploadDir)
+	if err != nil {
+		return "", err
+	}
+	relPath := filepath.Clean(filePath)
+	// UploadFile returns the path joined with the upload directory
+	for _, dir := range []string{filepath.Clean(f.uploadDir), root} {
+		if prefix := dir + string(filepath.Separator); strings.HasPrefix(relPath, prefix) {
+			relPath = strings.TrimPrefix(relPath, prefix)
+			break
+		}
+	}
+	if filepath.IsAbs(relPath) || !filepath.IsLocal(relPath) {
+		return "", types.ErrInvalidFilePath
+	}
+	fullPath := filepath.Join(root, relPath)
+	if !strings.HasPrefix(fullPath, root+string(filepath.Separator)) {
+		return "", types.ErrInvalidFilePath
+	}
+	return fullPath, nil
+}
+
+// GetFileList returns a page of the uploaded files, newest first
+func (f *fileService) GetFileList(ctx context.Context, page types.PageRequest) (items []*types.FileMetadata, total int64, nextCursor string, err error) {
+	return f.fileMetadataRepo.GetFileList(ctx, page)
+}
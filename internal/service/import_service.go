@@ -0,0 +1,648 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/remiehneppo/be-task-management/internal/database"
+	"github.com/remiehneppo/be-task-management/internal/repository"
+	"github.com/remiehneppo/be-task-management/types"
+	"github.com/xuri/excelize/v2"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// importMaxRows bounds the rows of an import file, header excluded
+const importMaxRows = 10000
+
+var importKinds = []string{
+	types.IMPORT_KIND_USERS,
+	types.IMPORT_KIND_TASKS,
+	types.IMPORT_KIND_REPORTS,
+}
+
+// importColumns are the columns an import file of each kind must have,
+// reports name their task by task_id or by task_title
+var importColumns = map[string][]string{
+	types.IMPORT_KIND_USERS:   {"username", "password", "full_name", "workspace_role", "workspace"},
+	types.IMPORT_KIND_TASKS:   {"title", "workspace", "creator", "assignee"},
+	types.IMPORT_KIND_REPORTS: {"creator", "report"},
+}
+
+// importTimeLayouts are the dates accepted besides unix timestamps, read in
+// the server timezone
+var importTimeLayouts = []string{
+	"2006-01-02",
+	"2006-01-02 15:04",
+	"02/01/2006",
+	"02/01/2006 15:04",
+}
+
+var _ ImportService = (*importService)(nil)
+
+// ImportService loads users, tasks and reports from CSV or XLSX files. The
+// columns are matched by the header names and the users of tasks and
+// reports are named by username or ID.
+type ImportService interface {
+	// Import imports into any workspace, it is meant for operators seeding
+	// an environment
+	Import(ctx context.Context, req *types.ImportRequest, file io.Reader, fileName string) (*types.ImportResult, error)
+	// ImportIntoWorkspace imports into the workspace of the current user,
+	// whose role must grant data.import, and user.manage for users. The users
+	// created are below the importer's management level and audit-logged.
+	ImportIntoWorkspace(ctx context.Context, req *types.ImportRequest, file io.Reader, fileName string) (*types.ImportResult, error)
+}
+
+type importService struct {
+	userRepo      repository.UserRepository
+	taskRepo      repository.TaskRepository
+	reportRepo    repository.ReportRepository
+	workspaceRepo repository.WorkspaceRepository
+	auditLogRepo  repository.AuditLogRepository
+	transactor    database.Transactor
+	authorizer    AuthorizationService
+}
+
+func NewImportService(
+	userRepo repository.UserRepository,
+	taskRepo repository.TaskRepository,
+	reportRepo repository.ReportRepository,
+	workspaceRepo repository.WorkspaceRepository,
+	auditLogRepo repository.AuditLogRepository,
+	transactor database.Transactor,
+	authorizer AuthorizationService,
+) ImportService {
+	return &importService{
+		userRepo:      userRepo,
+		taskRepo:      taskRepo,
+		reportRepo:    reportRepo,
+		workspaceRepo: workspaceRepo,
+		auditLogRepo:  auditLogRepo,
+		transactor:    transactor,
+		authorizer:    authorizer,
+	}
+}
+
+func (s *importService) ImportIntoWorkspace(ctx context.Context, req *types.ImportRequest, file io.Reader, fileName string) (*types.ImportResult, error) {
+	userID, ok := ctx.Value("user_id").(string)
+	if !ok {
+		return nil, types.ErrInvalidCredentials
+	}
+	user, err := s.authorizer.Authorize(ctx, userID, types.PERMISSION_DATA_IMPORT)
+	if err != nil {
+		return nil, err
+	}
+	if req.Kind == types.IMPORT_KIND_USERS {
+		allowed, err := s.authorizer.Can(ctx, user, types.PERMISSION_USER_MANAGE)
+		if err != nil {
+			return nil, err
+		}
+		if !allowed {
+			return nil, permissionError(types.PERMISSION_USER_MANAGE)
+		}
+	}
+	req.Workspace = user.Workspace
+	return s.importFile(ctx, user, req, file, fileName)
+}
+
+func (s *importService) Import(ctx context.Context, req *types.ImportRequest, file io.Reader, fileName string) (*types.ImportResult, error) {
+	return s.importFile(ctx, nil, req, file, fileName)
+}
+
+// importFile imports the rows of the file on behalf of the actor, nil for
+// the import command
+func (s *importService) importFile(ctx context.Context, actor *types.User, req *types.ImportRequest, file io.Reader, fileName string) (*types.ImportResult, error) {
+	if !slices.Contains(importKinds, req.Kind) {
+		return nil, types.ErrInvalidImportKind
+	}
+	records, err := readImportRecords(file, fileName, importColumns[req.Kind])
+	if err != nil {
+		return nil, err
+	}
+	result := &types.ImportResult{
+		Kind:        req.Kind,
+		DryRun:      req.DryRun,
+		SkipInvalid: req.SkipInvalid,
+		Rows:        len(records),
+		Errors:      make([]types.ImportRowError, 0),
+	}
+	workspaces, err := s.workspaceRepo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resolver := &importResolver{
+		service:    s,
+		actor:      actor,
+		workspace:  req.Workspace,
+		workspaces: make(map[string]bool, len(workspaces)),
+		usernames:  make(map[string]bool),
+	}
+	for _, workspace := range workspaces {
+		resolver.workspaces[workspace.Name] = true
+	}
+	rows := make([]*importRow, 0, len(records))
+	for _, record := range records {
+		var row *importRow
+		switch req.Kind {
+		case types.IMPORT_KIND_USERS:
+			row, err = resolver.userRow(ctx, record)
+		case types.IMPORT_KIND_TASKS:
+			row, err = resolver.taskRow(ctx, record)
+		default:
+			row, err = resolver.reportRow(ctx, record)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(record.errors) > 0 {
+			result.Errors = append(result.Errors, record.errors...)
+			result.Skipped++
+			continue
+		}
+		rows = append(rows, row)
+	}
+
+	switch {
+	case req.DryRun:
+		result.Imported = len(rows)
+		return result, nil
+	case !req.SkipInvalid && len(result.Errors) > 0:
+		result.Skipped = len(records)
+		return result, types.ErrImportInvalidRows
+	case !req.SkipInvalid:
+		err := s.transactor.WithTransaction(ctx, func(ctx context.Context) error {
+			for _, row := range rows {
+				if err := row.write(ctx); err != nil {
+					return fmt.Errorf("row %d: %w", row.line, err)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		result.Imported = len(rows)
+		return result, nil
+	}
+	// rows that fail to write are skipped like invalid ones
+	for _, row := range rows {
+		if err := row.write(ctx); err != nil {
+			result.Errors = append(result.Errors, types.ImportRowError{Row: row.line, Message: err.Error()})
+			result.Skipped++
+			continue
+		}
+		result.Imported++
+	}
+	return result, nil
+}
+
+// importRecord is a row of an import file by column name, the problems
+// found while reading it are collected in errors
+type importRecord struct {
+	line   int
+	values map[string]string
+	errors []types.ImportRowError
+}
+
+func (r *importRecord) fail(column, message string) {
+	r.errors = append(r.errors, types.ImportRowError{Row: r.line, Column: column, Message: message})
+}
+
+func (r *importRecord) get(column string) string {
+	return strings.TrimSpace(r.values[column])
+}
+
+func (r *importRecord) required(column string) string {
+	value := r.get(column)
+	if value == "" {
+		r.fail(column, "is required")
+	}
+	return value
+}
+
+func (r *importRecord) int(column string, defaultValue, minValue, maxValue int) int {
+	value := r.get(column)
+	if value == "" {
+		return defaultValue
+	}
+	number, err := strconv.Atoi(value)
+	if err != nil || number < minValue || number > maxValue {
+		r.fail(column, fmt.Sprintf("must be a number from %d to %d", minValue, maxValue))
+		return defaultValue
+	}
+	return number
+}
+
+// timestamp reads a unix timestamp or a date in one of importTimeLayouts
+func (r *importRecord) timestamp(column string, defaultValue int64) int64 {
+	value := r.get(column)
+	if value == "" {
+		return defaultValue
+	}
+	if timestamp, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return timestamp
+	}
+	for _, layout := range importTimeLayouts {
+		if date, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return date.Unix()
+		}
+	}
+	r.fail(column, "must be a unix timestamp or a date such as 2006-01-02")
+	return defaultValue
+}
+
+// importRow is a valid row, write builds its document anew on every call
+// since a transaction may be retried
+type importRow struct {
+	line  int
+	write func(ctx context.Context) error
+}
+
+// importResolver checks rows against the stored users and tasks, caching
+// what it looked up
+type importResolver struct {
+	service *importService
+	// actor is the user importing into their workspace, nil for the import
+	// command
+	actor     *types.User
+	workspace string
+	// workspaces are the names of the existing workspaces
+	workspaces map[string]bool
+	// usernames are the usernames taken by the previous rows
+	usernames map[string]bool
+	users     map[string]*types.User
+	tasks     map[string]*types.Task
+	// titles are the tasks of a workspace by title
+	titles map[string]map[string][]*types.Task
+}
+
+func (r *importResolver) userRow(ctx context.Context, record *importRecord) (*importRow, error) {
+	now := time.Now().Unix()
+	username := record.required("username")
+	password := record.required("password")
+	fullName := record.required("full_name")
+	role := record.required("workspace_role")
+	workspace := r.checkWorkspace(record, record.required("workspace"))
+	// admins are only created by the import command, a workspace import
+	// must not grant more than its importer holds
+	adminRole := record.get("role")
+	if adminRole != "" && adminRole != types.USER_ROLE_ADMIN {
+		record.fail("role", fmt.Sprintf("must be empty or %s", types.USER_ROLE_ADMIN))
+	} else if adminRole != "" && r.workspace != "" {
+		record.fail("role", "can only be set by the import command")
+	}
+	if username != "" {
+		if strings.ContainsFunc(username, func(c rune) bool { return c == ' ' || c == '\t' }) {
+			record.fail("username", "must not contain spaces")
+		} else if r.usernames[username] {
+			record.fail("username", "is repeated in the file")
+		} else {
+			_, err := r.service.userRepo.FindByUsername(ctx, username)
+			switch {
+			case err == nil:
+				record.fail("username", "is already taken")
+			case !errors.Is(err, types.ErrUserNotFound):
+				return nil, err
+			}
+		}
+		r.usernames[username] = true
+	}
+	level, ok := types.MAPPING_ROLE_TO_MANAGEMENT_LEVEL[role]
+	if role != "" && !ok {
+		record.fail("workspace_role", "is not a workspace role")
+	}
+	if ok && record.int("management_level", level, 1, types.USER_MANAGEMENT_LEVEL_EXECUTIVE) != level {
+		record.fail("management_level", fmt.Sprintf("must be %d for the %s role", level, role))
+	}
+	createdAt := record.timestamp("created_at", now)
+	updatedAt := record.timestamp("updated_at", createdAt)
+	if r.actor != nil && ok {
+		err := checkManagedUser(r.actor, &types.User{Role: adminRole, WorkspaceRole: role, Workspace: workspace})
+		if err != nil {
+			record.fail("workspace_role", err.Error())
+		}
+	}
+	return &importRow{
+		line: record.line,
+		write: func(ctx context.Context) error {
+			user := &types.User{
+				Username:        username,
+				Password:        password,
+				FullName:        fullName,
+				Role:            adminRole,
+				ManagementLevel: level,
+				WorkspaceRole:   role,
+				Workspace:       workspace,
+				CreateAt:        createdAt,
+				UpdateAt:        updatedAt,
+			}
+			if err := r.service.userRepo.Save(ctx, user); err != nil {
+				return err
+			}
+			if r.actor != nil {
+				recordAudit(ctx, r.service.auditLogRepo, &types.AuditLog{
+					Actor:      r.actor.ID,
+					Action:     types.AUDIT_ACTION_USER_CREATE,
+					TargetType: types.AUDIT_TARGET_USER,
+					TargetID:   user.ID,
+					Changes:    diffUser(&types.User{}, user),
+				})
+			}
+			return nil
+		},
+	}, nil
+}
+
+func (r *importResolver) taskRow(ctx context.Context, record *importRecord) (*importRow, error) {
+	now := time.Now().Unix()
+	title := record.required("title")
+	workspace := r.checkWorkspace(record, record.required("workspace"))
+	creator, err := r.member(ctx, record, "creator", workspace)
+	if err != nil {
+		return nil, err
+	}
+	assignee, err := r.member(ctx, record, "assignee", workspace)
+	if err != nil {
+		return nil, err
+	}
+	status := record.get("status")
+	if status == "" {
+		status = types.TASK_STATUS_OPEN
+	} else if !slices.Contains(types.TASK_BOARD_STATUSES, status) {
+		record.fail("status", "is not a task status")
+	}
+	progress := record.int("progress", 0, 0, 100)
+	priority := record.int("priority", types.TASK_PRIORITY_NONE, types.TASK_PRIORITY_NONE, types.TASK_PRIORITY_URGENT)
+	startAt := record.timestamp("start_at", 0)
+	deadline := record.timestamp("deadline", 0)
+	if startAt != 0 && deadline != 0 && startAt > deadline {
+		record.fail("deadline", "is before start_at")
+	}
+	createdAt := record.timestamp("created_at", now)
+	updatedAt := record.timestamp("updated_at", createdAt)
+	description := record.get("description")
+	return &importRow{
+		line: record.line,
+		write: func(ctx context.Context) error {
+			task := &types.Task{
+				Title:       title,
+				Description: description,
+				Workspace:   workspace,
+				Creator:     creator,
+				Assignee:    assignee,
+				Assignees:   []string{assignee},
+				Status:      status,
+				Progress:    progress,
+				Priority:    priority,
+				StartAt:     startAt,
+				Deadline:    deadline,
+				CreateAt:    createdAt,
+				UpdateAt:    updatedAt,
+			}
+			// imported tasks were completed when last updated
+			if status == types.TASK_STATUS_COMPLETED {
+				task.CompletedAt = updatedAt
+			}
+			return r.service.taskRepo.Save(ctx, task)
+		},
+	}, nil
+}
+
+func (r *importResolver) reportRow(ctx context.Context, record *importRecord) (*importRow, error) {
+	now := time.Now().Unix()
+	task, err := r.task(ctx, record)
+	if err != nil {
+		return nil, err
+	}
+	workspace := ""
+	if task != nil {
+		workspace = r.checkWorkspace(record, task.Workspace)
+	}
+	creator, err := r.member(ctx, record, "creator", workspace)
+	if err != nil {
+		return nil, err
+	}
+	report := record.required("report")
+	status := record.get("status")
+	if status != "" && !slices.Contains(reportStatuses, status) {
+		record.fail("status", "is not a report status")
+	}
+	createdAt := record.timestamp("created_at", now)
+	updatedAt := record.timestamp("updated_at", createdAt)
+	reportFile := record.get("report_file")
+	feedback := record.get("feedback")
+	return &importRow{
+		line: record.line,
+		write: func(ctx context.Context) error {
+			return r.service.reportRepo.Save(ctx, &types.Report{
+				TaskID:     task.ID,
+				Creator:    creator,
+				Report:     report,
+				ReportFile: reportFile,
+				Feedback:   feedback,
+				Status:     status,
+				CreatedAt:  createdAt,
+				UpdatedAt:  updatedAt,
+			})
+		},
+	}, nil
+}
+
+// checkWorkspace fails the row when the workspace does not exist or is out
+// of the import workspace
+func (r *importResolver) checkWorkspace(record *importRecord, workspace string) string {
+	switch {
+	case workspace == "":
+	case !r.workspaces[workspace]:
+		record.fail("workspace", fmt.Sprintf("workspace %s does not exist", workspace))
+	case r.workspace != "" && workspace != r.workspace:
+		record.fail("workspace", fmt.Sprintf("workspace %s is outside the import workspace", workspace))
+	}
+	return workspace
+}
+
+// member resolves the username or ID of the column to the ID of a member of
+// the workspace, any workspace when it is unknown
+func (r *importResolver) member(ctx context.Context, record *importRecord, column, workspace string) (string, error) {
+	value := record.required(column)
+	if value == "" {
+		return "", nil
+	}
+	user, err := r.user(ctx, value)
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case user == nil:
+		record.fail(column, fmt.Sprintf("user %s does not exist", value))
+		return "", nil
+	case workspace != "" && user.Workspace != workspace:
+		record.fail(column, fmt.Sprintf("user %s is not a member of %s", value, workspace))
+	}
+	return user.ID, nil
+}
+
+// user finds a user by username, or else by ID, nil when neither matches
+func (r *importResolver) user(ctx context.Context, value string) (*types.User, error) {
+	if r.users == nil {
+		r.users = make(map[string]*types.User)
+	}
+	if user, ok := r.users[value]; ok {
+		return user, nil
+	}
+	user, err := r.service.userRepo.FindByUsername(ctx, value)
+	if errors.Is(err, types.ErrUserNotFound) {
+		user, err = nil, nil
+		if isObjectID(value) {
+			user, err = r.service.userRepo.FindByID(ctx, value)
+			if err != nil && errors.Is(err, mongo.ErrNoDocuments) {
+				user, err = nil, nil
+			}
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	r.users[value] = user
+	return user, nil
+}
+
+// task finds the task of a report by task_id or, without one, by its
+// task_title in the workspace of the creator
+func (r *importResolver) task(ctx context.Context, record *importRecord) (*types.Task, error) {
+	if r.tasks == nil {
+		r.tasks = make(map[string]*types.Task)
+		r.titles = make(map[string]map[string][]*types.Task)
+	}
+	taskID := record.get("task_id")
+	if taskID != "" {
+		if task, ok := r.tasks[taskID]; ok {
+			return task, nil
+		}
+		if !isObjectID(taskID) {
+			record.fail("task_id", "is not a task ID")
+			return nil, nil
+		}
+		task, err := r.service.taskRepo.FindByID(ctx, taskID)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			record.fail("task_id", fmt.Sprintf("task %s does not exist", taskID))
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		r.tasks[taskID] = task
+		return task, nil
+	}
+
+	title := record.get("task_title")
+	if title == "" {
+		record.fail("task_id", "task_id or task_title is required")
+		return nil, nil
+	}
+	creator, err := r.user(ctx, record.get("creator"))
+	if err != nil || creator == nil {
+		// the creator column reports the missing user
+		return nil, err
+	}
+	titles, ok := r.titles[creator.Workspace]
+	if !ok {
+		tasks, err := r.service.taskRepo.FindByWorkspace(ctx, creator.Workspace)
+		if err != nil {
+			return nil, err
+		}
+		titles = make(map[string][]*types.Task)
+		for _, task := range tasks {
+			titles[task.Title] = append(titles[task.Title], task)
+		}
+		r.titles[creator.Workspace] = titles
+	}
+	switch len(titles[title]) {
+	case 0:
+		record.fail("task_title", fmt.Sprintf("no task is titled %s in %s", title, creator.Workspace))
+		return nil, nil
+	case 1:
+		return titles[title][0], nil
+	}
+	record.fail("task_title", fmt.Sprintf("several tasks are titled %s, use task_id", title))
+	return nil, nil
+}
+
+func isObjectID(value string) bool {
+	_, err := bson.ObjectIDFromHex(value)
+	return err == nil
+}
+
+// readImportRecords reads the rows of a CSV file or of the first sheet of an
+// XLSX file, the first row names the columns
+func readImportRecords(file io.Reader, fileName string, columns []string) ([]*importRecord, error) {
+	var rows [][]string
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".csv":
+		reader := csv.NewReader(file)
+		reader.FieldsPerRecord = -1
+		var err error
+		rows, err = reader.ReadAll()
+		if err != nil {
+			return nil, err
+		}
+	case ".xlsx":
+		workbook, err := excelize.OpenReader(file)
+		if err != nil {
+			return nil, err
+		}
+		defer workbook.Close()
+		rows, err = workbook.GetRows(workbook.GetSheetName(0))
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, types.ErrUnsupportedFileType
+	}
+	if len(rows) < 2 {
+		return nil, types.ErrImportEmpty
+	}
+	if len(rows)-1 > importMaxRows {
+		return nil, types.ErrImportTooManyRows
+	}
+
+	header := make([]string, len(rows[0]))
+	for i, name := range rows[0] {
+		// Excel writes a byte order mark before the header of UTF-8 files
+		header[i] = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\uFEFF")))
+	}
+	for _, column := range columns {
+		if !slices.Contains(header, column) {
+			return nil, fmt.Errorf("%w: %s", types.ErrImportMissingColumn, column)
+		}
+	}
+	records := make([]*importRecord, 0, len(rows)-1)
+	for i, row := range rows[1:] {
+		empty := true
+		values := make(map[string]string, len(header))
+		for j, value := range row {
+			if j < len(header) && header[j] != "" {
+				values[header[j]] = value
+			}
+			if strings.TrimSpace(value) != "" {
+				empty = false
+			}
+		}
+		if empty {
+			continue
+		}
+		records = append(records, &importRecord{line: i + 2, values: values})
+	}
+	if len(records) == 0 {
+		return nil, types.ErrImportEmpty
+	}
+	return records, nil
+}
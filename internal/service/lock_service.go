@@ -12,11 +12,21 @@ import (
 var _ LockService = (*lockService)(nil)
 
 type LockService interface {
-	Lock(ctx context.Context, key string, expiration time.Duration) (bool, error)
-	ReleaseLock(ctx context.Context, key string) error
+	// Lock acquires the lock, retrying for a few seconds while another
+	// holder has it
+	Lock(ctx context.Context, key string, expiration time.Duration) (HeldLock, error)
+	// TryLock acquires the lock in a single attempt, it fits request paths
+	// that must not wait
+	TryLock(ctx context.Context, key string, expiration time.Duration) (HeldLock, error)
 	WaitIfLocked(ctx context.Context, key string)
 }
 
+// HeldLock is one acquisition of a lock. Release only frees this
+// acquisition, once it expired a later holder keeps the lock.
+type HeldLock interface {
+	Release(ctx context.Context) error
+}
+
 type lockService struct {
 	pool *redsync.Redsync
 }
@@ -27,20 +37,19 @@ func NewLockService(redisClient *redis.Client) *lockService {
 	}
 }
 
-func (r *lockService) Lock(ctx context.Context, key string, expiration time.Duration) (bool, error) {
-	mutex := r.pool.NewMutex(key, redsync.WithExpiry(expiration))
-	if err := mutex.LockContext(ctx); err != nil {
-		return false, err
-	}
-	return true, nil
+func (r *lockService) Lock(ctx context.Context, key string, expiration time.Duration) (HeldLock, error) {
+	return r.acquire(ctx, r.pool.NewMutex(key, redsync.WithExpiry(expiration)))
 }
 
-func (r *lockService) ReleaseLock(ctx context.Context, key string) error {
-	_, err := r.pool.NewMutex(key).UnlockContext(ctx)
-	if err != nil {
-		return err
+func (r *lockService) TryLock(ctx context.Context, key string, expiration time.Duration) (HeldLock, error) {
+	return r.acquire(ctx, r.pool.NewMutex(key, redsync.WithExpiry(expiration), redsync.WithTries(1)))
+}
+
+func (r *lockService) acquire(ctx context.Context, mutex *redsync.Mutex) (HeldLock, error) {
+	if err := mutex.LockContext(ctx); err != nil {
+		return nil, err
 	}
-	return nil
+	return &heldLock{mutex: mutex}, nil
 }
 
 func (r *lockService) WaitIfLocked(ctx context.Context, key string) {
@@ -53,3 +62,20 @@ func (r *lockService) WaitIfLocked(ctx context.Context, key string) {
 		break
 	}
 }
+
+// heldLock releases through the mutex that acquired the lock, redsync
+// matches its random value so another holder's lock is never freed
+type heldLock struct {
+	mutex *redsync.Mutex
+}
+
+func (l *heldLock) Release(ctx context.Context) error {
+	ok, err := l.mutex.UnlockContext(ctx)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return redsync.ErrLockAlreadyExpired
+	}
+	return nil
+}
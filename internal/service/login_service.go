@@ -34,6 +34,9 @@ func (s *loginService) Login(ctx context.Context, req types.LoginRequest) (acces
 	if user.Password != req.Password {
 		return "", "", types.ErrInvalidCredentials
 	}
+	if user.Deactivated {
+		return "", "", types.ErrUserDeactivated
+	}
 
 	// Generate tokens
 	refreshToken, err = s.jwtService.GenerateRefreshToken(user)
@@ -56,10 +59,18 @@ func (s *loginService) Logout(ctx context.Context) error {
 }
 
 func (s *loginService) Refresh(ctx context.Context, oldRefreshToken string) (accessToken, refreshToken string, err error) {
-	user, err := s.jwtService.ValidateRefreshToken(oldRefreshToken)
+	claimed, err := s.jwtService.ValidateRefreshToken(oldRefreshToken)
 	if err != nil {
 		return "", "", err
 	}
+	// the user may have been deactivated or changed since the token
+	user, err := s.userRepo.FindByID(ctx, claimed.ID)
+	if err != nil {
+		return "", "", err
+	}
+	if user.Deactivated {
+		return "", "", types.ErrUserDeactivated
+	}
 
 	// Generate new tokens
 	refreshToken, err = s.jwtService.GenerateRefreshToken(user)
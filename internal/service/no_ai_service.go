@@ -5,6 +5,7 @@ import (
 
 	"github.com/remiehneppo/be-task-management/types"
 	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/jsonschema"
 	"github.com/sirupsen/logrus"
 )
 
@@ -24,3 +25,7 @@ func (s *NoAIService) Chat(ctx context.Context, messages []types.Message) (*type
 		Role:    openai.ChatMessageRoleAssistant,
 	}, nil
 }
+
+func (s *NoAIService) ChatStructured(ctx context.Context, messages []types.Message, name string, schema *jsonschema.Definition, v any) error {
+	return types.ErrAIUnavailable
+}
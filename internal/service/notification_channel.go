@@ -0,0 +1,197 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/smtp"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/remiehneppo/be-task-management/config"
+	"github.com/remiehneppo/be-task-management/types"
+)
+
+// NotificationChannel sends a delivery outside the application, an error
+// means the delivery should be retried later
+type NotificationChannel interface {
+	Send(ctx context.Context, delivery *types.NotificationDelivery) error
+}
+
+type emailChannel struct {
+	config config.SMTPConfig
+}
+
+// NewEmailChannel sends plain text emails through the configured SMTP
+// server, authenticating only when a username is set so that a local sink
+// can be used in development
+func NewEmailChannel(config config.SMTPConfig) NotificationChannel {
+	return &emailChannel{
+		config: config,
+	}
+}
+
+func (c *emailChannel) Send(ctx context.Context, delivery *types.NotificationDelivery) error {
+	var auth smtp.Auth
+	if c.config.Username != "" {
+		auth = smtp.PlainAuth("", c.config.Username, c.config.Password, c.config.Host)
+	}
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", c.config.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", delivery.Target)
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", delivery.Subject))
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	msg.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+	body := quotedprintable.NewWriter(&msg)
+	if _, err := body.Write([]byte(delivery.Body)); err != nil {
+		return err
+	}
+	if err := body.Close(); err != nil {
+		return err
+	}
+	return c.sendMail(ctx, auth, delivery.Target, msg.Bytes())
+}
+
+// sendMail does what smtp.SendMail does within the configured timeout, a
+// stalled server would otherwise hold the delivery job forever
+func (c *emailChannel) sendMail(ctx context.Context, auth smtp.Auth, to string, msg []byte) error {
+	dialer := &net.Dialer{Timeout: c.config.Timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(c.config.Host, strconv.Itoa(c.config.Port)))
+	if err != nil {
+		return err
+	}
+	if c.config.Timeout > 0 {
+		if err := conn.SetDeadline(time.Now().Add(c.config.Timeout)); err != nil {
+			conn.Close()
+			return err
+		}
+	}
+	client, err := smtp.NewClient(conn, c.config.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: c.config.Host}); err != nil {
+			return err
+		}
+	}
+	if auth != nil {
+		if err := client.Auth(auth); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(c.config.From); err != nil {
+		return err
+	}
+	if err := client.Rcpt(to); err != nil {
+		return err
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+type webhookChannel struct {
+	client *http.Client
+}
+
+// NewWebhookChannel posts the delivery body as JSON. Each request carries
+// the X-Webhook-Timestamp header and an X-Webhook-Signature header holding
+// "sha256=" and the hex HMAC-SHA256, keyed with the subscriber secret, of
+// the timestamp, a dot and the body. Receivers should reject old timestamps
+// and deduplicate on X-Webhook-Delivery since a delivery may be retried.
+// Subscribers choose the URL, so loopback, private and link-local addresses
+// are refused when dialing, redirects included, and no proxy is used.
+func NewWebhookChannel(timeout time.Duration) NotificationChannel {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: webhookDialControl,
+	}
+	return &webhookChannel{
+		client: &http.Client{
+			Timeout: timeout,
+			Transport: &http.Transport{
+				DialContext:         dialer.DialContext,
+				TLSHandshakeTimeout: timeout,
+			},
+		},
+	}
+}
+
+func (c *webhookChannel) Send(ctx context.Context, delivery *types.NotificationDelivery) error {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.Target, bytes.NewBufferString(delivery.Body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Webhook-Event", delivery.Event)
+	req.Header.Set("X-Webhook-Delivery", delivery.ID)
+	req.Header.Set("X-Webhook-Timestamp", timestamp)
+	req.Header.Set("X-Webhook-Signature", "sha256="+signWebhook(delivery.Secret, timestamp, delivery.Body))
+	res, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %d", res.StatusCode)
+	}
+	return nil
+}
+
+// webhookDialControl refuses the connection unless the resolved address is
+// public
+func webhookDialControl(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil || !publicAddress(addr) {
+		return types.ErrWebhookAddressForbidden
+	}
+	return nil
+}
+
+// carrierGradeNAT is the shared address space of RFC 6598, internal like the
+// private ranges
+var carrierGradeNAT = netip.MustParsePrefix("100.64.0.0/10")
+
+// publicAddress tells whether the address is routable on the internet
+func publicAddress(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsGlobalUnicast() &&
+		!addr.IsPrivate() &&
+		!addr.IsLoopback() &&
+		!addr.IsLinkLocalUnicast() &&
+		!carrierGradeNAT.Contains(addr)
+}
+
+func signWebhook(secret, timestamp, body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "." + body))
+	return hex.EncodeToString(mac.Sum(nil))
+}
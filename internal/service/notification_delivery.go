@@ -0,0 +1,300 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/remiehneppo/be-task-management/internal/worker"
+	"github.com/remiehneppo/be-task-management/types"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	notificationDeliveryJobLock = "notification_delivery_job"
+	notificationDigestJobLock   = "notification_digest_job"
+	// deliveryBatchSize bounds a delivery run so it ends well within its lock
+	deliveryBatchSize = 50
+)
+
+func (s *notificationService) GetPreference(ctx context.Context) (*types.NotificationPreference, error) {
+	userID, ok := ctx.Value("user_id").(string)
+	if !ok {
+		return nil, types.ErrInvalidCredentials
+	}
+	return s.findPreference(ctx, userID)
+}
+
+// UpdatePreference changes the channels of the current user, fields left
+// out of the request are kept and an empty type list subscribes to every type
+func (s *notificationService) UpdatePreference(ctx context.Context, req *types.UpdateNotificationPreferenceRequest) (*types.NotificationPreference, error) {
+	userID, ok := ctx.Value("user_id").(string)
+	if !ok {
+		return nil, types.ErrInvalidCredentials
+	}
+	preference, err := s.findPreference(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if req.Email != nil {
+		preference.Email = strings.TrimSpace(*req.Email)
+		if preference.Email != "" {
+			if _, err := mail.ParseAddress(preference.Email); err != nil {
+				return nil, types.ErrInvalidEmail
+			}
+		}
+	}
+	if req.EmailMode != "" {
+		switch req.EmailMode {
+		case types.EMAIL_MODE_OFF, types.EMAIL_MODE_INSTANT, types.EMAIL_MODE_DIGEST:
+		default:
+			return nil, types.ErrInvalidEmailMode
+		}
+		if req.EmailMode == types.EMAIL_MODE_DIGEST && preference.EmailMode != types.EMAIL_MODE_DIGEST {
+			// the first digest starts from the subscription
+			preference.LastDigestAt = time.Now().Unix()
+		}
+		preference.EmailMode = req.EmailMode
+	}
+	if req.EmailTypes != nil {
+		preference.EmailTypes = req.EmailTypes
+	}
+	if req.WebhookURL != nil {
+		preference.WebhookURL = strings.TrimSpace(*req.WebhookURL)
+		if preference.WebhookURL != "" {
+			webhookURL, err := url.Parse(preference.WebhookURL)
+			if err != nil || (webhookURL.Scheme != "http" && webhookURL.Scheme != "https") || webhookURL.Host == "" {
+				return nil, types.ErrInvalidWebhookURL
+			}
+			// names are checked again when dialing, they may resolve anywhere
+			addr, err := netip.ParseAddr(webhookURL.Hostname())
+			if (err == nil && !publicAddress(addr)) || strings.EqualFold(webhookURL.Hostname(), "localhost") {
+				return nil, types.ErrWebhookAddressForbidden
+			}
+		}
+	}
+	if req.WebhookSecret != nil {
+		preference.WebhookSecret = *req.WebhookSecret
+	}
+	if preference.WebhookURL != "" && preference.WebhookSecret == "" {
+		return nil, types.ErrWebhookSecretRequired
+	}
+	if req.WebhookTypes != nil {
+		preference.WebhookTypes = req.WebhookTypes
+	}
+	preference.UpdatedAt = time.Now().Unix()
+	if preference.ID == "" {
+		if err := s.preferenceRepo.Save(ctx, preference); err != nil {
+			return nil, err
+		}
+		return preference, nil
+	}
+	preferenceID := preference.ID
+	preference.ID = ""
+	if err := s.preferenceRepo.Update(ctx, preferenceID, preference); err != nil {
+		return nil, err
+	}
+	preference.ID = preferenceID
+	return preference, nil
+}
+
+// findPreference returns the stored preference of the user or, when there
+// is none yet, the default one which only keeps in-app notifications
+func (s *notificationService) findPreference(ctx context.Context, userID string) (*types.NotificationPreference, error) {
+	preference, err := s.preferenceRepo.FindByUserID(ctx, userID)
+	if errors.Is(err, types.ErrNotificationPreferenceNotFound) {
+		return &types.NotificationPreference{
+			UserID:    userID,
+			EmailMode: types.EMAIL_MODE_OFF,
+		}, nil
+	}
+	return preference, err
+}
+
+// queueDeliveries queues the notification on the instant email and webhook
+// channels the recipient subscribed to for its type
+func (s *notificationService) queueDeliveries(ctx context.Context, notification *types.Notification) error {
+	preference, err := s.findPreference(ctx, notification.Recipient)
+	if err != nil {
+		return err
+	}
+	if preference.EmailMode == types.EMAIL_MODE_INSTANT && preference.Email != "" &&
+		subscribedTo(preference.EmailTypes, notification.Type) {
+		err := s.queueDelivery(ctx, &types.NotificationDelivery{
+			Channel:        types.NOTIFICATION_CHANNEL_EMAIL,
+			Recipient:      notification.Recipient,
+			NotificationID: notification.ID,
+			Event:          notification.Type,
+			Target:         preference.Email,
+			Subject:        notification.Title,
+			Body:           notification.Message,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	if preference.WebhookURL != "" && subscribedTo(preference.WebhookTypes, notification.Type) {
+		body, err := json.Marshal(&types.WebhookPayload{
+			Event:        notification.Type,
+			Notification: notification,
+		})
+		if err != nil {
+			return err
+		}
+		err = s.queueDelivery(ctx, &types.NotificationDelivery{
+			Channel:        types.NOTIFICATION_CHANNEL_WEBHOOK,
+			Recipient:      notification.Recipient,
+			NotificationID: notification.ID,
+			Event:          notification.Type,
+			Target:         preference.WebhookURL,
+			Secret:         preference.WebhookSecret,
+			Body:           string(body),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *notificationService) queueDelivery(ctx context.Context, delivery *types.NotificationDelivery) error {
+	if _, ok := s.channels[delivery.Channel]; !ok {
+		return nil
+	}
+	now := time.Now().Unix()
+	delivery.Status = types.DELIVERY_STATUS_PENDING
+	delivery.NextAttemptAt = now
+	delivery.CreatedAt = now
+	delivery.UpdatedAt = now
+	return s.deliveryRepo.Save(ctx, delivery)
+}
+
+func subscribedTo(subscribed []string, notificationType string) bool {
+	return len(subscribed) == 0 || slices.Contains(subscribed, notificationType)
+}
+
+// DeliveryJob sends the due deliveries, a failed attempt is retried after
+// RetryBase, doubled on each further failure, until MaxAttempts is reached
+func (s *notificationService) DeliveryJob() worker.Do {
+	return func() error {
+		ctx := context.Background()
+		lock, err := s.lockService.Lock(ctx, notificationDeliveryJobLock, 10*time.Minute)
+		if err != nil {
+			return nil
+		}
+		defer func() {
+			if err := lock.Release(ctx); err != nil {
+				logrus.Errorf("Failed to release %s lock: %v", notificationDeliveryJobLock, err)
+			}
+		}()
+		deliveries, err := s.deliveryRepo.FindDue(ctx, time.Now().Unix(), deliveryBatchSize)
+		if err != nil {
+			return err
+		}
+		for _, delivery := range deliveries {
+			if err := s.attemptDelivery(ctx, delivery); err != nil {
+				logrus.Errorf("Failed to update delivery %s: %v", delivery.ID, err)
+			}
+		}
+		return nil
+	}
+}
+
+func (s *notificationService) attemptDelivery(ctx context.Context, delivery *types.NotificationDelivery) error {
+	channel, ok := s.channels[delivery.Channel]
+	if !ok {
+		// the channel was disabled since, keep the delivery for when it returns
+		return nil
+	}
+	now := time.Now()
+	delivery.Attempts++
+	if err := channel.Send(ctx, delivery); err != nil {
+		logrus.Warnf("Delivery %s to %s failed on attempt %d: %v", delivery.ID, delivery.Target, delivery.Attempts, err)
+		delivery.LastError = err.Error()
+		if delivery.Attempts >= s.config.MaxAttempts {
+			delivery.Status = types.DELIVERY_STATUS_FAILED
+		} else {
+			delivery.NextAttemptAt = now.Add(s.config.RetryBase << (delivery.Attempts - 1)).Unix()
+		}
+	} else {
+		delivery.Status = types.DELIVERY_STATUS_SENT
+		delivery.LastError = ""
+	}
+	deliveryID := delivery.ID
+	delivery.ID = ""
+	delivery.UpdatedAt = now.Unix()
+	return s.deliveryRepo.Update(ctx, deliveryID, delivery)
+}
+
+func (s *notificationService) DigestJob() worker.Do {
+	return func() error {
+		logrus.Info("Sending notification digests...")
+		ctx := context.Background()
+		lock, err := s.lockService.Lock(ctx, notificationDigestJobLock, 10*time.Minute)
+		if err != nil {
+			return nil
+		}
+		defer func() {
+			if err := lock.Release(ctx); err != nil {
+				logrus.Errorf("Failed to release %s lock: %v", notificationDigestJobLock, err)
+			}
+		}()
+		preferences, err := s.preferenceRepo.FindByEmailMode(ctx, types.EMAIL_MODE_DIGEST)
+		if err != nil {
+			return err
+		}
+		for _, preference := range preferences {
+			if err := s.queueDigest(ctx, preference, time.Now().Unix()); err != nil {
+				logrus.Errorf("Failed to queue digest of %s: %v", preference.UserID, err)
+			}
+		}
+		return nil
+	}
+}
+
+func (s *notificationService) queueDigest(ctx context.Context, preference *types.NotificationPreference, now int64) error {
+	if preference.Email == "" {
+		return nil
+	}
+	notifications, err := s.notificationRepo.FindByRecipientSince(ctx, preference.UserID, preference.LastDigestAt)
+	if err != nil {
+		return err
+	}
+	var body strings.Builder
+	count := 0
+	for _, notification := range notifications {
+		if notification.CreatedAt > now || !subscribedTo(preference.EmailTypes, notification.Type) {
+			continue
+		}
+		count++
+		fmt.Fprintf(&body, "- [%s] %s\n  %s\n",
+			time.Unix(notification.CreatedAt, 0).Format("15:04 02/01/2006"),
+			notification.Title,
+			notification.Message,
+		)
+	}
+	if count > 0 {
+		err := s.queueDelivery(ctx, &types.NotificationDelivery{
+			Channel:   types.NOTIFICATION_CHANNEL_EMAIL,
+			Recipient: preference.UserID,
+			Event:     types.NOTIFICATION_TYPE_DIGEST,
+			Target:    preference.Email,
+			Subject:   fmt.Sprintf("Tổng hợp %d thông báo mới", count),
+			Body:      fmt.Sprintf("Bạn có %d thông báo mới:\n\n%s", count, body.String()),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	preferenceID := preference.ID
+	preference.ID = ""
+	preference.LastDigestAt = now
+	return s.preferenceRepo.Update(ctx, preferenceID, preference)
+}
@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/remiehneppo/be-task-management/config"
+	"github.com/remiehneppo/be-task-management/internal/repository"
+	"github.com/remiehneppo/be-task-management/internal/worker"
+	"github.com/remiehneppo/be-task-management/types"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// notificationChannel is the Redis pub/sub channel shared by every replica
+	notificationChannel = "notifications"
+	// subscriberBuffer is how many notifications a slow stream may lag behind
+	// before new ones are dropped for it
+	subscriberBuffer = 16
+)
+
+var _ NotificationService = (*notificationService)(nil)
+
+// Notifier delivers a notification to its recipient
+type Notifier interface {
+	Notify(ctx context.Context, notification *types.Notification) error
+}
+
+type NotificationService interface {
+	Notifier
+	GetNotifications(ctx context.Context, unreadOnly bool, page, limit int64) (items []*types.Notification, total int64, err error)
+	CountUnread(ctx context.Context) (int64, error)
+	MarkRead(ctx context.Context, req *types.MarkNotificationsReadRequest) error
+	MarkAllRead(ctx context.Context) error
+	// Subscribe streams the notifications of the current user until the
+	// returned cancel function is called
+	Subscribe(ctx context.Context) (<-chan *types.Notification, func(), error)
+	// Run relays the notifications published by every replica to the local
+	// streams until ctx is done
+	Run(ctx context.Context)
+	GetPreference(ctx context.Context) (*types.NotificationPreference, error)
+	UpdatePreference(ctx context.Context, req *types.UpdateNotificationPreferenceRequest) (*types.NotificationPreference, error)
+	// DeliveryJob sends the due email and webhook deliveries
+	DeliveryJob() worker.Do
+	// DigestJob queues one email per digest subscriber summarising the
+	// notifications received since the previous digest
+	DigestJob() worker.Do
+}
+
+type notificationService struct {
+	notificationRepo repository.NotificationRepository
+	preferenceRepo   repository.NotificationPreferenceRepository
+	deliveryRepo     repository.NotificationDeliveryRepository
+	redisClient      *redis.Client
+	// channels are keyed by NOTIFICATION_CHANNEL_*, a missing channel is
+	// disabled and nothing is queued for it
+	channels    map[string]NotificationChannel
+	lockService LockService
+	config      config.NotificationConfig
+	mu          sync.RWMutex
+	subscribers map[string]map[chan *types.Notification]struct{}
+}
+
+func NewNotificationService(
+	notificationRepo repository.NotificationRepository,
+	preferenceRepo repository.NotificationPreferenceRepository,
+	deliveryRepo repository.NotificationDeliveryRepository,
+	redisClient *redis.Client,
+	channels map[string]NotificationChannel,
+	lockService LockService,
+	config config.NotificationConfig,
+) NotificationService {
+	return &notificationService{
+		notificationRepo: notificationRepo,
+		preferenceRepo:   preferenceRepo,
+		deliveryRepo:     deliveryRepo,
+		redisClient:      redisClient,
+		channels:         channels,
+		lockService:      lockService,
+		config:           config,
+		subscribers:      make(map[string]map[chan *types.Notification]struct{}),
+	}
+}
+
+// Notify persists the notification, pushes it to the open streams of its
+// recipient then queues it on the channels the recipient subscribed to
+func (s *notificationService) Notify(ctx context.Context, notification *types.Notification) error {
+	notification.ReadAt = 0
+	if err := s.notificationRepo.Save(ctx, notification); err != nil {
+		return err
+	}
+	s.publish(ctx, notification)
+	if err := s.queueDeliveries(ctx, notification); err != nil {
+		// the notification is stored, only the external copies are lost
+		logrus.Errorf("Failed to queue deliveries of notification %s: %v", notification.ID, err)
+	}
+	return nil
+}
+
+func (s *notificationService) GetNotifications(ctx context.Context, unreadOnly bool, page, limit int64) (items []*types.Notification, total int64, err error) {
+	userID, ok := ctx.Value("user_id").(string)
+	if !ok {
+		return nil, 0, types.ErrInvalidCredentials
+	}
+	return s.notificationRepo.PaginateByRecipient(ctx, userID, unreadOnly, page, limit)
+}
+
+func (s *notificationService) CountUnread(ctx context.Context) (int64, error) {
+	userID, ok := ctx.Value("user_id").(string)
+	if !ok {
+		return 0, types.ErrInvalidCredentials
+	}
+	return s.notificationRepo.CountUnread(ctx, userID)
+}
+
+func (s *notificationService) MarkRead(ctx context.Context, req *types.MarkNotificationsReadRequest) error {
+	userID, ok := ctx.Value("user_id").(string)
+	if !ok {
+		return types.ErrInvalidCredentials
+	}
+	if len(req.NotificationIDs) == 0 {
+		return nil
+	}
+	return s.notificationRepo.MarkRead(ctx, userID, req.NotificationIDs)
+}
+
+func (s *notificationService) MarkAllRead(ctx context.Context) error {
+	userID, ok := ctx.Value("user_id").(string)
+	if !ok {
+		return types.ErrInvalidCredentials
+	}
+	return s.notificationRepo.MarkAllRead(ctx, userID)
+}
+
+func (s *notificationService) Subscribe(ctx context.Context) (<-chan *types.Notification, func(), error) {
+	userID, ok := ctx.Value("user_id").(string)
+	if !ok {
+		return nil, nil, types.ErrInvalidCredentials
+	}
+	ch := make(chan *types.Notification, subscriberBuffer)
+	s.mu.Lock()
+	if s.subscribers[userID] == nil {
+		s.subscribers[userID] = make(map[chan *types.Notification]struct{})
+	}
+	s.subscribers[userID][ch] = struct{}{}
+	s.mu.Unlock()
+	var once sync.Once
+	cancel := func() {
+		once.Do(func() {
+			s.mu.Lock()
+			delete(s.subscribers[userID], ch)
+			if len(s.subscribers[userID]) == 0 {
+				delete(s.subscribers, userID)
+			}
+			s.mu.Unlock()
+			close(ch)
+		})
+	}
+	return ch, cancel, nil
+}
+
+// publish sends the notification through Redis so that the streams open on
+// other replicas get it too. Streams are best effort, a failure is logged and
+// the notification stays listed.
+func (s *notificationService) publish(ctx context.Context, notification *types.Notification) {
+	payload, err := json.Marshal(notification)
+	if err != nil {
+		logrus.Errorf("Failed to encode notification %s: %v", notification.ID, err)
+		return
+	}
+	if err := s.redisClient.Publish(ctx, notificationChannel, payload).Err(); err != nil {
+		logrus.Errorf("Failed to publish notification %s: %v", notification.ID, err)
+	}
+}
+
+func (s *notificationService) Run(ctx context.Context) {
+	relayPubSub(ctx, s.redisClient, notificationChannel, func(payload string) {
+		notification := &types.Notification{}
+		if err := json.Unmarshal([]byte(payload), notification); err != nil {
+			logrus.Errorf("Failed to decode notification: %v", err)
+			return
+		}
+		s.dispatch(notification)
+	})
+}
+
+func (s *notificationService) dispatch(notification *types.Notification) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for ch := range s.subscribers[notification.Recipient] {
+		select {
+		case ch <- notification:
+		default:
+			// the stream is not keeping up, the notification stays listed
+			logrus.Warnf("Dropping live notification %s for %s", notification.ID, notification.Recipient)
+		}
+	}
+}
@@ -43,9 +43,14 @@ func (s *ragService) AskAI(ctx context.Context, question string, chunks []*types
 
 func (s *ragService) ragPrompt(question string, chunks []*types.ChunkDocumentResponse) string {
 	prompt := "Bạn là một trợ lý AI thông minh có khả năng đọc và hiểu thông tin từ ngữ cảnh được cung cấp bên dưới.\n" +
-		"Hãy sử dụng thông tin trong phần ngữ cảnh để trả lời câu hỏi phía dưới bằng tiếng Việt.\n\n" +
+		"Hãy sử dụng thông tin trong phần ngữ cảnh để trả lời câu hỏi phía dưới bằng tiếng Việt.\n" +
+		"Khi sử dụng thông tin từ báo cáo công việc, hãy trích dẫn mã công việc và mã báo cáo.\n\n" +
 		"NGỮ CẢNH:\n{{"
 	for _, chunk := range chunks {
+		if chunk.Source == types.DOCUMENT_SOURCE_REPORT {
+			prompt += fmt.Sprintf("[Báo cáo công việc: %s, Mã công việc: %s, Mã báo cáo: %s\nNội dung: %s]\n\n", chunk.Title, chunk.TaskID, chunk.ReportID, chunk.Content)
+			continue
+		}
 		prompt += fmt.Sprintf("[Tên tài liệu: %s, Trang: %d\nNội dung: %s]\n\n", chunk.Title, chunk.PageNumber, chunk.Content)
 	}
 	prompt += "}}\n\n"
@@ -0,0 +1,399 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/remiehneppo/be-task-management/config"
+	"github.com/remiehneppo/be-task-management/internal/repository"
+	"github.com/remiehneppo/be-task-management/internal/worker"
+	"github.com/remiehneppo/be-task-management/types"
+	"github.com/sashabaranov/go-openai"
+	"github.com/sirupsen/logrus"
+)
+
+const summaryJobLock = "weekly_summary_job"
+
+// summaryReportRunes bounds the text of a report given to the AI
+const summaryReportRunes = 500
+
+// summaryCitation matches the task IDs the summary cites
+var summaryCitation = regexp.MustCompile(`[0-9a-f]{24}`)
+
+var _ SummaryService = (*summaryService)(nil)
+
+// SummaryService has the AI write the weekly status summary of a workspace
+// from its tasks, reports and feedback. Summaries are kept and only read by
+// the members of the workspace whose role grants summary.read.
+type SummaryService interface {
+	GenerateSummary(ctx context.Context, req *types.GenerateSummaryRequest) (*types.WeeklySummary, error)
+	GetSummaries(ctx context.Context, page, limit int64) ([]*types.WeeklySummary, int64, error)
+	GetSummary(ctx context.Context, id string) (*types.WeeklySummary, error)
+	// SummaryJob writes the summary of the current week of every workspace
+	// not summarized yet and notifies its readers
+	SummaryJob() worker.Do
+}
+
+type summaryService struct {
+	aiService     AIService
+	taskRepo      repository.TaskRepository
+	reportRepo    repository.ReportRepository
+	userRepo      repository.UserRepository
+	summaryRepo   repository.WeeklySummaryRepository
+	workspaceRepo repository.WorkspaceRepository
+	notifier      Notifier
+	lockService   LockService
+	authorizer    AuthorizationService
+	config        config.SummaryConfig
+}
+
+func NewSummaryService(
+	aiService AIService,
+	taskRepo repository.TaskRepository,
+	reportRepo repository.ReportRepository,
+	userRepo repository.UserRepository,
+	summaryRepo repository.WeeklySummaryRepository,
+	workspaceRepo repository.WorkspaceRepository,
+	notifier Notifier,
+	lockService LockService,
+	authorizer AuthorizationService,
+	config config.SummaryConfig,
+) SummaryService {
+	return &summaryService{
+		aiService:     aiService,
+		taskRepo:      taskRepo,
+		reportRepo:    reportRepo,
+		userRepo:      userRepo,
+		summaryRepo:   summaryRepo,
+		workspaceRepo: workspaceRepo,
+		notifier:      notifier,
+		lockService:   lockService,
+		authorizer:    authorizer,
+		config:        config,
+	}
+}
+
+func (s *summaryService) GenerateSummary(ctx context.Context, req *types.GenerateSummaryRequest) (*types.WeeklySummary, error) {
+	user, err := s.summaryReader(ctx)
+	if err != nil {
+		return nil, err
+	}
+	day := time.Now()
+	if req.Week != "" {
+		day, err = time.ParseInLocation("2006-01-02", req.Week, time.Local)
+		if err != nil || day.After(time.Now()) {
+			return nil, types.ErrInvalidSummaryWeek
+		}
+	}
+	summary, err := s.writeSummary(ctx, user.Workspace, day)
+	if err != nil {
+		return nil, err
+	}
+	summary.Requester = user.ID
+	if err := s.summaryRepo.Save(ctx, summary); err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
+
+func (s *summaryService) GetSummaries(ctx context.Context, page, limit int64) ([]*types.WeeklySummary, int64, error) {
+	user, err := s.summaryReader(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	return s.summaryRepo.PaginateByWorkspace(ctx, user.Workspace, page, limit)
+}
+
+func (s *summaryService) GetSummary(ctx context.Context, id string) (*types.WeeklySummary, error) {
+	user, err := s.summaryReader(ctx)
+	if err != nil {
+		return nil, err
+	}
+	summary, err := s.summaryRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if summary.Workspace != user.Workspace {
+		return nil, types.ErrSummaryForbidden
+	}
+	return summary, nil
+}
+
+func (s *summaryService) SummaryJob() worker.Do {
+	return func() error {
+		logrus.Info("Writing weekly summaries...")
+		ctx := context.Background()
+		lock, err := s.lockService.Lock(ctx, summaryJobLock, 30*time.Minute)
+		if err != nil {
+			return nil
+		}
+		defer func() {
+			if err := lock.Release(ctx); err != nil {
+				logrus.Errorf("Failed to release %s lock: %v", summaryJobLock, err)
+			}
+		}()
+		now := time.Now()
+		weekStart, _ := summaryWeek(now)
+		workspaces, err := s.workspaceRepo.FindAll(ctx)
+		if err != nil {
+			return err
+		}
+		for _, workspace := range workspaces {
+			count, err := s.summaryRepo.CountScheduled(ctx, workspace.Name, weekStart.Unix())
+			if err != nil {
+				return err
+			}
+			if count > 0 {
+				continue
+			}
+			if err := s.scheduledSummary(ctx, workspace.Name, now); err != nil {
+				logrus.Errorf("Failed to write weekly summary of %s: %v", workspace.Name, err)
+			}
+		}
+		return nil
+	}
+}
+
+func (s *summaryService) scheduledSummary(ctx context.Context, workspace string, now time.Time) error {
+	summary, err := s.writeSummary(ctx, workspace, now)
+	if err != nil {
+		return err
+	}
+	if err := s.summaryRepo.Save(ctx, summary); err != nil {
+		return err
+	}
+	members, err := s.userRepo.FindByWorkspace(ctx, workspace)
+	if err != nil {
+		return err
+	}
+	weekStart, weekEnd := summaryWeek(now)
+	for _, member := range members {
+		allowed, err := s.authorizer.Can(ctx, member, types.PERMISSION_SUMMARY_READ)
+		if err != nil {
+			return err
+		}
+		if !allowed {
+			continue
+		}
+		err = s.notifier.Notify(ctx, &types.Notification{
+			Recipient: member.ID,
+			Type:      types.NOTIFICATION_TYPE_WEEKLY_SUMMARY,
+			SummaryID: summary.ID,
+			Title:     fmt.Sprintf("Tóm tắt tuần %s - %s", weekStart.Format("02/01"), weekEnd.Format("02/01/2006")),
+			Message:   "Bản tóm tắt tiến độ, rủi ro và vướng mắc của tuần đã sẵn sàng",
+			CreatedAt: now.Unix(),
+		})
+		if err != nil {
+			logrus.Errorf("Failed to notify %s of weekly summary %s: %v", member.ID, summary.ID, err)
+		}
+	}
+	return nil
+}
+
+// writeSummary has the AI summarize the workspace week of day. The tasks
+// are the ones updated during the week or still unfinished today, so the
+// status of a past week is read as it is now.
+func (s *summaryService) writeSummary(ctx context.Context, workspace string, day time.Time) (*types.WeeklySummary, error) {
+	if _, ok := s.aiService.(*NoAIService); ok {
+		return nil, types.ErrAIUnavailable
+	}
+	weekStart, weekEnd := summaryWeek(day)
+	from, to := weekStart.Unix(), weekEnd.Unix()
+	// tasks are overdue against the end of a past week
+	now := min(time.Now().Unix(), to)
+
+	tasks, err := s.taskRepo.FindActiveInWorkspace(ctx, workspace, from, to)
+	if err != nil {
+		return nil, err
+	}
+	reportFilter := types.ReportFilter{Workspace: workspace, CreatedFrom: from, CreatedTo: to}
+	reports, reportCount, _, err := s.reportRepo.FilterReports(ctx, types.PageRequest{Page: 1, Limit: int64(s.config.MaxReports)}, reportFilter)
+	if err != nil {
+		return nil, err
+	}
+	reportFilter.Status = types.REPORT_STATUS_REJECTED
+	rejected, err := s.reportRepo.CountWithFilter(ctx, reportFilter)
+	if err != nil {
+		return nil, err
+	}
+	members, err := s.userRepo.FindByWorkspace(ctx, workspace)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]string, len(members))
+	for _, member := range members {
+		names[member.ID] = member.FullName
+	}
+
+	byID := make(map[string]*types.Task, len(tasks))
+	for _, task := range tasks {
+		byID[task.ID] = task
+	}
+	summary := &types.WeeklySummary{
+		Workspace:     workspace,
+		WeekStart:     from,
+		WeekEnd:       to,
+		SourceTaskIDs: make([]string, 0),
+		CitedTaskIDs:  make([]string, 0),
+		Stats: types.WeeklySummaryStats{
+			Tasks:    len(tasks),
+			Reports:  int(reportCount),
+			Rejected: int(rejected),
+		},
+		CreatedAt: time.Now().Unix(),
+	}
+	lines := make([]summaryTaskLine, 0, len(tasks))
+	for _, task := range tasks {
+		line := summaryTaskLine{task: task, blockedBy: make([]string, 0)}
+		unfinished := !isTaskFinished(task.Status)
+		line.completed = task.CompletedAt >= from && task.CompletedAt <= to
+		line.overdue = unfinished && task.Deadline > 0 && task.Deadline < now
+		if unfinished {
+			// every unfinished task of the workspace is loaded
+			for _, predecessorID := range task.DependsOn {
+				if predecessor, ok := byID[predecessorID]; ok && !isTaskFinished(predecessor.Status) {
+					line.blockedBy = append(line.blockedBy, predecessorID)
+				}
+			}
+		}
+		if line.completed {
+			summary.Stats.Completed++
+		}
+		if line.overdue {
+			summary.Stats.Overdue++
+		}
+		if len(line.blockedBy) > 0 {
+			summary.Stats.Blocked++
+		}
+		lines = append(lines, line)
+	}
+	// the tasks needing attention are kept first when there are too many
+	slices.SortStableFunc(lines, func(a, b summaryTaskLine) int {
+		return b.rank() - a.rank()
+	})
+	if s.config.MaxTasks > 0 && len(lines) > s.config.MaxTasks {
+		lines = lines[:s.config.MaxTasks]
+	}
+	for _, line := range lines {
+		summary.SourceTaskIDs = append(summary.SourceTaskIDs, line.task.ID)
+	}
+
+	if len(lines) == 0 && len(reports) == 0 {
+		summary.Content = "Trong tuần không có công việc hay báo cáo nào."
+		return summary, nil
+	}
+	message, err := s.aiService.Chat(ctx, []types.Message{
+		{Role: openai.ChatMessageRoleUser, Content: summaryPrompt(summary, lines, reports, byID, names)},
+	})
+	if err != nil {
+		return nil, err
+	}
+	summary.Content = strings.TrimSpace(message.Content)
+	if summary.Content == "" {
+		return nil, errors.New("empty summary generated")
+	}
+	for _, id := range summaryCitation.FindAllString(summary.Content, -1) {
+		if slices.Contains(summary.SourceTaskIDs, id) && !slices.Contains(summary.CitedTaskIDs, id) {
+			summary.CitedTaskIDs = append(summary.CitedTaskIDs, id)
+		}
+	}
+	return summary, nil
+}
+
+// summaryReader returns the current user, whose role must grant
+// summary.read
+func (s *summaryService) summaryReader(ctx context.Context) (*types.User, error) {
+	userID, ok := ctx.Value("user_id").(string)
+	if !ok {
+		return nil, types.ErrInvalidCredentials
+	}
+	return s.authorizer.Authorize(ctx, userID, types.PERMISSION_SUMMARY_READ)
+}
+
+// summaryWeek returns the first and the last second of the week of day,
+// weeks start on Monday in the server timezone
+func summaryWeek(day time.Time) (time.Time, time.Time) {
+	day = day.In(time.Local)
+	start := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.Local)
+	start = start.AddDate(0, 0, -((int(start.Weekday()) + 6) % 7))
+	return start, start.AddDate(0, 0, 7).Add(-time.Second)
+}
+
+type summaryTaskLine struct {
+	task      *types.Task
+	completed bool
+	overdue   bool
+	blockedBy []string
+}
+
+func (l summaryTaskLine) rank() int {
+	switch {
+	case l.overdue:
+		return 3
+	case len(l.blockedBy) > 0:
+		return 2
+	case l.completed:
+		return 1
+	}
+	return 0
+}
+
+func summaryPrompt(summary *types.WeeklySummary, lines []summaryTaskLine, reports []*types.Report, tasks map[string]*types.Task, names map[string]string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Bạn là trợ lý của trưởng bộ phận %s. Hãy viết bản tóm tắt tình hình công việc tuần từ %s đến %s bằng tiếng Việt, ", summary.Workspace,
+		time.Unix(summary.WeekStart, 0).Format("02/01/2006"), time.Unix(summary.WeekEnd, 0).Format("02/01/2006"))
+	b.WriteString("ngắn gọn, gồm các mục: Tiến độ chung, Rủi ro, Công việc quá hạn, Vướng mắc.\n")
+	b.WriteString("Chỉ sử dụng dữ liệu bên dưới, không suy đoán. Mỗi khi nhắc tới một công việc, ghi mã công việc trong ngoặc vuông, ví dụ [66f1c2a9e4b0a1b2c3d4e5f6], để người đọc tra cứu.\n\n")
+	fmt.Fprintf(&b, "SỐ LIỆU: %d công việc, %d hoàn thành trong tuần, %d quá hạn, %d bị chặn bởi công việc khác, %d báo cáo mới, %d báo cáo bị yêu cầu bổ sung.\n\n",
+		summary.Stats.Tasks, summary.Stats.Completed, summary.Stats.Overdue, summary.Stats.Blocked, summary.Stats.Reports, summary.Stats.Rejected)
+
+	b.WriteString("CÔNG VIỆC:\n")
+	for _, line := range lines {
+		task := line.task
+		fmt.Fprintf(&b, "[%s] %s | Trạng thái: %s | Tiến độ: %d%%", task.ID, task.Title, exportTaskStatusLabels[task.Status], task.Progress)
+		if task.Deadline > 0 {
+			fmt.Fprintf(&b, " | Hạn: %s", time.Unix(task.Deadline, 0).Format("02/01/2006"))
+		}
+		if name := names[task.Assignee]; name != "" {
+			fmt.Fprintf(&b, " | Phụ trách: %s", name)
+		}
+		if line.overdue {
+			b.WriteString(" | QUÁ HẠN")
+		}
+		if len(line.blockedBy) > 0 {
+			fmt.Fprintf(&b, " | Chờ công việc: [%s]", strings.Join(line.blockedBy, "], ["))
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\nBÁO CÁO VÀ PHẢN HỒI TRONG TUẦN:\n")
+	for _, report := range reports {
+		title := ""
+		if task, ok := tasks[report.TaskID]; ok {
+			title = " " + task.Title
+		}
+		status := reportStatus(report)
+		fmt.Fprintf(&b, "Công việc [%s]%s, %s báo cáo ngày %s: %s | Đánh giá: %s",
+			report.TaskID, title, names[report.Creator], time.Unix(report.CreatedAt, 0).Format("02/01"),
+			summaryTruncate(report.Report), exportReportStatusLabels[status])
+		if report.Feedback != "" {
+			fmt.Fprintf(&b, " | Phản hồi: %s", summaryTruncate(report.Feedback))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+func summaryTruncate(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	runes := []rune(text)
+	if len(runes) <= summaryReportRunes {
+		return text
+	}
+	return string(runes[:summaryReportRunes]) + "…"
+}
@@ -0,0 +1,172 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"slices"
+	"time"
+
+	"github.com/remiehneppo/be-task-management/types"
+	"github.com/sirupsen/logrus"
+)
+
+// GetTaskHistory returns the activity log of a task, newest first, deleted
+// tasks included
+func (s *taskService) GetTaskHistory(ctx context.Context, taskID string, page, limit int64) (items []*types.TaskActivityResponse, total int64, err error) {
+	userID, ok := ctx.Value("user_id").(string)
+	if !ok {
+		return nil, 0, types.ErrInvalidCredentials
+	}
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, 0, err
+	}
+	// the history outlives the task, it is authorized by the workspace the
+	// activities were recorded in
+	activities, total, err := s.activityRepo.PaginateByTaskID(ctx, taskID, user.Workspace, page, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	if total == 0 {
+		task, err := s.taskRepo.FindByID(ctx, taskID)
+		if err != nil {
+			return nil, 0, err
+		}
+		if task.Workspace != user.Workspace {
+			return nil, 0, types.ErrTaskNotInWorkspace
+		}
+	}
+	actorIDs := make([]string, 0)
+	actorIDsMap := make(map[string]bool)
+	for _, activity := range activities {
+		if actorIDsMap[activity.Actor] {
+			continue
+		}
+		actorIDs = append(actorIDs, activity.Actor)
+		actorIDsMap[activity.Actor] = true
+	}
+	usersMap, err := s.userRepo.FindByIDs(ctx, actorIDs)
+	if err != nil {
+		return nil, 0, err
+	}
+	items = make([]*types.TaskActivityResponse, 0, len(activities))
+	for _, activity := range activities {
+		// actors may have been removed since, keep their entries anyway
+		actorName := ""
+		if actor, ok := usersMap[activity.Actor]; ok {
+			actorName = actor.FullName
+		}
+		items = append(items, &types.TaskActivityResponse{
+			ID:        activity.ID,
+			TaskID:    activity.TaskID,
+			ReportID:  activity.ReportID,
+			CommentID: activity.CommentID,
+			Actor:     activity.Actor,
+			ActorName: actorName,
+			Action:    activity.Action,
+			Changes:   activity.Changes,
+			CreatedAt: activity.CreatedAt,
+		})
+	}
+	return items, total, nil
+}
+
+// recordActivity appends an entry to the task history and broadcasts it to
+// the task boards of the workspace. The change it describes is already
+// persisted, so a failure is logged and not returned.
+func (s *taskService) recordActivity(ctx context.Context, activity *types.TaskActivity) {
+	activity.CreatedAt = time.Now().Unix()
+	if activity.Changes == nil {
+		activity.Changes = make([]types.FieldChange, 0)
+	}
+	if activity.Workspace == "" {
+		// report changes do not load the task
+		task, err := s.taskRepo.FindByID(ctx, activity.TaskID)
+		if err != nil {
+			logrus.Errorf("Failed to find workspace of task %s: %v", activity.TaskID, err)
+		} else {
+			activity.Workspace = task.Workspace
+		}
+	}
+	if err := s.activityRepo.Save(ctx, activity); err != nil {
+		logrus.Errorf("Failed to record %s activity for task %s: %v", activity.Action, activity.TaskID, err)
+	}
+	if activity.Workspace != "" {
+		s.eventPublisher.PublishTaskEvent(ctx, activity)
+	}
+}
+
+// recordTaskUpdate splits the differences between two versions of a task into
+// status change, reassignment and plain update activities
+func (s *taskService) recordTaskUpdate(ctx context.Context, taskID, actor string, before, after *types.Task) {
+	var statusChanges, assigneeChanges, fieldChanges []types.FieldChange
+	for _, change := range diffTask(before, after) {
+		switch change.Field {
+		case "status":
+			statusChanges = append(statusChanges, change)
+		case "assignee", "assignees":
+			assigneeChanges = append(assigneeChanges, change)
+		default:
+			fieldChanges = append(fieldChanges, change)
+		}
+	}
+	activities := []*types.TaskActivity{
+		{Action: types.TASK_ACTIVITY_UPDATE, Changes: fieldChanges},
+		{Action: types.TASK_ACTIVITY_REASSIGN, Changes: assigneeChanges},
+		{Action: types.TASK_ACTIVITY_STATUS_CHANGE, Changes: statusChanges},
+	}
+	for _, activity := range activities {
+		if len(activity.Changes) == 0 {
+			continue
+		}
+		activity.TaskID = taskID
+		activity.Workspace = after.Workspace
+		activity.Actor = actor
+		s.recordActivity(ctx, activity)
+	}
+}
+
+func diffTask(before, after *types.Task) []types.FieldChange {
+	changes := make([]types.FieldChange, 0)
+	addChange := func(field string, oldValue, newValue interface{}) {
+		if oldValue != newValue {
+			changes = append(changes, types.FieldChange{Field: field, OldValue: oldValue, NewValue: newValue})
+		}
+	}
+	addChange("title", before.Title, after.Title)
+	addChange("description", before.Description, after.Description)
+	addChange("start_at", before.StartAt, after.StartAt)
+	addChange("deadline", before.Deadline, after.Deadline)
+	addChange("assignee", before.Assignee, after.Assignee)
+	addChange("progress", before.Progress, after.Progress)
+	addChange("status", before.Status, after.Status)
+	addChange("parent_id", before.ParentID, after.ParentID)
+	addChange("priority", before.Priority, after.Priority)
+	// slices are not comparable as interface values
+	addListChange := func(field string, oldValue, newValue []string) {
+		if !slices.Equal(oldValue, newValue) {
+			changes = append(changes, types.FieldChange{Field: field, OldValue: oldValue, NewValue: newValue})
+		}
+	}
+	addListChange("assignees", before.Assignees, after.Assignees)
+	addListChange("watchers", before.Watchers, after.Watchers)
+	addListChange("tags", before.Tags, after.Tags)
+	// custom fields are recorded one by one, removed ones become nil
+	keys := make([]string, 0, len(before.CustomFields)+len(after.CustomFields))
+	for key := range before.CustomFields {
+		keys = append(keys, key)
+	}
+	for key := range after.CustomFields {
+		if _, ok := before.CustomFields[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		oldValue, newValue := before.CustomFields[key], after.CustomFields[key]
+		if !reflect.DeepEqual(oldValue, newValue) {
+			changes = append(changes, types.FieldChange{Field: "custom_fields." + key, OldValue: oldValue, NewValue: newValue})
+		}
+	}
+	return changes
+}
@@ -0,0 +1,535 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"mime/multipart"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/remiehneppo/be-task-management/types"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	commentMaxLength      = 10000
+	commentMaxAttachments = 10
+)
+
+// mentionPattern finds @username mentions, the @ of an email address is
+// preceded by a word character and does not match
+var mentionPattern = regexp.MustCompile(`(?:^|[^A-Za-z0-9_])@([A-Za-z0-9._-]+)`)
+
+// GetTaskComments returns a page of the comment threads of a task, oldest
+// first, each with all its replies
+func (s *taskService) GetTaskComments(ctx context.Context, taskID string, page types.PageRequest) (items []*types.CommentResponse, total int64, nextCursor string, err error) {
+	if _, _, err := s.commentTarget(ctx, taskID, ""); err != nil {
+		return nil, 0, "", err
+	}
+	return s.commentThreads(ctx, taskID, "", page)
+}
+
+// GetReportComments returns a page of the comment threads of a report
+func (s *taskService) GetReportComments(ctx context.Context, reportID string, page types.PageRequest) (items []*types.CommentResponse, total int64, nextCursor string, err error) {
+	task, _, err := s.commentTarget(ctx, "", reportID)
+	if err != nil {
+		return nil, 0, "", err
+	}
+	return s.commentThreads(ctx, task.ID, reportID, page)
+}
+
+func (s *taskService) commentThreads(ctx context.Context, taskID, reportID string, page types.PageRequest) (items []*types.CommentResponse, total int64, nextCursor string, err error) {
+	threads, total, nextCursor, err := s.commentRepo.PaginateThreads(ctx, taskID, reportID, page)
+	if err != nil {
+		return nil, 0, "", err
+	}
+	threadIDs := make([]string, 0, len(threads))
+	for _, thread := range threads {
+		threadIDs = append(threadIDs, thread.ID)
+	}
+	replies, err := s.commentRepo.FindReplies(ctx, threadIDs)
+	if err != nil {
+		return nil, 0, "", err
+	}
+	authorIDs := make([]string, 0, len(threads)+len(replies))
+	for _, comment := range append(slices.Clone(threads), replies...) {
+		authorIDs = append(authorIDs, comment.Author)
+	}
+	usersMap, err := s.userRepo.FindByIDs(ctx, uniqueIDs(authorIDs))
+	if err != nil {
+		return nil, 0, "", err
+	}
+	items = make([]*types.CommentResponse, 0, len(threads))
+	threadsMap := make(map[string]*types.CommentResponse, len(threads))
+	for _, thread := range threads {
+		item := convertCommentToCommentRes(thread, usersMap)
+		item.Replies = make([]*types.CommentResponse, 0)
+		threadsMap[thread.ID] = item
+		items = append(items, item)
+	}
+	for _, reply := range replies {
+		if thread, ok := threadsMap[reply.ParentID]; ok {
+			thread.Replies = append(thread.Replies, convertCommentToCommentRes(reply, usersMap))
+		}
+	}
+	return items, total, nextCursor, nil
+}
+
+// CreateComment posts a comment on a task or a report, uploads its
+// attachments and notifies the members it mentions
+func (s *taskService) CreateComment(ctx context.Context, req *types.CreateCommentRequest) (*types.CommentResponse, error) {
+	userID, ok := ctx.Value("user_id").(string)
+	if !ok {
+		return nil, types.ErrInvalidCredentials
+	}
+	task, user, err := s.commentTarget(ctx, req.TaskID, req.ReportID)
+	if err != nil {
+		return nil, err
+	}
+	parentID := ""
+	if req.ParentID != "" {
+		parent, err := s.commentRepo.FindByID(ctx, req.ParentID)
+		if err != nil {
+			return nil, err
+		}
+		if parent.TaskID != task.ID || parent.ReportID != req.ReportID {
+			return nil, types.ErrCommentParentMismatch
+		}
+		// answers to a reply stay in the thread of its first comment
+		parentID = parent.ID
+		if parent.ParentID != "" {
+			parentID = parent.ParentID
+		}
+	}
+	content := strings.TrimSpace(req.Content)
+	if err := validateComment(content, len(req.Attachments)); err != nil {
+		return nil, err
+	}
+	mentions, err := s.resolveMentions(ctx, user, content)
+	if err != nil {
+		return nil, err
+	}
+	attachments, err := s.uploadCommentAttachments(ctx, req.Attachments)
+	if err != nil {
+		return nil, err
+	}
+	comment := &types.Comment{
+		Workspace:   task.Workspace,
+		TaskID:      task.ID,
+		ReportID:    req.ReportID,
+		ParentID:    parentID,
+		Author:      userID,
+		Content:     content,
+		Mentions:    mentions,
+		Attachments: attachments,
+		Edits:       make([]types.CommentEdit, 0),
+		CreatedAt:   time.Now().Unix(),
+		UpdatedAt:   time.Now().Unix(),
+	}
+	if err := s.commentRepo.Save(ctx, comment); err != nil {
+		return nil, err
+	}
+	s.recordActivity(ctx, &types.TaskActivity{
+		TaskID:    task.ID,
+		Workspace: task.Workspace,
+		ReportID:  comment.ReportID,
+		CommentID: comment.ID,
+		Actor:     userID,
+		Action:    types.TASK_ACTIVITY_COMMENT,
+		Changes: []types.FieldChange{
+			{Field: "content", OldValue: "", NewValue: comment.Content},
+		},
+	})
+	s.notifyCommentMentions(ctx, task, comment, mentions)
+	return convertCommentToCommentRes(comment, map[string]*types.User{user.ID: user}), nil
+}
+
+// UpdateComment replaces the content of a comment, drops and adds
+// attachments. The replaced version is kept in the comment history and only
+// the newly mentioned members are notified.
+func (s *taskService) UpdateComment(ctx context.Context, req *types.UpdateCommentRequest) (*types.CommentResponse, error) {
+	userID, ok := ctx.Value("user_id").(string)
+	if !ok {
+		return nil, types.ErrInvalidCredentials
+	}
+	comment, err := s.commentRepo.FindByID(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+	if comment.Author != userID {
+		return nil, types.ErrCommentNotAuthor
+	}
+	if comment.Deleted {
+		return nil, types.ErrCommentDeleted
+	}
+	task, user, err := s.commentTarget(ctx, comment.TaskID, comment.ReportID)
+	if err != nil {
+		return nil, err
+	}
+	attachments := make([]types.CommentAttachment, 0, len(comment.Attachments))
+	for _, attachment := range comment.Attachments {
+		if !slices.Contains(req.RemoveAttachments, attachment.FilePath) {
+			attachments = append(attachments, attachment)
+		}
+	}
+	if len(comment.Attachments)-len(attachments) != len(uniqueIDs(req.RemoveAttachments)) {
+		return nil, types.ErrCommentAttachmentMissing
+	}
+	content := strings.TrimSpace(req.Content)
+	if err := validateComment(content, len(attachments)+len(req.Attachments)); err != nil {
+		return nil, err
+	}
+	mentions, err := s.resolveMentions(ctx, user, content)
+	if err != nil {
+		return nil, err
+	}
+	uploaded, err := s.uploadCommentAttachments(ctx, req.Attachments)
+	if err != nil {
+		return nil, err
+	}
+	newMentions := make([]string, 0, len(mentions))
+	for _, mention := range mentions {
+		if !slices.Contains(comment.Mentions, mention) {
+			newMentions = append(newMentions, mention)
+		}
+	}
+	oldContent := comment.Content
+	comment.Edits = append(comment.Edits, types.CommentEdit{
+		Content:     comment.Content,
+		Attachments: comment.Attachments,
+		Editor:      userID,
+		EditedAt:    time.Now().Unix(),
+	})
+	comment.Content = content
+	comment.Mentions = mentions
+	comment.Attachments = append(attachments, uploaded...)
+	comment.UpdatedAt = time.Now().Unix()
+	comment.ID = ""
+	err = s.commentRepo.Update(ctx, req.ID, comment)
+	comment.ID = req.ID
+	if err != nil {
+		return nil, err
+	}
+	s.recordActivity(ctx, &types.TaskActivity{
+		TaskID:    task.ID,
+		Workspace: task.Workspace,
+		ReportID:  comment.ReportID,
+		CommentID: comment.ID,
+		Actor:     userID,
+		Action:    types.TASK_ACTIVITY_COMMENT_UPDATE,
+		Changes: []types.FieldChange{
+			{Field: "content", OldValue: oldContent, NewValue: comment.Content},
+		},
+	})
+	s.notifyCommentMentions(ctx, task, comment, newMentions)
+	return convertCommentToCommentRes(comment, map[string]*types.User{user.ID: user}), nil
+}
+
+// DeleteComment empties a comment, its replies stay in the thread. The
+// author, the task creator and its managers may delete comments, the last
+// version is kept in the comment history.
+func (s *taskService) DeleteComment(ctx context.Context, id string) error {
+	userID, ok := ctx.Value("user_id").(string)
+	if !ok {
+		return types.ErrInvalidCredentials
+	}
+	comment, err := s.commentRepo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if comment.Deleted {
+		return types.ErrCommentDeleted
+	}
+	task, user, err := s.commentTarget(ctx, comment.TaskID, comment.ReportID)
+	if err != nil {
+		return err
+	}
+	if comment.Author != userID {
+		roles, err := s.taskActorRoles(ctx, task, user)
+		if err != nil {
+			return err
+		}
+		if !roles[types.TASK_ACTOR_CREATOR] && !roles[types.TASK_ACTOR_MANAGER] {
+			return types.ErrCommentNotAuthor
+		}
+	}
+	oldContent := comment.Content
+	comment.Edits = append(comment.Edits, types.CommentEdit{
+		Content:     comment.Content,
+		Attachments: comment.Attachments,
+		Editor:      userID,
+		EditedAt:    time.Now().Unix(),
+	})
+	comment.Content = ""
+	comment.Mentions = nil
+	comment.Attachments = nil
+	comment.Deleted = true
+	comment.DeletedAt = time.Now().Unix()
+	comment.UpdatedAt = time.Now().Unix()
+	comment.ID = ""
+	if err := s.commentRepo.Update(ctx, id, comment); err != nil {
+		return err
+	}
+	s.recordActivity(ctx, &types.TaskActivity{
+		TaskID:    task.ID,
+		Workspace: task.Workspace,
+		ReportID:  comment.ReportID,
+		CommentID: id,
+		Actor:     userID,
+		Action:    types.TASK_ACTIVITY_COMMENT_DELETE,
+		Changes: []types.FieldChange{
+			{Field: "content", OldValue: oldContent, NewValue: ""},
+		},
+	})
+	return nil
+}
+
+// GetCommentHistory returns the previous versions of a comment, oldest first
+func (s *taskService) GetCommentHistory(ctx context.Context, id string) ([]*types.CommentEditResponse, error) {
+	comment, err := s.commentRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if _, _, err := s.commentTarget(ctx, comment.TaskID, comment.ReportID); err != nil {
+		return nil, err
+	}
+	editorIDs := make([]string, 0, len(comment.Edits))
+	for _, edit := range comment.Edits {
+		editorIDs = append(editorIDs, edit.Editor)
+	}
+	usersMap, err := s.userRepo.FindByIDs(ctx, uniqueIDs(editorIDs))
+	if err != nil {
+		return nil, err
+	}
+	items := make([]*types.CommentEditResponse, 0, len(comment.Edits))
+	for _, edit := range comment.Edits {
+		editorName := ""
+		if editor, ok := usersMap[edit.Editor]; ok {
+			editorName = editor.FullName
+		}
+		items = append(items, &types.CommentEditResponse{
+			Content:     edit.Content,
+			Attachments: edit.Attachments,
+			Editor:      edit.Editor,
+			EditorName:  editorName,
+			EditedAt:    edit.EditedAt,
+		})
+	}
+	return items, nil
+}
+
+// GetCommentAttachment returns the file on disk and the name of an
+// attachment of a comment
+func (s *taskService) GetCommentAttachment(ctx context.Context, id string, index int) (filePath, fileName string, err error) {
+	comment, err := s.commentRepo.FindByID(ctx, id)
+	if err != nil {
+		return "", "", err
+	}
+	if _, _, err := s.commentTarget(ctx, comment.TaskID, comment.ReportID); err != nil {
+		return "", "", err
+	}
+	if index < 0 || index >= len(comment.Attachments) {
+		return "", "", types.ErrCommentAttachmentMissing
+	}
+	attachment := comment.Attachments[index]
+	filePath, err = s.fileService.GetFilePath(ctx, attachment.FilePath)
+	if err != nil {
+		return "", "", err
+	}
+	return filePath, attachment.FileName, nil
+}
+
+// commentTarget loads the task discussed, through the report when reportID
+// is set, and checks the current user belongs to its workspace
+func (s *taskService) commentTarget(ctx context.Context, taskID, reportID string) (*types.Task, *types.User, error) {
+	userID, ok := ctx.Value("user_id").(string)
+	if !ok {
+		return nil, nil, types.ErrInvalidCredentials
+	}
+	if reportID != "" {
+		report, err := s.reportRepo.FindByID(ctx, reportID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if taskID != "" && taskID != report.TaskID {
+			return nil, nil, types.ErrInvalidReport
+		}
+		taskID = report.TaskID
+	}
+	if taskID == "" {
+		return nil, nil, types.ErrInvalidTask
+	}
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	task, err := s.taskRepo.FindByID(ctx, taskID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if task.Workspace != user.Workspace {
+		return nil, nil, types.ErrTaskNotInWorkspace
+	}
+	return task, user, nil
+}
+
+// resolveMentions returns the members of the author workspace mentioned by
+// username in content, unknown names are plain text
+func (s *taskService) resolveMentions(ctx context.Context, author *types.User, content string) ([]string, error) {
+	matches := mentionPattern.FindAllStringSubmatch(content, -1)
+	if len(matches) == 0 {
+		return make([]string, 0), nil
+	}
+	members, err := s.userRepo.FindByWorkspace(ctx, author.Workspace)
+	if err != nil {
+		return nil, err
+	}
+	mentions := make([]string, 0, len(matches))
+	for _, match := range matches {
+		// a mention may end a sentence
+		username := strings.TrimRight(match[1], ".")
+		for _, member := range members {
+			if member.ID != author.ID && strings.EqualFold(member.Username, username) && !slices.Contains(mentions, member.ID) {
+				mentions = append(mentions, member.ID)
+			}
+		}
+	}
+	return mentions, nil
+}
+
+// uploadCommentAttachments stores the files under unique names, the
+// original names are kept for display
+func (s *taskService) uploadCommentAttachments(ctx context.Context, files []*multipart.FileHeader) ([]types.CommentAttachment, error) {
+	attachments := make([]types.CommentAttachment, 0, len(files))
+	for _, fileHeader := range files {
+		fileName := filepath.Base(fileHeader.Filename)
+		uploadRes, err := s.fileService.UploadFile(ctx, types.UploadFileRequest{
+			FileName:   fmt.Sprintf("comment_%d_%s", time.Now().UnixNano(), fileName),
+			FileHeader: fileHeader,
+		})
+		if err != nil {
+			return nil, err
+		}
+		attachments = append(attachments, types.CommentAttachment{
+			FileName: fileName,
+			FilePath: uploadRes.FilePath,
+			FileSize: fileHeader.Size,
+		})
+	}
+	return attachments, nil
+}
+
+// deleteComments deletes the comments of the task, or only those of the
+// report when reportID is set, and the files attached to any of their
+// versions. The comments go first, a file left behind is only logged.
+func (s *taskService) deleteComments(ctx context.Context, taskID, reportID string) error {
+	comments, err := s.commentRepo.FindByTaskID(ctx, taskID, reportID)
+	if err != nil {
+		return err
+	}
+	if len(comments) == 0 {
+		return nil
+	}
+	if reportID != "" {
+		err = s.commentRepo.DeleteByReportID(ctx, reportID)
+	} else {
+		err = s.commentRepo.DeleteByTaskID(ctx, taskID)
+	}
+	if err != nil {
+		return err
+	}
+	filePaths := make([]string, 0)
+	for _, comment := range comments {
+		versions := [][]types.CommentAttachment{comment.Attachments}
+		for _, edit := range comment.Edits {
+			versions = append(versions, edit.Attachments)
+		}
+		for _, attachments := range versions {
+			for _, attachment := range attachments {
+				if !slices.Contains(filePaths, attachment.FilePath) {
+					filePaths = append(filePaths, attachment.FilePath)
+				}
+			}
+		}
+	}
+	for _, filePath := range filePaths {
+		if err := s.fileService.DeleteFile(ctx, filePath); err != nil {
+			logrus.Errorf("Failed to delete comment attachment %s: %v", filePath, err)
+		}
+	}
+	return nil
+}
+
+// recordFeedbackComment posts report feedback to the report discussion, the
+// report keeps the latest feedback as a summary of it. The feedback is
+// already saved, so a failure is logged and not returned.
+func (s *taskService) recordFeedbackComment(ctx context.Context, report *types.Report, reportID, actor, feedback string) {
+	task, err := s.taskRepo.FindByID(ctx, report.TaskID)
+	if err != nil {
+		logrus.Errorf("Failed to find task %s of report %s: %v", report.TaskID, reportID, err)
+		return
+	}
+	comment := &types.Comment{
+		Workspace:   task.Workspace,
+		TaskID:      report.TaskID,
+		ReportID:    reportID,
+		Author:      actor,
+		Content:     feedback,
+		Mentions:    make([]string, 0),
+		Attachments: make([]types.CommentAttachment, 0),
+		Edits:       make([]types.CommentEdit, 0),
+		CreatedAt:   time.Now().Unix(),
+		UpdatedAt:   time.Now().Unix(),
+	}
+	if err := s.commentRepo.Save(ctx, comment); err != nil {
+		logrus.Errorf("Failed to add feedback of report %s to its discussion: %v", reportID, err)
+	}
+}
+
+func validateComment(content string, attachments int) error {
+	if content == "" && attachments == 0 {
+		return types.ErrEmptyComment
+	}
+	if utf8.RuneCountInString(content) > commentMaxLength {
+		return types.ErrCommentTooLong
+	}
+	if attachments > commentMaxAttachments {
+		return types.ErrTooManyAttachments
+	}
+	return nil
+}
+
+func convertCommentToCommentRes(comment *types.Comment, usersMap map[string]*types.User) *types.CommentResponse {
+	// authors may have been removed since, keep their comments anyway
+	authorName := ""
+	if author, ok := usersMap[comment.Author]; ok {
+		authorName = author.FullName
+	}
+	mentions := comment.Mentions
+	if mentions == nil {
+		mentions = make([]string, 0)
+	}
+	attachments := comment.Attachments
+	if attachments == nil {
+		attachments = make([]types.CommentAttachment, 0)
+	}
+	return &types.CommentResponse{
+		ID:          comment.ID,
+		TaskID:      comment.TaskID,
+		ReportID:    comment.ReportID,
+		ParentID:    comment.ParentID,
+		Author:      comment.Author,
+		AuthorName:  authorName,
+		Content:     comment.Content,
+		Mentions:    mentions,
+		Attachments: attachments,
+		Edited:      len(comment.Edits) > 0 && !comment.Deleted,
+		Deleted:     comment.Deleted,
+		CreatedAt:   comment.CreatedAt,
+		UpdatedAt:   comment.UpdatedAt,
+	}
+}
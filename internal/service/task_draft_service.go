@@ -0,0 +1,274 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/remiehneppo/be-task-management/internal/repository"
+	"github.com/remiehneppo/be-task-management/types"
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/jsonschema"
+)
+
+const (
+	// taskDraftMaxRunes bounds the text given to the AI
+	taskDraftMaxRunes = 20000
+	// taskDraftMaxPages is how many pages of a PDF are read
+	taskDraftMaxPages  = 10
+	taskDraftMaxDrafts = 20
+)
+
+var taskDraftWeekdays = []string{"Chủ nhật", "Thứ Hai", "Thứ Ba", "Thứ Tư", "Thứ Năm", "Thứ Sáu", "Thứ Bảy"}
+
+var _ TaskDraftService = (*taskDraftService)(nil)
+
+// TaskDraftService has the AI draft tasks from a meeting note or a work
+// order. Drafts are only suggestions, the confirmed ones are created by
+// TaskService.CreateTask with its usual checks.
+type TaskDraftService interface {
+	DraftTasks(ctx context.Context, req *types.DraftTasksRequest, fileHeader *multipart.FileHeader) ([]*types.TaskDraft, error)
+	ConfirmDrafts(ctx context.Context, req *types.ConfirmTaskDraftsRequest) (*types.ConfirmTaskDraftsResponse, error)
+}
+
+type taskDraftService struct {
+	aiService   AIService
+	taskService TaskService
+	userRepo    repository.UserRepository
+	pdfService  PDFService
+	docxService DOCXService
+	authorizer  AuthorizationService
+}
+
+func NewTaskDraftService(
+	aiService AIService,
+	taskService TaskService,
+	userRepo repository.UserRepository,
+	pdfService PDFService,
+	docxService DOCXService,
+	authorizer AuthorizationService,
+) TaskDraftService {
+	return &taskDraftService{
+		aiService:   aiService,
+		taskService: taskService,
+		userRepo:    userRepo,
+		pdfService:  pdfService,
+		docxService: docxService,
+		authorizer:  authorizer,
+	}
+}
+
+// taskDraftOutput is the answer the AI is held to
+type taskDraftOutput struct {
+	Tasks []taskDraftOutputTask `json:"tasks"`
+}
+
+type taskDraftOutputTask struct {
+	Title       string `json:"title" description:"Tên công việc ngắn gọn, bắt đầu bằng động từ"`
+	Description string `json:"description" description:"Nội dung, yêu cầu và kết quả cần đạt của công việc"`
+	AssigneeID  string `json:"assignee_id" description:"Mã của thành viên được giao hoặc phù hợp nhất, để trống khi không xác định được"`
+	StartDate   string `json:"start_date" description:"Ngày bắt đầu dạng YYYY-MM-DD, để trống khi văn bản không nêu"`
+	Deadline    string `json:"deadline" description:"Hạn hoàn thành dạng YYYY-MM-DD, để trống khi văn bản không nêu"`
+	Priority    int    `json:"priority" description:"Mức ưu tiên: 0 không rõ, 1 thấp, 2 trung bình, 3 cao, 4 khẩn"`
+}
+
+func (s *taskDraftService) DraftTasks(ctx context.Context, req *types.DraftTasksRequest, fileHeader *multipart.FileHeader) ([]*types.TaskDraft, error) {
+	userID, ok := ctx.Value("user_id").(string)
+	if !ok {
+		return nil, types.ErrInvalidCredentials
+	}
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	location := time.Local
+	if req.Timezone != "" {
+		location, err = time.LoadLocation(req.Timezone)
+		if err != nil {
+			return nil, types.ErrInvalidTimezone
+		}
+	}
+	text := strings.TrimSpace(req.Text)
+	if fileHeader != nil {
+		fileText, err := s.readDraftFile(fileHeader)
+		if err != nil {
+			return nil, err
+		}
+		text = strings.TrimSpace(text + "\n\n" + fileText)
+	}
+	if text == "" {
+		return nil, types.ErrTaskDraftEmpty
+	}
+	if runes := []rune(text); len(runes) > taskDraftMaxRunes {
+		text = string(runes[:taskDraftMaxRunes])
+	}
+
+	members, err := s.userRepo.FindByWorkspace(ctx, user.Workspace)
+	if err != nil {
+		return nil, err
+	}
+	canAssign, err := s.authorizer.Can(ctx, user, types.PERMISSION_TASK_ASSIGN)
+	if err != nil {
+		return nil, err
+	}
+	// only the members the user may assign are suggested
+	assignable := make(map[string]*types.User)
+	memberIDs := []string{""}
+	var memberList strings.Builder
+	for _, member := range members {
+		if member.ID != user.ID && (!canAssign || member.Deactivated || s.authorizer.Manages(member, user)) {
+			continue
+		}
+		assignable[member.ID] = member
+		memberIDs = append(memberIDs, member.ID)
+		fmt.Fprintf(&memberList, "- %s: %s (%s)\n", member.ID, member.FullName, member.WorkspaceRole)
+	}
+
+	schema, err := jsonschema.GenerateSchemaForType(taskDraftOutput{})
+	if err != nil {
+		return nil, err
+	}
+	tasksSchema := schema.Properties["tasks"]
+	assigneeSchema := tasksSchema.Items.Properties["assignee_id"]
+	assigneeSchema.Enum = memberIDs
+	tasksSchema.Items.Properties["assignee_id"] = assigneeSchema
+
+	now := time.Now().In(location)
+	prompt := fmt.Sprintf("Bạn là trợ lý lập kế hoạch của bộ phận %s. Hãy trích xuất các công việc cần giao từ văn bản của người dùng (biên bản họp, lệnh công việc...), tối đa %d công việc, viết bằng tiếng Việt.\n"+
+		"Chỉ đưa ra công việc có trong văn bản, không tự thêm. Ngày hôm nay là %s, %s; hãy quy đổi các mốc thời gian tương đối (tuần sau, thứ Sáu...) ra ngày cụ thể.\n"+
+		"Chọn người thực hiện trong danh sách thành viên dưới đây theo tên hoặc chức trách được nêu, để trống khi không xác định được.\n\n"+
+		"THÀNH VIÊN:\n%s",
+		user.Workspace, taskDraftMaxDrafts, taskDraftWeekdays[now.Weekday()], now.Format("2006-01-02"), memberList.String())
+	var output taskDraftOutput
+	err = s.aiService.ChatStructured(ctx, []types.Message{
+		{Role: openai.ChatMessageRoleSystem, Content: prompt},
+		{Role: openai.ChatMessageRoleUser, Content: text},
+	}, "task_drafts", schema, &output)
+	if err != nil {
+		return nil, err
+	}
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, location)
+	drafts := make([]*types.TaskDraft, 0, len(output.Tasks))
+	for _, task := range output.Tasks {
+		if len(drafts) == taskDraftMaxDrafts {
+			break
+		}
+		draft := &types.TaskDraft{
+			Title:       strings.TrimSpace(task.Title),
+			Description: strings.TrimSpace(task.Description),
+			Priority:    min(max(task.Priority, types.TASK_PRIORITY_NONE), types.TASK_PRIORITY_URGENT),
+			StartAt:     today.Unix(),
+		}
+		if draft.Title == "" {
+			continue
+		}
+		if member, ok := assignable[task.AssigneeID]; ok {
+			draft.Assignee = member.ID
+			draft.AssigneeName = member.FullName
+		}
+		if start, err := time.ParseInLocation("2006-01-02", task.StartDate, location); err == nil {
+			draft.StartAt = start.Unix()
+		}
+		// a deadline lasts until the end of its day
+		if deadline, err := time.ParseInLocation("2006-01-02", task.Deadline, location); err == nil {
+			draft.Deadline = deadline.AddDate(0, 0, 1).Unix() - 1
+			if draft.StartAt > draft.Deadline {
+				draft.StartAt = min(today.Unix(), deadline.Unix())
+			}
+		}
+		drafts = append(drafts, draft)
+	}
+	return drafts, nil
+}
+
+func (s *taskDraftService) ConfirmDrafts(ctx context.Context, req *types.ConfirmTaskDraftsRequest) (*types.ConfirmTaskDraftsResponse, error) {
+	res := &types.ConfirmTaskDraftsResponse{
+		Errors: make([]types.TaskDraftError, 0),
+	}
+	for i := range req.Drafts {
+		if _, err := s.taskService.CreateTask(ctx, &req.Drafts[i]); err != nil {
+			if errors.Is(err, types.ErrInvalidCredentials) {
+				return nil, err
+			}
+			res.Errors = append(res.Errors, types.TaskDraftError{Index: i, Message: err.Error()})
+			continue
+		}
+		res.Created++
+	}
+	return res, nil
+}
+
+// readDraftFile returns the text of a PDF, DOCX or plain text upload. The
+// first pages of a PDF are read, with OCR when they have no text layer.
+func (s *taskDraftService) readDraftFile(fileHeader *multipart.FileHeader) (string, error) {
+	ext := strings.ToLower(filepath.Ext(fileHeader.Filename))
+	if ext != ".pdf" && ext != ".docx" && ext != ".txt" {
+		return "", types.ErrUnsupportedFileType
+	}
+	src, err := fileHeader.Open()
+	if err != nil {
+		return "", err
+	}
+	defer src.Close()
+	if ext == ".txt" {
+		content, err := io.ReadAll(io.LimitReader(src, taskDraftMaxRunes*4))
+		if err != nil {
+			return "", err
+		}
+		return string(content), nil
+	}
+
+	tempFile, err := os.CreateTemp("", "task-draft-*"+ext)
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tempFile.Name())
+	defer tempFile.Close()
+	if _, err := io.Copy(tempFile, src); err != nil {
+		return "", err
+	}
+	if err := tempFile.Close(); err != nil {
+		return "", err
+	}
+
+	var parts []string
+	if ext == ".docx" {
+		parts, err = s.docxService.ReadText(tempFile.Name())
+		if err != nil {
+			return "", err
+		}
+		return strings.Join(parts, "\n"), nil
+	}
+	totalPages, err := s.pdfService.GetTotalPages(tempFile.Name())
+	if err != nil {
+		return "", err
+	}
+	req := &types.ExtractPageContentRequest{
+		ToolUse:  "pdftotext",
+		FilePath: tempFile.Name(),
+		FromPage: 1,
+		ToPage:   min(totalPages, taskDraftMaxPages),
+	}
+	parts, err = s.pdfService.ExtractPageContent(req)
+	if err != nil {
+		return "", err
+	}
+	text := strings.TrimSpace(strings.Join(parts, "\n"))
+	if text == "" {
+		// scanned work orders have no text layer
+		req.ToolUse = "ocr"
+		parts, err = s.pdfService.ExtractPageContent(req)
+		if err != nil {
+			return "", err
+		}
+		text = strings.Join(parts, "\n")
+	}
+	return text, nil
+}
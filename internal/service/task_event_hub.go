@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/remiehneppo/be-task-management/internal/repository"
+	"github.com/remiehneppo/be-task-management/types"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// taskEventChannel is the Redis pub/sub channel shared by every replica
+	taskEventChannel = "task_events"
+	// taskEventBuffer is how many events a slow board may lag behind before
+	// new ones are dropped for it
+	taskEventBuffer = 64
+	// pubsubRetryMin and pubsubRetryMax bound the wait before subscribing
+	// again after a Redis subscription failed
+	pubsubRetryMin = time.Second
+	pubsubRetryMax = time.Minute
+)
+
+var _ TaskEventHub = (*taskEventHub)(nil)
+
+// TaskEventPublisher broadcasts task and report changes to the boards of
+// the task workspace
+type TaskEventPublisher interface {
+	PublishTaskEvent(ctx context.Context, activity *types.TaskActivity)
+}
+
+type TaskEventHub interface {
+	TaskEventPublisher
+	// Subscribe streams the events of the current user workspace until the
+	// returned cancel function is called
+	Subscribe(ctx context.Context) (<-chan *types.TaskActivity, func(), error)
+	// Run relays the events published by every replica to the local
+	// subscribers until ctx is done
+	Run(ctx context.Context)
+}
+
+type taskEventHub struct {
+	redisClient *redis.Client
+	userRepo    repository.UserRepository
+	mu          sync.RWMutex
+	subscribers map[string]map[chan *types.TaskActivity]struct{}
+}
+
+func NewTaskEventHub(redisClient *redis.Client, userRepo repository.UserRepository) TaskEventHub {
+	return &taskEventHub{
+		redisClient: redisClient,
+		userRepo:    userRepo,
+		subscribers: make(map[string]map[chan *types.TaskActivity]struct{}),
+	}
+}
+
+// PublishTaskEvent sends the event through Redis so that boards connected to
+// other replicas get it too. Events are best effort, a failure is logged.
+func (h *taskEventHub) PublishTaskEvent(ctx context.Context, activity *types.TaskActivity) {
+	payload, err := json.Marshal(activity)
+	if err != nil {
+		logrus.Errorf("Failed to encode task event of %s: %v", activity.TaskID, err)
+		return
+	}
+	if err := h.redisClient.Publish(ctx, taskEventChannel, payload).Err(); err != nil {
+		logrus.Errorf("Failed to publish task event of %s: %v", activity.TaskID, err)
+	}
+}
+
+func (h *taskEventHub) Subscribe(ctx context.Context) (<-chan *types.TaskActivity, func(), error) {
+	userID, ok := ctx.Value("user_id").(string)
+	if !ok {
+		return nil, nil, types.ErrInvalidCredentials
+	}
+	user, err := h.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	workspace := user.Workspace
+	ch := make(chan *types.TaskActivity, taskEventBuffer)
+	h.mu.Lock()
+	if h.subscribers[workspace] == nil {
+		h.subscribers[workspace] = make(map[chan *types.TaskActivity]struct{})
+	}
+	h.subscribers[workspace][ch] = struct{}{}
+	h.mu.Unlock()
+	var once sync.Once
+	cancel := func() {
+		once.Do(func() {
+			h.mu.Lock()
+			delete(h.subscribers[workspace], ch)
+			if len(h.subscribers[workspace]) == 0 {
+				delete(h.subscribers, workspace)
+			}
+			h.mu.Unlock()
+			close(ch)
+		})
+	}
+	return ch, cancel, nil
+}
+
+func (h *taskEventHub) Run(ctx context.Context) {
+	relayPubSub(ctx, h.redisClient, taskEventChannel, func(payload string) {
+		activity := &types.TaskActivity{}
+		if err := json.Unmarshal([]byte(payload), activity); err != nil {
+			logrus.Errorf("Failed to decode task event: %v", err)
+			return
+		}
+		h.dispatch(activity)
+	})
+}
+
+func (h *taskEventHub) dispatch(activity *types.TaskActivity) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for ch := range h.subscribers[activity.Workspace] {
+		select {
+		case ch <- activity:
+		default:
+			logrus.Warnf("Dropping task event of %s for a slow board", activity.TaskID)
+		}
+	}
+}
+
+// relayPubSub passes the payloads published on the Redis channel to handle
+// until ctx is done. A failed subscription is retried with an exponential
+// backoff, the relay outlives a Redis outage.
+func relayPubSub(ctx context.Context, redisClient *redis.Client, channel string, handle func(payload string)) {
+	backoff := pubsubRetryMin
+	for {
+		subscribed, err := relayPubSubOnce(ctx, redisClient, channel, handle)
+		if ctx.Err() != nil {
+			return
+		}
+		if subscribed {
+			backoff = pubsubRetryMin
+		}
+		logrus.Errorf("Lost the subscription to %s, retrying in %s: %v", channel, backoff, err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, pubsubRetryMax)
+	}
+}
+
+// relayPubSubOnce subscribes to the channel and passes its payloads to handle
+// until the subscription fails, it tells whether the subscription was made
+func relayPubSubOnce(ctx context.Context, redisClient *redis.Client, channel string, handle func(payload string)) (bool, error) {
+	pubsub := redisClient.Subscribe(ctx, channel)
+	defer pubsub.Close()
+	// wait for the subscription so that no payload is missed once it is made
+	if _, err := pubsub.Receive(ctx); err != nil {
+		return false, err
+	}
+	// the channel reconnects on its own when the connection drops
+	messages := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return true, ctx.Err()
+		case message, ok := <-messages:
+			if !ok {
+				return true, redis.ErrClosed
+			}
+			handle(message.Payload)
+		}
+	}
+}
@@ -0,0 +1,320 @@
+package service
+
+import (
+	"context"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/remiehneppo/be-task-management/types"
+)
+
+// customFieldKeyPattern keeps keys usable as document paths
+var customFieldKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,39}$`)
+
+var customFieldTypes = []string{
+	types.CUSTOM_FIELD_TYPE_TEXT,
+	types.CUSTOM_FIELD_TYPE_NUMBER,
+	types.CUSTOM_FIELD_TYPE_DATE,
+	types.CUSTOM_FIELD_TYPE_ENUM,
+}
+
+// GetCustomFields returns the custom fields of the user workspace
+func (s *taskService) GetCustomFields(ctx context.Context) ([]*types.CustomFieldDefinition, error) {
+	userID, ok := ctx.Value("user_id").(string)
+	if !ok {
+		return nil, types.ErrInvalidCredentials
+	}
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.customFieldRepo.FindByWorkspace(ctx, user.Workspace)
+}
+
+func (s *taskService) CreateCustomField(ctx context.Context, req *types.CreateCustomFieldRequest) (*types.CustomFieldDefinition, error) {
+	user, err := s.customFieldManager(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !customFieldKeyPattern.MatchString(req.Key) {
+		return nil, types.ErrInvalidCustomFieldKey
+	}
+	if !slices.Contains(customFieldTypes, req.Type) {
+		return nil, types.ErrInvalidCustomFieldType
+	}
+	options, err := customFieldOptions(req.Type, req.Options)
+	if err != nil {
+		return nil, err
+	}
+	_, err = s.customFieldRepo.FindByKey(ctx, user.Workspace, req.Key)
+	if err == nil {
+		return nil, types.ErrCustomFieldExists
+	}
+	if err != types.ErrCustomFieldDefinitionMissing {
+		return nil, err
+	}
+	field := &types.CustomFieldDefinition{
+		Workspace: user.Workspace,
+		Key:       req.Key,
+		Name:      req.Name,
+		Type:      req.Type,
+		Options:   options,
+		Required:  req.Required,
+		Creator:   user.ID,
+		CreatedAt: time.Now().Unix(),
+		UpdatedAt: time.Now().Unix(),
+	}
+	if err := s.customFieldRepo.Save(ctx, field); err != nil {
+		return nil, err
+	}
+	return field, nil
+}
+
+// UpdateCustomField changes the name, options or requirement of a field.
+// Enum options may be added or removed, values already stored are kept.
+func (s *taskService) UpdateCustomField(ctx context.Context, req *types.UpdateCustomFieldRequest) (*types.CustomFieldDefinition, error) {
+	user, err := s.customFieldManager(ctx)
+	if err != nil {
+		return nil, err
+	}
+	field, err := s.customFieldRepo.FindByID(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+	if field.Workspace != user.Workspace {
+		return nil, types.ErrCustomFieldNotInWorkspace
+	}
+	if req.Name != "" {
+		field.Name = req.Name
+	}
+	if req.Options != nil {
+		options, err := customFieldOptions(field.Type, req.Options)
+		if err != nil {
+			return nil, err
+		}
+		field.Options = options
+	}
+	if req.Required != nil {
+		field.Required = *req.Required
+	}
+	field.ID = ""
+	field.UpdatedAt = time.Now().Unix()
+	if err := s.customFieldRepo.Update(ctx, req.ID, field); err != nil {
+		return nil, err
+	}
+	field.ID = req.ID
+	return field, nil
+}
+
+// DeleteCustomField removes the field and its values from the workspace tasks
+func (s *taskService) DeleteCustomField(ctx context.Context, id string) error {
+	user, err := s.customFieldManager(ctx)
+	if err != nil {
+		return err
+	}
+	field, err := s.customFieldRepo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if field.Workspace != user.Workspace {
+		return types.ErrCustomFieldNotInWorkspace
+	}
+	if err := s.customFieldRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+	return s.taskRepo.UnsetCustomField(ctx, field.Workspace, field.Key)
+}
+
+// customFieldManager returns the current user when they may manage the
+// custom fields of their workspace, their role must grant field.manage
+func (s *taskService) customFieldManager(ctx context.Context) (*types.User, error) {
+	userID, ok := ctx.Value("user_id").(string)
+	if !ok {
+		return nil, types.ErrInvalidCredentials
+	}
+	return s.authorizer.Authorize(ctx, userID, types.PERMISSION_FIELD_MANAGE)
+}
+
+func customFieldOptions(fieldType string, options []string) ([]string, error) {
+	if fieldType != types.CUSTOM_FIELD_TYPE_ENUM {
+		return nil, nil
+	}
+	unique := make([]string, 0, len(options))
+	for _, option := range options {
+		option = strings.TrimSpace(option)
+		if option == "" || slices.Contains(unique, option) {
+			return nil, types.ErrInvalidCustomFieldOptions
+		}
+		unique = append(unique, option)
+	}
+	if len(unique) == 0 {
+		return nil, types.ErrInvalidCustomFieldOptions
+	}
+	return unique, nil
+}
+
+// applyCustomFields validates the values against the workspace definitions
+// and merges them into current, a nil value clears the field. Required
+// fields must be set on create and cannot be cleared afterwards.
+func (s *taskService) applyCustomFields(ctx context.Context, workspace string, current, values map[string]interface{}, create bool) (map[string]interface{}, error) {
+	if len(values) == 0 && !create {
+		return current, nil
+	}
+	fields, err := s.customFieldRepo.FindByWorkspace(ctx, workspace)
+	if err != nil {
+		return nil, err
+	}
+	definitions := make(map[string]*types.CustomFieldDefinition, len(fields))
+	for _, field := range fields {
+		definitions[field.Key] = field
+	}
+	merged := make(map[string]interface{}, len(current)+len(values))
+	for key, value := range current {
+		merged[key] = value
+	}
+	for key, value := range values {
+		field, ok := definitions[key]
+		if !ok {
+			return nil, &types.CustomFieldError{Key: key, Err: types.ErrUnknownCustomField}
+		}
+		if value == nil {
+			if field.Required {
+				return nil, &types.CustomFieldError{Key: key, Err: types.ErrCustomFieldRequired}
+			}
+			delete(merged, key)
+			continue
+		}
+		converted, err := customFieldValue(field, value)
+		if err != nil {
+			return nil, &types.CustomFieldError{Key: key, Err: err}
+		}
+		merged[key] = converted
+	}
+	if create {
+		for _, field := range fields {
+			if _, ok := merged[field.Key]; field.Required && !ok {
+				return nil, &types.CustomFieldError{Key: field.Key, Err: types.ErrCustomFieldRequired}
+			}
+		}
+	}
+	return merged, nil
+}
+
+// customFieldValue checks a decoded JSON value against the field type,
+// dates are stored as integral unix timestamps
+func customFieldValue(field *types.CustomFieldDefinition, value interface{}) (interface{}, error) {
+	switch field.Type {
+	case types.CUSTOM_FIELD_TYPE_TEXT:
+		if text, ok := value.(string); ok {
+			return text, nil
+		}
+	case types.CUSTOM_FIELD_TYPE_NUMBER:
+		if number, ok := value.(float64); ok {
+			return number, nil
+		}
+	case types.CUSTOM_FIELD_TYPE_DATE:
+		if number, ok := value.(float64); ok && number == math.Trunc(number) {
+			return int64(number), nil
+		}
+	case types.CUSTOM_FIELD_TYPE_ENUM:
+		if option, ok := value.(string); ok && slices.Contains(field.Options, option) {
+			return option, nil
+		}
+	}
+	return nil, types.ErrInvalidCustomFieldValue
+}
+
+// parseCustomFieldValue converts a query string value to the field type
+func parseCustomFieldValue(field *types.CustomFieldDefinition, value string) (interface{}, error) {
+	switch field.Type {
+	case types.CUSTOM_FIELD_TYPE_NUMBER:
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, types.ErrInvalidCustomFieldValue
+		}
+		return number, nil
+	case types.CUSTOM_FIELD_TYPE_DATE:
+		date, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, types.ErrInvalidCustomFieldValue
+		}
+		return date, nil
+	}
+	return value, nil
+}
+
+// resolveCustomFieldFilters types the string values of the custom field
+// filters after the workspace definitions. Ranges only apply to number and
+// date fields.
+func (s *taskService) resolveCustomFieldFilters(ctx context.Context, filter *types.TaskFilter) error {
+	if len(filter.CustomFields) == 0 {
+		return nil
+	}
+	fields, err := s.customFieldRepo.FindByWorkspace(ctx, filter.Workspace)
+	if err != nil {
+		return err
+	}
+	definitions := make(map[string]*types.CustomFieldDefinition, len(fields))
+	for _, field := range fields {
+		definitions[field.Key] = field
+	}
+	resolved := make([]types.CustomFieldFilter, 0, len(filter.CustomFields))
+	for _, customFilter := range filter.CustomFields {
+		field, ok := definitions[customFilter.Key]
+		if !ok {
+			return &types.CustomFieldError{Key: customFilter.Key, Err: types.ErrUnknownCustomField}
+		}
+		ranged := field.Type == types.CUSTOM_FIELD_TYPE_NUMBER || field.Type == types.CUSTOM_FIELD_TYPE_DATE
+		if !ranged && (customFilter.From != nil || customFilter.To != nil) {
+			return &types.CustomFieldError{Key: customFilter.Key, Err: types.ErrInvalidCustomFieldValue}
+		}
+		parsed := types.CustomFieldFilter{Key: customFilter.Key}
+		for _, bound := range []struct {
+			raw    interface{}
+			target *interface{}
+		}{
+			{customFilter.Equals, &parsed.Equals},
+			{customFilter.From, &parsed.From},
+			{customFilter.To, &parsed.To},
+		} {
+			raw, ok := bound.raw.(string)
+			if !ok {
+				continue
+			}
+			value, err := parseCustomFieldValue(field, raw)
+			if err != nil {
+				return &types.CustomFieldError{Key: customFilter.Key, Err: err}
+			}
+			*bound.target = value
+		}
+		if parsed.Equals == nil && parsed.From == nil && parsed.To == nil {
+			continue
+		}
+		resolved = append(resolved, parsed)
+	}
+	filter.CustomFields = resolved
+	return nil
+}
+
+func validatePriority(priority int) error {
+	if priority < types.TASK_PRIORITY_NONE || priority > types.TASK_PRIORITY_URGENT {
+		return types.ErrInvalidPriority
+	}
+	return nil
+}
+
+// normalizeTags trims the labels and drops empty and repeated ones
+func normalizeTags(tags []string) []string {
+	unique := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag != "" && !slices.Contains(unique, tag) {
+			unique = append(unique, tag)
+		}
+	}
+	return unique
+}
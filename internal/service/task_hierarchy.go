@@ -0,0 +1,385 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/remiehneppo/be-task-management/types"
+)
+
+// GetTaskSubtree returns the task with all of its subtasks, nested by level
+func (s *taskService) GetTaskSubtree(ctx context.Context, id string) (*types.TaskTreeNode, error) {
+	root, err := s.findTaskInUserWorkspace(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	tasks := []*types.Task{root}
+	level := []string{root.ID}
+	// visited guards against corrupted data, the parent chain is kept acyclic
+	visited := map[string]bool{root.ID: true}
+	for len(level) > 0 {
+		children, err := s.taskRepo.FindByParentIDs(ctx, level)
+		if err != nil {
+			return nil, err
+		}
+		level = make([]string, 0)
+		for _, child := range children {
+			if visited[child.ID] {
+				continue
+			}
+			visited[child.ID] = true
+			tasks = append(tasks, child)
+			level = append(level, child.ID)
+		}
+	}
+	tasksRes, err := s.toTaskResponses(ctx, tasks)
+	if err != nil {
+		return nil, err
+	}
+	nodes := make(map[string]*types.TaskTreeNode)
+	for _, taskRes := range tasksRes {
+		nodes[taskRes.ID] = &types.TaskTreeNode{
+			Task:     taskRes,
+			Children: make([]*types.TaskTreeNode, 0),
+		}
+	}
+	for _, task := range tasks[1:] {
+		parent := nodes[task.ParentID]
+		parent.Children = append(parent.Children, nodes[task.ID])
+	}
+	return nodes[root.ID], nil
+}
+
+// GetTaskDependencyGraph returns every task the given task transitively
+// depends on or is depended on by, with the finish-to-start edges between them
+func (s *taskService) GetTaskDependencyGraph(ctx context.Context, id string) (*types.TaskDependencyGraph, error) {
+	root, err := s.findTaskInUserWorkspace(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	tasksMap := map[string]*types.Task{root.ID: root}
+
+	// walk predecessors
+	level := root.DependsOn
+	for len(level) > 0 {
+		predecessors, err := s.taskRepo.FindByIDs(ctx, level)
+		if err != nil {
+			return nil, err
+		}
+		level = make([]string, 0)
+		for _, predecessor := range predecessors {
+			if _, ok := tasksMap[predecessor.ID]; ok {
+				continue
+			}
+			tasksMap[predecessor.ID] = predecessor
+			level = append(level, predecessor.DependsOn...)
+		}
+	}
+	// walk successors
+	level = []string{root.ID}
+	for len(level) > 0 {
+		successors, err := s.taskRepo.FindDependents(ctx, level)
+		if err != nil {
+			return nil, err
+		}
+		level = make([]string, 0)
+		for _, successor := range successors {
+			if _, ok := tasksMap[successor.ID]; ok {
+				continue
+			}
+			tasksMap[successor.ID] = successor
+			level = append(level, successor.ID)
+		}
+	}
+
+	tasks := make([]*types.Task, 0, len(tasksMap))
+	edges := make([]*types.TaskDependencyEdge, 0)
+	for _, task := range tasksMap {
+		tasks = append(tasks, task)
+		for _, dependsOn := range task.DependsOn {
+			if _, ok := tasksMap[dependsOn]; !ok {
+				continue
+			}
+			edges = append(edges, &types.TaskDependencyEdge{
+				TaskID:    task.ID,
+				DependsOn: dependsOn,
+			})
+		}
+	}
+	tasksRes, err := s.toTaskResponses(ctx, tasks)
+	if err != nil {
+		return nil, err
+	}
+	unfinished, err := s.unfinishedPredecessors(ctx, root)
+	if err != nil {
+		return nil, err
+	}
+	return &types.TaskDependencyGraph{
+		TaskID:  root.ID,
+		Blocked: len(unfinished) > 0,
+		Tasks:   tasksRes,
+		Edges:   edges,
+	}, nil
+}
+
+func (s *taskService) AddTaskDependency(ctx context.Context, req *types.TaskDependencyRequest) error {
+	userID, task, err := s.findTaskForPlanning(ctx, req.TaskID)
+	if err != nil {
+		return err
+	}
+	if slices.Contains(task.DependsOn, req.DependsOn) {
+		return types.ErrTaskDependencyExists
+	}
+	if err := s.validateDependencies(ctx, task.ID, task.Workspace, []string{req.DependsOn}); err != nil {
+		return err
+	}
+	oldDependsOn := task.DependsOn
+	task.DependsOn = append(slices.Clone(task.DependsOn), req.DependsOn)
+	return s.saveTaskDependencies(ctx, userID, task, oldDependsOn)
+}
+
+func (s *taskService) RemoveTaskDependency(ctx context.Context, req *types.TaskDependencyRequest) error {
+	userID, task, err := s.findTaskForPlanning(ctx, req.TaskID)
+	if err != nil {
+		return err
+	}
+	index := slices.Index(task.DependsOn, req.DependsOn)
+	if index < 0 {
+		return types.ErrTaskDependencyMissing
+	}
+	oldDependsOn := task.DependsOn
+	task.DependsOn = slices.Delete(slices.Clone(task.DependsOn), index, index+1)
+	return s.saveTaskDependencies(ctx, userID, task, oldDependsOn)
+}
+
+func (s *taskService) saveTaskDependencies(ctx context.Context, userID string, task *types.Task, oldDependsOn []string) error {
+	taskID := task.ID
+	task.ID = ""
+	task.UpdateAt = time.Now().Unix()
+	if err := s.taskRepo.Update(ctx, taskID, task); err != nil {
+		return err
+	}
+	s.recordActivity(ctx, &types.TaskActivity{
+		TaskID:    taskID,
+		Workspace: task.Workspace,
+		Actor:     userID,
+		Action:    types.TASK_ACTIVITY_DEPENDENCY,
+		Changes: []types.FieldChange{
+			{Field: "depends_on", OldValue: oldDependsOn, NewValue: task.DependsOn},
+		},
+	})
+	return nil
+}
+
+// findTaskForPlanning loads a task that the current user may restructure,
+// only its creator or a higher management level may change dependencies
+func (s *taskService) findTaskForPlanning(ctx context.Context, taskID string) (string, *types.Task, error) {
+	userID, ok := ctx.Value("user_id").(string)
+	if !ok {
+		return "", nil, types.ErrInvalidCredentials
+	}
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return "", nil, err
+	}
+	task, err := s.taskRepo.FindByID(ctx, taskID)
+	if err != nil {
+		return "", nil, err
+	}
+	roles, err := s.taskActorRoles(ctx, task, user)
+	if err != nil {
+		return "", nil, err
+	}
+	if !roles[types.TASK_ACTOR_CREATOR] && !roles[types.TASK_ACTOR_MANAGER] {
+		return "", nil, types.ErrTaskNotCreator
+	}
+	return userID, task, nil
+}
+
+func (s *taskService) findTaskInUserWorkspace(ctx context.Context, taskID string) (*types.Task, error) {
+	userID, ok := ctx.Value("user_id").(string)
+	if !ok {
+		return nil, types.ErrInvalidCredentials
+	}
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	task, err := s.taskRepo.FindByID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if task.Workspace != user.Workspace {
+		return nil, types.ErrTaskNotInWorkspace
+	}
+	return task, nil
+}
+
+// validateParent checks that parentID is a task of the workspace and that
+// making it the parent of taskID does not close a loop in the parent chain
+func (s *taskService) validateParent(ctx context.Context, taskID, workspace, parentID string) error {
+	visited := make(map[string]bool)
+	for currentID := parentID; currentID != ""; {
+		if currentID == taskID || visited[currentID] {
+			return types.ErrTaskParentCycle
+		}
+		visited[currentID] = true
+		current, err := s.taskRepo.FindByID(ctx, currentID)
+		if err != nil {
+			return err
+		}
+		if current.Workspace != workspace {
+			return types.ErrTaskNotInWorkspace
+		}
+		currentID = current.ParentID
+	}
+	return nil
+}
+
+// validateDependencies checks that every predecessor exists in the workspace
+// and that none of them already depends, directly or not, on taskID
+func (s *taskService) validateDependencies(ctx context.Context, taskID, workspace string, dependsOn []string) error {
+	if len(dependsOn) == 0 {
+		return nil
+	}
+	predecessors, err := s.taskRepo.FindByIDs(ctx, dependsOn)
+	if err != nil {
+		return err
+	}
+	for _, id := range dependsOn {
+		predecessor, ok := predecessors[id]
+		if !ok {
+			return types.ErrInvalidTask
+		}
+		if predecessor.Workspace != workspace {
+			return types.ErrTaskNotInWorkspace
+		}
+		if taskID == "" {
+			continue
+		}
+		if id == taskID {
+			return types.ErrTaskDependencyCycle
+		}
+		cycle, err := s.dependsTransitivelyOn(ctx, predecessor, taskID)
+		if err != nil {
+			return err
+		}
+		if cycle {
+			return types.ErrTaskDependencyCycle
+		}
+	}
+	return nil
+}
+
+func (s *taskService) dependsTransitivelyOn(ctx context.Context, task *types.Task, targetID string) (bool, error) {
+	visited := map[string]bool{task.ID: true}
+	level := task.DependsOn
+	for len(level) > 0 {
+		if slices.Contains(level, targetID) {
+			return true, nil
+		}
+		predecessors, err := s.taskRepo.FindByIDs(ctx, level)
+		if err != nil {
+			return false, err
+		}
+		level = make([]string, 0)
+		for _, predecessor := range predecessors {
+			if visited[predecessor.ID] {
+				continue
+			}
+			visited[predecessor.ID] = true
+			level = append(level, predecessor.DependsOn...)
+		}
+	}
+	return false, nil
+}
+
+// unfinishedPredecessors returns the ids of the predecessors that are still
+// open, a task cannot be worked on until they are completed or cancelled
+func (s *taskService) unfinishedPredecessors(ctx context.Context, task *types.Task) ([]string, error) {
+	if len(task.DependsOn) == 0 {
+		return nil, nil
+	}
+	predecessors, err := s.taskRepo.FindByIDs(ctx, task.DependsOn)
+	if err != nil {
+		return nil, err
+	}
+	unfinished := make([]string, 0)
+	for _, predecessor := range predecessors {
+		if !isTaskFinished(predecessor.Status) {
+			unfinished = append(unfinished, predecessor.ID)
+		}
+	}
+	return unfinished, nil
+}
+
+func isTaskFinished(status string) bool {
+	return status == types.TASK_STATUS_COMPLETED ||
+		status == types.TASK_STATUS_CLOSE ||
+		status == types.TASK_STATUS_CANCEL
+}
+
+// hasSubtasks reports whether the task progress is rolled up from children
+func (s *taskService) hasSubtasks(ctx context.Context, taskID string) (bool, error) {
+	children, err := s.taskRepo.FindByParentIDs(ctx, []string{taskID})
+	if err != nil {
+		return false, err
+	}
+	return len(children) > 0, nil
+}
+
+// rollupProgress sets the progress of parentID, then of its ancestors, to
+// the average progress of their non cancelled subtasks
+func (s *taskService) rollupProgress(ctx context.Context, parentID, actor string) error {
+	visited := make(map[string]bool)
+	for parentID != "" && !visited[parentID] {
+		visited[parentID] = true
+		parent, err := s.taskRepo.FindByID(ctx, parentID)
+		if err != nil {
+			return err
+		}
+		children, err := s.taskRepo.FindByParentIDs(ctx, []string{parentID})
+		if err != nil {
+			return err
+		}
+		total, count := 0, 0
+		for _, child := range children {
+			if child.Status == types.TASK_STATUS_CANCEL {
+				continue
+			}
+			total += child.Progress
+			count++
+		}
+		if count == 0 || parent.Progress == total/count {
+			return nil
+		}
+		before := *parent
+		parent.Progress = total / count
+		parent.ID = ""
+		parent.UpdateAt = time.Now().Unix()
+		if err := s.taskRepo.Update(ctx, parentID, parent); err != nil {
+			return err
+		}
+		s.recordTaskUpdate(ctx, parentID, actor, &before, parent)
+		parentID = parent.ParentID
+	}
+	return nil
+}
+
+// toTaskResponses converts tasks, resolving creator and member names
+func (s *taskService) toTaskResponses(ctx context.Context, tasks []*types.Task) ([]*types.TaskResponse, error) {
+	usersMap, err := s.userRepo.FindByIDs(ctx, taskUserIDs(tasks))
+	if err != nil {
+		return nil, err
+	}
+	tasksRes := make([]*types.TaskResponse, 0, len(tasks))
+	for _, task := range tasks {
+		taskRes, err := s.convertTaskToTaskRes(task, usersMap)
+		if err != nil {
+			return nil, err
+		}
+		tasksRes = append(tasksRes, taskRes)
+	}
+	return tasksRes, nil
+}
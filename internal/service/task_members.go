@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/remiehneppo/be-task-management/types"
+)
+
+// GetTasksWatchedByUser returns the tasks the current user is watching
+func (s *taskService) GetTasksWatchedByUser(ctx context.Context, page types.PageRequest) (items []*types.TaskResponse, total int64, nextCursor string, err error) {
+	userID, ok := ctx.Value("user_id").(string)
+	if !ok {
+		return nil, 0, "", types.ErrInvalidCredentials
+	}
+	return s.FilterTasks(ctx, page, types.TaskFilter{
+		Watcher: userID,
+	})
+}
+
+// WatchTask adds the current user to the watchers of a task
+func (s *taskService) WatchTask(ctx context.Context, taskID string) error {
+	userID, ok := ctx.Value("user_id").(string)
+	if !ok {
+		return types.ErrInvalidCredentials
+	}
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	task, err := s.taskRepo.FindByID(ctx, taskID)
+	if err != nil {
+		return err
+	}
+	if task.Workspace != user.Workspace {
+		return types.ErrTaskNotInWorkspace
+	}
+	if slices.Contains(task.Watchers, userID) {
+		return nil
+	}
+	before := *task
+	task.Watchers = append(slices.Clone(task.Watchers), userID)
+	return s.saveTaskMembers(ctx, userID, &before, task)
+}
+
+// UnwatchTask removes the current user from the watchers of a task
+func (s *taskService) UnwatchTask(ctx context.Context, taskID string) error {
+	userID, ok := ctx.Value("user_id").(string)
+	if !ok {
+		return types.ErrInvalidCredentials
+	}
+	task, err := s.taskRepo.FindByID(ctx, taskID)
+	if err != nil {
+		return err
+	}
+	index := slices.Index(task.Watchers, userID)
+	if index < 0 {
+		return nil
+	}
+	before := *task
+	task.Watchers = slices.Delete(slices.Clone(task.Watchers), index, index+1)
+	return s.saveTaskMembers(ctx, userID, &before, task)
+}
+
+func (s *taskService) saveTaskMembers(ctx context.Context, userID string, before, task *types.Task) error {
+	taskID := task.ID
+	task.ID = ""
+	task.UpdateAt = time.Now().Unix()
+	if err := s.taskRepo.Update(ctx, taskID, task); err != nil {
+		return err
+	}
+	s.recordTaskUpdate(ctx, taskID, userID, before, task)
+	return nil
+}
+
+// resolveTaskMembers normalises the assignee and watcher lists of a task, the
+// primary assignee always comes first. Members not in known are new to the
+// task and must be assignable by the actor.
+func (s *taskService) resolveTaskMembers(ctx context.Context, actor *types.User, primary string, assignees, watchers, known []string) ([]string, []string, error) {
+	assignees = uniqueIDs(append([]string{primary}, assignees...))
+	watchers = uniqueIDs(watchers)
+	newMembers := make([]string, 0)
+	for _, id := range append(slices.Clone(assignees), watchers...) {
+		if !slices.Contains(known, id) && !slices.Contains(newMembers, id) {
+			newMembers = append(newMembers, id)
+		}
+	}
+	if len(newMembers) == 0 {
+		return assignees, watchers, nil
+	}
+	usersMap, err := s.userRepo.FindByIDs(ctx, newMembers)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, id := range newMembers {
+		member, ok := usersMap[id]
+		if !ok {
+			return nil, nil, types.ErrUserNotFound
+		}
+		if err := s.validateCreateQuestPermission(ctx, actor, member); err != nil {
+			return nil, nil, err
+		}
+	}
+	return assignees, watchers, nil
+}
+
+// taskAssignees returns every assignee of the task, tasks created before
+// assignee lists existed only carry the primary assignee
+func taskAssignees(task *types.Task) []string {
+	if len(task.Assignees) == 0 {
+		return []string{task.Assignee}
+	}
+	return task.Assignees
+}
+
+func isTaskAssignee(task *types.Task, userID string) bool {
+	return slices.Contains(taskAssignees(task), userID)
+}
+
+// taskUserIDs returns the users a task response refers to
+func taskUserIDs(tasks []*types.Task) []string {
+	userIDs := make([]string, 0)
+	for _, task := range tasks {
+		userIDs = append(userIDs, task.Creator)
+		userIDs = append(userIDs, taskAssignees(task)...)
+		userIDs = append(userIDs, task.Watchers...)
+	}
+	return uniqueIDs(userIDs)
+}
+
+// taskMembers maps member ids to names, members removed since are skipped
+func taskMembers(ids []string, primary string, usersMap map[string]*types.User) []*types.TaskMember {
+	members := make([]*types.TaskMember, 0, len(ids))
+	for _, id := range ids {
+		user, ok := usersMap[id]
+		if !ok {
+			continue
+		}
+		members = append(members, &types.TaskMember{
+			ID:       id,
+			FullName: user.FullName,
+			Primary:  id == primary,
+		})
+	}
+	return members
+}
+
+func uniqueIDs(ids []string) []string {
+	unique := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if id != "" && !slices.Contains(unique, id) {
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/remiehneppo/be-task-management/types"
+	"github.com/sirupsen/logrus"
+)
+
+// notify sends the notification to every recipient but the actor. The change
+// it describes is already persisted, so a failure is logged and not returned.
+func (s *taskService) notify(ctx context.Context, recipients []string, actor string, notification types.Notification) {
+	for _, recipient := range uniqueIDs(recipients) {
+		if recipient == actor {
+			continue
+		}
+		notification := notification
+		notification.Recipient = recipient
+		notification.Actor = actor
+		notification.CreatedAt = time.Now().Unix()
+		if err := s.notifier.Notify(ctx, &notification); err != nil {
+			logrus.Errorf("Failed to notify %s about task %s: %v", recipient, notification.TaskID, err)
+		}
+	}
+}
+
+func (s *taskService) notifyTaskAssigned(ctx context.Context, task *types.Task, assignees []string, actor string) {
+	s.notify(ctx, assignees, actor, types.Notification{
+		Type:    types.NOTIFICATION_TYPE_TASK_ASSIGNED,
+		TaskID:  task.ID,
+		Title:   fmt.Sprintf("Công việc mới: %s", task.Title),
+		Message: fmt.Sprintf("Bạn được giao công việc \"%s\"", task.Title),
+	})
+}
+
+// notifyTaskUpdate tells new assignees about their assignment and everyone
+// following the task about a status change
+func (s *taskService) notifyTaskUpdate(ctx context.Context, taskID string, before, after *types.Task, actor string) {
+	task := *after
+	task.ID = taskID
+	newAssignees := slices.DeleteFunc(slices.Clone(taskAssignees(&task)), func(id string) bool {
+		return slices.Contains(taskAssignees(before), id)
+	})
+	if len(newAssignees) > 0 {
+		s.notifyTaskAssigned(ctx, &task, newAssignees, actor)
+	}
+	if before.Status == after.Status {
+		return
+	}
+	s.notify(ctx, taskFollowers(&task), actor, types.Notification{
+		Type:    types.NOTIFICATION_TYPE_TASK_STATUS,
+		TaskID:  taskID,
+		Title:   fmt.Sprintf("Cập nhật trạng thái: %s", task.Title),
+		Message: fmt.Sprintf("Công việc \"%s\" chuyển từ %s sang %s", task.Title, before.Status, after.Status),
+	})
+}
+
+func (s *taskService) notifyReportAdded(ctx context.Context, task *types.Task, report *types.Report, actor string) {
+	s.notify(ctx, append([]string{task.Creator}, task.Watchers...), actor, types.Notification{
+		Type:     types.NOTIFICATION_TYPE_REPORT_ADDED,
+		TaskID:   task.ID,
+		ReportID: report.ID,
+		Title:    fmt.Sprintf("Báo cáo mới: %s", task.Title),
+		Message:  report.Report,
+	})
+}
+
+func (s *taskService) notifyReportFeedback(ctx context.Context, report *types.Report, reportID, actor string) {
+	title := "Phản hồi báo cáo"
+	if task, err := s.taskRepo.FindByID(ctx, report.TaskID); err == nil {
+		title = fmt.Sprintf("Phản hồi báo cáo: %s", task.Title)
+	}
+	s.notify(ctx, []string{report.Creator}, actor, types.Notification{
+		Type:     types.NOTIFICATION_TYPE_REPORT_FEEDBACK,
+		TaskID:   report.TaskID,
+		ReportID: reportID,
+		Title:    title,
+		Message:  report.Feedback,
+	})
+}
+
+func (s *taskService) notifyReportReviewed(ctx context.Context, task *types.Task, report *types.Report, reportID, actor string) {
+	decision := "được duyệt"
+	if report.Status == types.REPORT_STATUS_REJECTED {
+		decision = "bị từ chối"
+	}
+	message := fmt.Sprintf("Báo cáo của bạn cho công việc \"%s\" đã %s", task.Title, decision)
+	if report.Feedback != "" {
+		message = fmt.Sprintf("%s: %s", message, report.Feedback)
+	}
+	s.notify(ctx, []string{report.Creator}, actor, types.Notification{
+		Type:     types.NOTIFICATION_TYPE_REPORT_REVIEWED,
+		TaskID:   report.TaskID,
+		ReportID: reportID,
+		Title:    fmt.Sprintf("Duyệt báo cáo: %s", task.Title),
+		Message:  message,
+	})
+}
+
+func (s *taskService) notifyCommentMentions(ctx context.Context, task *types.Task, comment *types.Comment, mentions []string) {
+	if len(mentions) == 0 {
+		return
+	}
+	title := fmt.Sprintf("Bạn được nhắc đến trong công việc: %s", task.Title)
+	if comment.ReportID != "" {
+		title = fmt.Sprintf("Bạn được nhắc đến trong báo cáo: %s", task.Title)
+	}
+	s.notify(ctx, mentions, comment.Author, types.Notification{
+		Type:      types.NOTIFICATION_TYPE_COMMENT_MENTION,
+		TaskID:    task.ID,
+		ReportID:  comment.ReportID,
+		CommentID: comment.ID,
+		Title:     title,
+		Message:   comment.Content,
+	})
+}
+
+// taskFollowers returns everyone concerned by the task: its creator,
+// assignees and watchers
+func taskFollowers(task *types.Task) []string {
+	followers := append([]string{task.Creator}, taskAssignees(task)...)
+	return append(followers, task.Watchers...)
+}
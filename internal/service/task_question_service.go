@@ -0,0 +1,420 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/remiehneppo/be-task-management/internal/repository"
+	"github.com/remiehneppo/be-task-management/types"
+	"github.com/sashabaranov/go-openai"
+	"github.com/sashabaranov/go-openai/jsonschema"
+)
+
+const (
+	taskQuestionDefaultLimit = 15
+	taskQuestionMaxLimit     = 30
+	// taskQuestionCandidates bounds the tasks matching the filter of a
+	// question that are ranked by meaning
+	taskQuestionCandidates = 500
+)
+
+var _ TaskQuestionService = (*taskQuestionService)(nil)
+
+// TaskQuestionService answers questions about the tasks and reports of the
+// user's workspace. The AI first reads the question as a TaskFilter and a
+// text to search by meaning, the tasks matching the filter are ranked by
+// that text and the answer is written from them and their closest reports.
+type TaskQuestionService interface {
+	AskTasks(ctx context.Context, req *types.AskTasksRequest) (*types.AskTasksResponse, error)
+}
+
+type taskQuestionService struct {
+	aiService          AIService
+	taskRepo           repository.TaskRepository
+	reportRepo         repository.ReportRepository
+	userRepo           repository.UserRepository
+	taskVectorRepo     repository.TaskVectorRepository
+	documentVectorRepo repository.DocumentVectorRepository
+}
+
+func NewTaskQuestionService(
+	aiService AIService,
+	taskRepo repository.TaskRepository,
+	reportRepo repository.ReportRepository,
+	userRepo repository.UserRepository,
+	taskVectorRepo repository.TaskVectorRepository,
+	documentVectorRepo repository.DocumentVectorRepository,
+) TaskQuestionService {
+	return &taskQuestionService{
+		aiService:          aiService,
+		taskRepo:           taskRepo,
+		reportRepo:         reportRepo,
+		userRepo:           userRepo,
+		taskVectorRepo:     taskVectorRepo,
+		documentVectorRepo: documentVectorRepo,
+	}
+}
+
+// taskQuestionOutput is the filter the AI reads a question as, dates are
+// empty when the question has none
+type taskQuestionOutput struct {
+	Search       string   `json:"search" description:"Nội dung cần tìm theo ý nghĩa trong công việc và báo cáo, ví dụ: chậm tiến độ do nhà cung cấp. Không gồm thời gian, trạng thái, người phụ trách; để trống khi câu hỏi chỉ có các điều kiện đó"`
+	Statuses     []string `json:"statuses" description:"Trạng thái công việc: open mới, doing đang thực hiện, review chờ nghiệm thu, completed hoàn thành, close đã đóng, cancel đã hủy"`
+	AssigneeIDs  []string `json:"assignee_ids" description:"Mã của những người phụ trách được nêu trong câu hỏi"`
+	Priorities   []int    `json:"priorities" description:"Mức ưu tiên: 1 thấp, 2 trung bình, 3 cao, 4 khẩn"`
+	Overdue      bool     `json:"overdue" description:"Chỉ các công việc chưa hoàn thành và đã quá hạn tại thời điểm hiện tại"`
+	SpanFrom     string   `json:"span_from" description:"Công việc diễn ra (từ ngày bắt đầu đến hạn) từ ngày này, dạng YYYY-MM-DD"`
+	SpanTo       string   `json:"span_to" description:"Công việc diễn ra (từ ngày bắt đầu đến hạn) đến ngày này, dạng YYYY-MM-DD"`
+	DeadlineFrom string   `json:"deadline_from" description:"Hạn hoàn thành từ ngày này, dạng YYYY-MM-DD"`
+	DeadlineTo   string   `json:"deadline_to" description:"Hạn hoàn thành đến ngày này, dạng YYYY-MM-DD"`
+	ReportFrom   string   `json:"report_from" description:"Có báo cáo từ ngày này, dạng YYYY-MM-DD"`
+	ReportTo     string   `json:"report_to" description:"Có báo cáo đến ngày này, dạng YYYY-MM-DD"`
+}
+
+func (s *taskQuestionService) AskTasks(ctx context.Context, req *types.AskTasksRequest) (*types.AskTasksResponse, error) {
+	userID, ok := ctx.Value("user_id").(string)
+	if !ok {
+		return nil, types.ErrInvalidCredentials
+	}
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	location := time.Local
+	if req.Timezone != "" {
+		location, err = time.LoadLocation(req.Timezone)
+		if err != nil {
+			return nil, types.ErrInvalidTimezone
+		}
+	}
+	limit := req.Limit
+	if limit <= 0 {
+		limit = taskQuestionDefaultLimit
+	}
+	limit = min(limit, taskQuestionMaxLimit)
+	members, err := s.userRepo.FindByWorkspace(ctx, user.Workspace)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]string, len(members))
+	for _, member := range members {
+		names[member.ID] = member.FullName
+	}
+
+	filter, search, err := s.readQuestion(ctx, req.Question, user.Workspace, members, time.Now().In(location))
+	if err != nil {
+		return nil, err
+	}
+	res := &types.AskTasksResponse{
+		Filter:  *filter,
+		Tasks:   make([]*types.TaskCitation, 0),
+		Reports: make([]*types.ReportCitation, 0),
+	}
+	res.Filter.Search = search
+	if search == "" {
+		search = req.Question
+	}
+
+	// candidateIDs bound the search to the tasks matching the filter
+	var candidateIDs []string
+	tasks := make([]*types.Task, 0, limit)
+	if taskQuestionFiltered(filter) {
+		matched, _, _, err := s.taskRepo.PaginateWithFilter(ctx, types.PageRequest{Page: 1, Limit: taskQuestionCandidates}, *filter)
+		if err != nil {
+			return nil, err
+		}
+		if len(matched) == 0 {
+			res.Answer = "Không tìm thấy công việc nào phù hợp với câu hỏi."
+			return res, nil
+		}
+		candidateIDs = make([]string, 0, len(matched))
+		for _, task := range matched {
+			candidateIDs = append(candidateIDs, task.ID)
+		}
+		if res.Filter.Search != "" && len(matched) > limit {
+			tasks, err = s.searchTasks(ctx, user.Workspace, candidateIDs, search, limit)
+			if err != nil {
+				return nil, err
+			}
+		}
+		// without a match by meaning the first tasks of the filter are kept
+		if len(tasks) == 0 {
+			tasks = matched[:min(limit, len(matched))]
+		}
+	} else {
+		tasks, err = s.searchTasks(ctx, user.Workspace, nil, search, limit)
+		if err != nil {
+			return nil, err
+		}
+	}
+	reports, excerpts, err := s.searchReports(ctx, user.Workspace, candidateIDs, search, limit, filter)
+	if err != nil {
+		return nil, err
+	}
+	tasks, err = s.withReportTasks(ctx, user.Workspace, tasks, reports)
+	if err != nil {
+		return nil, err
+	}
+	if len(tasks) == 0 && len(reports) == 0 {
+		res.Answer = "Không tìm thấy công việc hay báo cáo nào phù hợp với câu hỏi."
+		return res, nil
+	}
+
+	message, err := s.aiService.Chat(ctx, []types.Message{
+		{Role: openai.ChatMessageRoleSystem, Content: taskQuestionPrompt(user.Workspace, tasks, reports, excerpts, names, time.Now().In(location))},
+		{Role: openai.ChatMessageRoleUser, Content: req.Question},
+	})
+	if err != nil {
+		return nil, err
+	}
+	res.Answer = strings.TrimSpace(message.Content)
+	for _, id := range summaryCitation.FindAllString(res.Answer, -1) {
+		if i := slices.IndexFunc(tasks, func(task *types.Task) bool { return task.ID == id }); i >= 0 {
+			if !slices.ContainsFunc(res.Tasks, func(cited *types.TaskCitation) bool { return cited.ID == id }) {
+				task := tasks[i]
+				res.Tasks = append(res.Tasks, &types.TaskCitation{
+					ID:       task.ID,
+					Title:    task.Title,
+					Status:   task.Status,
+					Assignee: task.Assignee,
+					Deadline: task.Deadline,
+				})
+			}
+			continue
+		}
+		if i := slices.IndexFunc(reports, func(report *types.Report) bool { return report.ID == id }); i >= 0 {
+			if !slices.ContainsFunc(res.Reports, func(cited *types.ReportCitation) bool { return cited.ID == id }) {
+				report := reports[i]
+				status := reportStatus(report)
+				res.Reports = append(res.Reports, &types.ReportCitation{
+					ID:        report.ID,
+					TaskID:    report.TaskID,
+					Creator:   report.Creator,
+					Report:    report.Report,
+					Status:    status,
+					CreatedAt: report.CreatedAt,
+				})
+			}
+		}
+	}
+	return res, nil
+}
+
+// readQuestion has the AI read the question as a filter of the workspace
+// tasks and the text to search by meaning
+func (s *taskQuestionService) readQuestion(ctx context.Context, question, workspace string, members []*types.User, now time.Time) (*types.TaskFilter, string, error) {
+	memberIDs := make([]string, 0, len(members))
+	var memberList strings.Builder
+	for _, member := range members {
+		memberIDs = append(memberIDs, member.ID)
+		fmt.Fprintf(&memberList, "- %s: %s (%s)\n", member.ID, member.FullName, member.WorkspaceRole)
+	}
+	schema, err := jsonschema.GenerateSchemaForType(taskQuestionOutput{})
+	if err != nil {
+		return nil, "", err
+	}
+	schema.Properties["statuses"].Items.Enum = types.TASK_BOARD_STATUSES
+	schema.Properties["assignee_ids"].Items.Enum = memberIDs
+
+	prompt := fmt.Sprintf("Bạn chuyển câu hỏi của người dùng về công việc và báo cáo của bộ phận %s thành bộ lọc tìm kiếm.\n"+
+		"Ngày hôm nay là %s, %s; hãy quy đổi các mốc thời gian tương đối (quý trước, tháng này...) ra ngày cụ thể.\n"+
+		"Chỉ điền các điều kiện được nêu trong câu hỏi, để trống các điều kiện khác.\n\n"+
+		"THÀNH VIÊN:\n%s",
+		workspace, taskDraftWeekdays[now.Weekday()], now.Format("2006-01-02"), memberList.String())
+	var output taskQuestionOutput
+	err = s.aiService.ChatStructured(ctx, []types.Message{
+		{Role: openai.ChatMessageRoleSystem, Content: prompt},
+		{Role: openai.ChatMessageRoleUser, Content: question},
+	}, "task_question", schema, &output)
+	if err != nil {
+		return nil, "", err
+	}
+
+	filter := &types.TaskFilter{
+		Workspace: workspace,
+		Overdue:   output.Overdue,
+	}
+	for _, status := range output.Statuses {
+		if slices.Contains(types.TASK_BOARD_STATUSES, status) && !slices.Contains(filter.Statuses, status) {
+			filter.Statuses = append(filter.Statuses, status)
+		}
+	}
+	for _, assignee := range output.AssigneeIDs {
+		if slices.Contains(memberIDs, assignee) && !slices.Contains(filter.Assignees, assignee) {
+			filter.Assignees = append(filter.Assignees, assignee)
+		}
+	}
+	for _, priority := range output.Priorities {
+		if priority >= types.TASK_PRIORITY_LOW && priority <= types.TASK_PRIORITY_URGENT && !slices.Contains(filter.Priorities, priority) {
+			filter.Priorities = append(filter.Priorities, priority)
+		}
+	}
+	location := now.Location()
+	filter.SpanFrom = taskQuestionDay(output.SpanFrom, location, false)
+	filter.SpanTo = taskQuestionDay(output.SpanTo, location, true)
+	filter.DeadlineFrom = taskQuestionDay(output.DeadlineFrom, location, false)
+	filter.DeadlineTo = taskQuestionDay(output.DeadlineTo, location, true)
+	filter.ReportFrom = taskQuestionDay(output.ReportFrom, location, false)
+	filter.ReportTo = taskQuestionDay(output.ReportTo, location, true)
+	return filter, strings.TrimSpace(output.Search), nil
+}
+
+// searchTasks returns the tasks of the workspace closest to the search,
+// only among taskIDs when set
+func (s *taskQuestionService) searchTasks(ctx context.Context, workspace string, taskIDs []string, search string, limit int) ([]*types.Task, error) {
+	found, err := s.taskVectorRepo.Search(ctx, workspace, taskIDs, search, limit)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(found))
+	for _, task := range found {
+		ids = append(ids, task.TaskID)
+	}
+	byID, err := s.taskRepo.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	tasks := make([]*types.Task, 0, len(ids))
+	for _, id := range ids {
+		// the vector of a deleted task may outlive it
+		if task, ok := byID[id]; ok && task.Workspace == workspace {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks, nil
+}
+
+// searchReports returns the reports of the workspace closest to the search,
+// only those of taskIDs when set and written in the report range of the
+// filter. Excerpts are the matching passages of the report attachments.
+func (s *taskQuestionService) searchReports(ctx context.Context, workspace string, taskIDs []string, search string, limit int, filter *types.TaskFilter) ([]*types.Report, map[string]string, error) {
+	// a report is split in several chunks
+	chunks, err := s.documentVectorRepo.SearchReportVector(ctx, workspace, taskIDs, []string{search}, limit*3)
+	if err != nil {
+		return nil, nil, err
+	}
+	chunkTaskIDs := make([]string, 0, len(chunks))
+	for _, chunk := range chunks {
+		if chunk.TaskID != "" && !slices.Contains(chunkTaskIDs, chunk.TaskID) {
+			chunkTaskIDs = append(chunkTaskIDs, chunk.TaskID)
+		}
+	}
+	reportsByTask, err := s.reportRepo.FindByTaskIDs(ctx, chunkTaskIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+	byID := make(map[string]*types.Report)
+	for _, reports := range reportsByTask {
+		for _, report := range reports {
+			byID[report.ID] = report
+		}
+	}
+	reports := make([]*types.Report, 0, limit)
+	excerpts := make(map[string]string)
+	for _, chunk := range chunks {
+		report, ok := byID[chunk.ReportID]
+		if !ok {
+			continue
+		}
+		if (filter.ReportFrom > 0 && report.CreatedAt < filter.ReportFrom) || (filter.ReportTo > 0 && report.CreatedAt > filter.ReportTo) {
+			continue
+		}
+		if _, ok := excerpts[report.ID]; !ok && !strings.Contains(report.Report, strings.TrimSpace(chunk.Content)) {
+			excerpts[report.ID] = chunk.Content
+		}
+		if slices.Contains(reports, report) || len(reports) == limit {
+			continue
+		}
+		reports = append(reports, report)
+	}
+	return reports, excerpts, nil
+}
+
+// withReportTasks adds the tasks of the reports missing from tasks, the
+// answer names the task a report is about
+func (s *taskQuestionService) withReportTasks(ctx context.Context, workspace string, tasks []*types.Task, reports []*types.Report) ([]*types.Task, error) {
+	missing := make([]string, 0)
+	for _, report := range reports {
+		if !slices.ContainsFunc(tasks, func(task *types.Task) bool { return task.ID == report.TaskID }) && !slices.Contains(missing, report.TaskID) {
+			missing = append(missing, report.TaskID)
+		}
+	}
+	if len(missing) == 0 {
+		return tasks, nil
+	}
+	byID, err := s.taskRepo.FindByIDs(ctx, missing)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range missing {
+		if task, ok := byID[id]; ok && task.Workspace == workspace {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks, nil
+}
+
+func taskQuestionFiltered(filter *types.TaskFilter) bool {
+	return len(filter.Statuses) > 0 || len(filter.Assignees) > 0 || len(filter.Priorities) > 0 || filter.Overdue ||
+		filter.SpanFrom > 0 || filter.SpanTo > 0 || filter.DeadlineFrom > 0 || filter.DeadlineTo > 0 ||
+		filter.ReportFrom > 0 || filter.ReportTo > 0
+}
+
+// taskQuestionDay returns the first or, with end, the last second of the
+// YYYY-MM-DD day, 0 when the day is empty or invalid
+func taskQuestionDay(day string, location *time.Location, end bool) int64 {
+	date, err := time.ParseInLocation("2006-01-02", strings.TrimSpace(day), location)
+	if err != nil {
+		return 0
+	}
+	if end {
+		return date.AddDate(0, 0, 1).Unix() - 1
+	}
+	return date.Unix()
+}
+
+func taskQuestionPrompt(workspace string, tasks []*types.Task, reports []*types.Report, excerpts map[string]string, names map[string]string, now time.Time) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Bạn là trợ lý của bộ phận %s. Hôm nay là %s. Hãy trả lời câu hỏi của người dùng bằng tiếng Việt, ngắn gọn, chỉ dựa trên các công việc và báo cáo dưới đây, không suy đoán; nếu dữ liệu không đủ để trả lời, hãy nói rõ.\n", workspace, now.Format("02/01/2006"))
+	b.WriteString("Mỗi khi nhắc tới một công việc hoặc một báo cáo, ghi mã của nó trong ngoặc vuông, ví dụ [66f1c2a9e4b0a1b2c3d4e5f6], để người đọc tra cứu.\n\n")
+
+	b.WriteString("CÔNG VIỆC:\n")
+	for _, task := range tasks {
+		fmt.Fprintf(&b, "[%s] %s | Trạng thái: %s | Tiến độ: %d%%", task.ID, task.Title, exportTaskStatusLabels[task.Status], task.Progress)
+		if task.StartAt > 0 {
+			fmt.Fprintf(&b, " | Bắt đầu: %s", time.Unix(task.StartAt, 0).In(now.Location()).Format("02/01/2006"))
+		}
+		if task.Deadline > 0 {
+			fmt.Fprintf(&b, " | Hạn: %s", time.Unix(task.Deadline, 0).In(now.Location()).Format("02/01/2006"))
+		}
+		if task.CompletedAt > 0 {
+			fmt.Fprintf(&b, " | Hoàn thành: %s", time.Unix(task.CompletedAt, 0).In(now.Location()).Format("02/01/2006"))
+		}
+		if name := names[task.Assignee]; name != "" {
+			fmt.Fprintf(&b, " | Phụ trách: %s", name)
+		}
+		if description := summaryTruncate(task.Description); description != "" {
+			fmt.Fprintf(&b, "\n  Mô tả: %s", description)
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\nBÁO CÁO:\n")
+	for _, report := range reports {
+		status := reportStatus(report)
+		fmt.Fprintf(&b, "[%s] Báo cáo công việc [%s] của %s ngày %s: %s | Đánh giá: %s",
+			report.ID, report.TaskID, names[report.Creator], time.Unix(report.CreatedAt, 0).In(now.Location()).Format("02/01/2006"),
+			summaryTruncate(report.Report), exportReportStatusLabels[status])
+		if report.Feedback != "" {
+			fmt.Fprintf(&b, " | Phản hồi: %s", summaryTruncate(report.Feedback))
+		}
+		if excerpt := excerpts[report.ID]; excerpt != "" {
+			fmt.Fprintf(&b, "\n  Trích tệp đính kèm: %s", summaryTruncate(excerpt))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
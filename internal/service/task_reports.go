@@ -0,0 +1,288 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/remiehneppo/be-task-management/types"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	REPORT_DECISION_APPROVE = "approve"
+	REPORT_DECISION_REJECT  = "reject"
+)
+
+var reportStatuses = []string{
+	types.REPORT_STATUS_PENDING,
+	types.REPORT_STATUS_APPROVED,
+	types.REPORT_STATUS_REJECTED,
+}
+
+// GetReports returns a page of the reports of the user workspace matching
+// filter, newest first
+func (s *taskService) GetReports(ctx context.Context, page types.PageRequest, filter types.ReportFilter) (items []*types.ReportResponse, total int64, nextCursor string, err error) {
+	userID, ok := ctx.Value("user_id").(string)
+	if !ok {
+		return nil, 0, "", types.ErrInvalidCredentials
+	}
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, 0, "", err
+	}
+	if filter.Status != "" && !slices.Contains(reportStatuses, filter.Status) {
+		return nil, 0, "", types.ErrInvalidReportStatus
+	}
+	filter.Workspace = user.Workspace
+	filter.Awaiting = false
+	filter.ExcludeCreator = ""
+	filter.TaskCreators = nil
+	return s.filterReports(ctx, page, filter)
+}
+
+// GetReportsAwaitingReview returns the review inbox of the user, the reports
+// waiting for a decision on the tasks they created or manage. A manager
+// holds task.manage and has a higher management level than the task
+// creator.
+func (s *taskService) GetReportsAwaitingReview(ctx context.Context, page types.PageRequest) (items []*types.ReportResponse, total int64, nextCursor string, err error) {
+	userID, ok := ctx.Value("user_id").(string)
+	if !ok {
+		return nil, 0, "", types.ErrInvalidCredentials
+	}
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, 0, "", err
+	}
+	manager, err := s.authorizer.Can(ctx, user, types.PERMISSION_TASK_MANAGE)
+	if err != nil {
+		return nil, 0, "", err
+	}
+	taskCreators := []string{userID}
+	if manager {
+		members, err := s.userRepo.FindByWorkspace(ctx, user.Workspace)
+		if err != nil {
+			return nil, 0, "", err
+		}
+		for _, member := range members {
+			if s.authorizer.Manages(user, member) {
+				taskCreators = append(taskCreators, member.ID)
+			}
+		}
+	}
+	return s.filterReports(ctx, page, types.ReportFilter{
+		Workspace:      user.Workspace,
+		Awaiting:       true,
+		ExcludeCreator: userID,
+		TaskCreators:   taskCreators,
+	})
+}
+
+// filterReports pages through the reports matching filter with their task
+// title and creator name
+func (s *taskService) filterReports(ctx context.Context, page types.PageRequest, filter types.ReportFilter) (items []*types.ReportResponse, total int64, nextCursor string, err error) {
+	reports, total, nextCursor, err := s.reportRepo.FilterReports(ctx, page, filter)
+	if err != nil {
+		return nil, 0, "", err
+	}
+	taskIDs := make([]string, 0, len(reports))
+	userIDs := make([]string, 0, len(reports))
+	for _, report := range reports {
+		if !slices.Contains(taskIDs, report.TaskID) {
+			taskIDs = append(taskIDs, report.TaskID)
+		}
+		if !slices.Contains(userIDs, report.Creator) {
+			userIDs = append(userIDs, report.Creator)
+		}
+	}
+	tasksMap, err := s.taskRepo.FindByIDs(ctx, taskIDs)
+	if err != nil {
+		return nil, 0, "", err
+	}
+	usersMap, err := s.userRepo.FindByIDs(ctx, userIDs)
+	if err != nil {
+		return nil, 0, "", err
+	}
+	items = make([]*types.ReportResponse, 0, len(reports))
+	for _, report := range reports {
+		item := &types.ReportResponse{
+			ID:         report.ID,
+			Creator:    report.Creator,
+			Report:     report.Report,
+			Feedback:   report.Feedback,
+			TaskID:     report.TaskID,
+			ReportFile: report.ReportFile,
+			Status:     reportStatus(report),
+			ReviewedBy: report.ReviewedBy,
+			ReviewedAt: report.ReviewedAt,
+			CreatedAt:  report.CreatedAt,
+			UpdatedAt:  report.UpdatedAt,
+		}
+		if task, ok := tasksMap[report.TaskID]; ok {
+			item.TaskTitle = task.Title
+		}
+		if creator, ok := usersMap[report.Creator]; ok {
+			item.CreatorName = creator.FullName
+		}
+		items = append(items, item)
+	}
+	return items, total, nextCursor, nil
+}
+
+// ReviewReport approves or rejects a report with feedback. The review moves
+// a task waiting in review forward: approval completes it, rejection sends
+// it back to doing.
+func (s *taskService) ReviewReport(ctx context.Context, req *types.ReviewReportRequest) error {
+	userID, ok := ctx.Value("user_id").(string)
+	if !ok {
+		return types.ErrInvalidCredentials
+	}
+	status := ""
+	switch req.Decision {
+	case REPORT_DECISION_APPROVE:
+		status = types.REPORT_STATUS_APPROVED
+	case REPORT_DECISION_REJECT:
+		if req.Feedback == "" {
+			return types.ErrReportRejectionFeedback
+		}
+		status = types.REPORT_STATUS_REJECTED
+	default:
+		return types.ErrInvalidReportDecision
+	}
+	report, err := s.reportRepo.FindByID(ctx, req.ReportID)
+	if err != nil {
+		return err
+	}
+	if report.Creator == userID {
+		return types.ErrReportSelfReview
+	}
+	if reportStatus(report) != types.REPORT_STATUS_PENDING {
+		return types.ErrReportNotPending
+	}
+	task, err := s.taskRepo.FindByID(ctx, report.TaskID)
+	if err != nil {
+		return err
+	}
+	reviewer, err := s.authorizer.Authorize(ctx, userID, types.PERMISSION_REPORT_REVIEW)
+	if err != nil {
+		return err
+	}
+	roles, err := s.taskActorRoles(ctx, task, reviewer)
+	if err != nil {
+		return err
+	}
+	if !roles[types.TASK_ACTOR_CREATOR] && !roles[types.TASK_ACTOR_MANAGER] {
+		return types.ErrReportReviewForbidden
+	}
+
+	// a blocked completion is refused before anything is stored
+	to := ""
+	if task.Status == types.TASK_STATUS_REVIEW {
+		to = types.TASK_STATUS_DOING
+		if status == types.REPORT_STATUS_APPROVED {
+			to = types.TASK_STATUS_COMPLETED
+		}
+		if err := s.checkTaskStatusChange(ctx, task, to, roles); err != nil {
+			return err
+		}
+	}
+
+	before := *report
+	report.Status = status
+	report.ReviewedBy = userID
+	report.ReviewedAt = time.Now().Unix()
+	if req.Feedback != "" {
+		report.Feedback = req.Feedback
+	}
+	report.ID = ""
+	if err := s.reportRepo.Update(ctx, req.ReportID, report); err != nil {
+		return err
+	}
+	// the report is stored first so that a failed save never moves the
+	// task, the report is restored when the task fails to move
+	if to != "" {
+		if err := s.changeTaskStatus(ctx, task, to, userID, roles); err != nil {
+			restored := before
+			restored.ID = ""
+			restored.Status = reportStatus(&before)
+			if restoreErr := s.reportRepo.Update(ctx, req.ReportID, &restored); restoreErr != nil {
+				logrus.Errorf("Failed to restore report %s after a failed review: %v", req.ReportID, restoreErr)
+			}
+			return err
+		}
+	}
+	s.recordActivity(ctx, &types.TaskActivity{
+		TaskID:    report.TaskID,
+		Workspace: task.Workspace,
+		ReportID:  req.ReportID,
+		Actor:     userID,
+		Action:    types.TASK_ACTIVITY_REVIEW,
+		Changes: []types.FieldChange{
+			{Field: "status", OldValue: reportStatus(&before), NewValue: status},
+			{Field: "feedback", OldValue: before.Feedback, NewValue: report.Feedback},
+		},
+	})
+	if req.Feedback != "" {
+		s.recordFeedbackComment(ctx, report, req.ReportID, userID, req.Feedback)
+	}
+	s.notifyReportReviewed(ctx, task, report, req.ReportID, userID)
+	return nil
+}
+
+// checkTaskStatusChange checks the task may move to status: the move
+// exists, one of the roles may make it and a completion is not blocked
+func (s *taskService) checkTaskStatusChange(ctx context.Context, task *types.Task, to string, roles map[string]bool) error {
+	if err := validateTaskTransition(task.Status, to, roles); err != nil {
+		return err
+	}
+	if to == types.TASK_STATUS_COMPLETED {
+		unfinished, err := s.unfinishedPredecessors(ctx, task)
+		if err != nil {
+			return err
+		}
+		if len(unfinished) > 0 {
+			return &types.TaskTransitionError{From: task.Status, To: to, Err: types.ErrTaskBlockedByDependency}
+		}
+	}
+	return nil
+}
+
+// changeTaskStatus moves the task along the state machine on behalf of the
+// actor, recording the transition like a status update would
+func (s *taskService) changeTaskStatus(ctx context.Context, task *types.Task, to, actor string, roles map[string]bool) error {
+	if err := s.checkTaskStatusChange(ctx, task, to, roles); err != nil {
+		return err
+	}
+	taskID := task.ID
+	before := *task
+	transition := &types.TaskStatusTransition{
+		TaskID:    taskID,
+		From:      task.Status,
+		To:        to,
+		Actor:     actor,
+		CreatedAt: time.Now().Unix(),
+	}
+	setTaskStatus(task, to)
+	task.ID = ""
+	task.UpdateAt = time.Now().Unix()
+	err := s.taskRepo.Update(ctx, taskID, task)
+	task.ID = taskID
+	if err != nil {
+		return err
+	}
+	if err := s.transitionRepo.Save(ctx, transition); err != nil {
+		return err
+	}
+	s.recordTaskUpdate(ctx, taskID, actor, &before, task)
+	s.notifyTaskUpdate(ctx, taskID, &before, task, actor)
+	return nil
+}
+
+// reportStatus returns the review status of the report, pending for
+// reports written before reviews existed
+func reportStatus(report *types.Report) string {
+	if report.Status == "" {
+		return types.REPORT_STATUS_PENDING
+	}
+	return report.Status
+}
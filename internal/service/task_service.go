@@ -2,161 +2,153 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
 	"time"
 
+	"github.com/remiehneppo/be-task-management/config"
 	"github.com/remiehneppo/be-task-management/internal/repository"
+	"github.com/remiehneppo/be-task-management/internal/worker"
 	"github.com/remiehneppo/be-task-management/types"
+	"github.com/sirupsen/logrus"
 )
 
 type TaskService interface {
-	GetTasksAssignedToUser(ctx context.Context, page, limit int64) (items []*types.TaskResponse, total int64, err error)
-	GetTaskCreatedByUser(ctx context.Context, page, limit int64) (items []*types.TaskResponse, total int64, err error)
+	GetTasksAssignedToUser(ctx context.Context, page types.PageRequest) (items []*types.TaskResponse, total int64, nextCursor string, err error)
+	GetTaskCreatedByUser(ctx context.Context, page types.PageRequest) (items []*types.TaskResponse, total int64, nextCursor string, err error)
 	GetTaskByID(ctx context.Context, id string) (*types.TaskResponse, error)
-	CreateTask(ctx context.Context, task *types.CreateTaskRequest) error
+	CreateTask(ctx context.Context, task *types.CreateTaskRequest) (*types.CreateTaskResponse, error)
 	UpdateTask(ctx context.Context, req types.UpdateTaskRequest) error
 	DeleteTask(ctx context.Context, id string) error
-	FilterTasks(ctx context.Context, page, limit int64, filter types.TaskFilter) (items []*types.TaskResponse, total int64, err error)
+	FilterTasks(ctx context.Context, page types.PageRequest, filter types.TaskFilter) (items []*types.TaskResponse, total int64, nextCursor string, err error)
 	AddReport(ctx context.Context, req types.CreateReportRequest) error
 	DeleteReport(ctx context.Context, req *types.DeleteReportRequest) error
 	UpdateReport(ctx context.Context, req *types.UpdateReportRequest) error
 	FeedbackReport(ctx context.Context, req *types.FeedbackRequest) error
+	GetTaskHistory(ctx context.Context, taskID string, page, limit int64) (items []*types.TaskActivityResponse, total int64, err error)
+	GetTaskSubtree(ctx context.Context, id string) (*types.TaskTreeNode, error)
+	GetTaskDependencyGraph(ctx context.Context, id string) (*types.TaskDependencyGraph, error)
+	AddTaskDependency(ctx context.Context, req *types.TaskDependencyRequest) error
+	RemoveTaskDependency(ctx context.Context, req *types.TaskDependencyRequest) error
+	GetTasksWatchedByUser(ctx context.Context, page types.PageRequest) (items []*types.TaskResponse, total int64, nextCursor string, err error)
+	WatchTask(ctx context.Context, taskID string) error
+	UnwatchTask(ctx context.Context, taskID string) error
+	CreateTaskTemplate(ctx context.Context, req *types.CreateTaskTemplateRequest) error
+	UpdateTaskTemplate(ctx context.Context, req *types.UpdateTaskTemplateRequest) error
+	DeleteTaskTemplate(ctx context.Context, id string) error
+	GetTaskTemplateByID(ctx context.Context, id string) (*types.TaskTemplateResponse, error)
+	GetTaskTemplates(ctx context.Context, page, limit int64) (items []*types.TaskTemplateResponse, total int64, err error)
+	GenerateRecurringTasksJob() worker.Do
+	GetTaskBoard(ctx context.Context, page types.PageRequest, filter types.TaskFilter) (*types.TaskBoard, error)
+	GetTaskCalendar(ctx context.Context, req *types.TaskCalendarRequest, filter types.TaskFilter) (*types.TaskCalendar, error)
+	GetCustomFields(ctx context.Context) ([]*types.CustomFieldDefinition, error)
+	CreateCustomField(ctx context.Context, req *types.CreateCustomFieldRequest) (*types.CustomFieldDefinition, error)
+	UpdateCustomField(ctx context.Context, req *types.UpdateCustomFieldRequest) (*types.CustomFieldDefinition, error)
+	DeleteCustomField(ctx context.Context, id string) error
+	GetReports(ctx context.Context, page types.PageRequest, filter types.ReportFilter) (items []*types.ReportResponse, total int64, nextCursor string, err error)
+	GetReportsAwaitingReview(ctx context.Context, page types.PageRequest) (items []*types.ReportResponse, total int64, nextCursor string, err error)
+	ReviewReport(ctx context.Context, req *types.ReviewReportRequest) error
+	GetTaskComments(ctx context.Context, taskID string, page types.PageRequest) (items []*types.CommentResponse, total int64, nextCursor string, err error)
+	GetReportComments(ctx context.Context, reportID string, page types.PageRequest) (items []*types.CommentResponse, total int64, nextCursor string, err error)
+	CreateComment(ctx context.Context, req *types.CreateCommentRequest) (*types.CommentResponse, error)
+	UpdateComment(ctx context.Context, req *types.UpdateCommentRequest) (*types.CommentResponse, error)
+	DeleteComment(ctx context.Context, id string) error
+	GetCommentHistory(ctx context.Context, id string) ([]*types.CommentEditResponse, error)
+	GetCommentAttachment(ctx context.Context, id string, index int) (filePath, fileName string, err error)
+	GetSimilarTasks(ctx context.Context, id string, limit int) ([]*types.SimilarTask, error)
+	IndexTasksJob() worker.Do
 }
 
 type taskService struct {
-	taskRepo   repository.TaskRepository
-	userRepo   repository.UserRepository
-	reportRepo repository.ReportRepository
+	taskRepo            repository.TaskRepository
+	userRepo            repository.UserRepository
+	reportRepo          repository.ReportRepository
+	pendingDocumentRepo repository.PendingDocumentRepository
+	transitionRepo      repository.TaskTransitionRepository
+	activityRepo        repository.TaskActivityRepository
+	templateRepo        repository.TaskTemplateRepository
+	customFieldRepo     repository.CustomFieldRepository
+	commentRepo         repository.CommentRepository
+	taskVectorRepo      repository.TaskVectorRepository
+	documentVectorRepo  repository.DocumentVectorRepository
+	fileService         FileService
+	lockService         LockService
+	notifier            Notifier
+	eventPublisher      TaskEventPublisher
+	authorizer          AuthorizationService
+	similarity          config.SimilarityConfig
 }
 
-func NewTaskService(taskRepo repository.TaskRepository, reportRepo repository.ReportRepository, userRepo repository.UserRepository) TaskService {
+func NewTaskService(
+	taskRepo repository.TaskRepository,
+	reportRepo repository.ReportRepository,
+	userRepo repository.UserRepository,
+	pendingDocumentRepo repository.PendingDocumentRepository,
+	transitionRepo repository.TaskTransitionRepository,
+	activityRepo repository.TaskActivityRepository,
+	templateRepo repository.TaskTemplateRepository,
+	customFieldRepo repository.CustomFieldRepository,
+	commentRepo repository.CommentRepository,
+	taskVectorRepo repository.TaskVectorRepository,
+	documentVectorRepo repository.DocumentVectorRepository,
+	fileService FileService,
+	lockService LockService,
+	notifier Notifier,
+	eventPublisher TaskEventPublisher,
+	authorizer AuthorizationService,
+	similarity config.SimilarityConfig,
+) TaskService {
 	return &taskService{
-		taskRepo:   taskRepo,
-		userRepo:   userRepo,
-		reportRepo: reportRepo,
+		taskRepo:            taskRepo,
+		userRepo:            userRepo,
+		reportRepo:          reportRepo,
+		pendingDocumentRepo: pendingDocumentRepo,
+		transitionRepo:      transitionRepo,
+		activityRepo:        activityRepo,
+		templateRepo:        templateRepo,
+		customFieldRepo:     customFieldRepo,
+		commentRepo:         commentRepo,
+		taskVectorRepo:      taskVectorRepo,
+		documentVectorRepo:  documentVectorRepo,
+		fileService:         fileService,
+		lockService:         lockService,
+		notifier:            notifier,
+		eventPublisher:      eventPublisher,
+		authorizer:          authorizer,
+		similarity:          similarity,
 	}
 }
 
-func (s *taskService) GetTasksAssignedToUser(ctx context.Context, page, limit int64) (items []*types.TaskResponse, total int64, err error) {
+func (s *taskService) GetTasksAssignedToUser(ctx context.Context, page types.PageRequest) (items []*types.TaskResponse, total int64, nextCursor string, err error) {
 	userID, ok := ctx.Value("user_id").(string)
 	if !ok {
-		return nil, 0, types.ErrInvalidCredentials
+		return nil, 0, "", types.ErrInvalidCredentials
 	}
 	user, err := s.userRepo.FindByID(ctx, userID)
 	if err != nil {
-		return nil, 0, err
+		return nil, 0, "", err
 	}
-
-	tasks, total, err := s.taskRepo.PaginateWithFilter(ctx, page, limit, types.TaskFilter{
+	return s.filterWorkspaceTasks(ctx, page, types.TaskFilter{
 		Assignee:  userID,
 		Workspace: user.Workspace,
 	})
-	if err != nil {
-		return nil, 0, err
-	}
-	userIDs := make([]string, 0)
-	userIDsMap := make(map[string]bool)
-	for _, task := range tasks {
-		if userIDsMap[task.Creator] {
-			continue
-		}
-		userIDs = append(userIDs, task.Creator)
-		userIDsMap[task.Creator] = true
-	}
-	usersMap, err := s.userRepo.FindByIDs(ctx, userIDs)
-	if err != nil {
-		return nil, 0, err
-	}
-
-	taskIDs := make([]string, 0)
-	for _, task := range tasks {
-		taskIDs = append(taskIDs, task.ID)
-	}
-	reportsMap, err := s.reportRepo.FindByTaskIDs(ctx, taskIDs)
-	if err != nil {
-		return nil, 0, err
-	}
-	tasksRes := make([]*types.TaskResponse, 0)
-	for _, task := range tasks {
-		creator, ok := usersMap[task.Creator]
-		if !ok {
-			return nil, 0, types.ErrInvalidUser
-		}
-		taskRes := s.convertTaskToTaskRes(task, creator.FullName, user.FullName)
-		reports := make([]*types.ReportResponse, 0)
-		for _, report := range reportsMap[task.ID] {
-			reportRes := &types.ReportResponse{
-				ID:       report.ID,
-				Creator:  report.Creator,
-				Report:   report.Report,
-				Feedback: report.Feedback,
-			}
-			reports = append(reports, reportRes)
-		}
-		taskRes.Reports = reports
-		tasksRes = append(tasksRes, taskRes)
-	}
-
-	return tasksRes, total, nil
 }
 
-func (s *taskService) GetTaskCreatedByUser(ctx context.Context, page, limit int64) (items []*types.TaskResponse, total int64, err error) {
+func (s *taskService) GetTaskCreatedByUser(ctx context.Context, page types.PageRequest) (items []*types.TaskResponse, total int64, nextCursor string, err error) {
 	userID, ok := ctx.Value("user_id").(string)
 	if !ok {
-		return nil, 0, types.ErrInvalidCredentials
+		return nil, 0, "", types.ErrInvalidCredentials
 	}
 	user, err := s.userRepo.FindByID(ctx, userID)
 	if err != nil {
-		return nil, 0, err
+		return nil, 0, "", err
 	}
-	tasks, total, err := s.taskRepo.PaginateWithFilter(ctx, page, limit, types.TaskFilter{
+	return s.filterWorkspaceTasks(ctx, page, types.TaskFilter{
 		Creator:   userID,
 		Workspace: user.Workspace,
 	})
-	if err != nil {
-		return nil, 0, err
-	}
-	assigneeIds := make([]string, 0)
-	assigneeIdsMap := make(map[string]bool)
-	for _, task := range tasks {
-		if assigneeIdsMap[task.Assignee] {
-			continue
-		}
-		assigneeIds = append(assigneeIds, task.Assignee)
-		assigneeIdsMap[task.Assignee] = true
-	}
-	usersMap, err := s.userRepo.FindByIDs(ctx, assigneeIds)
-	if err != nil {
-		return nil, 0, err
-	}
-	taskIDs := make([]string, 0)
-	for _, task := range tasks {
-		taskIDs = append(taskIDs, task.ID)
-	}
-	reportsMap, err := s.reportRepo.FindByTaskIDs(ctx, taskIDs)
-	if err != nil {
-		return nil, 0, err
-	}
-	tasksRes := make([]*types.TaskResponse, 0)
-	for _, task := range tasks {
-		assgnee, ok := usersMap[task.Assignee]
-		taskRes := s.convertTaskToTaskRes(task, user.FullName, assgnee.FullName)
-		if !ok {
-			return nil, 0, types.ErrInvalidUser
-		}
-		reports := make([]*types.ReportResponse, 0)
-		for _, report := range reportsMap[task.ID] {
-			reportRes := &types.ReportResponse{
-				ID:       report.ID,
-				Creator:  report.Creator,
-				Report:   report.Report,
-				Feedback: report.Feedback,
-			}
-			reports = append(reports, reportRes)
-		}
-		taskRes.Reports = reports
-		tasksRes = append(tasksRes, taskRes)
-	}
-	return tasksRes, total, nil
 }
 
 func (s *taskService) GetTaskByID(ctx context.Context, id string) (*types.TaskResponse, error) {
@@ -175,22 +167,14 @@ func (s *taskService) GetTaskByID(ctx context.Context, id string) (*types.TaskRe
 	if task.Workspace != user.Workspace {
 		return nil, types.ErrTaskNotInWorkspace
 	}
-	users, err := s.userRepo.FindByIDs(ctx, []string{task.Creator, task.Assignee})
+	users, err := s.userRepo.FindByIDs(ctx, taskUserIDs([]*types.Task{task}))
 	if err != nil {
 		return nil, err
 	}
-	if len(users) != 2 {
-		return nil, types.ErrInvalidUser
-	}
-	if _, ok := users[task.Creator]; !ok {
-		return nil, types.ErrInvalidUser
-	}
-	if _, ok := users[task.Assignee]; !ok {
-		return nil, types.ErrInvalidUser
+	taskRes, err := s.convertTaskToTaskRes(task, users)
+	if err != nil {
+		return nil, err
 	}
-	taskRes := s.convertTaskToTaskRes(task, users[task.Creator].FullName, users[task.Assignee].FullName)
-	taskRes.Creator = users[task.Creator].FullName
-	taskRes.Assignee = users[task.Assignee].FullName
 	reports, err := s.reportRepo.FindByTaskID(ctx, task.ID)
 	if err != nil {
 		return nil, err
@@ -209,41 +193,76 @@ func (s *taskService) GetTaskByID(ctx context.Context, id string) (*types.TaskRe
 	return taskRes, nil
 }
 
-func (s *taskService) CreateTask(ctx context.Context, req *types.CreateTaskRequest) error {
+func (s *taskService) CreateTask(ctx context.Context, req *types.CreateTaskRequest) (*types.CreateTaskResponse, error) {
 	userID, ok := ctx.Value("user_id").(string)
 	if !ok {
-		return types.ErrInvalidCredentials
+		return nil, types.ErrInvalidCredentials
 	}
-	creator, err := s.userRepo.FindByID(ctx, userID)
+	creator, err := s.authorizer.Authorize(ctx, userID, types.PERMISSION_TASK_CREATE)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	assignee, err := s.userRepo.FindByID(ctx, req.Assignee)
+	assignees, watchers, err := s.resolveTaskMembers(ctx, creator, req.Assignee, req.Assignees, req.Watchers, nil)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if req.ParentID != "" {
+		if err := s.validateParent(ctx, "", creator.Workspace, req.ParentID); err != nil {
+			return nil, err
+		}
+	}
+	dependsOn := uniqueIDs(req.DependsOn)
+	if err := s.validateDependencies(ctx, "", creator.Workspace, dependsOn); err != nil {
+		return nil, err
+	}
+	if err := validatePriority(req.Priority); err != nil {
+		return nil, err
 	}
-	err = s.validateCreateQuestPermission(ctx, creator, assignee)
+	customFields, err := s.applyCustomFields(ctx, creator.Workspace, nil, req.CustomFields, true)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	task := &types.Task{
-		Title:       req.Title,
-		Description: req.Description,
-		Workspace:   creator.Workspace,
-		StartAt:     req.StartAt,
-		Deadline:    req.Deadline,
-		Creator:     userID,
-		Assignee:    req.Assignee,
-		Status:      types.TASK_STATUS_OPEN,
-		CreateAt:    time.Now().Unix(),
-		UpdateAt:    time.Now().Unix(),
-		Progress:    0,
+		Title:        req.Title,
+		Description:  req.Description,
+		Workspace:    creator.Workspace,
+		StartAt:      req.StartAt,
+		Deadline:     req.Deadline,
+		Creator:      userID,
+		Assignee:     req.Assignee,
+		Assignees:    assignees,
+		Watchers:     watchers,
+		Status:       types.TASK_STATUS_OPEN,
+		CreateAt:     time.Now().Unix(),
+		UpdateAt:     time.Now().Unix(),
+		Progress:     0,
+		ParentID:     req.ParentID,
+		DependsOn:    dependsOn,
+		Priority:     req.Priority,
+		Tags:         normalizeTags(req.Tags),
+		CustomFields: customFields,
 	}
 	err = s.taskRepo.Save(ctx, task)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	return nil
+	s.recordActivity(ctx, &types.TaskActivity{
+		TaskID:    task.ID,
+		Workspace: task.Workspace,
+		Actor:     userID,
+		Action:    types.TASK_ACTIVITY_CREATE,
+		Changes:   diffTask(&types.Task{}, task),
+	})
+	s.indexTask(ctx, task)
+	res := &types.CreateTaskResponse{
+		ID:         task.ID,
+		Duplicates: s.findDuplicates(ctx, task),
+	}
+	s.notifyTaskAssigned(ctx, task, taskAssignees(task), userID)
+	if err := s.rollupProgress(ctx, task.ParentID, userID); err != nil {
+		return nil, err
+	}
+	return res, nil
 }
 
 func (s *taskService) UpdateTask(ctx context.Context, req types.UpdateTaskRequest) error {
@@ -251,59 +270,142 @@ func (s *taskService) UpdateTask(ctx context.Context, req types.UpdateTaskReques
 	if !ok {
 		return types.ErrInvalidCredentials
 	}
-	taskInDB, err := s.taskRepo.FindByID(ctx, req.TaskID)
+	task, err := s.taskRepo.FindByID(ctx, req.TaskID)
 	if err != nil {
 		return err
 	}
-	if taskInDB.Creator != userID || taskInDB.Assignee != userID {
-		return types.ErrTaskNotCreatorOrAssignee
-	}
-	task, err := s.taskRepo.FindByID(ctx, req.TaskID)
+	creator, err := s.userRepo.FindByID(ctx, userID)
 	if err != nil {
 		return err
 	}
-	creator, err := s.userRepo.FindByID(ctx, userID)
+	roles, err := s.taskActorRoles(ctx, task, creator)
 	if err != nil {
 		return err
 	}
+	if len(roles) == 0 {
+		return types.ErrTaskNotCreatorOrAssignee
+	}
+	before := *task
+	var transition *types.TaskStatusTransition
+	if req.Status != "" && req.Status != task.Status {
+		if err := validateTaskTransition(task.Status, req.Status, roles); err != nil {
+			return err
+		}
+		if req.Status == types.TASK_STATUS_DOING || req.Status == types.TASK_STATUS_REVIEW || req.Status == types.TASK_STATUS_COMPLETED {
+			unfinished, err := s.unfinishedPredecessors(ctx, task)
+			if err != nil {
+				return err
+			}
+			if len(unfinished) > 0 {
+				return &types.TaskTransitionError{From: task.Status, To: req.Status, Err: types.ErrTaskBlockedByDependency}
+			}
+		}
+		transition = &types.TaskStatusTransition{
+			TaskID:    req.TaskID,
+			From:      task.Status,
+			To:        req.Status,
+			Actor:     userID,
+			CreatedAt: time.Now().Unix(),
+		}
+		setTaskStatus(task, req.Status)
+	}
 	if req.Title != "" {
 		task.Title = req.Title
 	}
 	if req.Description != "" {
 		task.Description = req.Description
 	}
-	if req.Assignee != "" {
-		assignee, err := s.userRepo.FindByID(ctx, req.Assignee)
-		if err != nil {
-			return err
+	if req.Assignee != "" || req.Assignees != nil || req.Watchers != nil {
+		primary := task.Assignee
+		assignees := taskAssignees(task)
+		watchers := task.Watchers
+		if req.Assignee != "" {
+			primary = req.Assignee
+			// the new lead takes the place of the previous one
+			assignees = slices.DeleteFunc(slices.Clone(assignees), func(id string) bool {
+				return id == task.Assignee
+			})
+		}
+		if req.Assignees != nil {
+			assignees = req.Assignees
 		}
-		err = s.validateCreateQuestPermission(ctx, creator, assignee)
+		if req.Watchers != nil {
+			watchers = req.Watchers
+		}
+		known := append(slices.Clone(taskAssignees(task)), task.Watchers...)
+		assignees, watchers, err = s.resolveTaskMembers(ctx, creator, primary, assignees, watchers, known)
 		if err != nil {
 			return err
 		}
-		task.Assignee = req.Assignee
+		task.Assignee = primary
+		task.Assignees = assignees
+		task.Watchers = watchers
 	}
 	if req.Deadline != 0 {
 		task.Deadline = req.Deadline
 	}
-	if req.Status != "" {
-		task.Status = req.Status
-	}
 	if req.Progress != 0 {
 		if req.Progress < 0 || req.Progress > 100 {
 			return types.ErrInvalidProgress
 		}
+		hasSubtasks, err := s.hasSubtasks(ctx, req.TaskID)
+		if err != nil {
+			return err
+		}
+		if hasSubtasks {
+			return types.ErrTaskProgressRolledUp
+		}
 		task.Progress = req.Progress
 	}
 	if req.StartAt != 0 {
 		task.StartAt = req.StartAt
 	}
+	if req.ParentID != "" && req.ParentID != task.ParentID {
+		if err := s.validateParent(ctx, req.TaskID, task.Workspace, req.ParentID); err != nil {
+			return err
+		}
+		task.ParentID = req.ParentID
+	}
+	if req.Priority != nil {
+		if err := validatePriority(*req.Priority); err != nil {
+			return err
+		}
+		task.Priority = *req.Priority
+	}
+	if req.Tags != nil {
+		task.Tags = normalizeTags(req.Tags)
+	}
+	if req.CustomFields != nil {
+		customFields, err := s.applyCustomFields(ctx, task.Workspace, task.CustomFields, req.CustomFields, false)
+		if err != nil {
+			return err
+		}
+		task.CustomFields = customFields
+	}
 	task.ID = ""
+	task.UpdateAt = time.Now().Unix()
 	err = s.taskRepo.Update(ctx, req.TaskID, task)
 	if err != nil {
 		return err
 	}
-	return nil
+	if transition != nil {
+		if err := s.transitionRepo.Save(ctx, transition); err != nil {
+			return err
+		}
+	}
+	s.recordTaskUpdate(ctx, req.TaskID, userID, &before, task)
+	if task.Title != before.Title || task.Description != before.Description {
+		indexed := *task
+		indexed.ID = req.TaskID
+		s.indexTask(ctx, &indexed)
+	}
+	s.notifyTaskUpdate(ctx, req.TaskID, &before, task, userID)
+	if before.ParentID != task.ParentID {
+		if err := s.rollupProgress(ctx, before.ParentID, userID); err != nil {
+			return err
+		}
+	}
+	return s.rollupProgress(ctx, task.ParentID, userID)
 }
 
 func (s *taskService) DeleteTask(ctx context.Context, id string) error {
@@ -318,6 +420,13 @@ func (s *taskService) DeleteTask(ctx context.Context, id string) error {
 	if taskInDB.Creator != userID {
 		return types.ErrTaskNotCreator
 	}
+	hasSubtasks, err := s.hasSubtasks(ctx, id)
+	if err != nil {
+		return err
+	}
+	if hasSubtasks {
+		return types.ErrTaskHasSubtasks
+	}
 	err = s.taskRepo.Delete(ctx, id)
 	if err != nil {
 		return err
@@ -326,38 +435,68 @@ func (s *taskService) DeleteTask(ctx context.Context, id string) error {
 	if err != nil {
 		return err
 	}
-	return nil
+	err = s.deleteComments(ctx, id, "")
+	if err != nil {
+		return err
+	}
+	err = s.taskRepo.RemoveDependencyOnTask(ctx, id)
+	if err != nil {
+		return err
+	}
+	s.recordActivity(ctx, &types.TaskActivity{
+		TaskID:    id,
+		Workspace: taskInDB.Workspace,
+		Actor:     userID,
+		Action:    types.TASK_ACTIVITY_DELETE,
+		Changes:   diffTask(taskInDB, &types.Task{}),
+	})
+	s.removeTaskIndex(ctx, id)
+	s.removeReportIndex(ctx, id, "")
+	return s.rollupProgress(ctx, taskInDB.ParentID, userID)
 }
 
-func (s *taskService) FilterTasks(ctx context.Context, page, limit int64, filter types.TaskFilter) (items []*types.TaskResponse, total int64, err error) {
+func (s *taskService) FilterTasks(ctx context.Context, page types.PageRequest, filter types.TaskFilter) (items []*types.TaskResponse, total int64, nextCursor string, err error) {
 	userID, ok := ctx.Value("user_id").(string)
 	if !ok {
-		return nil, 0, types.ErrInvalidCredentials
+		return nil, 0, "", types.ErrInvalidCredentials
 	}
 	user, err := s.userRepo.FindByID(ctx, userID)
 	if err != nil {
-		return nil, 0, err
+		return nil, 0, "", err
 	}
 	filter.Workspace = user.Workspace
-	tasks, total, err := s.taskRepo.PaginateWithFilter(ctx, page, limit, filter)
-	if err != nil {
-		return nil, 0, err
+	if err := validateTaskFilter(&filter); err != nil {
+		return nil, 0, "", err
 	}
-	userIDs := make([]string, 0)
-	userIDsMap := make(map[string]bool)
-	for _, task := range tasks {
-		if !userIDsMap[task.Creator] {
-			userIDs = append(userIDs, task.Creator)
-			userIDsMap[task.Creator] = true
+	if err := s.resolveCustomFieldFilters(ctx, &filter); err != nil {
+		return nil, 0, "", err
+	}
+	return s.filterWorkspaceTasks(ctx, page, filter)
+}
+
+func validateTaskFilter(filter *types.TaskFilter) error {
+	for _, key := range filter.Sort {
+		if !slices.Contains(types.TASK_SORT_FIELDS, key.Field) {
+			return types.ErrInvalidSortField
 		}
-		if !userIDsMap[task.Assignee] {
-			userIDs = append(userIDs, task.Assignee)
-			userIDsMap[task.Assignee] = true
+		if key.Field == types.TASK_SORT_RELEVANCE && filter.Search == "" {
+			return types.ErrRelevanceWithoutSearch
 		}
 	}
-	usersMap, err := s.userRepo.FindByIDs(ctx, userIDs)
+	return nil
+}
+
+// filterWorkspaceTasks pages through the tasks matching filter with their
+// reports and the cursor of the next page, the filter must already be
+// scoped to the user workspace
+func (s *taskService) filterWorkspaceTasks(ctx context.Context, page types.PageRequest, filter types.TaskFilter) (items []*types.TaskResponse, total int64, nextCursor string, err error) {
+	tasks, total, nextCursor, err := s.taskRepo.PaginateWithFilter(ctx, page, filter)
 	if err != nil {
-		return nil, 0, err
+		return nil, 0, "", err
+	}
+	usersMap, err := s.userRepo.FindByIDs(ctx, taskUserIDs(tasks))
+	if err != nil {
+		return nil, 0, "", err
 	}
 	taskIDs := make([]string, 0)
 	for _, task := range tasks {
@@ -365,19 +504,14 @@ func (s *taskService) FilterTasks(ctx context.Context, page, limit int64, filter
 	}
 	reportsMap, err := s.reportRepo.FindByTaskIDs(ctx, taskIDs)
 	if err != nil {
-		return nil, 0, err
+		return nil, 0, "", err
 	}
 	tasksRes := make([]*types.TaskResponse, 0)
 	for _, task := range tasks {
-		creator, ok := usersMap[task.Creator]
-		if !ok {
-			return nil, 0, types.ErrInvalidUser
-		}
-		assignee, ok := usersMap[task.Assignee]
-		if !ok {
-			return nil, 0, types.ErrInvalidUser
+		taskRes, err := s.convertTaskToTaskRes(task, usersMap)
+		if err != nil {
+			return nil, 0, "", err
 		}
-		taskRes := s.convertTaskToTaskRes(task, creator.FullName, assignee.FullName)
 		reports := make([]*types.ReportResponse, 0)
 		for _, report := range reportsMap[task.ID] {
 			reportRes := &types.ReportResponse{
@@ -391,7 +525,7 @@ func (s *taskService) FilterTasks(ctx context.Context, page, limit int64, filter
 		taskRes.Reports = reports
 		tasksRes = append(tasksRes, taskRes)
 	}
-	return tasksRes, total, nil
+	return tasksRes, total, nextCursor, nil
 }
 
 func (s *taskService) AddReport(ctx context.Context, req types.CreateReportRequest) error {
@@ -399,11 +533,14 @@ func (s *taskService) AddReport(ctx context.Context, req types.CreateReportReque
 	if !ok {
 		return types.ErrInvalidCredentials
 	}
+	if _, err := s.authorizer.Authorize(ctx, userID, types.PERMISSION_REPORT_CREATE); err != nil {
+		return err
+	}
 	taskInDB, err := s.taskRepo.FindByID(ctx, req.TaskID)
 	if err != nil {
 		return err
 	}
-	if taskInDB.Assignee != userID {
+	if !isTaskAssignee(taskInDB, userID) {
 		return types.ErrTaskNotAssignee
 	}
 	reportObj := &types.Report{
@@ -412,13 +549,84 @@ func (s *taskService) AddReport(ctx context.Context, req types.CreateReportReque
 		Report:     req.Report,
 		CreatedAt:  time.Now().Unix(),
 		ReportFile: req.ReportFile,
+		Status:     types.REPORT_STATUS_PENDING,
 	}
 	err = s.reportRepo.Save(ctx, reportObj)
 	if err != nil {
 		return err
 	}
+	s.recordActivity(ctx, &types.TaskActivity{
+		TaskID:    req.TaskID,
+		Workspace: taskInDB.Workspace,
+		ReportID:  reportObj.ID,
+		Actor:     userID,
+		Action:    types.TASK_ACTIVITY_REPORT,
+		Changes: []types.FieldChange{
+			{Field: "report", OldValue: "", NewValue: reportObj.Report},
+			{Field: "report_file", OldValue: "", NewValue: reportObj.ReportFile},
+		},
+	})
+	s.notifyReportAdded(ctx, taskInDB, reportObj, userID)
+	// the report is saved, a failed ingestion must not fail the request
+	if err := s.queueReportIngestion(ctx, taskInDB, reportObj); err != nil {
+		logrus.Errorf("Failed to queue report %s for ingestion: %v", reportObj.ID, err)
+	}
+	return nil
+}
+
+// queueReportIngestion queues the report text and its PDF/DOCX attachment for
+// the knowledge base, tagged with the task, workspace and assignee
+func (s *taskService) queueReportIngestion(ctx context.Context, task *types.Task, report *types.Report) error {
+	title := fmt.Sprintf("Báo cáo: %s", task.Title)
+	pendingDocuments := []*types.PendingDocument{
+		{
+			DocumentName: fmt.Sprintf("report_%s", report.ID),
+			Title:        title,
+			Content:      report.Report,
+		},
+	}
+	ext := strings.ToLower(filepath.Ext(report.ReportFile))
+	if ext == ".pdf" || ext == ".docx" {
+		fileName := filepath.Base(report.ReportFile)
+		pendingDocuments = append(pendingDocuments, &types.PendingDocument{
+			DocumentPath: report.ReportFile,
+			DocumentName: fmt.Sprintf("report_%s_%s", report.ID, fileName),
+			Title:        fmt.Sprintf("%s - %s", title, fileName),
+			ToolUse:      "pdftotext",
+		})
+	}
+	for _, pendingDocument := range pendingDocuments {
+		pendingDocument.Source = types.DOCUMENT_SOURCE_REPORT
+		pendingDocument.Tags = []string{types.DOCUMENT_SOURCE_REPORT}
+		pendingDocument.TaskID = task.ID
+		pendingDocument.ReportID = report.ID
+		pendingDocument.Workspace = task.Workspace
+		pendingDocument.Assignee = task.Assignee
+		pendingDocument.CreatedAt = time.Now().Unix()
+		if err := s.pendingDocumentRepo.Save(ctx, pendingDocument); err != nil {
+			return err
+		}
+	}
 	return nil
 }
+
+// removeReportIndex removes the chunks of a report from the knowledge base,
+// of every report of the task when reportID is empty, and drops its queued
+// ingestion. Failures are logged, the report change is already saved.
+func (s *taskService) removeReportIndex(ctx context.Context, taskID, reportID string) {
+	if err := s.pendingDocumentRepo.RemoveReportDocuments(ctx, taskID, reportID); err != nil {
+		logrus.Errorf("Failed to remove queued documents of task %s report %s: %v", taskID, reportID, err)
+	}
+	err := s.documentVectorRepo.RemoveDocuments(ctx, &types.DocumentMetadata{
+		Source:   types.DOCUMENT_SOURCE_REPORT,
+		TaskID:   taskID,
+		ReportID: reportID,
+	})
+	if err != nil {
+		logrus.Errorf("Failed to remove index of task %s report %s: %v", taskID, reportID, err)
+	}
+}
+
 func (s *taskService) DeleteReport(ctx context.Context, req *types.DeleteReportRequest) error {
 	userID, ok := ctx.Value("user_id").(string)
 	if !ok {
@@ -431,10 +639,29 @@ func (s *taskService) DeleteReport(ctx context.Context, req *types.DeleteReportR
 	if reportInDB.Creator != userID {
 		return types.ErrReportNotCreator
 	}
+	task, err := s.taskRepo.FindByID(ctx, reportInDB.TaskID)
+	if err != nil {
+		return err
+	}
 	err = s.reportRepo.Delete(ctx, req.ReportID)
 	if err != nil {
 		return err
 	}
+	err = s.deleteComments(ctx, reportInDB.TaskID, req.ReportID)
+	if err != nil {
+		return err
+	}
+	s.recordActivity(ctx, &types.TaskActivity{
+		TaskID:    reportInDB.TaskID,
+		Workspace: task.Workspace,
+		ReportID:  req.ReportID,
+		Actor:     userID,
+		Action:    types.TASK_ACTIVITY_REPORT_DELETE,
+		Changes: []types.FieldChange{
+			{Field: "report", OldValue: reportInDB.Report, NewValue: ""},
+		},
+	})
+	s.removeReportIndex(ctx, reportInDB.TaskID, req.ReportID)
 	return nil
 }
 
@@ -450,20 +677,62 @@ func (s *taskService) UpdateReport(ctx context.Context, req *types.UpdateReportR
 	if reportInDB.Creator != userID {
 		return types.ErrReportNotCreator
 	}
+	task, err := s.taskRepo.FindByID(ctx, reportInDB.TaskID)
+	if err != nil {
+		return err
+	}
+	oldReport := reportInDB.Report
 	reportInDB.Report = req.Report
+	reportInDB.UpdatedAt = time.Now().Unix()
+	// a reworked rejected report goes back to the review queue
+	if reportInDB.Status == types.REPORT_STATUS_REJECTED {
+		reportInDB.Status = types.REPORT_STATUS_PENDING
+	}
 	reportInDB.ID = ""
 	err = s.reportRepo.Update(ctx, req.ReportID, reportInDB)
 	if err != nil {
 		return err
 	}
+	s.recordActivity(ctx, &types.TaskActivity{
+		TaskID:    reportInDB.TaskID,
+		Workspace: task.Workspace,
+		ReportID:  req.ReportID,
+		Actor:     userID,
+		Action:    types.TASK_ACTIVITY_REPORT_UPDATE,
+		Changes: []types.FieldChange{
+			{Field: "report", OldValue: oldReport, NewValue: req.Report},
+		},
+	})
+	// the knowledge base is re-indexed from the new text
+	reportInDB.ID = req.ReportID
+	s.removeReportIndex(ctx, reportInDB.TaskID, req.ReportID)
+	if err := s.queueReportIngestion(ctx, task, reportInDB); err != nil {
+		logrus.Errorf("Failed to queue report %s for ingestion: %v", req.ReportID, err)
+	}
 	return nil
 }
 
+// validateCreateQuestPermission checks the creator may put the member on a
+// task: themselves, or with task.assign a member of the workspace at most of
+// their management level
 func (s *taskService) validateCreateQuestPermission(ctx context.Context, creator, assignee *types.User) error {
+	if creator.ID == assignee.ID {
+		return nil
+	}
+	if assignee.Deactivated {
+		return types.ErrUserDeactivated
+	}
 	if creator.Workspace != assignee.Workspace {
 		return types.ErrQuestAssignNotWorkspaceMember
 	}
-	if types.MAPPING_ROLE_TO_MANAGEMENT_LEVEL[creator.WorkspaceRole] < types.MAPPING_ROLE_TO_MANAGEMENT_LEVEL[assignee.WorkspaceRole] {
+	allowed, err := s.authorizer.Can(ctx, creator, types.PERMISSION_TASK_ASSIGN)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return permissionError(types.PERMISSION_TASK_ASSIGN)
+	}
+	if s.authorizer.Manages(assignee, creator) {
 		return types.ErrQuestAssignNoPermission
 	}
 	return nil
@@ -474,11 +743,14 @@ func (s *taskService) ReportTaskById(ctx context.Context, id string, report stri
 	if !ok {
 		return types.ErrInvalidCredentials
 	}
+	if _, err := s.authorizer.Authorize(ctx, userID, types.PERMISSION_REPORT_CREATE); err != nil {
+		return err
+	}
 	taskInDB, err := s.taskRepo.FindByID(ctx, id)
 	if err != nil {
 		return err
 	}
-	if taskInDB.Assignee != userID {
+	if !isTaskAssignee(taskInDB, userID) {
 		return types.ErrTaskNotAssignee
 	}
 	reportObj := &types.Report{
@@ -486,6 +758,7 @@ func (s *taskService) ReportTaskById(ctx context.Context, id string, report stri
 		Creator:   userID,
 		Report:    report,
 		CreatedAt: time.Now().Unix(),
+		Status:    types.REPORT_STATUS_PENDING,
 	}
 	err = s.reportRepo.Save(ctx, reportObj)
 	if err != nil {
@@ -499,6 +772,10 @@ func (s *taskService) FeedbackReport(ctx context.Context, req *types.FeedbackReq
 	if !ok {
 		return types.ErrInvalidCredentials
 	}
+	reviewer, err := s.authorizer.Authorize(ctx, userID, types.PERMISSION_REPORT_FEEDBACK)
+	if err != nil {
+		return err
+	}
 	reportInDB, err := s.reportRepo.FindByID(ctx, req.ReportID)
 	if err != nil {
 		return err
@@ -506,27 +783,75 @@ func (s *taskService) FeedbackReport(ctx context.Context, req *types.FeedbackReq
 	if reportInDB.Creator == userID {
 		return types.ErrReportNotCreator
 	}
+	task, err := s.taskRepo.FindByID(ctx, reportInDB.TaskID)
+	if err != nil {
+		return err
+	}
+	if task.Workspace != reviewer.Workspace {
+		return types.ErrTaskNotInWorkspace
+	}
+	roles, err := s.taskActorRoles(ctx, task, reviewer)
+	if err != nil {
+		return err
+	}
+	if !roles[types.TASK_ACTOR_CREATOR] && !roles[types.TASK_ACTOR_MANAGER] {
+		return types.ErrReportFeedbackForbidden
+	}
+	oldFeedback := reportInDB.Feedback
 	reportInDB.Feedback = req.Feedback
 	reportInDB.ID = ""
 	err = s.reportRepo.Update(ctx, req.ReportID, reportInDB)
 	if err != nil {
 		return err
 	}
+	s.recordActivity(ctx, &types.TaskActivity{
+		TaskID:    reportInDB.TaskID,
+		Workspace: task.Workspace,
+		ReportID:  req.ReportID,
+		Actor:     userID,
+		Action:    types.TASK_ACTIVITY_FEEDBACK,
+		Changes: []types.FieldChange{
+			{Field: "feedback", OldValue: oldFeedback, NewValue: req.Feedback},
+		},
+	})
+	s.recordFeedbackComment(ctx, reportInDB, req.ReportID, userID, req.Feedback)
+	s.notifyReportFeedback(ctx, reportInDB, req.ReportID, userID)
 	return nil
 }
 
-func (s *taskService) convertTaskToTaskRes(task *types.Task, creator, assignee string) *types.TaskResponse {
+// convertTaskToTaskRes builds the response of a task, usersMap must hold at
+// least its creator and primary assignee
+func (s *taskService) convertTaskToTaskRes(task *types.Task, usersMap map[string]*types.User) (*types.TaskResponse, error) {
+	creator, ok := usersMap[task.Creator]
+	if !ok {
+		return nil, types.ErrInvalidUser
+	}
+	assignee, ok := usersMap[task.Assignee]
+	if !ok {
+		return nil, types.ErrInvalidUser
+	}
 	taskRes := &types.TaskResponse{
-		ID:          task.ID,
-		Title:       task.Title,
-		Description: task.Description,
-		Workspace:   task.Workspace,
-		Creator:     creator,
-		Deadline:    task.Deadline,
-		Assignee:    assignee,
-		Status:      task.Status,
-		CreateAt:    task.CreateAt,
-		UpdateAt:    task.UpdateAt,
-	}
-	return taskRes
+		ID:           task.ID,
+		Title:        task.Title,
+		Description:  task.Description,
+		Workspace:    task.Workspace,
+		Creator:      creator.FullName,
+		Deadline:     task.Deadline,
+		Assignee:     assignee.FullName,
+		Assignees:    taskMembers(taskAssignees(task), task.Assignee, usersMap),
+		Watchers:     taskMembers(task.Watchers, "", usersMap),
+		Status:       task.Status,
+		StartAt:      task.StartAt,
+		Progress:     task.Progress,
+		ParentID:     task.ParentID,
+		DependsOn:    task.DependsOn,
+		TemplateID:   task.TemplateID,
+		Priority:     task.Priority,
+		Tags:         task.Tags,
+		CustomFields: task.CustomFields,
+		CreateAt:     task.CreateAt,
+		UpdateAt:     task.UpdateAt,
+		CompletedAt:  task.CompletedAt,
+	}
+	return taskRes, nil
 }
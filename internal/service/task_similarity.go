@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/remiehneppo/be-task-management/internal/worker"
+	"github.com/remiehneppo/be-task-management/types"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	taskIndexJobLock = "task_index_job"
+	// taskIndexBatchSize is how many tasks the index job checks at once
+	taskIndexBatchSize = 100
+	// maxSimilarTasks bounds the limit of a similar task search
+	maxSimilarTasks = 50
+)
+
+func (s *taskService) GetSimilarTasks(ctx context.Context, id string, limit int) ([]*types.SimilarTask, error) {
+	userID, ok := ctx.Value("user_id").(string)
+	if !ok {
+		return nil, types.ErrInvalidCredentials
+	}
+	task, err := s.taskRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if task.Workspace != user.Workspace {
+		return nil, types.ErrTaskNotInWorkspace
+	}
+	if limit <= 0 {
+		limit = s.similarity.Limit
+	}
+	limit = min(limit, maxSimilarTasks)
+	// tasks created before the index or while it failed are indexed on demand
+	indexed, err := s.taskVectorRepo.HasTask(ctx, task.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !indexed {
+		if err := s.taskVectorRepo.SaveTask(ctx, task); err != nil {
+			return nil, err
+		}
+	}
+	return s.findSimilarTasks(ctx, task, s.similarity.SimilarThreshold, limit, false)
+}
+
+// IndexTasksJob indexes the tasks missing from the vector database: the
+// tasks created before it, imported or whose indexing failed
+func (s *taskService) IndexTasksJob() worker.Do {
+	return func() error {
+		ctx := context.Background()
+		lock, err := s.lockService.Lock(ctx, taskIndexJobLock, 30*time.Minute)
+		if err != nil {
+			return nil
+		}
+		defer func() {
+			if err := lock.Release(ctx); err != nil {
+				logrus.Errorf("Failed to release %s lock: %v", taskIndexJobLock, err)
+			}
+		}()
+		// every task is checked, vectors left by deleted tasks make counts
+		// unreliable
+		missing := 0
+		afterID := ""
+		for {
+			tasks, err := s.taskRepo.FindAfter(ctx, afterID, taskIndexBatchSize)
+			if err != nil {
+				return err
+			}
+			if len(tasks) == 0 {
+				break
+			}
+			ids := make([]string, 0, len(tasks))
+			for _, task := range tasks {
+				ids = append(ids, task.ID)
+			}
+			indexed, err := s.taskVectorRepo.IndexedTasks(ctx, ids)
+			if err != nil {
+				return err
+			}
+			for _, task := range tasks {
+				if !indexed[task.ID] {
+					missing++
+					s.indexTask(ctx, task)
+				}
+			}
+			afterID = tasks[len(tasks)-1].ID
+		}
+		if missing > 0 {
+			logrus.Infof("Indexed %d tasks missing from the vector database", missing)
+		}
+		return nil
+	}
+}
+
+// findDuplicates returns the open tasks of the workspace that look like the
+// task, a failed search only loses the warning
+func (s *taskService) findDuplicates(ctx context.Context, task *types.Task) []*types.SimilarTask {
+	duplicates, err := s.findSimilarTasks(ctx, task, s.similarity.DuplicateThreshold, s.similarity.Limit, true)
+	if err != nil {
+		logrus.Errorf("Failed to find duplicates of task %s: %v", task.ID, err)
+		return make([]*types.SimilarTask, 0)
+	}
+	return duplicates
+}
+
+// findSimilarTasks returns the tasks of the workspace at least as similar
+// to the task as threshold, most similar first
+func (s *taskService) findSimilarTasks(ctx context.Context, task *types.Task, threshold float32, limit int, unfinishedOnly bool) ([]*types.SimilarTask, error) {
+	searchLimit := limit
+	if unfinishedOnly {
+		// finished tasks are dropped after the search
+		searchLimit = limit * 4
+	}
+	candidates, err := s.taskVectorRepo.FindSimilar(ctx, task.ID, task.Workspace, threshold, searchLimit)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(candidates))
+	for _, candidate := range candidates {
+		ids = append(ids, candidate.TaskID)
+	}
+	tasks, err := s.taskRepo.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	similar := make([]*types.SimilarTask, 0, limit)
+	for _, candidate := range candidates {
+		if len(similar) == limit {
+			break
+		}
+		found, ok := tasks[candidate.TaskID]
+		// the vector of a deleted task may outlive it
+		if !ok || found.Workspace != task.Workspace {
+			continue
+		}
+		if unfinishedOnly && isTaskFinished(found.Status) {
+			continue
+		}
+		similar = append(similar, &types.SimilarTask{
+			ID:         found.ID,
+			Title:      found.Title,
+			Status:     found.Status,
+			Assignee:   found.Assignee,
+			Deadline:   found.Deadline,
+			Similarity: candidate.Similarity,
+		})
+	}
+	return similar, nil
+}
+
+// indexTask saves the vector of the task, IndexTasksJob retries the tasks
+// whose indexing failed
+func (s *taskService) indexTask(ctx context.Context, task *types.Task) {
+	if err := s.taskVectorRepo.SaveTask(ctx, task); err != nil {
+		logrus.Errorf("Failed to index task %s: %v", task.ID, err)
+	}
+}
+
+func (s *taskService) removeTaskIndex(ctx context.Context, taskID string) {
+	if err := s.taskVectorRepo.RemoveTask(ctx, taskID); err != nil {
+		logrus.Errorf("Failed to remove index of task %s: %v", taskID, err)
+	}
+}
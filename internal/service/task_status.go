@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/remiehneppo/be-task-management/types"
+)
+
+// taskStatusTransitions is the task state machine: for every status it lists
+// the statuses it may move to and the actors allowed to make that move.
+// close is terminal, a cancelled task can only be reopened.
+var taskStatusTransitions = map[string]map[string][]string{
+	types.TASK_STATUS_OPEN: {
+		types.TASK_STATUS_DOING:  {types.TASK_ACTOR_ASSIGNEE},
+		types.TASK_STATUS_CANCEL: {types.TASK_ACTOR_CREATOR, types.TASK_ACTOR_MANAGER},
+	},
+	types.TASK_STATUS_DOING: {
+		types.TASK_STATUS_REVIEW: {types.TASK_ACTOR_ASSIGNEE},
+		types.TASK_STATUS_CANCEL: {types.TASK_ACTOR_CREATOR, types.TASK_ACTOR_MANAGER},
+	},
+	types.TASK_STATUS_REVIEW: {
+		types.TASK_STATUS_COMPLETED: {types.TASK_ACTOR_CREATOR, types.TASK_ACTOR_MANAGER},
+		types.TASK_STATUS_DOING:     {types.TASK_ACTOR_CREATOR, types.TASK_ACTOR_MANAGER},
+		types.TASK_STATUS_CANCEL:    {types.TASK_ACTOR_CREATOR, types.TASK_ACTOR_MANAGER},
+	},
+	types.TASK_STATUS_COMPLETED: {
+		types.TASK_STATUS_CLOSE: {types.TASK_ACTOR_CREATOR, types.TASK_ACTOR_MANAGER},
+		types.TASK_STATUS_DOING: {types.TASK_ACTOR_CREATOR, types.TASK_ACTOR_MANAGER},
+	},
+	types.TASK_STATUS_CANCEL: {
+		types.TASK_STATUS_OPEN: {types.TASK_ACTOR_CREATOR, types.TASK_ACTOR_MANAGER},
+	},
+	types.TASK_STATUS_CLOSE: {},
+}
+
+func isValidTaskStatus(status string) bool {
+	_, ok := taskStatusTransitions[status]
+	return ok
+}
+
+// validateTaskTransition checks that the move from -> to exists in the state
+// machine and that one of the actor roles may perform it
+func validateTaskTransition(from, to string, roles map[string]bool) error {
+	if !isValidTaskStatus(to) {
+		return types.ErrInvalidTaskStatus
+	}
+	allowedActors, ok := taskStatusTransitions[from][to]
+	if !ok {
+		return &types.TaskTransitionError{From: from, To: to, Err: types.ErrTaskTransitionNotAllowed}
+	}
+	for _, actor := range allowedActors {
+		if roles[actor] {
+			return nil
+		}
+	}
+	return &types.TaskTransitionError{From: from, To: to, Err: types.ErrTaskTransitionForbidden}
+}
+
+// taskActorRoles returns the roles the user holds on the task. A manager is a
+// member of the task workspace holding task.manage with a higher management
+// level than the creator.
+func (s *taskService) taskActorRoles(ctx context.Context, task *types.Task, user *types.User) (map[string]bool, error) {
+	roles := make(map[string]bool)
+	if isTaskAssignee(task, user.ID) {
+		roles[types.TASK_ACTOR_ASSIGNEE] = true
+	}
+	if task.Creator == user.ID {
+		roles[types.TASK_ACTOR_CREATOR] = true
+		return roles, nil
+	}
+	if task.Workspace != user.Workspace {
+		return roles, nil
+	}
+	manager, err := s.authorizer.Can(ctx, user, types.PERMISSION_TASK_MANAGE)
+	if err != nil {
+		return nil, err
+	}
+	if !manager {
+		return roles, nil
+	}
+	creator, err := s.userRepo.FindByID(ctx, task.Creator)
+	if err != nil {
+		return nil, err
+	}
+	if s.authorizer.Manages(user, creator) {
+		roles[types.TASK_ACTOR_MANAGER] = true
+	}
+	return roles, nil
+}
+
+// setTaskStatus moves the task to status and keeps its completion time for
+// the analytics, closing the task keeps it and reopening the task clears it
+func setTaskStatus(task *types.Task, status string) {
+	task.Status = status
+	switch status {
+	case types.TASK_STATUS_COMPLETED:
+		task.CompletedAt = time.Now().Unix()
+	case types.TASK_STATUS_DOING:
+		task.CompletedAt = 0
+	}
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/remiehneppo/be-task-management/types"
+)
+
+func TestValidateTaskTransition(t *testing.T) {
+	assignee := map[string]bool{types.TASK_ACTOR_ASSIGNEE: true}
+	creator := map[string]bool{types.TASK_ACTOR_CREATOR: true}
+	manager := map[string]bool{types.TASK_ACTOR_MANAGER: true}
+	none := map[string]bool{}
+
+	tests := []struct {
+		name    string
+		from    string
+		to      string
+		roles   map[string]bool
+		wantErr error
+	}{
+		{name: "assignee starts", from: types.TASK_STATUS_OPEN, to: types.TASK_STATUS_DOING, roles: assignee},
+		{name: "assignee submits", from: types.TASK_STATUS_DOING, to: types.TASK_STATUS_REVIEW, roles: assignee},
+		{name: "creator completes", from: types.TASK_STATUS_REVIEW, to: types.TASK_STATUS_COMPLETED, roles: creator},
+		{name: "manager sends back", from: types.TASK_STATUS_REVIEW, to: types.TASK_STATUS_DOING, roles: manager},
+		{name: "creator closes", from: types.TASK_STATUS_COMPLETED, to: types.TASK_STATUS_CLOSE, roles: creator},
+		{name: "manager reopens", from: types.TASK_STATUS_COMPLETED, to: types.TASK_STATUS_DOING, roles: manager},
+		{name: "creator restores", from: types.TASK_STATUS_CANCEL, to: types.TASK_STATUS_OPEN, roles: creator},
+		{name: "either role is enough", from: types.TASK_STATUS_OPEN, to: types.TASK_STATUS_CANCEL, roles: map[string]bool{types.TASK_ACTOR_ASSIGNEE: true, types.TASK_ACTOR_CREATOR: true}},
+		{name: "unknown status", from: types.TASK_STATUS_OPEN, to: "archived", roles: creator, wantErr: types.ErrInvalidTaskStatus},
+		{name: "skipping review", from: types.TASK_STATUS_DOING, to: types.TASK_STATUS_COMPLETED, roles: creator, wantErr: types.ErrTaskTransitionNotAllowed},
+		{name: "close is terminal", from: types.TASK_STATUS_CLOSE, to: types.TASK_STATUS_DOING, roles: creator, wantErr: types.ErrTaskTransitionNotAllowed},
+		{name: "cancelled only reopens", from: types.TASK_STATUS_CANCEL, to: types.TASK_STATUS_DOING, roles: assignee, wantErr: types.ErrTaskTransitionNotAllowed},
+		{name: "assignee cannot complete", from: types.TASK_STATUS_REVIEW, to: types.TASK_STATUS_COMPLETED, roles: assignee, wantErr: types.ErrTaskTransitionForbidden},
+		{name: "creator cannot start", from: types.TASK_STATUS_OPEN, to: types.TASK_STATUS_DOING, roles: creator, wantErr: types.ErrTaskTransitionForbidden},
+		{name: "no role", from: types.TASK_STATUS_DOING, to: types.TASK_STATUS_CANCEL, roles: none, wantErr: types.ErrTaskTransitionForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateTaskTransition(tt.from, tt.to, tt.roles)
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatalf("validateTaskTransition(%s, %s) = %v, want nil", tt.from, tt.to, err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("validateTaskTransition(%s, %s) = %v, want %v", tt.from, tt.to, err, tt.wantErr)
+			}
+			var transitionErr *types.TaskTransitionError
+			if tt.wantErr != types.ErrInvalidTaskStatus && !errors.As(err, &transitionErr) {
+				t.Errorf("validateTaskTransition(%s, %s) = %T, want *types.TaskTransitionError", tt.from, tt.to, err)
+			}
+		})
+	}
+}
+
+func TestSetTaskStatusKeepsCompletion(t *testing.T) {
+	task := &types.Task{Status: types.TASK_STATUS_REVIEW}
+	setTaskStatus(task, types.TASK_STATUS_COMPLETED)
+	completedAt := task.CompletedAt
+	if completedAt == 0 {
+		t.Fatal("completing the task did not record its completion time")
+	}
+	setTaskStatus(task, types.TASK_STATUS_CLOSE)
+	if task.CompletedAt != completedAt {
+		t.Errorf("closing the task changed its completion time to %d, want %d", task.CompletedAt, completedAt)
+	}
+	task.Status = types.TASK_STATUS_COMPLETED
+	setTaskStatus(task, types.TASK_STATUS_DOING)
+	if task.CompletedAt != 0 {
+		t.Errorf("reopening the task kept its completion time %d", task.CompletedAt)
+	}
+}
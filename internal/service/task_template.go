@@ -0,0 +1,514 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/remiehneppo/be-task-management/internal/worker"
+	"github.com/remiehneppo/be-task-management/types"
+	"github.com/robfig/cron/v3"
+	"github.com/sirupsen/logrus"
+	"github.com/teambition/rrule-go"
+)
+
+const (
+	taskTemplateJobLock = "task_template_job"
+	// defaultGenerateAhead is used when a template does not set how long
+	// before an occurrence its task is created
+	defaultGenerateAhead = int64(7 * 24 * 60 * 60)
+	maxGenerateAhead     = int64(90 * 24 * 60 * 60)
+	// maxOccurrencesPerRun bounds the work done for a single template
+	maxOccurrencesPerRun = 100
+)
+
+// recurrence yields the occurrences of a template, Next returns the zero time
+// once the recurrence is exhausted
+type recurrence interface {
+	Next(after time.Time) time.Time
+}
+
+type cronRecurrence struct {
+	schedule cron.Schedule
+	location *time.Location
+}
+
+func (r *cronRecurrence) Next(after time.Time) time.Time {
+	return r.schedule.Next(after.In(r.location))
+}
+
+type rruleRecurrence struct {
+	rule *rrule.RRule
+}
+
+func (r *rruleRecurrence) Next(after time.Time) time.Time {
+	return r.rule.After(after, false)
+}
+
+// parseRecurrence accepts a standard 5 field cron expression or an RRULE,
+// with or without the RRULE: prefix. The RRULE DTSTART is the template start.
+func parseRecurrence(expr, timezone string, startAt int64) (recurrence, error) {
+	location := time.Local
+	if timezone != "" {
+		loc, err := time.LoadLocation(timezone)
+		if err != nil {
+			return nil, types.ErrInvalidTimezone
+		}
+		location = loc
+	}
+	expr = strings.TrimSpace(expr)
+	if strings.Contains(strings.ToUpper(expr), "FREQ=") {
+		option, err := rrule.StrToROptionInLocation(expr, location)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", types.ErrInvalidRecurrence, err)
+		}
+		option.Dtstart = time.Unix(startAt, 0).In(location)
+		rule, err := rrule.NewRRule(*option)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", types.ErrInvalidRecurrence, err)
+		}
+		return &rruleRecurrence{rule: rule}, nil
+	}
+	schedule, err := cron.ParseStandard(expr)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", types.ErrInvalidRecurrence, err)
+	}
+	return &cronRecurrence{schedule: schedule, location: location}, nil
+}
+
+func (s *taskService) CreateTaskTemplate(ctx context.Context, req *types.CreateTaskTemplateRequest) error {
+	userID, ok := ctx.Value("user_id").(string)
+	if !ok {
+		return types.ErrInvalidCredentials
+	}
+	creator, err := s.authorizer.Authorize(ctx, userID, types.PERMISSION_TASK_TEMPLATE)
+	if err != nil {
+		return err
+	}
+	assignees, watchers, err := s.resolveTaskMembers(ctx, creator, req.Assignee, req.Assignees, req.Watchers, nil)
+	if err != nil {
+		return err
+	}
+	template := &types.TaskTemplate{
+		Title:         req.Title,
+		Description:   req.Description,
+		Workspace:     creator.Workspace,
+		Creator:       userID,
+		Assignee:      req.Assignee,
+		Assignees:     assignees,
+		Watchers:      watchers,
+		Recurrence:    req.Recurrence,
+		Timezone:      req.Timezone,
+		StartAt:       req.StartAt,
+		EndAt:         req.EndAt,
+		Duration:      req.Duration,
+		GenerateAhead: req.GenerateAhead,
+		OverlapPolicy: req.OverlapPolicy,
+		Active:        true,
+		CreatedAt:     time.Now().Unix(),
+		UpdatedAt:     time.Now().Unix(),
+	}
+	if err := validateTaskTemplate(template); err != nil {
+		return err
+	}
+	if err := s.templateRepo.Save(ctx, template); err != nil {
+		return err
+	}
+	// the occurrences due soon are created now, the job takes the rest. The
+	// job lock keeps a concurrent run from creating them too, it is tried
+	// once and while a run holds it the next run creates them.
+	lock, err := s.lockService.TryLock(ctx, taskTemplateJobLock, time.Minute)
+	if err != nil {
+		logrus.Infof("Leaving the tasks of template %s to the job: %v", template.ID, err)
+		return nil
+	}
+	defer func() {
+		if err := lock.Release(ctx); err != nil {
+			logrus.Errorf("Failed to release %s lock: %v", taskTemplateJobLock, err)
+		}
+	}()
+	if err := s.generateTemplateTasks(ctx, template, time.Now()); err != nil {
+		logrus.Errorf("Failed to generate tasks for template %s: %v", template.ID, err)
+	}
+	return nil
+}
+
+func (s *taskService) UpdateTaskTemplate(ctx context.Context, req *types.UpdateTaskTemplateRequest) error {
+	user, template, err := s.findTaskTemplateForUpdate(ctx, req.TemplateID)
+	if err != nil {
+		return err
+	}
+	if req.Title != "" {
+		template.Title = req.Title
+	}
+	if req.Description != "" {
+		template.Description = req.Description
+	}
+	if req.Assignee != "" || req.Assignees != nil || req.Watchers != nil {
+		primary := template.Assignee
+		assignees := template.Assignees
+		watchers := template.Watchers
+		if req.Assignee != "" {
+			primary = req.Assignee
+		}
+		if req.Assignees != nil {
+			assignees = req.Assignees
+		}
+		if req.Watchers != nil {
+			watchers = req.Watchers
+		}
+		known := append(append([]string{}, template.Assignees...), template.Watchers...)
+		assignees, watchers, err = s.resolveTaskMembers(ctx, user, primary, assignees, watchers, known)
+		if err != nil {
+			return err
+		}
+		template.Assignee = primary
+		template.Assignees = assignees
+		template.Watchers = watchers
+	}
+	if req.Recurrence != "" {
+		template.Recurrence = req.Recurrence
+	}
+	if req.Timezone != "" {
+		template.Timezone = req.Timezone
+	}
+	if req.EndAt != 0 {
+		template.EndAt = req.EndAt
+	}
+	if req.Duration != 0 {
+		template.Duration = req.Duration
+	}
+	if req.GenerateAhead != 0 {
+		template.GenerateAhead = req.GenerateAhead
+	}
+	if req.OverlapPolicy != "" {
+		template.OverlapPolicy = req.OverlapPolicy
+	}
+	if req.Active != nil {
+		template.Active = *req.Active
+	}
+	if err := validateTaskTemplate(template); err != nil {
+		return err
+	}
+	templateID := template.ID
+	template.ID = ""
+	template.UpdatedAt = time.Now().Unix()
+	return s.templateRepo.Update(ctx, templateID, template)
+}
+
+// DeleteTaskTemplate stops the recurrence, tasks already generated are kept
+func (s *taskService) DeleteTaskTemplate(ctx context.Context, id string) error {
+	_, template, err := s.findTaskTemplateForUpdate(ctx, id)
+	if err != nil {
+		return err
+	}
+	return s.templateRepo.Delete(ctx, template.ID)
+}
+
+func (s *taskService) GetTaskTemplateByID(ctx context.Context, id string) (*types.TaskTemplateResponse, error) {
+	userID, ok := ctx.Value("user_id").(string)
+	if !ok {
+		return nil, types.ErrInvalidCredentials
+	}
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	template, err := s.templateRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if template.Workspace != user.Workspace {
+		return nil, types.ErrTaskTemplateNotInWorkspace
+	}
+	templatesRes, err := s.toTaskTemplateResponses(ctx, []*types.TaskTemplate{template})
+	if err != nil {
+		return nil, err
+	}
+	return templatesRes[0], nil
+}
+
+func (s *taskService) GetTaskTemplates(ctx context.Context, page, limit int64) (items []*types.TaskTemplateResponse, total int64, err error) {
+	userID, ok := ctx.Value("user_id").(string)
+	if !ok {
+		return nil, 0, types.ErrInvalidCredentials
+	}
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, 0, err
+	}
+	templates, total, err := s.templateRepo.PaginateByWorkspace(ctx, user.Workspace, page, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	items, err = s.toTaskTemplateResponses(ctx, templates)
+	if err != nil {
+		return nil, 0, err
+	}
+	return items, total, nil
+}
+
+// GenerateRecurringTasksJob creates the tasks of every active template whose
+// occurrences fall within the template generation window
+func (s *taskService) GenerateRecurringTasksJob() worker.Do {
+	return func() error {
+		logrus.Info("Generating recurring tasks...")
+		ctx := context.Background()
+		// several instances may run the job, only one generates at a time
+		lock, err := s.lockService.Lock(ctx, taskTemplateJobLock, 10*time.Minute)
+		if err != nil {
+			return nil
+		}
+		defer func() {
+			if err := lock.Release(ctx); err != nil {
+				logrus.Errorf("Failed to release %s lock: %v", taskTemplateJobLock, err)
+			}
+		}()
+		templates, err := s.templateRepo.FindActive(ctx)
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		for _, template := range templates {
+			if err := s.generateTemplateTasks(ctx, template, now); err != nil {
+				logrus.Errorf("Failed to generate tasks for template %s: %v", template.ID, err)
+			}
+		}
+		return nil
+	}
+}
+
+// generateTemplateTasks walks the occurrences following the last one handled
+// up to the generation window. An occurrence whose deadline has already
+// passed, because the job did not run in time, is skipped rather than created
+// late, and so is one overlapping an unfinished task of the template when the
+// overlap policy is skip.
+func (s *taskService) generateTemplateTasks(ctx context.Context, template *types.TaskTemplate, now time.Time) error {
+	if !template.Active {
+		return nil
+	}
+	rec, err := parseRecurrence(template.Recurrence, template.Timezone, template.StartAt)
+	if err != nil {
+		return err
+	}
+	generateAhead := template.GenerateAhead
+	if generateAhead == 0 {
+		generateAhead = defaultGenerateAhead
+	}
+	horizon := now.Add(time.Duration(generateAhead) * time.Second)
+	after := templateCursor(template)
+	lastOccurrence, skipped := template.LastOccurrence, template.Skipped
+	var generateErr error
+	for i := 0; i < maxOccurrencesPerRun; i++ {
+		occurrence := rec.Next(after)
+		if occurrence.IsZero() || occurrence.After(horizon) {
+			break
+		}
+		if template.EndAt != 0 && occurrence.Unix() > template.EndAt {
+			break
+		}
+		after = occurrence
+		created, err := s.createTemplateOccurrence(ctx, template, occurrence, now)
+		if err != nil {
+			// retry from this occurrence on the next run
+			generateErr = err
+			break
+		}
+		if !created {
+			skipped++
+		}
+		lastOccurrence = occurrence.Unix()
+	}
+	if lastOccurrence == template.LastOccurrence {
+		return generateErr
+	}
+	// a targeted update, the template may have been edited meanwhile
+	if err := s.templateRepo.UpdateOccurrences(ctx, template.ID, lastOccurrence, skipped); err != nil {
+		return err
+	}
+	template.LastOccurrence = lastOccurrence
+	template.Skipped = skipped
+	return generateErr
+}
+
+// templateCursor returns the time after which the next occurrence is looked
+// up. Occurrences that ended before the template was created are ignored.
+func templateCursor(template *types.TaskTemplate) time.Time {
+	if template.LastOccurrence != 0 {
+		return time.Unix(template.LastOccurrence, 0)
+	}
+	return time.Unix(max(template.StartAt-1, template.CreatedAt-template.Duration), 0)
+}
+
+// createTemplateOccurrence creates the task of one occurrence, it reports
+// false when the occurrence is skipped
+func (s *taskService) createTemplateOccurrence(ctx context.Context, template *types.TaskTemplate, occurrence, now time.Time) (bool, error) {
+	deadline := occurrence.Add(time.Duration(template.Duration) * time.Second)
+	if deadline.Before(now) {
+		logrus.Infof("Skipping missed occurrence %s of template %s", occurrence.Format(time.RFC3339), template.ID)
+		return false, nil
+	}
+	count, err := s.taskRepo.CountByTemplateOccurrence(ctx, template.ID, occurrence.Unix())
+	if err != nil {
+		return false, err
+	}
+	if count > 0 {
+		// already generated, the previous run failed before saving the template
+		return true, nil
+	}
+	if template.OverlapPolicy == types.RECURRENCE_OVERLAP_SKIP {
+		unfinished, err := s.taskRepo.FindUnfinishedByTemplateID(ctx, template.ID)
+		if err != nil {
+			return false, err
+		}
+		for _, task := range unfinished {
+			if task.Deadline > occurrence.Unix() {
+				logrus.Infof("Skipping occurrence %s of template %s overlapping task %s", occurrence.Format(time.RFC3339), template.ID, task.ID)
+				return false, nil
+			}
+		}
+	}
+	task := &types.Task{
+		Title:        template.Title,
+		Description:  template.Description,
+		Workspace:    template.Workspace,
+		StartAt:      occurrence.Unix(),
+		Deadline:     deadline.Unix(),
+		Creator:      template.Creator,
+		Assignee:     template.Assignee,
+		Assignees:    template.Assignees,
+		Watchers:     template.Watchers,
+		Status:       types.TASK_STATUS_OPEN,
+		CreateAt:     now.Unix(),
+		UpdateAt:     now.Unix(),
+		Progress:     0,
+		TemplateID:   template.ID,
+		OccurrenceAt: occurrence.Unix(),
+	}
+	if err := s.taskRepo.Save(ctx, task); err != nil {
+		return false, err
+	}
+	s.recordActivity(ctx, &types.TaskActivity{
+		TaskID:    task.ID,
+		Workspace: task.Workspace,
+		Actor:     template.Creator,
+		Action:    types.TASK_ACTIVITY_CREATE,
+		Changes:   diffTask(&types.Task{}, task),
+	})
+	s.indexTask(ctx, task)
+	s.notifyTaskAssigned(ctx, task, taskAssignees(task), "")
+	return true, nil
+}
+
+func validateTaskTemplate(template *types.TaskTemplate) error {
+	if _, err := parseRecurrence(template.Recurrence, template.Timezone, template.StartAt); err != nil {
+		return err
+	}
+	if template.Duration <= 0 {
+		return types.ErrInvalidTemplateDuration
+	}
+	if template.GenerateAhead < 0 || template.GenerateAhead > maxGenerateAhead {
+		return types.ErrInvalidTemplateDuration
+	}
+	if template.OverlapPolicy == "" {
+		template.OverlapPolicy = types.RECURRENCE_OVERLAP_ALLOW
+	}
+	if template.OverlapPolicy != types.RECURRENCE_OVERLAP_ALLOW && template.OverlapPolicy != types.RECURRENCE_OVERLAP_SKIP {
+		return types.ErrInvalidOverlapPolicy
+	}
+	return nil
+}
+
+// findTaskTemplateForUpdate loads a template the current user may change,
+// its creator or a higher management level of the same workspace
+func (s *taskService) findTaskTemplateForUpdate(ctx context.Context, id string) (*types.User, *types.TaskTemplate, error) {
+	userID, ok := ctx.Value("user_id").(string)
+	if !ok {
+		return nil, nil, types.ErrInvalidCredentials
+	}
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	template, err := s.templateRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if template.Workspace != user.Workspace {
+		return nil, nil, types.ErrTaskTemplateNotInWorkspace
+	}
+	if template.Creator == userID {
+		return user, template, nil
+	}
+	manager, err := s.authorizer.Can(ctx, user, types.PERMISSION_TASK_MANAGE)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !manager {
+		return nil, nil, types.ErrTaskTemplateNotCreator
+	}
+	creator, err := s.userRepo.FindByID(ctx, template.Creator)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !s.authorizer.Manages(user, creator) {
+		return nil, nil, types.ErrTaskTemplateNotCreator
+	}
+	return user, template, nil
+}
+
+func (s *taskService) toTaskTemplateResponses(ctx context.Context, templates []*types.TaskTemplate) ([]*types.TaskTemplateResponse, error) {
+	userIDs := make([]string, 0)
+	for _, template := range templates {
+		userIDs = append(userIDs, template.Creator, template.Assignee)
+		userIDs = append(userIDs, template.Assignees...)
+		userIDs = append(userIDs, template.Watchers...)
+	}
+	usersMap, err := s.userRepo.FindByIDs(ctx, uniqueIDs(userIDs))
+	if err != nil {
+		return nil, err
+	}
+	templatesRes := make([]*types.TaskTemplateResponse, 0, len(templates))
+	for _, template := range templates {
+		creator, ok := usersMap[template.Creator]
+		if !ok {
+			return nil, types.ErrInvalidUser
+		}
+		assignee, ok := usersMap[template.Assignee]
+		if !ok {
+			return nil, types.ErrInvalidUser
+		}
+		var nextOccurrence int64
+		if rec, err := parseRecurrence(template.Recurrence, template.Timezone, template.StartAt); err == nil {
+			next := rec.Next(templateCursor(template))
+			if !next.IsZero() && (template.EndAt == 0 || next.Unix() <= template.EndAt) {
+				nextOccurrence = next.Unix()
+			}
+		}
+		templatesRes = append(templatesRes, &types.TaskTemplateResponse{
+			ID:             template.ID,
+			Title:          template.Title,
+			Description:    template.Description,
+			Workspace:      template.Workspace,
+			Creator:        creator.FullName,
+			Assignee:       assignee.FullName,
+			Assignees:      taskMembers(template.Assignees, template.Assignee, usersMap),
+			Watchers:       taskMembers(template.Watchers, "", usersMap),
+			Recurrence:     template.Recurrence,
+			Timezone:       template.Timezone,
+			StartAt:        template.StartAt,
+			EndAt:          template.EndAt,
+			Duration:       template.Duration,
+			GenerateAhead:  template.GenerateAhead,
+			OverlapPolicy:  template.OverlapPolicy,
+			Active:         template.Active,
+			LastOccurrence: template.LastOccurrence,
+			NextOccurrence: nextOccurrence,
+			Skipped:        template.Skipped,
+			CreatedAt:      template.CreatedAt,
+			UpdatedAt:      template.UpdatedAt,
+		})
+	}
+	return templatesRes, nil
+}
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/remiehneppo/be-task-management/types"
+)
+
+func TestParseRecurrence(t *testing.T) {
+	hanoi, err := time.LoadLocation("Asia/Ho_Chi_Minh")
+	if err != nil {
+		t.Fatalf("LoadLocation: %v", err)
+	}
+	// Wednesday 1 May 2024, 09:00 in Hanoi
+	start := time.Date(2024, 5, 1, 9, 0, 0, 0, hanoi)
+
+	tests := []struct {
+		name     string
+		expr     string
+		timezone string
+		after    time.Time
+		want     []time.Time
+	}{
+		{
+			name:     "cron in the template timezone",
+			expr:     "0 9 * * 1",
+			timezone: "Asia/Ho_Chi_Minh",
+			after:    start,
+			want: []time.Time{
+				time.Date(2024, 5, 6, 9, 0, 0, 0, hanoi),
+				time.Date(2024, 5, 13, 9, 0, 0, 0, hanoi),
+			},
+		},
+		{
+			name:     "rrule from the template start",
+			expr:     "FREQ=WEEKLY;BYDAY=MO,WE;COUNT=3",
+			timezone: "Asia/Ho_Chi_Minh",
+			after:    start.Add(-time.Second),
+			want: []time.Time{
+				start,
+				time.Date(2024, 5, 6, 9, 0, 0, 0, hanoi),
+				time.Date(2024, 5, 8, 9, 0, 0, 0, hanoi),
+				{},
+			},
+		},
+		{
+			name:     "rrule with prefix",
+			expr:     " RRULE:FREQ=MONTHLY;BYMONTHDAY=15;COUNT=2 ",
+			timezone: "Asia/Ho_Chi_Minh",
+			after:    start,
+			want: []time.Time{
+				time.Date(2024, 5, 15, 9, 0, 0, 0, hanoi),
+				time.Date(2024, 6, 15, 9, 0, 0, 0, hanoi),
+				{},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec, err := parseRecurrence(tt.expr, tt.timezone, start.Unix())
+			if err != nil {
+				t.Fatalf("parseRecurrence(%q): %v", tt.expr, err)
+			}
+			after := tt.after
+			for i, want := range tt.want {
+				got := rec.Next(after)
+				if !got.Equal(want) {
+					t.Fatalf("occurrence %d = %v, want %v", i, got, want)
+				}
+				after = got
+			}
+		})
+	}
+}
+
+func TestParseRecurrenceRejects(t *testing.T) {
+	tests := []struct {
+		name     string
+		expr     string
+		timezone string
+		wantErr  error
+	}{
+		{name: "not a schedule", expr: "every monday", wantErr: types.ErrInvalidRecurrence},
+		{name: "cron with seconds", expr: "0 0 9 * * 1", wantErr: types.ErrInvalidRecurrence},
+		{name: "cron out of range", expr: "0 25 * * *", wantErr: types.ErrInvalidRecurrence},
+		{name: "unknown frequency", expr: "FREQ=SOMETIMES", wantErr: types.ErrInvalidRecurrence},
+		{name: "unknown timezone", expr: "0 9 * * 1", timezone: "Mars/Olympus", wantErr: types.ErrInvalidTimezone},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := parseRecurrence(tt.expr, tt.timezone, 0); !errors.Is(err, tt.wantErr) {
+				t.Errorf("parseRecurrence(%q) error = %v, want %v", tt.expr, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/remiehneppo/be-task-management/types"
+)
+
+const (
+	// calendarMaxTasks bounds the tasks a calendar returns, the response is
+	// flagged as truncated beyond it
+	calendarMaxTasks = 1000
+	// calendarMaxRange keeps a calendar to about a year
+	calendarMaxRange = 366 * 24 * time.Hour
+)
+
+// GetTaskBoard returns one page of tasks per status column, each with the
+// column total. A status filter restricts the board to that column, which
+// is how a single column loads its next pages, by number or with the column
+// cursor.
+func (s *taskService) GetTaskBoard(ctx context.Context, page types.PageRequest, filter types.TaskFilter) (*types.TaskBoard, error) {
+	userID, ok := ctx.Value("user_id").(string)
+	if !ok {
+		return nil, types.ErrInvalidCredentials
+	}
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	filter.Workspace = user.Workspace
+	if err := validateTaskFilter(&filter); err != nil {
+		return nil, err
+	}
+	if err := s.resolveCustomFieldFilters(ctx, &filter); err != nil {
+		return nil, err
+	}
+	if page.Cursor != "" && filter.Status == "" {
+		return nil, types.ErrBoardCursorWithoutStatus
+	}
+	statuses := types.TASK_BOARD_STATUSES
+	if filter.Status != "" {
+		statuses = []string{filter.Status}
+	}
+	board := &types.TaskBoard{
+		Columns: make([]*types.TaskBoardColumn, 0, len(statuses)),
+	}
+	for _, status := range statuses {
+		filter.Status = status
+		tasks, total, nextCursor, err := s.filterWorkspaceTasks(ctx, page, filter)
+		if err != nil {
+			return nil, err
+		}
+		board.Columns = append(board.Columns, &types.TaskBoardColumn{
+			Status:     status,
+			Total:      total,
+			Page:       page.Page,
+			Limit:      page.Limit,
+			NextCursor: nextCursor,
+			Tasks:      tasks,
+		})
+	}
+	return board, nil
+}
+
+// GetTaskCalendar returns the tasks whose start to deadline span overlaps
+// the requested range, listed in every day or week they span
+func (s *taskService) GetTaskCalendar(ctx context.Context, req *types.TaskCalendarRequest, filter types.TaskFilter) (*types.TaskCalendar, error) {
+	userID, ok := ctx.Value("user_id").(string)
+	if !ok {
+		return nil, types.ErrInvalidCredentials
+	}
+	if req.To < req.From || time.Duration(req.To-req.From)*time.Second > calendarMaxRange {
+		return nil, types.ErrInvalidCalendarRange
+	}
+	if req.GroupBy == "" {
+		req.GroupBy = types.CALENDAR_GROUP_DAY
+	}
+	if req.GroupBy != types.CALENDAR_GROUP_DAY && req.GroupBy != types.CALENDAR_GROUP_WEEK {
+		return nil, types.ErrInvalidCalendarGroup
+	}
+	location := time.Local
+	if req.Timezone != "" {
+		var err error
+		location, err = time.LoadLocation(req.Timezone)
+		if err != nil {
+			return nil, types.ErrInvalidTimezone
+		}
+	}
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	filter.Workspace = user.Workspace
+	if err := validateTaskFilter(&filter); err != nil {
+		return nil, err
+	}
+	if err := s.resolveCustomFieldFilters(ctx, &filter); err != nil {
+		return nil, err
+	}
+	filter.SpanFrom = req.From
+	filter.SpanTo = req.To
+	tasks, total, _, err := s.taskRepo.PaginateWithFilter(ctx, types.PageRequest{Page: 1, Limit: calendarMaxTasks}, filter)
+	if err != nil {
+		return nil, err
+	}
+	buckets := calendarBuckets(time.Unix(req.From, 0).In(location), time.Unix(req.To, 0).In(location), req.GroupBy)
+	for _, task := range tasks {
+		spanStart, spanEnd := task.Deadline, task.Deadline
+		if task.StartAt > 0 {
+			spanStart = min(task.StartAt, task.Deadline)
+		}
+		for _, bucket := range buckets {
+			if spanStart <= bucket.End && spanEnd >= bucket.Start {
+				bucket.TaskIDs = append(bucket.TaskIDs, task.ID)
+			}
+		}
+	}
+	tasksRes, err := s.toTaskResponses(ctx, tasks)
+	if err != nil {
+		return nil, err
+	}
+	return &types.TaskCalendar{
+		From:      req.From,
+		To:        req.To,
+		GroupBy:   req.GroupBy,
+		Timezone:  location.String(),
+		Buckets:   buckets,
+		Tasks:     tasksRes,
+		Total:     total,
+		Truncated: total > int64(len(tasks)),
+	}, nil
+}
+
+// calendarBuckets splits the range into consecutive days or Monday based
+// weeks of the range location, the first and last ones may extend past it
+func calendarBuckets(from, to time.Time, groupBy string) []*types.TaskCalendarBucket {
+	start := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, from.Location())
+	days := 1
+	if groupBy == types.CALENDAR_GROUP_WEEK {
+		days = 7
+		start = start.AddDate(0, 0, -((int(start.Weekday()) + 6) % 7))
+	}
+	buckets := make([]*types.TaskCalendarBucket, 0)
+	for !start.After(to) {
+		// AddDate keeps midnight across daylight saving changes
+		next := start.AddDate(0, 0, days)
+		buckets = append(buckets, &types.TaskCalendarBucket{
+			Date:    start.Format("2006-01-02"),
+			Start:   start.Unix(),
+			End:     next.Unix() - 1,
+			TaskIDs: make([]string, 0),
+		})
+		start = next
+	}
+	return buckets
+}
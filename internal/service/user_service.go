@@ -12,6 +12,7 @@ type UserService interface {
 	UpdateUserInfo(ctx context.Context, id string, user *types.User) error
 	UpdatePassword(ctx context.Context, id string, oldPassword, newPassword string) error
 	GetUsersInWorkspace(ctx context.Context, workspace string) ([]*types.User, error)
+	PaginateUsersInWorkspace(ctx context.Context, workspace string, page types.PageRequest) (items []*types.User, total int64, nextCursor string, err error)
 }
 
 type userService struct {
@@ -69,3 +70,8 @@ func (s *userService) GetUsersInWorkspace(ctx context.Context, workspace string)
 	}
 	return users, nil
 }
+
+// PaginateUsersInWorkspace returns a page of the workspace members by name
+func (s *userService) PaginateUsersInWorkspace(ctx context.Context, workspace string, page types.PageRequest) (items []*types.User, total int64, nextCursor string, err error) {
+	return s.userRepo.PaginateByWorkspace(ctx, workspace, page)
+}
@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"time"
+
+	"github.com/remiehneppo/be-task-management/internal/repository"
+	"github.com/remiehneppo/be-task-management/types"
+)
+
+// workspaceName is the form of a workspace name, users and tasks store it
+var workspaceName = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_-]{1,63}$`)
+
+var _ WorkspaceService = (*workspaceService)(nil)
+
+// WorkspaceService manages the workspaces, every user may list them and
+// the members whose role grants workspace.manage change them. Every change
+// is audit-logged.
+type WorkspaceService interface {
+	GetWorkspaces(ctx context.Context) ([]*types.Workspace, error)
+	CreateWorkspace(ctx context.Context, req *types.CreateWorkspaceRequest) (*types.Workspace, error)
+	UpdateWorkspace(ctx context.Context, req *types.UpdateWorkspaceRequest) (*types.Workspace, error)
+	// DeleteWorkspace deletes a workspace without users nor tasks
+	DeleteWorkspace(ctx context.Context, id string) error
+}
+
+type workspaceService struct {
+	workspaceRepo repository.WorkspaceRepository
+	userRepo      repository.UserRepository
+	taskRepo      repository.TaskRepository
+	auditLogRepo  repository.AuditLogRepository
+	authorizer    AuthorizationService
+}
+
+func NewWorkspaceService(
+	workspaceRepo repository.WorkspaceRepository,
+	userRepo repository.UserRepository,
+	taskRepo repository.TaskRepository,
+	auditLogRepo repository.AuditLogRepository,
+	authorizer AuthorizationService,
+) WorkspaceService {
+	return &workspaceService{
+		workspaceRepo: workspaceRepo,
+		userRepo:      userRepo,
+		taskRepo:      taskRepo,
+		auditLogRepo:  auditLogRepo,
+		authorizer:    authorizer,
+	}
+}
+
+func (s *workspaceService) GetWorkspaces(ctx context.Context) ([]*types.Workspace, error) {
+	return s.workspaceRepo.FindAll(ctx)
+}
+
+func (s *workspaceService) CreateWorkspace(ctx context.Context, req *types.CreateWorkspaceRequest) (*types.Workspace, error) {
+	actor, err := s.workspaceManager(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !workspaceName.MatchString(req.Name) {
+		return nil, types.ErrInvalidWorkspaceName
+	}
+	_, err = s.workspaceRepo.FindByName(ctx, req.Name)
+	if err == nil {
+		return nil, types.ErrWorkspaceExists
+	}
+	if !errors.Is(err, types.ErrWorkspaceNotFound) {
+		return nil, err
+	}
+	now := time.Now().Unix()
+	workspace := &types.Workspace{
+		Name:        req.Name,
+		DisplayName: req.DisplayName,
+		Description: req.Description,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if workspace.DisplayName == "" {
+		workspace.DisplayName = workspace.Name
+	}
+	if err := s.workspaceRepo.Save(ctx, workspace); err != nil {
+		return nil, err
+	}
+	recordAudit(ctx, s.auditLogRepo, &types.AuditLog{
+		Actor:      actor.ID,
+		Action:     types.AUDIT_ACTION_WORKSPACE_CREATE,
+		TargetType: types.AUDIT_TARGET_WORKSPACE,
+		TargetID:   workspace.ID,
+		Changes:    diffWorkspace(&types.Workspace{}, workspace),
+	})
+	return workspace, nil
+}
+
+func (s *workspaceService) UpdateWorkspace(ctx context.Context, req *types.UpdateWorkspaceRequest) (*types.Workspace, error) {
+	actor, err := s.workspaceManager(ctx)
+	if err != nil {
+		return nil, err
+	}
+	workspace, err := s.workspaceRepo.FindByID(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+	updated := *workspace
+	updated.DisplayName = req.DisplayName
+	if updated.DisplayName == "" {
+		updated.DisplayName = workspace.Name
+	}
+	updated.Description = req.Description
+	updated.UpdatedAt = time.Now().Unix()
+	updated.ID = ""
+	if err := s.workspaceRepo.Update(ctx, req.ID, &updated); err != nil {
+		return nil, err
+	}
+	updated.ID = req.ID
+	recordAudit(ctx, s.auditLogRepo, &types.AuditLog{
+		Actor:      actor.ID,
+		Action:     types.AUDIT_ACTION_WORKSPACE_UPDATE,
+		TargetType: types.AUDIT_TARGET_WORKSPACE,
+		TargetID:   req.ID,
+		Changes:    diffWorkspace(workspace, &updated),
+	})
+	return &updated, nil
+}
+
+func (s *workspaceService) DeleteWorkspace(ctx context.Context, id string) error {
+	actor, err := s.workspaceManager(ctx)
+	if err != nil {
+		return err
+	}
+	workspace, err := s.workspaceRepo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	users, err := s.userRepo.CountWithFilter(ctx, types.UserFilter{Workspace: workspace.Name})
+	if err != nil {
+		return err
+	}
+	tasks, err := s.taskRepo.CountWithFilter(ctx, types.TaskFilter{Workspace: workspace.Name})
+	if err != nil {
+		return err
+	}
+	if users+tasks > 0 {
+		return types.ErrWorkspaceNotEmpty
+	}
+	if err := s.workspaceRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+	recordAudit(ctx, s.auditLogRepo, &types.AuditLog{
+		Actor:      actor.ID,
+		Action:     types.AUDIT_ACTION_WORKSPACE_DELETE,
+		TargetType: types.AUDIT_TARGET_WORKSPACE,
+		TargetID:   id,
+		Changes:    diffWorkspace(workspace, &types.Workspace{}),
+	})
+	return nil
+}
+
+// workspaceManager returns the current user, whose role must grant
+// workspace.manage
+func (s *workspaceService) workspaceManager(ctx context.Context) (*types.User, error) {
+	userID, ok := ctx.Value("user_id").(string)
+	if !ok {
+		return nil, types.ErrInvalidCredentials
+	}
+	return s.authorizer.Authorize(ctx, userID, types.PERMISSION_WORKSPACE_MANAGE)
+}
+
+func diffWorkspace(before, after *types.Workspace) []types.FieldChange {
+	changes := make([]types.FieldChange, 0)
+	addChange := func(field string, oldValue, newValue interface{}) {
+		if oldValue != newValue {
+			changes = append(changes, types.FieldChange{Field: field, OldValue: oldValue, NewValue: newValue})
+		}
+	}
+	addChange("name", before.Name, after.Name)
+	addChange("display_name", before.DisplayName, after.DisplayName)
+	addChange("description", before.Description, after.Description)
+	return changes
+}
@@ -70,6 +70,7 @@ func (w *Worker) Start() {
 			w.logger.Errorf("Error adding scheduled job: %v", err)
 		}
 	}
+	w.c.Start()
 }
 
 func (w *Worker) RegisterIntervalJob(intervalTime int64, do Do) {
@@ -1,6 +1,9 @@
 package types
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var (
 	ErrInvalidUser              = errors.New("invalid user")
@@ -22,9 +25,201 @@ var (
 	ErrUserNotFound                  = errors.New("user not found")
 )
 
+var (
+	ErrInvalidTaskStatus        = errors.New("invalid task status")
+	ErrTaskTransitionNotAllowed = errors.New("task status transition not allowed")
+	ErrTaskTransitionForbidden  = errors.New("task status transition forbidden")
+	ErrTaskBlockedByDependency  = errors.New("task blocked by unfinished dependencies")
+)
+
+var (
+	ErrTaskTemplateNotCreator     = errors.New("task template not creator")
+	ErrTaskTemplateNotInWorkspace = errors.New("task template not in workspace")
+	ErrInvalidRecurrence          = errors.New("invalid recurrence")
+	ErrInvalidTimezone            = errors.New("invalid timezone")
+	ErrInvalidOverlapPolicy       = errors.New("invalid overlap policy")
+	ErrInvalidTemplateDuration    = errors.New("invalid template duration")
+)
+
+var (
+	ErrInvalidEmailMode  = errors.New("invalid email mode")
+	ErrInvalidEmail      = errors.New("invalid email")
+	ErrInvalidWebhookURL = errors.New("invalid webhook url")
+	// ErrWebhookSecretRequired is returned for a webhook without a signing secret
+	ErrWebhookSecretRequired = errors.New("webhook secret is required")
+	// ErrWebhookAddressForbidden is returned when a webhook resolves to a
+	// loopback, private or link-local address
+	ErrWebhookAddressForbidden = errors.New("webhook address is not allowed")
+
+	ErrNotificationPreferenceNotFound = errors.New("notification preference not found")
+)
+
+var (
+	ErrInvalidPriority              = errors.New("invalid priority")
+	ErrInvalidCustomFieldKey        = errors.New("invalid custom field key")
+	ErrInvalidCustomFieldType       = errors.New("invalid custom field type")
+	ErrInvalidCustomFieldOptions    = errors.New("enum custom fields need distinct, non empty options")
+	ErrCustomFieldExists            = errors.New("custom field already exists")
+	ErrCustomFieldNotInWorkspace    = errors.New("custom field not in workspace")
+	ErrUnknownCustomField           = errors.New("unknown custom field")
+	ErrInvalidCustomFieldValue      = errors.New("invalid custom field value")
+	ErrCustomFieldRequired          = errors.New("custom field is required")
+	ErrCustomFieldDefinitionMissing = errors.New("custom field definition not found")
+)
+
+var (
+	// ErrInvalidCursor is returned for a malformed cursor or one issued for
+	// another sort order
+	ErrInvalidCursor = errors.New("invalid cursor")
+	// ErrBoardCursorWithoutStatus is returned when paging the task board by
+	// cursor without selecting a single column
+	ErrBoardCursorWithoutStatus = errors.New("board cursor requires a status")
+)
+
+var (
+	ErrInvalidCalendarRange = errors.New("invalid calendar range")
+	ErrInvalidCalendarGroup = errors.New("invalid calendar group")
+	ErrInvalidSortField     = errors.New("invalid sort field")
+	// ErrRelevanceWithoutSearch is returned when sorting by relevance
+	// without a full-text search
+	ErrRelevanceWithoutSearch = errors.New("relevance sort requires a search")
+)
+
+var (
+	ErrInvalidReportDecision = errors.New("invalid report decision")
+	ErrInvalidReportStatus   = errors.New("invalid report status")
+	// ErrReportRejectionFeedback is returned for a rejection without feedback
+	ErrReportRejectionFeedback = errors.New("rejecting a report needs feedback")
+	ErrReportSelfReview        = errors.New("reports cannot be reviewed by their creator")
+	ErrReportReviewForbidden   = errors.New("only the task creator or a manager can review its reports")
+	ErrReportFeedbackForbidden = errors.New("only the task creator or a manager can give feedback on its reports")
+	// ErrReportNotPending is returned when reviewing a report already
+	// decided, a rejected report is reviewed again once reworked
+	ErrReportNotPending = errors.New("report is not pending review")
+)
+
+var (
+	ErrInvalidAnalyticsRange = errors.New("invalid analytics range")
+	// ErrAnalyticsForbidden is returned for the metrics of a user with a
+	// management level not below the requester, or for workspace metrics
+	// requested below head level
+	ErrAnalyticsForbidden = errors.New("analytics not allowed at this management level")
+)
+
+var (
+	ErrEmptyComment             = errors.New("comment needs content or attachments")
+	ErrCommentTooLong           = errors.New("comment too long")
+	ErrTooManyAttachments       = errors.New("too many attachments")
+	ErrCommentNotAuthor         = errors.New("comment not author")
+	ErrCommentDeleted           = errors.New("comment deleted")
+	ErrCommentParentMismatch    = errors.New("reply must belong to the same discussion as its parent")
+	ErrCommentAttachmentMissing = errors.New("comment attachment not found")
+)
+
+var (
+	ErrTaskParentCycle       = errors.New("task parent would create a cycle")
+	ErrTaskDependencyCycle   = errors.New("task dependency would create a cycle")
+	ErrTaskHasSubtasks       = errors.New("task has subtasks")
+	ErrTaskProgressRolledUp  = errors.New("progress of a task with subtasks is computed from its subtasks")
+	ErrTaskDependencyExists  = errors.New("task dependency already exists")
+	ErrTaskDependencyMissing = errors.New("task dependency not found")
+)
+
+// TaskTransitionError reports a rejected status change, it wraps one of the
+// ErrTaskTransition* errors so callers can match it with errors.Is
+type TaskTransitionError struct {
+	From string
+	To   string
+	Err  error
+}
+
+func (e *TaskTransitionError) Error() string {
+	return fmt.Sprintf("%s: %s -> %s", e.Err.Error(), e.From, e.To)
+}
+
+func (e *TaskTransitionError) Unwrap() error {
+	return e.Err
+}
+
+// CustomFieldError names the custom field a task value or filter was
+// rejected for, it wraps one of the custom field errors
+type CustomFieldError struct {
+	Key string
+	Err error
+}
+
+func (e *CustomFieldError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Err.Error(), e.Key)
+}
+
+func (e *CustomFieldError) Unwrap() error {
+	return e.Err
+}
+
+var (
+	ErrInvalidExportFormat = errors.New("invalid export format")
+	ErrInvalidExportSource = errors.New("invalid export source")
+	ErrExportNotRequester  = errors.New("export not requester")
+	// ErrExportNotReady is returned when downloading an export the worker
+	// has not written yet, or failed to write
+	ErrExportNotReady = errors.New("export not ready")
+	ErrExportExpired  = errors.New("export expired")
+)
+
+var (
+	ErrInvalidImportKind   = errors.New("invalid import kind")
+	ErrImportEmpty         = errors.New("import file has no rows")
+	ErrImportTooManyRows   = errors.New("import file has too many rows")
+	ErrImportMissingColumn = errors.New("import file misses a required column")
+	// ErrImportInvalidRows is returned when invalid rows abort an import
+	ErrImportInvalidRows = errors.New("import aborted, some rows are invalid")
+)
+
+var (
+	ErrTaskDraftEmpty = errors.New("no text or file to draft tasks from")
+)
+
+var (
+	ErrUserDeactivated        = errors.New("user is deactivated")
+	ErrUsernameTaken          = errors.New("username is already taken")
+	ErrInvalidUsername        = errors.New("username must not be empty nor contain spaces")
+	ErrInvalidManagementLevel = errors.New("management level does not match the workspace role")
+	// ErrUserHasHistory is returned when deleting a user whom tasks, reports
+	// or comments refer to, they are deactivated instead
+	ErrUserHasHistory = errors.New("user has tasks, reports or comments, deactivate them instead")
+	// ErrAdminSelf is returned when admins would lock themselves out
+	ErrAdminSelf = errors.New("cannot deactivate, delete or change the role of yourself")
+	// ErrUserNotManaged is returned when a user manager without admin role
+	// acts on a user outside their workspace, above their level or admin
+	ErrUserNotManaged       = errors.New("user is outside your management")
+	ErrWorkspaceNotFound    = errors.New("workspace not found")
+	ErrWorkspaceExists      = errors.New("workspace already exists")
+	ErrInvalidWorkspaceName = errors.New("invalid workspace name")
+	ErrWorkspaceNotEmpty    = errors.New("workspace still has users or tasks")
+)
+
+var (
+	// ErrPermissionDenied is returned when no role of the user grants the
+	// permission an action needs
+	ErrPermissionDenied        = errors.New("permission denied")
+	ErrInvalidRole             = errors.New("invalid role")
+	ErrInvalidPermission       = errors.New("invalid permission")
+	ErrRolePermissionsNotFound = errors.New("role permissions not found")
+)
+
+var (
+	ErrSummaryForbidden   = errors.New("summary belongs to another workspace")
+	ErrInvalidSummaryWeek = errors.New("invalid summary week")
+	// ErrAIUnavailable is returned when a feature needs the AI service and
+	// it is disabled
+	ErrAIUnavailable = errors.New("AI service is not available")
+	ErrInvalidChatID = errors.New("invalid chat id")
+)
+
 var (
 	ErrUnsupportedFileType = errors.New("unsupported file type")
 	ErrFileTooLarge        = errors.New("file too large")
+	ErrInvalidFilePath     = errors.New("invalid file path")
 )
 
 var (
@@ -30,17 +30,240 @@ type UpdateTaskRequest struct {
 	Assignee    string `json:"assignee"`
 	Progress    int    `json:"progress"`
 	Status      string `json:"status"`
+	ParentID    string `json:"parent_id"`
+	// Assignees and Watchers replace the current lists when present
+	Assignees []string `json:"assignees"`
+	Watchers  []string `json:"watchers"`
+	// Priority is changed when present, 0 clears it
+	Priority *int `json:"priority"`
+	// Tags replace the current labels when present
+	Tags []string `json:"tags"`
+	// CustomFields are merged into the current values, null clears a field
+	CustomFields map[string]interface{} `json:"custom_fields"`
 }
 
 type GetTasksAssignedToUserRequest struct {
 }
 
 type CreateTaskRequest struct {
-	Title       string `json:"title" binding:"required"`
-	Description string `json:"description" binding:"required"`
-	StartAt     int64  `json:"start_at" binding:"required"`
-	Deadline    int64  `json:"deadline" binding:"required"`
-	Assignee    string `json:"assignee" binding:"required"`
+	Title       string   `json:"title" binding:"required"`
+	Description string   `json:"description" binding:"required"`
+	StartAt     int64    `json:"start_at" binding:"required"`
+	Deadline    int64    `json:"deadline" binding:"required"`
+	Assignee    string   `json:"assignee" binding:"required"`
+	Assignees   []string `json:"assignees"`
+	Watchers    []string `json:"watchers"`
+	ParentID    string   `json:"parent_id"`
+	DependsOn   []string `json:"depends_on"`
+	// Priority is one of 0 (none), 1 (low), 2 (medium), 3 (high), 4 (urgent)
+	Priority     int                    `json:"priority"`
+	Tags         []string               `json:"tags"`
+	CustomFields map[string]interface{} `json:"custom_fields"`
+}
+
+type CreateTaskTemplateRequest struct {
+	Title         string   `json:"title" binding:"required"`
+	Description   string   `json:"description" binding:"required"`
+	Assignee      string   `json:"assignee" binding:"required"`
+	Assignees     []string `json:"assignees"`
+	Watchers      []string `json:"watchers"`
+	Recurrence    string   `json:"recurrence" binding:"required"`
+	Timezone      string   `json:"timezone"`
+	StartAt       int64    `json:"start_at" binding:"required"`
+	EndAt         int64    `json:"end_at"`
+	Duration      int64    `json:"duration" binding:"required"`
+	GenerateAhead int64    `json:"generate_ahead"`
+	OverlapPolicy string   `json:"overlap_policy"`
+}
+
+type UpdateTaskTemplateRequest struct {
+	TemplateID    string   `json:"template_id" binding:"required"`
+	Title         string   `json:"title"`
+	Description   string   `json:"description"`
+	Assignee      string   `json:"assignee"`
+	Assignees     []string `json:"assignees"`
+	Watchers      []string `json:"watchers"`
+	Recurrence    string   `json:"recurrence"`
+	Timezone      string   `json:"timezone"`
+	EndAt         int64    `json:"end_at"`
+	Duration      int64    `json:"duration"`
+	GenerateAhead int64    `json:"generate_ahead"`
+	OverlapPolicy string   `json:"overlap_policy"`
+	Active        *bool    `json:"active"`
+}
+
+type MarkNotificationsReadRequest struct {
+	NotificationIDs []string `json:"notification_ids" binding:"required"`
+}
+
+type UpdateNotificationPreferenceRequest struct {
+	Email      *string  `json:"email"`
+	EmailMode  string   `json:"email_mode"`
+	EmailTypes []string `json:"email_types"`
+	WebhookURL *string  `json:"webhook_url"`
+	// WebhookSecret signs the webhook payloads, it is never returned
+	WebhookSecret *string  `json:"webhook_secret"`
+	WebhookTypes  []string `json:"webhook_types"`
+}
+
+type TaskCalendarRequest struct {
+	From int64 `json:"from"`
+	To   int64 `json:"to"`
+	// GroupBy is day or week, weeks start on Monday
+	GroupBy string `json:"group_by"`
+	// Timezone is an IANA name, the server zone when empty
+	Timezone string `json:"timezone"`
+}
+
+type AnalyticsRequest struct {
+	From int64 `json:"from"`
+	To   int64 `json:"to"`
+	// Timezone is an IANA name used to cut weeks, the server zone when empty
+	Timezone string `json:"timezone"`
+	// UserID restricts the analytics to the tasks of one user
+	UserID string `json:"user_id"`
+}
+
+// ExportRequest selects the rows of an export. CSV holds the Source table
+// only, XLSX has a sheet for tasks and one for reports and PDF summarizes
+// both.
+type ExportRequest struct {
+	// Format is one of EXPORT_FORMAT_*
+	Format string `json:"format" bson:"format"`
+	// Source is one of EXPORT_SOURCE_*, tasks when empty
+	Source       string       `json:"source" bson:"source"`
+	TaskFilter   TaskFilter   `json:"task_filter" bson:"task_filter"`
+	ReportFilter ReportFilter `json:"report_filter" bson:"report_filter"`
+	// Timezone is an IANA name dates are written in, the server zone when
+	// empty
+	Timezone string `json:"timezone" bson:"timezone"`
+	// Async runs the export in the background whatever its size
+	Async bool `json:"async" bson:"-"`
+}
+
+// DraftTasksRequest holds the free text tasks are drafted from, a file
+// uploaded with it is read after the text
+type DraftTasksRequest struct {
+	Text string `json:"text" form:"text"`
+	// Timezone is an IANA name the dates of the text are read in, the
+	// server zone when empty
+	Timezone string `json:"timezone" form:"timezone"`
+}
+
+// ConfirmTaskDraftsRequest creates the drafts the user reviewed
+type ConfirmTaskDraftsRequest struct {
+	Drafts []CreateTaskRequest `json:"drafts" binding:"required,min=1,dive"`
+}
+
+// CreateUserRequest creates a user, the management level defaults to the
+// level of the workspace role
+type CreateUserRequest struct {
+	Username        string `json:"username" binding:"required"`
+	Password        string `json:"password" binding:"required"`
+	FullName        string `json:"full_name" binding:"required"`
+	Workspace       string `json:"workspace" binding:"required"`
+	WorkspaceRole   string `json:"workspace_role" binding:"required"`
+	ManagementLevel int    `json:"management_level"`
+	// Role is USER_ROLE_ADMIN to create an admin, only admins may
+	Role string `json:"role"`
+}
+
+// UpdateUserRequest updates the profile of a user, empty fields are kept
+type UpdateUserRequest struct {
+	ID        string `json:"id" binding:"required"`
+	FullName  string `json:"full_name"`
+	Workspace string `json:"workspace"`
+}
+
+// UpdateUserRoleRequest changes the roles of a user, the management level
+// defaults to the level of the workspace role
+type UpdateUserRoleRequest struct {
+	ID              string `json:"id" binding:"required"`
+	WorkspaceRole   string `json:"workspace_role" binding:"required"`
+	ManagementLevel int    `json:"management_level"`
+	Role            string `json:"role"`
+}
+
+type ResetPasswordRequest struct {
+	ID       string `json:"id" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+type CreateWorkspaceRequest struct {
+	Name        string `json:"name" binding:"required"`
+	DisplayName string `json:"display_name"`
+	Description string `json:"description"`
+}
+
+// UpdateWorkspaceRequest updates a workspace, its name cannot change
+type UpdateWorkspaceRequest struct {
+	ID          string `json:"id" binding:"required"`
+	DisplayName string `json:"display_name"`
+	Description string `json:"description"`
+}
+
+// UpdateRolePermissionsRequest replaces the permissions of a workspace
+// role
+type UpdateRolePermissionsRequest struct {
+	Role        string   `json:"role" binding:"required"`
+	Permissions []string `json:"permissions" binding:"required"`
+}
+
+// AskTasksRequest is a question about the tasks and reports of the
+// workspace, such as "which tasks were delayed by supplier issues last
+// quarter?"
+type AskTasksRequest struct {
+	Question string `json:"question" binding:"required"`
+	// Timezone is an IANA name the dates of the question are read in, the
+	// server zone when empty
+	Timezone string `json:"timezone"`
+	// Limit is how many tasks and how many reports the answer is written
+	// from at most
+	Limit int `json:"limit"`
+}
+
+// GenerateSummaryRequest asks for the summary of a week
+type GenerateSummaryRequest struct {
+	// Week is any day of the week as 2006-01-02, the current week when
+	// empty
+	Week string `json:"week" example:"2025-05-05"`
+}
+
+// ImportRequest describes a bulk import of one kind of rows from a CSV or
+// XLSX file
+type ImportRequest struct {
+	// Kind is one of IMPORT_KIND_*
+	Kind string `json:"kind" form:"kind"`
+	// DryRun validates every row without writing any
+	DryRun bool `json:"dry_run" form:"dryRun"`
+	// SkipInvalid writes the valid rows and reports the others, otherwise an
+	// invalid row aborts the import and the rows are written in a single
+	// transaction
+	SkipInvalid bool `json:"skip_invalid" form:"skipInvalid"`
+	// Workspace restricts the rows to a workspace, any workspace when empty
+	Workspace string `json:"-" form:"-"`
+}
+
+type CreateCustomFieldRequest struct {
+	// Key names the field in task custom_fields, lowercase letters, digits
+	// and underscores
+	Key      string   `json:"key" binding:"required"`
+	Name     string   `json:"name" binding:"required"`
+	Type     string   `json:"type" binding:"required"`
+	Options  []string `json:"options"`
+	Required bool     `json:"required"`
+}
+
+type UpdateCustomFieldRequest struct {
+	ID       string   `json:"id" binding:"required"`
+	Name     string   `json:"name"`
+	Options  []string `json:"options"`
+	Required *bool    `json:"required"`
+}
+
+type TaskDependencyRequest struct {
+	TaskID    string `json:"task_id" binding:"required"`
+	DependsOn string `json:"depends_on" binding:"required"`
 }
 
 type UpdateReportRequest struct {
@@ -63,6 +286,33 @@ type FeedbackRequest struct {
 	Feedback string `json:"feedback" binding:"required"`
 }
 
+// CreateCommentRequest posts a comment on a task, or on a report when
+// ReportID is set. ParentID answers an existing comment of the same thread.
+type CreateCommentRequest struct {
+	TaskID   string `json:"task_id"`
+	ReportID string `json:"report_id"`
+	ParentID string `json:"parent_id"`
+	Content  string `json:"content"`
+	// Attachments are the uploaded files, sent as multipart parts
+	Attachments []*multipart.FileHeader `json:"-" swaggerignore:"true"`
+}
+
+type UpdateCommentRequest struct {
+	ID      string `json:"id" binding:"required"`
+	Content string `json:"content"`
+	// RemoveAttachments are the file paths of the attachments to drop
+	RemoveAttachments []string `json:"remove_attachments"`
+	// Attachments are the files to add, sent as multipart parts
+	Attachments []*multipart.FileHeader `json:"-" swaggerignore:"true"`
+}
+
+type ReviewReportRequest struct {
+	ReportID string `json:"report_id" binding:"required"`
+	// Decision is approve or reject, a rejection needs feedback
+	Decision string `json:"decision" binding:"required"`
+	Feedback string `json:"feedback"`
+}
+
 type UploadRequest struct {
 	Title  string   `json:"title" binding:"required"`
 	Source string   `json:"source"`
@@ -80,8 +330,10 @@ type ChatStatelessRequest struct {
 
 type PaginateMessagesRequest struct {
 	ChatId string `json:"chat_id" binding:"required"`
-	Page   int64  `json:"page" binding:"required"`
+	Page   int64  `json:"page"`
 	Limit  int64  `json:"limit" binding:"required"`
+	// Cursor continues from a previous page and takes precedence over Page
+	Cursor string `json:"cursor"`
 }
 
 type UploadFileRequest struct {
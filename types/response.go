@@ -14,6 +14,8 @@ type PaginatedData struct {
 	Limit int64       `json:"limit"`
 	Page  int64       `json:"page"`
 	Items interface{} `json:"items"`
+	// NextCursor fetches the items after this page, empty on the last page
+	NextCursor string `json:"next_cursor,omitempty"`
 }
 
 // PaginatedResponse for paginated API responses
@@ -29,17 +31,117 @@ type LoginResponse struct {
 }
 
 type TaskResponse struct {
-	ID          string            `json:"id" bson:"_id,omitempty"`
-	Title       string            `json:"title" bson:"title"`
-	Description string            `json:"description" bson:"description"`
-	Workspace   string            `json:"workspace" bson:"workspace"`
-	Creator     string            `json:"creator" bson:"creator"`
-	Deadline    int64             `json:"deadline" bson:"deadline"`
-	Assignee    string            `json:"assignee" bson:"assignee"`
-	Status      string            `json:"status" bson:"status"`
-	CreateAt    int64             `json:"created_at" bson:"created_at"`
-	UpdateAt    int64             `json:"updated_at" bson:"updated_at"`
-	Reports     []*ReportResponse `json:"reports" bson:"reports"`
+	ID           string                 `json:"id" bson:"_id,omitempty"`
+	Title        string                 `json:"title" bson:"title"`
+	Description  string                 `json:"description" bson:"description"`
+	Workspace    string                 `json:"workspace" bson:"workspace"`
+	Creator      string                 `json:"creator" bson:"creator"`
+	StartAt      int64                  `json:"start_at" bson:"start_at"`
+	Deadline     int64                  `json:"deadline" bson:"deadline"`
+	Assignee     string                 `json:"assignee" bson:"assignee"`
+	Assignees    []*TaskMember          `json:"assignees" bson:"assignees"`
+	Watchers     []*TaskMember          `json:"watchers" bson:"watchers"`
+	Status       string                 `json:"status" bson:"status"`
+	Progress     int                    `json:"progress" bson:"progress"`
+	ParentID     string                 `json:"parent_id" bson:"parent_id"`
+	DependsOn    []string               `json:"depends_on" bson:"depends_on"`
+	TemplateID   string                 `json:"template_id,omitempty" bson:"template_id"`
+	Priority     int                    `json:"priority" bson:"priority"`
+	Tags         []string               `json:"tags" bson:"tags"`
+	CustomFields map[string]interface{} `json:"custom_fields" bson:"custom_fields"`
+	CreateAt     int64                  `json:"created_at" bson:"created_at"`
+	UpdateAt     int64                  `json:"updated_at" bson:"updated_at"`
+	CompletedAt  int64                  `json:"completed_at,omitempty" bson:"completed_at"`
+	Reports      []*ReportResponse      `json:"reports" bson:"reports"`
+}
+
+type TaskTemplateResponse struct {
+	ID             string        `json:"id"`
+	Title          string        `json:"title"`
+	Description    string        `json:"description"`
+	Workspace      string        `json:"workspace"`
+	Creator        string        `json:"creator"`
+	Assignee       string        `json:"assignee"`
+	Assignees      []*TaskMember `json:"assignees"`
+	Watchers       []*TaskMember `json:"watchers"`
+	Recurrence     string        `json:"recurrence"`
+	Timezone       string        `json:"timezone"`
+	StartAt        int64         `json:"start_at"`
+	EndAt          int64         `json:"end_at"`
+	Duration       int64         `json:"duration"`
+	GenerateAhead  int64         `json:"generate_ahead"`
+	OverlapPolicy  string        `json:"overlap_policy"`
+	Active         bool          `json:"active"`
+	LastOccurrence int64         `json:"last_occurrence"`
+	NextOccurrence int64         `json:"next_occurrence"`
+	Skipped        int64         `json:"skipped"`
+	CreatedAt      int64         `json:"created_at"`
+	UpdatedAt      int64         `json:"updated_at"`
+}
+
+type UnreadCountResponse struct {
+	Unread int64 `json:"unread"`
+}
+
+type TaskMember struct {
+	ID       string `json:"id"`
+	FullName string `json:"full_name"`
+	Primary  bool   `json:"primary,omitempty"`
+}
+
+// TaskBoardColumn is one page of the tasks in a status
+type TaskBoardColumn struct {
+	Status string `json:"status"`
+	Total  int64  `json:"total"`
+	Page   int64  `json:"page"`
+	Limit  int64  `json:"limit"`
+	// NextCursor fetches the next page of the column, with its status
+	NextCursor string          `json:"next_cursor,omitempty"`
+	Tasks      []*TaskResponse `json:"tasks"`
+}
+
+type TaskBoard struct {
+	Columns []*TaskBoardColumn `json:"columns"`
+}
+
+// TaskCalendarBucket lists the tasks spanning a day or a week, Start and
+// End are the first and last second of the period
+type TaskCalendarBucket struct {
+	Date    string   `json:"date"`
+	Start   int64    `json:"start"`
+	End     int64    `json:"end"`
+	TaskIDs []string `json:"task_ids"`
+}
+
+type TaskCalendar struct {
+	From     int64                 `json:"from"`
+	To       int64                 `json:"to"`
+	GroupBy  string                `json:"group_by"`
+	Timezone string                `json:"timezone"`
+	Buckets  []*TaskCalendarBucket `json:"buckets"`
+	Tasks    []*TaskResponse       `json:"tasks"`
+	Total    int64                 `json:"total"`
+	// Truncated is set when more tasks than returned overlap the range
+	Truncated bool `json:"truncated"`
+}
+
+type TaskTreeNode struct {
+	Task     *TaskResponse   `json:"task"`
+	Children []*TaskTreeNode `json:"children"`
+}
+
+type TaskDependencyEdge struct {
+	TaskID    string `json:"task_id"`
+	DependsOn string `json:"depends_on"`
+}
+
+// TaskDependencyGraph holds every task upstream and downstream of a task,
+// Blocked tells whether the task still waits on unfinished predecessors
+type TaskDependencyGraph struct {
+	TaskID  string                `json:"task_id"`
+	Blocked bool                  `json:"blocked"`
+	Tasks   []*TaskResponse       `json:"tasks"`
+	Edges   []*TaskDependencyEdge `json:"edges"`
 }
 
 type ReportResponse struct {
@@ -47,6 +149,219 @@ type ReportResponse struct {
 	Creator  string `json:"creator" bson:"creator"`
 	Report   string `json:"report" bson:"report"`
 	Feedback string `json:"feedback" bson:"feedback"`
+	// the fields below are set by the report listings
+	TaskID      string `json:"task_id,omitempty" bson:"task_id"`
+	TaskTitle   string `json:"task_title,omitempty" bson:"task_title"`
+	CreatorName string `json:"creator_name,omitempty" bson:"creator_name"`
+	ReportFile  string `json:"report_file,omitempty" bson:"report_file"`
+	Status      string `json:"status,omitempty" bson:"status"`
+	ReviewedBy  string `json:"reviewed_by,omitempty" bson:"reviewed_by"`
+	ReviewedAt  int64  `json:"reviewed_at,omitempty" bson:"reviewed_at"`
+	CreatedAt   int64  `json:"created_at,omitempty" bson:"created_at"`
+	UpdatedAt   int64  `json:"updated_at,omitempty" bson:"updated_at"`
+}
+
+// CommentResponse is a comment with its author, the replies are set on the
+// first comment of a thread. Deleted comments have no content left.
+type CommentResponse struct {
+	ID          string              `json:"id"`
+	TaskID      string              `json:"task_id"`
+	ReportID    string              `json:"report_id,omitempty"`
+	ParentID    string              `json:"parent_id,omitempty"`
+	Author      string              `json:"author"`
+	AuthorName  string              `json:"author_name"`
+	Content     string              `json:"content"`
+	Mentions    []string            `json:"mentions"`
+	Attachments []CommentAttachment `json:"attachments"`
+	Edited      bool                `json:"edited"`
+	Deleted     bool                `json:"deleted"`
+	CreatedAt   int64               `json:"created_at"`
+	UpdatedAt   int64               `json:"updated_at"`
+	Replies     []*CommentResponse  `json:"replies,omitempty"`
+}
+
+type CommentEditResponse struct {
+	Content     string              `json:"content"`
+	Attachments []CommentAttachment `json:"attachments"`
+	Editor      string              `json:"editor"`
+	EditorName  string              `json:"editor_name"`
+	EditedAt    int64               `json:"edited_at"`
+}
+
+// TaskMetrics are the task counters of a user or a workspace over a range.
+// A task counts for every user it is assigned to.
+type TaskMetrics struct {
+	// Created counts the tasks created in the range
+	Created   int64 `json:"created" bson:"created"`
+	Completed int64 `json:"completed" bson:"completed"`
+	// CompletedOnTime counts the completions on or before the deadline
+	CompletedOnTime int64   `json:"completed_on_time" bson:"completed_on_time"`
+	OnTimeRate      float64 `json:"on_time_rate" bson:"-"`
+	// AvgCycleTime is the mean time in seconds from the first move to doing
+	// to the completion of the tasks completed in the range
+	AvgCycleTime float64 `json:"avg_cycle_time" bson:"avg_cycle_time"`
+	// Overdue counts the unfinished tasks whose deadline passed in the range
+	Overdue int64 `json:"overdue" bson:"overdue"`
+	// Reports counts the reports written in the range
+	Reports int64 `json:"reports" bson:"-"`
+}
+
+type UserMetrics struct {
+	UserID      string `json:"user_id" bson:"_id"`
+	FullName    string `json:"full_name" bson:"-"`
+	TaskMetrics `bson:",inline"`
+}
+
+// the workspace totals are only returned with analytics.workspace
+// the workspace totals are only returned to heads and executives
+type AnalyticsMetrics struct {
+	From      int64          `json:"from"`
+	To        int64          `json:"to"`
+	Workspace *TaskMetrics   `json:"workspace,omitempty"`
+	Users     []*UserMetrics `json:"users"`
+}
+
+// AnalyticsWeek is a point of the weekly series. Remaining is the burn-down,
+// the unfinished tasks at the end of the week, Completed the throughput.
+type AnalyticsWeek struct {
+	Date      string `json:"date"`
+	Start     int64  `json:"start"`
+	End       int64  `json:"end"`
+	Created   int64  `json:"created"`
+	Completed int64  `json:"completed"`
+	Remaining int64  `json:"remaining"`
+}
+
+type AnalyticsSeries struct {
+	From     int64            `json:"from"`
+	To       int64            `json:"to"`
+	Timezone string           `json:"timezone"`
+	UserID   string           `json:"user_id,omitempty"`
+	Weeks    []*AnalyticsWeek `json:"weeks"`
+}
+
+// ExportJobResponse is a background export, DownloadURL is set once the
+// file is ready
+type ExportJobResponse struct {
+	ID          string `json:"id"`
+	Format      string `json:"format"`
+	Source      string `json:"source,omitempty"`
+	Status      string `json:"status"`
+	FileName    string `json:"file_name"`
+	Rows        int64  `json:"rows"`
+	Error       string `json:"error,omitempty"`
+	DownloadURL string `json:"download_url,omitempty"`
+	CreatedAt   int64  `json:"created_at"`
+	FinishedAt  int64  `json:"finished_at,omitempty"`
+	ExpiresAt   int64  `json:"expires_at,omitempty"`
+}
+
+// CreateTaskResponse lists the open tasks of the workspace that look like
+// the created task, most similar first
+type CreateTaskResponse struct {
+	ID         string         `json:"id"`
+	Duplicates []*SimilarTask `json:"duplicates"`
+}
+
+// SimilarTask is a task close in meaning to another one, Similarity goes
+// from 0 to 1
+type SimilarTask struct {
+	ID         string  `json:"id"`
+	Title      string  `json:"title"`
+	Status     string  `json:"status"`
+	Assignee   string  `json:"assignee"`
+	Deadline   int64   `json:"deadline"`
+	Similarity float64 `json:"similarity"`
+}
+
+// AskTasksResponse answers a question about the tasks and reports of the
+// workspace. The answer cites tasks and reports as their ID in brackets,
+// Tasks and Reports are the cited ones.
+type AskTasksResponse struct {
+	Answer string `json:"answer"`
+	// Filter is what the question was read as, Search being the part
+	// matched by meaning
+	Filter  TaskFilter        `json:"filter"`
+	Tasks   []*TaskCitation   `json:"tasks"`
+	Reports []*ReportCitation `json:"reports"`
+}
+
+type TaskCitation struct {
+	ID       string `json:"id"`
+	Title    string `json:"title"`
+	Status   string `json:"status"`
+	Assignee string `json:"assignee"`
+	Deadline int64  `json:"deadline"`
+}
+
+type ReportCitation struct {
+	ID        string `json:"id"`
+	TaskID    string `json:"task_id"`
+	Creator   string `json:"creator"`
+	Report    string `json:"report"`
+	Status    string `json:"status"`
+	CreatedAt int64  `json:"created_at"`
+}
+
+// TaskDraft is a task suggested by the AI, to be reviewed and confirmed
+// through /tasks/drafts/confirm. Assignee is empty when no member fits and
+// dates are 0 when the text has none.
+type TaskDraft struct {
+	Title        string `json:"title"`
+	Description  string `json:"description"`
+	Assignee     string `json:"assignee"`
+	AssigneeName string `json:"assignee_name"`
+	StartAt      int64  `json:"start_at"`
+	Deadline     int64  `json:"deadline"`
+	Priority     int    `json:"priority"`
+}
+
+type TaskDraftsResponse struct {
+	Drafts []*TaskDraft `json:"drafts"`
+}
+
+// ConfirmTaskDraftsResponse lists the drafts that could not be created by
+// their index in the request
+type ConfirmTaskDraftsResponse struct {
+	Created int              `json:"created"`
+	Errors  []TaskDraftError `json:"errors"`
+}
+
+type TaskDraftError struct {
+	Index   int    `json:"index"`
+	Message string `json:"message"`
+}
+
+// ImportResult reports an import row by row, Imported counts the rows
+// written or, on a dry run, the valid rows that would be
+type ImportResult struct {
+	Kind        string           `json:"kind"`
+	DryRun      bool             `json:"dry_run"`
+	SkipInvalid bool             `json:"skip_invalid"`
+	Rows        int              `json:"rows"`
+	Imported    int              `json:"imported"`
+	Skipped     int              `json:"skipped"`
+	Errors      []ImportRowError `json:"errors"`
+}
+
+// ImportRowError is a problem with a row, Row is its line in the file
+// where the header is line 1
+type ImportRowError struct {
+	Row     int    `json:"row"`
+	Column  string `json:"column,omitempty"`
+	Message string `json:"message"`
+}
+
+type TaskActivityResponse struct {
+	ID        string        `json:"id"`
+	TaskID    string        `json:"task_id"`
+	ReportID  string        `json:"report_id,omitempty"`
+	CommentID string        `json:"comment_id,omitempty"`
+	Actor     string        `json:"actor"`
+	ActorName string        `json:"actor_name"`
+	Action    string        `json:"action"`
+	Changes   []FieldChange `json:"changes"`
+	CreatedAt int64         `json:"created_at"`
 }
 
 type ChatResponse struct {
@@ -66,6 +381,11 @@ type ChunkDocumentResponse struct {
 	PageNumber  int      `json:"page_number" bson:"page_number"`
 	ChunkNumber int      `json:"chunk_number" bson:"chunk_number"`
 	Tags        []string `json:"tags" bson:"tags"`
+	Source      string   `json:"source,omitempty" bson:"source,omitempty"`
+	TaskID      string   `json:"task_id,omitempty" bson:"task_id,omitempty"`
+	ReportID    string   `json:"report_id,omitempty" bson:"report_id,omitempty"`
+	Workspace   string   `json:"workspace,omitempty" bson:"workspace,omitempty"`
+	Assignee    string   `json:"assignee,omitempty" bson:"assignee,omitempty"`
 }
 
 type UploadDocumentResponse struct {
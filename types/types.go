@@ -16,7 +16,7 @@ const (
 	USER_MANAGEMENT_LEVEL_HEAD      = 4
 	USER_MANAGEMENT_LEVEL_DHEAD     = 3
 	USER_MANAGEMENT_LEVEL_ASSISTANT = 2
-	USER_MANAGEMENT_LEVEL_STAFF     = 2
+	USER_MANAGEMENT_LEVEL_STAFF     = 1
 )
 
 var MAPPING_ROLE_TO_MANAGEMENT_LEVEL map[string]int = map[string]int{
@@ -27,6 +27,95 @@ var MAPPING_ROLE_TO_MANAGEMENT_LEVEL map[string]int = map[string]int{
 	USER_WORKSPACE_ROLE_STAFF:     USER_MANAGEMENT_LEVEL_STAFF,
 }
 
+// WORKSPACE_ROLES are the workspace roles, highest level first
+var WORKSPACE_ROLES = []string{
+	USER_WORKSPACE_ROLE_EXECUTIVE,
+	USER_WORKSPACE_ROLE_HEAD,
+	USER_WORKSPACE_ROLE_DHEAD,
+	USER_WORKSPACE_ROLE_ASSISTANT,
+	USER_WORKSPACE_ROLE_STAFF,
+}
+
+// Permissions are granted to workspace roles, admins hold all of them
+const (
+	PERMISSION_TASK_CREATE = "task.create"
+	// PERMISSION_TASK_ASSIGN assigns tasks to other members, at most of the
+	// same management level
+	PERMISSION_TASK_ASSIGN = "task.assign"
+	// PERMISSION_TASK_MANAGE manages the tasks and templates created by
+	// members of a lower management level
+	PERMISSION_TASK_MANAGE     = "task.manage"
+	PERMISSION_TASK_TEMPLATE   = "task.template"
+	PERMISSION_REPORT_CREATE   = "report.create"
+	PERMISSION_REPORT_FEEDBACK = "report.feedback"
+	PERMISSION_REPORT_REVIEW   = "report.review"
+	PERMISSION_FIELD_MANAGE    = "field.manage"
+	PERMISSION_DOCUMENT_READ   = "document.read"
+	PERMISSION_DOCUMENT_UPLOAD = "document.upload"
+	// PERMISSION_ANALYTICS_WORKSPACE reads the analytics of the whole
+	// workspace, the others only see themselves and the members below them
+	PERMISSION_ANALYTICS_WORKSPACE = "analytics.workspace"
+	PERMISSION_SUMMARY_READ        = "summary.read"
+	PERMISSION_DATA_IMPORT         = "data.import"
+	// PERMISSION_USER_MANAGE manages the users of the workspace, admins
+	// manage every user
+	PERMISSION_USER_MANAGE      = "user.manage"
+	PERMISSION_ROLE_MANAGE      = "role.manage"
+	PERMISSION_WORKSPACE_MANAGE = "workspace.manage"
+	PERMISSION_AUDIT_READ       = "audit.read"
+)
+
+var PERMISSIONS = []string{
+	PERMISSION_TASK_CREATE,
+	PERMISSION_TASK_ASSIGN,
+	PERMISSION_TASK_MANAGE,
+	PERMISSION_TASK_TEMPLATE,
+	PERMISSION_REPORT_CREATE,
+	PERMISSION_REPORT_FEEDBACK,
+	PERMISSION_REPORT_REVIEW,
+	PERMISSION_FIELD_MANAGE,
+	PERMISSION_DOCUMENT_READ,
+	PERMISSION_DOCUMENT_UPLOAD,
+	PERMISSION_ANALYTICS_WORKSPACE,
+	PERMISSION_SUMMARY_READ,
+	PERMISSION_DATA_IMPORT,
+	PERMISSION_USER_MANAGE,
+	PERMISSION_ROLE_MANAGE,
+	PERMISSION_WORKSPACE_MANAGE,
+	PERMISSION_AUDIT_READ,
+}
+
+// DEFAULT_ROLE_PERMISSIONS are the permissions of the roles an admin has
+// not edited
+var DEFAULT_ROLE_PERMISSIONS = map[string][]string{
+	USER_WORKSPACE_ROLE_EXECUTIVE: {
+		PERMISSION_TASK_CREATE, PERMISSION_TASK_ASSIGN, PERMISSION_TASK_MANAGE, PERMISSION_TASK_TEMPLATE,
+		PERMISSION_REPORT_CREATE, PERMISSION_REPORT_FEEDBACK, PERMISSION_REPORT_REVIEW, PERMISSION_FIELD_MANAGE,
+		PERMISSION_DOCUMENT_READ, PERMISSION_DOCUMENT_UPLOAD, PERMISSION_ANALYTICS_WORKSPACE, PERMISSION_SUMMARY_READ,
+		PERMISSION_DATA_IMPORT,
+	},
+	USER_WORKSPACE_ROLE_HEAD: {
+		PERMISSION_TASK_CREATE, PERMISSION_TASK_ASSIGN, PERMISSION_TASK_MANAGE, PERMISSION_TASK_TEMPLATE,
+		PERMISSION_REPORT_CREATE, PERMISSION_REPORT_FEEDBACK, PERMISSION_REPORT_REVIEW, PERMISSION_FIELD_MANAGE,
+		PERMISSION_DOCUMENT_READ, PERMISSION_DOCUMENT_UPLOAD, PERMISSION_ANALYTICS_WORKSPACE, PERMISSION_SUMMARY_READ,
+	},
+	USER_WORKSPACE_ROLE_DHEAD: {
+		PERMISSION_TASK_CREATE, PERMISSION_TASK_ASSIGN, PERMISSION_TASK_MANAGE, PERMISSION_TASK_TEMPLATE,
+		PERMISSION_REPORT_CREATE, PERMISSION_REPORT_FEEDBACK, PERMISSION_REPORT_REVIEW,
+		PERMISSION_DOCUMENT_READ, PERMISSION_DOCUMENT_UPLOAD,
+	},
+	USER_WORKSPACE_ROLE_ASSISTANT: {
+		PERMISSION_TASK_CREATE, PERMISSION_TASK_ASSIGN, PERMISSION_TASK_TEMPLATE,
+		PERMISSION_REPORT_CREATE, PERMISSION_REPORT_FEEDBACK, PERMISSION_REPORT_REVIEW,
+		PERMISSION_DOCUMENT_READ, PERMISSION_DOCUMENT_UPLOAD,
+	},
+	USER_WORKSPACE_ROLE_STAFF: {
+		PERMISSION_TASK_CREATE, PERMISSION_TASK_TEMPLATE,
+		PERMISSION_REPORT_CREATE, PERMISSION_REPORT_FEEDBACK, PERMISSION_REPORT_REVIEW,
+		PERMISSION_DOCUMENT_READ, PERMISSION_DOCUMENT_UPLOAD,
+	},
+}
+
 const (
 	TASK_STATUS_OPEN      = "open"
 	TASK_STATUS_CLOSE     = "close"
@@ -36,6 +125,148 @@ const (
 	TASK_STATUS_REVIEW    = "review"
 )
 
+const (
+	REPORT_STATUS_PENDING  = "pending"
+	REPORT_STATUS_APPROVED = "approved"
+	REPORT_STATUS_REJECTED = "rejected"
+)
+
+const (
+	TASK_PRIORITY_NONE   = 0
+	TASK_PRIORITY_LOW    = 1
+	TASK_PRIORITY_MEDIUM = 2
+	TASK_PRIORITY_HIGH   = 3
+	TASK_PRIORITY_URGENT = 4
+)
+
+const (
+	CUSTOM_FIELD_TYPE_TEXT   = "text"
+	CUSTOM_FIELD_TYPE_NUMBER = "number"
+	// CUSTOM_FIELD_TYPE_DATE values are unix timestamps
+	CUSTOM_FIELD_TYPE_DATE = "date"
+	CUSTOM_FIELD_TYPE_ENUM = "enum"
+)
+
+// TASK_BOARD_STATUSES is the column order of the task board
+var TASK_BOARD_STATUSES = []string{
+	TASK_STATUS_OPEN,
+	TASK_STATUS_DOING,
+	TASK_STATUS_REVIEW,
+	TASK_STATUS_COMPLETED,
+	TASK_STATUS_CLOSE,
+	TASK_STATUS_CANCEL,
+}
+
+// TASK_SORT_RELEVANCE orders a full-text search by score
+const TASK_SORT_RELEVANCE = "relevance"
+
+var TASK_SORT_FIELDS = []string{
+	"deadline",
+	"start_at",
+	"created_at",
+	"updated_at",
+	"progress",
+	"priority",
+	"title",
+	"status",
+	TASK_SORT_RELEVANCE,
+}
+
+const (
+	CALENDAR_GROUP_DAY  = "day"
+	CALENDAR_GROUP_WEEK = "week"
+)
+
+const (
+	TASK_ACTOR_ASSIGNEE = "assignee"
+	TASK_ACTOR_CREATOR  = "creator"
+	TASK_ACTOR_MANAGER  = "manager"
+)
+
+const (
+	TASK_ACTIVITY_CREATE         = "create"
+	TASK_ACTIVITY_UPDATE         = "update"
+	TASK_ACTIVITY_STATUS_CHANGE  = "status_change"
+	TASK_ACTIVITY_REASSIGN       = "reassign"
+	TASK_ACTIVITY_DELETE         = "delete"
+	TASK_ACTIVITY_REPORT         = "report"
+	TASK_ACTIVITY_REPORT_UPDATE  = "report_update"
+	TASK_ACTIVITY_REPORT_DELETE  = "report_delete"
+	TASK_ACTIVITY_FEEDBACK       = "feedback"
+	TASK_ACTIVITY_REVIEW         = "review"
+	TASK_ACTIVITY_DEPENDENCY     = "dependency"
+	TASK_ACTIVITY_COMMENT        = "comment"
+	TASK_ACTIVITY_COMMENT_UPDATE = "comment_update"
+	TASK_ACTIVITY_COMMENT_DELETE = "comment_delete"
+)
+
+const (
+	DOCUMENT_SOURCE_UPLOAD = "upload"
+	DOCUMENT_SOURCE_REPORT = "report"
+)
+
+const (
+	NOTIFICATION_TYPE_TASK_ASSIGNED      = "task_assigned"
+	NOTIFICATION_TYPE_TASK_STATUS        = "task_status"
+	NOTIFICATION_TYPE_REPORT_ADDED       = "report_added"
+	NOTIFICATION_TYPE_REPORT_FEEDBACK    = "report_feedback"
+	NOTIFICATION_TYPE_REPORT_REVIEWED    = "report_reviewed"
+	NOTIFICATION_TYPE_DEADLINE_REMINDER  = "deadline_reminder"
+	NOTIFICATION_TYPE_OVERDUE_ESCALATION = "overdue_escalation"
+	NOTIFICATION_TYPE_DOCUMENT_INGESTED  = "document_ingested"
+	NOTIFICATION_TYPE_DIGEST             = "digest"
+	NOTIFICATION_TYPE_COMMENT_MENTION    = "comment_mention"
+	NOTIFICATION_TYPE_EXPORT_READY       = "export_ready"
+	NOTIFICATION_TYPE_EXPORT_FAILED      = "export_failed"
+	NOTIFICATION_TYPE_WEEKLY_SUMMARY     = "weekly_summary"
+)
+
+const (
+	EXPORT_FORMAT_CSV  = "csv"
+	EXPORT_FORMAT_XLSX = "xlsx"
+	EXPORT_FORMAT_PDF  = "pdf"
+)
+
+const (
+	EXPORT_SOURCE_TASKS   = "tasks"
+	EXPORT_SOURCE_REPORTS = "reports"
+)
+
+const (
+	EXPORT_STATUS_PENDING = "pending"
+	EXPORT_STATUS_RUNNING = "running"
+	EXPORT_STATUS_DONE    = "done"
+	EXPORT_STATUS_FAILED  = "failed"
+)
+
+const (
+	IMPORT_KIND_USERS   = "users"
+	IMPORT_KIND_TASKS   = "tasks"
+	IMPORT_KIND_REPORTS = "reports"
+)
+
+const (
+	NOTIFICATION_CHANNEL_EMAIL   = "email"
+	NOTIFICATION_CHANNEL_WEBHOOK = "webhook"
+)
+
+const (
+	EMAIL_MODE_OFF     = "off"
+	EMAIL_MODE_INSTANT = "instant"
+	EMAIL_MODE_DIGEST  = "digest"
+)
+
+const (
+	DELIVERY_STATUS_PENDING = "pending"
+	DELIVERY_STATUS_SENT    = "sent"
+	DELIVERY_STATUS_FAILED  = "failed"
+)
+
+const (
+	RECURRENCE_OVERLAP_ALLOW = "allow"
+	RECURRENCE_OVERLAP_SKIP  = "skip"
+)
+
 const (
 	DepartmentTechnical      = "DepartmentTechnical"
 	DepartmentProductionPlan = "DepartmentProductionPlan"
@@ -43,28 +274,40 @@ const (
 	DepartmentMaterial       = "DepartmentMaterial"
 )
 
-type Admin struct {
-	ID       string `json:"id" bson:"_id,omitempty"`
-	Username string `json:"username" bson:"username"`
-	Password string `json:"password" bson:"password"`
-	Role     string `json:"role" bson:"role"`
+// DEFAULT_WORKSPACES are the departments created as workspaces on first
+// start, the workspaces are then managed by admins
+var DEFAULT_WORKSPACES = []string{
+	DepartmentTechnical,
+	DepartmentProductionPlan,
+	DepartmentQuality,
+	DepartmentMaterial,
 }
 
 type User struct {
-	ID              string `json:"id" bson:"_id,omitempty"`
-	Username        string `json:"username" bson:"username"`
-	Password        string `json:"password" bson:"password"`
-	FullName        string `json:"full_name" bson:"full_name"`
+	ID       string `json:"id" bson:"_id,omitempty"`
+	Username string `json:"username" bson:"username"`
+	Password string `json:"password" bson:"password"`
+	FullName string `json:"full_name" bson:"full_name"`
+	// Role is USER_ROLE_ADMIN for admins, empty for the other users
+	Role            string `json:"role,omitempty" bson:"role"`
 	ManagementLevel int    `json:"management_level" bson:"management_level"`
 	WorkspaceRole   string `json:"workspace_role" bson:"workspace_role"`
 	Workspace       string `json:"workspace" bson:"workspace"`
-	CreateAt        int64  `json:"created_at" bson:"created_at"`
-	UpdateAt        int64  `json:"updated_at" bson:"updated_at"`
+	// Deactivated users can no longer sign in nor be put on tasks
+	Deactivated bool  `json:"deactivated" bson:"deactivated"`
+	CreateAt    int64 `json:"created_at" bson:"created_at"`
+	UpdateAt    int64 `json:"updated_at" bson:"updated_at"`
 }
 
+// Workspace is a department users and tasks belong to, they refer to it by
+// its name, which never changes
 type Workspace struct {
-	ID   string `json:"id" bson:"_id,omitempty"`
-	Name string `json:"name" bson:"name"`
+	ID          string `json:"id" bson:"_id,omitempty"`
+	Name        string `json:"name" bson:"name"`
+	DisplayName string `json:"display_name" bson:"display_name"`
+	Description string `json:"description" bson:"description"`
+	CreatedAt   int64  `json:"created_at" bson:"created_at"`
+	UpdatedAt   int64  `json:"updated_at" bson:"updated_at"`
 }
 
 type Task struct {
@@ -74,12 +317,217 @@ type Task struct {
 	Workspace   string `json:"workspace" bson:"workspace"`
 	Creator     string `json:"creator" bson:"creator"`
 	Deadline    int64  `json:"deadline" bson:"deadline"`
-	Assignee    string `json:"assignee" bson:"assignee"`
-	Status      string `json:"status" bson:"status"`
-	Progress    int    `json:"progress" bson:"progress"`
-	CreateAt    int64  `json:"created_at" bson:"created_at"`
-	StartAt     int64  `json:"start_at" bson:"start_at"`
-	UpdateAt    int64  `json:"updated_at" bson:"updated_at"`
+	// Assignee is the primary assignee, the lead responsible for the task
+	Assignee string `json:"assignee" bson:"assignee"`
+	// Assignees lists everyone working on the task, the primary included
+	Assignees []string `json:"assignees" bson:"assignees"`
+	// Watchers follow the task without working on it
+	Watchers []string `json:"watchers" bson:"watchers"`
+	Status   string   `json:"status" bson:"status"`
+	Progress int      `json:"progress" bson:"progress"`
+	CreateAt int64    `json:"created_at" bson:"created_at"`
+	StartAt  int64    `json:"start_at" bson:"start_at"`
+	UpdateAt int64    `json:"updated_at" bson:"updated_at"`
+	// CompletedAt is when the task was last completed, it is kept once the
+	// task is closed and 0 until it is completed or after it is reopened
+	CompletedAt int64 `json:"completed_at" bson:"completed_at"`
+	// ParentID is set on subtasks, the parent progress is rolled up from them
+	ParentID string `json:"parent_id" bson:"parent_id,omitempty"`
+	// DependsOn lists finish-to-start predecessors of the task
+	DependsOn []string `json:"depends_on" bson:"depends_on"`
+	// TemplateID and OccurrenceAt are set on tasks generated from a template
+	TemplateID   string `json:"template_id" bson:"template_id,omitempty"`
+	OccurrenceAt int64  `json:"occurrence_at" bson:"occurrence_at,omitempty"`
+	// Priority is one of TASK_PRIORITY_*, 0 when unset
+	Priority int `json:"priority" bson:"priority"`
+	// Tags are free labels
+	Tags []string `json:"tags" bson:"tags"`
+	// CustomFields holds the values of the workspace custom fields by key
+	CustomFields map[string]interface{} `json:"custom_fields" bson:"custom_fields"`
+}
+
+// CustomFieldDefinition is a task field a workspace tracks on top of the
+// built-in ones, Key and Type cannot change once tasks use it
+type CustomFieldDefinition struct {
+	ID        string `json:"id" bson:"_id,omitempty"`
+	Workspace string `json:"workspace" bson:"workspace"`
+	Key       string `json:"key" bson:"key"`
+	Name      string `json:"name" bson:"name"`
+	Type      string `json:"type" bson:"type"`
+	// Options are the allowed values of an enum field
+	Options   []string `json:"options" bson:"options,omitempty"`
+	Required  bool     `json:"required" bson:"required"`
+	Creator   string   `json:"creator" bson:"creator"`
+	CreatedAt int64    `json:"created_at" bson:"created_at"`
+	UpdatedAt int64    `json:"updated_at" bson:"updated_at"`
+}
+
+// CustomFieldFilter matches a custom field value, Equals or the inclusive
+// From and To bounds of number and date fields. Values are parsed from
+// strings to the field type.
+type CustomFieldFilter struct {
+	Key    string      `json:"key"`
+	Equals interface{} `json:"equals"`
+	From   interface{} `json:"from"`
+	To     interface{} `json:"to"`
+}
+
+type Notification struct {
+	ID        string `json:"id" bson:"_id,omitempty"`
+	Recipient string `json:"recipient" bson:"recipient"`
+	Actor     string `json:"actor" bson:"actor,omitempty"`
+	Type      string `json:"type" bson:"type"`
+	TaskID    string `json:"task_id" bson:"task_id"`
+	ReportID  string `json:"report_id" bson:"report_id,omitempty"`
+	CommentID string `json:"comment_id,omitempty" bson:"comment_id,omitempty"`
+	SummaryID string `json:"summary_id,omitempty" bson:"summary_id,omitempty"`
+	Title     string `json:"title" bson:"title"`
+	Message   string `json:"message" bson:"message"`
+	// ReadAt is 0 while the notification is unread
+	ReadAt    int64 `json:"read_at" bson:"read_at"`
+	CreatedAt int64 `json:"created_at" bson:"created_at"`
+}
+
+// NotificationPreference holds the external channels a user subscribed to,
+// an empty type list means every notification type
+type NotificationPreference struct {
+	ID            string   `json:"id" bson:"_id,omitempty"`
+	UserID        string   `json:"user_id" bson:"user_id"`
+	Email         string   `json:"email" bson:"email"`
+	EmailMode     string   `json:"email_mode" bson:"email_mode"`
+	EmailTypes    []string `json:"email_types" bson:"email_types"`
+	WebhookURL    string   `json:"webhook_url" bson:"webhook_url"`
+	WebhookSecret string   `json:"-" bson:"webhook_secret"`
+	WebhookTypes  []string `json:"webhook_types" bson:"webhook_types"`
+	LastDigestAt  int64    `json:"last_digest_at" bson:"last_digest_at"`
+	UpdatedAt     int64    `json:"updated_at" bson:"updated_at"`
+}
+
+// NotificationDelivery is an outbound email or webhook, retried with backoff
+// until it is sent or runs out of attempts
+type NotificationDelivery struct {
+	ID             string `json:"id" bson:"_id,omitempty"`
+	Channel        string `json:"channel" bson:"channel"`
+	Recipient      string `json:"recipient" bson:"recipient"`
+	NotificationID string `json:"notification_id" bson:"notification_id,omitempty"`
+	Event          string `json:"event" bson:"event"`
+	// Target is the email address or the webhook URL
+	Target        string `json:"target" bson:"target"`
+	Secret        string `json:"-" bson:"secret,omitempty"`
+	Subject       string `json:"subject" bson:"subject,omitempty"`
+	Body          string `json:"body" bson:"body"`
+	Status        string `json:"status" bson:"status"`
+	Attempts      int    `json:"attempts" bson:"attempts"`
+	NextAttemptAt int64  `json:"next_attempt_at" bson:"next_attempt_at"`
+	LastError     string `json:"last_error" bson:"last_error"`
+	CreatedAt     int64  `json:"created_at" bson:"created_at"`
+	UpdatedAt     int64  `json:"updated_at" bson:"updated_at"`
+}
+
+// WebhookPayload is the JSON body posted to webhook subscribers
+type WebhookPayload struct {
+	Event        string        `json:"event"`
+	Notification *Notification `json:"notification"`
+}
+
+// TaskDeadlineAlert records a reminder or escalation step sent for a task
+// deadline so that it is sent once, a new deadline starts the steps over
+type TaskDeadlineAlert struct {
+	ID         string   `json:"id" bson:"_id,omitempty"`
+	TaskID     string   `json:"task_id" bson:"task_id"`
+	Type       string   `json:"type" bson:"type"`
+	Step       int      `json:"step" bson:"step"`
+	Deadline   int64    `json:"deadline" bson:"deadline"`
+	Recipients []string `json:"recipients" bson:"recipients"`
+	CreatedAt  int64    `json:"created_at" bson:"created_at"`
+}
+
+// TaskTemplate generates a task for every occurrence of its recurrence,
+// GenerateAhead seconds before the occurrence starts
+type TaskTemplate struct {
+	ID          string   `json:"id" bson:"_id,omitempty"`
+	Title       string   `json:"title" bson:"title"`
+	Description string   `json:"description" bson:"description"`
+	Workspace   string   `json:"workspace" bson:"workspace"`
+	Creator     string   `json:"creator" bson:"creator"`
+	Assignee    string   `json:"assignee" bson:"assignee"`
+	Assignees   []string `json:"assignees" bson:"assignees"`
+	Watchers    []string `json:"watchers" bson:"watchers"`
+	// Recurrence is a standard 5 field cron expression or an RFC 5545 RRULE
+	Recurrence string `json:"recurrence" bson:"recurrence"`
+	// Timezone is an IANA name, the server timezone is used when empty
+	Timezone      string `json:"timezone" bson:"timezone"`
+	StartAt       int64  `json:"start_at" bson:"start_at"`
+	EndAt         int64  `json:"end_at" bson:"end_at"`
+	Duration      int64  `json:"duration" bson:"duration"`
+	GenerateAhead int64  `json:"generate_ahead" bson:"generate_ahead"`
+	OverlapPolicy string `json:"overlap_policy" bson:"overlap_policy"`
+	Active        bool   `json:"active" bson:"active"`
+	// LastOccurrence is the latest occurrence handled, generated or skipped
+	LastOccurrence int64 `json:"last_occurrence" bson:"last_occurrence"`
+	Skipped        int64 `json:"skipped" bson:"skipped"`
+	CreatedAt      int64 `json:"created_at" bson:"created_at"`
+	UpdatedAt      int64 `json:"updated_at" bson:"updated_at"`
+}
+
+type TaskStatusTransition struct {
+	ID        string `json:"id" bson:"_id,omitempty"`
+	TaskID    string `json:"task_id" bson:"task_id"`
+	From      string `json:"from" bson:"from"`
+	To        string `json:"to" bson:"to"`
+	Actor     string `json:"actor" bson:"actor"`
+	CreatedAt int64  `json:"created_at" bson:"created_at"`
+}
+
+type FieldChange struct {
+	Field    string      `json:"field" bson:"field"`
+	OldValue interface{} `json:"old_value" bson:"old_value"`
+	NewValue interface{} `json:"new_value" bson:"new_value"`
+}
+
+const (
+	AUDIT_TARGET_USER      = "user"
+	AUDIT_TARGET_WORKSPACE = "workspace"
+	AUDIT_TARGET_ROLE      = "role"
+)
+
+const (
+	AUDIT_ACTION_USER_CREATE      = "user.create"
+	AUDIT_ACTION_USER_UPDATE      = "user.update"
+	AUDIT_ACTION_USER_ROLE        = "user.role"
+	AUDIT_ACTION_USER_PASSWORD    = "user.password"
+	AUDIT_ACTION_USER_DEACTIVATE  = "user.deactivate"
+	AUDIT_ACTION_USER_ACTIVATE    = "user.activate"
+	AUDIT_ACTION_USER_DELETE      = "user.delete"
+	AUDIT_ACTION_WORKSPACE_CREATE = "workspace.create"
+	AUDIT_ACTION_WORKSPACE_UPDATE = "workspace.update"
+	AUDIT_ACTION_WORKSPACE_DELETE = "workspace.delete"
+	AUDIT_ACTION_ROLE_PERMISSIONS = "role.permissions"
+)
+
+// AuditLog is an append-only record of an admin action, passwords are
+// never recorded
+type AuditLog struct {
+	ID         string        `json:"id" bson:"_id,omitempty"`
+	Actor      string        `json:"actor" bson:"actor"`
+	Action     string        `json:"action" bson:"action"`
+	TargetType string        `json:"target_type" bson:"target_type"`
+	TargetID   string        `json:"target_id" bson:"target_id"`
+	Changes    []FieldChange `json:"changes,omitempty" bson:"changes,omitempty"`
+	CreatedAt  int64         `json:"created_at" bson:"created_at"`
+}
+
+// TaskActivity is an append-only history entry for a task or one of its reports
+type TaskActivity struct {
+	ID        string        `json:"id" bson:"_id,omitempty"`
+	TaskID    string        `json:"task_id" bson:"task_id"`
+	Workspace string        `json:"workspace" bson:"workspace,omitempty"`
+	ReportID  string        `json:"report_id,omitempty" bson:"report_id,omitempty"`
+	CommentID string        `json:"comment_id,omitempty" bson:"comment_id,omitempty"`
+	Actor     string        `json:"actor" bson:"actor"`
+	Action    string        `json:"action" bson:"action"`
+	Changes   []FieldChange `json:"changes" bson:"changes"`
+	CreatedAt int64         `json:"created_at" bson:"created_at"`
 }
 
 type Report struct {
@@ -88,11 +536,56 @@ type Report struct {
 	Creator    string `json:"creator" bson:"creator"`
 	Report     string `json:"report" bson:"report"`
 	ReportFile string `json:"report_file" bson:"report_file"`
-	Feedback   string `json:"feedback" bson:"feedback"`
+	// Feedback is the latest feedback given by feedback or review, it is
+	// also posted to the report discussion. Comments posted to the
+	// discussion leave it unchanged.
+	Feedback string `json:"feedback" bson:"feedback"`
+	// Status is one of REPORT_STATUS_*, reports written before reviews
+	// existed have none and count as pending
+	Status     string `json:"status" bson:"status,omitempty"`
+	ReviewedBy string `json:"reviewed_by" bson:"reviewed_by,omitempty"`
+	ReviewedAt int64  `json:"reviewed_at" bson:"reviewed_at,omitempty"`
 	CreatedAt  int64  `json:"created_at" bson:"created_at"`
 	UpdatedAt  int64  `json:"updated_at" bson:"updated_at"`
 }
 
+// Comment is a message in the discussion of a task or, with a report ID, of
+// one of its reports. Replies point to the first comment of their thread,
+// threads are a single level deep.
+type Comment struct {
+	ID        string `json:"id" bson:"_id,omitempty"`
+	Workspace string `json:"workspace" bson:"workspace"`
+	TaskID    string `json:"task_id" bson:"task_id"`
+	ReportID  string `json:"report_id,omitempty" bson:"report_id,omitempty"`
+	ParentID  string `json:"parent_id,omitempty" bson:"parent_id,omitempty"`
+	Author    string `json:"author" bson:"author"`
+	Content   string `json:"content" bson:"content"`
+	// Mentions are the IDs of the workspace members mentioned by username
+	Mentions    []string            `json:"mentions" bson:"mentions"`
+	Attachments []CommentAttachment `json:"attachments" bson:"attachments"`
+	// Edits are the previous versions of the comment, oldest first. A
+	// deleted comment keeps its last version there.
+	Edits     []CommentEdit `json:"edits" bson:"edits"`
+	Deleted   bool          `json:"deleted" bson:"deleted"`
+	CreatedAt int64         `json:"created_at" bson:"created_at"`
+	UpdatedAt int64         `json:"updated_at" bson:"updated_at"`
+	DeletedAt int64         `json:"deleted_at,omitempty" bson:"deleted_at,omitempty"`
+}
+
+type CommentAttachment struct {
+	FileName string `json:"file_name" bson:"file_name"`
+	FilePath string `json:"file_path" bson:"file_path"`
+	FileSize int64  `json:"file_size" bson:"file_size"`
+}
+
+// CommentEdit is a replaced version of a comment, Editor is who replaced it
+type CommentEdit struct {
+	Content     string              `json:"content" bson:"content"`
+	Attachments []CommentAttachment `json:"attachments" bson:"attachments"`
+	Editor      string              `json:"editor" bson:"editor"`
+	EditedAt    int64               `json:"edited_at" bson:"edited_at"`
+}
+
 type FileMetadata struct {
 	ID        string `json:"id" bson:"_id,omitempty"`
 	FileName  string `json:"file_name" bson:"file_name"`
@@ -109,8 +602,10 @@ type Chat struct {
 }
 
 type ChatMessage struct {
-	ID        string `json:"id" bson:"_id,omitempty"`
-	ChatID    string `json:"chat_id" bson:"chat_id"`
+	ID     string `json:"id" bson:"_id,omitempty"`
+	ChatID string `json:"chat_id" bson:"chat_id"`
+	// UserID owns the chat, only they read its messages
+	UserID    string `json:"user_id" bson:"user_id"`
 	Role      string `json:"role" bson:"role"`
 	Content   string `json:"content" bson:"content"`
 	CreatedAt int64  `json:"created_at" bson:"created_at"`
@@ -127,7 +622,52 @@ type TaskFilter struct {
 	ReportFrom   int64  `json:"report_from" bson:"report_from"`
 	ReportTo     int64  `json:"report_to" bson:"report_to"`
 	Assignee     string `json:"assignee" bson:"assignee"`
-	Status       string `json:"status" bson:"status"`
+	// PrimaryAssignee only matches the lead, Assignee matches any assignee
+	PrimaryAssignee string `json:"primary_assignee" bson:"primary_assignee"`
+	Watcher         string `json:"watcher" bson:"watcher"`
+	Status          string `json:"status" bson:"status"`
+	// SpanFrom and SpanTo match tasks whose start to deadline span overlaps
+	// them, a task without start date spans its deadline only
+	SpanFrom int64 `json:"span_from" bson:"span_from"`
+	SpanTo   int64 `json:"span_to" bson:"span_to"`
+	// Statuses, Assignees, Priorities and Tags match any of their values
+	Statuses   []string `json:"statuses" bson:"statuses"`
+	Assignees  []string `json:"assignees" bson:"assignees"`
+	Priorities []int    `json:"priorities" bson:"priorities"`
+	Tags       []string `json:"tags" bson:"tags"`
+	// ProgressMin and ProgressMax are inclusive bounds, nil when unset
+	ProgressMin *int `json:"progress_min" bson:"progress_min"`
+	ProgressMax *int `json:"progress_max" bson:"progress_max"`
+	// Overdue matches unfinished tasks past their deadline
+	Overdue   bool `json:"overdue" bson:"overdue"`
+	NoReports bool `json:"no_reports" bson:"no_reports"`
+	// Search is a full-text search over title and description
+	Search       string              `json:"search" bson:"search"`
+	Sort         []TaskSort          `json:"sort" bson:"sort"`
+	CustomFields []CustomFieldFilter `json:"custom_fields" bson:"custom_fields"`
+}
+
+// TaskSort is a sort key of a task search, Field is one of TASK_SORT_FIELDS
+type TaskSort struct {
+	Field string `json:"field"`
+	Desc  bool   `json:"desc"`
+}
+
+// PageRequest selects a page of a listing by number or, when Cursor is set,
+// the Limit items after the position the cursor was issued for. Cursors are
+// stable while items move between numbered pages.
+type PageRequest struct {
+	Page   int64
+	Limit  int64
+	Cursor string
+}
+
+// AuditLogFilter selects audit logs, empty fields match any log
+type AuditLogFilter struct {
+	Actor      string
+	Action     string
+	TargetType string
+	TargetID   string
 }
 
 type ReportFilter struct {
@@ -136,12 +676,47 @@ type ReportFilter struct {
 	CreatedFrom int64  `json:"created_from" bson:"created_from"`
 	CreatedTo   int64  `json:"created_to" bson:"created_to"`
 	Workspace   string `json:"workspace" bson:"workspace"`
+	// Status matches REPORT_STATUS_*, pending includes unreviewed reports
+	// without status
+	Status string `json:"status" bson:"status"`
+	// Awaiting matches the reports still waiting for a review decision
+	Awaiting bool `json:"awaiting" bson:"awaiting"`
+	// ExcludeCreator drops the reports written by this user
+	ExcludeCreator string `json:"exclude_creator" bson:"exclude_creator"`
+	// TaskCreators keeps the reports of tasks created by any of these users
+	TaskCreators []string `json:"task_creators" bson:"task_creators"`
+}
+
+// AnalyticsQuery selects the workspace tasks measured over the inclusive
+// From to To range
+type AnalyticsQuery struct {
+	Workspace string
+	From      int64
+	To        int64
+	// Now tells overdue tasks from the ones still on time
+	Now int64
+	// Users keeps the tasks assigned to any of these users, all tasks when
+	// empty
+	Users []string
 }
 
+// TaskSeriesCounts are the task counts of consecutive buckets, Unfinished is
+// the number of tasks still unfinished when the first bucket starts
+type TaskSeriesCounts struct {
+	Unfinished int64
+	Created    []int64
+	Completed  []int64
+	// Finished counts the tasks completed, closed or cancelled
+	Finished []int64
+}
+
+// UserFilter selects users, empty fields match any user. Username and
+// FullName match part of the value, ignoring case.
 type UserFilter struct {
-	Username  string `json:"username" bson:"username"`
-	FullName  string `json:"full_name" bson:"full_name"`
-	Workspace string `json:"workspace" bson:"workspace"`
+	Username      string `json:"username" bson:"username"`
+	FullName      string `json:"full_name" bson:"full_name"`
+	Workspace     string `json:"workspace" bson:"workspace"`
+	WorkspaceRole string `json:"workspace_role" bson:"workspace_role"`
 }
 
 type DocumentChunk struct {
@@ -151,16 +726,100 @@ type DocumentChunk struct {
 }
 
 type DocumentMetadata struct {
-	Title    string   `json:"title"`
-	Tags     []string `json:"tags"`
-	FilePath string   `json:"file_path"`
+	Title     string   `json:"title"`
+	Tags      []string `json:"tags"`
+	FilePath  string   `json:"file_path"`
+	Source    string   `json:"source"`
+	TaskID    string   `json:"task_id"`
+	ReportID  string   `json:"report_id"`
+	Workspace string   `json:"workspace"`
+	Assignee  string   `json:"assignee"`
+}
+
+// TaskSimilarity is a task found near another one in the vector database
+type TaskSimilarity struct {
+	TaskID     string  `json:"task_id"`
+	Similarity float64 `json:"similarity"`
+}
+
+// RolePermissions are the permissions granted to a workspace role, stored
+// once an admin edits them
+type RolePermissions struct {
+	ID          string   `json:"-" bson:"_id,omitempty"`
+	Role        string   `json:"role" bson:"role"`
+	Permissions []string `json:"permissions" bson:"permissions"`
+	// Default is true while the role has the DEFAULT_ROLE_PERMISSIONS
+	Default   bool   `json:"default" bson:"-"`
+	UpdatedBy string `json:"updated_by,omitempty" bson:"updated_by,omitempty"`
+	UpdatedAt int64  `json:"updated_at,omitempty" bson:"updated_at,omitempty"`
 }
 
 type PendingDocument struct {
 	ID           string   `json:"id" bson:"_id,omitempty"`
 	DocumentPath string   `json:"document_path" bson:"document_path"`
 	DocumentName string   `json:"document_name" bson:"document_name"`
+	Title        string   `json:"title" bson:"title,omitempty"`
 	Tags         []string `json:"tags" bson:"tags"`
 	ToolUse      string   `json:"tool_use" bson:"tool_use"`
-	CreatedAt    int64    `json:"created_at" bson:"created_at"`
+	Source       string   `json:"source" bson:"source,omitempty"`
+	Content      string   `json:"content" bson:"content,omitempty"`
+	TaskID       string   `json:"task_id" bson:"task_id,omitempty"`
+	ReportID     string   `json:"report_id" bson:"report_id,omitempty"`
+	Workspace    string   `json:"workspace" bson:"workspace,omitempty"`
+	Assignee     string   `json:"assignee" bson:"assignee,omitempty"`
+	// Uploader is notified once the document is ingested
+	Uploader  string `json:"uploader" bson:"uploader,omitempty"`
+	CreatedAt int64  `json:"created_at" bson:"created_at"`
+}
+
+// ExportJob is an export too large to write within the request, the worker
+// writes it to FilePath and the requester downloads it until ExpiresAt
+type ExportJob struct {
+	ID        string        `json:"id" bson:"_id,omitempty"`
+	Workspace string        `json:"workspace" bson:"workspace"`
+	Requester string        `json:"requester" bson:"requester"`
+	Request   ExportRequest `json:"request" bson:"request"`
+	// Status is one of EXPORT_STATUS_*
+	Status   string `json:"status" bson:"status"`
+	FileName string `json:"file_name" bson:"file_name"`
+	FilePath string `json:"-" bson:"file_path,omitempty"`
+	Rows     int64  `json:"rows" bson:"rows"`
+	Error    string `json:"error" bson:"error,omitempty"`
+	// StartedAt is set when the worker picks the job
+	StartedAt  int64 `json:"started_at" bson:"started_at"`
+	FinishedAt int64 `json:"finished_at" bson:"finished_at"`
+	ExpiresAt  int64 `json:"expires_at" bson:"expires_at"`
+	CreatedAt  int64 `json:"created_at" bson:"created_at"`
+}
+
+// WeeklySummary is an AI written status summary of the tasks, reports and
+// feedback of a workspace over the week starting on Monday at WeekStart
+type WeeklySummary struct {
+	ID        string `json:"id" bson:"_id,omitempty"`
+	Workspace string `json:"workspace" bson:"workspace"`
+	WeekStart int64  `json:"week_start" bson:"week_start"`
+	WeekEnd   int64  `json:"week_end" bson:"week_end"`
+	// Content is the Vietnamese summary, it cites tasks by ID in brackets
+	Content string             `json:"content" bson:"content"`
+	Stats   WeeklySummaryStats `json:"stats" bson:"stats"`
+	// SourceTaskIDs are the tasks given to the AI, CitedTaskIDs those the
+	// summary refers to
+	SourceTaskIDs []string `json:"source_task_ids" bson:"source_task_ids"`
+	CitedTaskIDs  []string `json:"cited_task_ids" bson:"cited_task_ids"`
+	// Requester is the user who asked for the summary, empty for the
+	// scheduled ones
+	Requester string `json:"requester" bson:"requester,omitempty"`
+	CreatedAt int64  `json:"created_at" bson:"created_at"`
+}
+
+// WeeklySummaryStats are the counts the summary is written from
+type WeeklySummaryStats struct {
+	// Tasks counts the tasks updated during the week or still unfinished
+	Tasks     int `json:"tasks" bson:"tasks"`
+	Completed int `json:"completed" bson:"completed"`
+	Overdue   int `json:"overdue" bson:"overdue"`
+	// Blocked counts the unfinished tasks waiting on unfinished predecessors
+	Blocked  int `json:"blocked" bson:"blocked"`
+	Reports  int `json:"reports" bson:"reports"`
+	Rejected int `json:"rejected" bson:"rejected"`
 }
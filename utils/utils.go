@@ -15,3 +15,11 @@ func ParseStringArray(v interface{}) []string {
 	}
 	return result
 }
+
+func ParseString(v interface{}) string {
+	str, ok := v.(string)
+	if !ok {
+		return ""
+	}
+	return str
+}